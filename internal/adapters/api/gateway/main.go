@@ -0,0 +1,132 @@
+// Package gateway is HTTP/JSON front end of gRPC services and OpenAPI description of them.
+package gateway
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	pb "github.com/k0st1a/gophkeeper/internal/adapters/api/grpc/gen/proto/v1"
+	openapi "github.com/k0st1a/gophkeeper/third_party/OpenAPI"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// OpenAPIPath - путь, по которому отдается описание API.
+	OpenAPIPath = "/openapi.json"
+
+	bearerPrefix      = "Bearer "
+	readHeaderTimeout = 10 * time.Second
+)
+
+type Server struct {
+	listener net.Listener
+	server   *http.Server
+}
+
+// New - создать HTTP шлюз, где:
+//   - ctx - контекст шлюза, при его отмене шлюз перестает обращаться к gRPC сервисам;
+//   - address - адрес HTTP эндпоинта в формате host:port;
+//   - cc - соединение с gRPC сервером, через которое выполняются запросы;
+//   - tc - TLS конфигурация, если nil, то соединения принимаются без шифрования.
+//
+// Токен пользователя передается в заголовке `Authorization: Bearer <ТОКЕН>`.
+func New(ctx context.Context, address string, cc *grpc.ClientConn, tc *tls.Config) (*Server, error) {
+	gw := runtime.NewServeMux(runtime.WithMetadata(authMetadata))
+
+	err := pb.RegisterUsersServiceHandler(ctx, gw, cc)
+	if err != nil {
+		return nil, fmt.Errorf("register users service handler error:%w", err)
+	}
+
+	err = pb.RegisterItemsServiceHandler(ctx, gw, cc)
+	if err != nil {
+		return nil, fmt.Errorf("register items service handler error:%w", err)
+	}
+
+	err = pb.RegisterSharesServiceHandler(ctx, gw, cc)
+	if err != nil {
+		return nil, fmt.Errorf("register shares service handler error:%w", err)
+	}
+
+	err = pb.RegisterOrgsServiceHandler(ctx, gw, cc)
+	if err != nil {
+		return nil, fmt.Errorf("register orgs service handler error:%w", err)
+	}
+
+	err = pb.RegisterEmergencyAccessServiceHandler(ctx, gw, cc)
+	if err != nil {
+		return nil, fmt.Errorf("register emergency access service handler error:%w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+OpenAPIPath, serveOpenAPI)
+	mux.Handle("/", gw)
+
+	l, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("net listen error:%w", err)
+	}
+
+	if tc != nil {
+		l = tls.NewListener(l, tc.Clone())
+	}
+
+	return &Server{
+		listener: l,
+		server: &http.Server{
+			Handler:           mux,
+			ReadHeaderTimeout: readHeaderTimeout,
+		},
+	}, nil
+}
+
+// Run - запуск шлюза.
+func (s *Server) Run() error {
+	log.Printf("Run http gateway")
+
+	err := s.server.Serve(s.listener)
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("server listen error:%w", err)
+	}
+
+	return nil
+}
+
+// Shutdown - graceful выключение шлюза.
+func (s *Server) Shutdown(ctx context.Context) error {
+	err := s.server.Shutdown(ctx)
+	if err != nil {
+		return fmt.Errorf("server shutdown error:%w", err)
+	}
+
+	return nil
+}
+
+// authMetadata - передать токен из заголовка Authorization в метаданных запроса, как это делает gRPC клиент.
+func authMetadata(_ context.Context, r *http.Request) metadata.MD {
+	a := r.Header.Get("Authorization")
+	if !strings.HasPrefix(a, bearerPrefix) {
+		return nil
+	}
+
+	return metadata.Pairs("token", strings.TrimPrefix(a, bearerPrefix))
+}
+
+func serveOpenAPI(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	_, err := w.Write(openapi.Spec)
+	if err != nil {
+		log.Error().Err(err).Msg("error of write openapi spec")
+	}
+}
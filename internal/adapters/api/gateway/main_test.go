@@ -0,0 +1,151 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	pb "github.com/k0st1a/gophkeeper/internal/adapters/api/grpc/gen/proto/v1"
+)
+
+type items struct {
+	pb.UnimplementedItemsServiceServer
+}
+
+func (i *items) GetItem(ctx context.Context, req *pb.GetItemRequest) (*pb.GetItemResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if t := md.Get("token"); len(t) == 0 || t[0] != "token" {
+		return nil, status.Error(codes.Unauthenticated, "no token") //nolint:wrapcheck // test
+	}
+
+	return &pb.GetItemResponse{Item: &pb.Item{Id: req.GetId(), Data: []byte("data"), Revision: 2}}, nil
+}
+
+func newServer(t *testing.T) string {
+	t.Helper()
+
+	l := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer()
+	pb.RegisterItemsServiceServer(s, &items{})
+	go s.Serve(l) //nolint:errcheck // test
+	t.Cleanup(s.Stop)
+
+	cc, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return l.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = cc.Close() })
+
+	gw, err := New(context.Background(), "127.0.0.1:0", cc, nil)
+	require.NoError(t, err)
+	go gw.Run() //nolint:errcheck // test
+	t.Cleanup(func() { _ = gw.Shutdown(context.Background()) })
+
+	return "http://" + gw.listener.Addr().String()
+}
+
+func get(t *testing.T, url, token string) (int, []byte) {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, http.NoBody)
+	require.NoError(t, err)
+
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close() //nolint:errcheck // test
+
+	b, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	return resp.StatusCode, b
+}
+
+func TestGateway(t *testing.T) {
+	url := newServer(t)
+
+	tests := []struct {
+		name   string
+		path   string
+		token  string
+		code   int
+		expect map[string]any
+	}{
+		{
+			name:  "Check get item",
+			path:  "/v1/items/5",
+			token: "token",
+			code:  http.StatusOK,
+			expect: map[string]any{
+				"item": map[string]any{
+					"id":       "5",
+					"data":     "ZGF0YQ==",
+					"revision": "2",
+				},
+			},
+		},
+		{
+			name: "Check get item without token",
+			path: "/v1/items/5",
+			code: http.StatusUnauthorized,
+		},
+		{
+			name: "Check unknown path",
+			path: "/v1/unknown",
+			code: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			code, b := get(t, url+test.path, test.token)
+			require.Equal(t, test.code, code)
+
+			if test.expect == nil {
+				return
+			}
+
+			var got map[string]any
+			require.NoError(t, json.Unmarshal(b, &got))
+
+			item, ok := got["item"].(map[string]any)
+			require.True(t, ok)
+			for k, v := range test.expect["item"].(map[string]any) {
+				require.Equal(t, v, item[k])
+			}
+		})
+	}
+}
+
+func TestOpenAPI(t *testing.T) {
+	url := newServer(t)
+
+	code, b := get(t, url+OpenAPIPath, "")
+	require.Equal(t, http.StatusOK, code)
+
+	var spec struct {
+		Swagger string         `json:"swagger"`
+		Paths   map[string]any `json:"paths"`
+	}
+	require.NoError(t, json.Unmarshal(b, &spec))
+	require.Equal(t, "2.0", spec.Swagger)
+	require.Contains(t, spec.Paths, "/v1/items/{id}")
+	require.Contains(t, spec.Paths, "/v1/users:login")
+}
@@ -2,9 +2,19 @@ package client
 
 import (
 	"context"
+	"errors"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/k0st1a/gophkeeper/internal/adapters/api/grpc/gen/proto/v1"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	ErrNoRefreshToken = errors.New("no refresh token")
 )
 
 // AddAuthToken – интерсептор, который добавляет token аутентикации в запрос.
@@ -15,3 +25,105 @@ func AddAuthToken(g AuthTokenGeter) grpc.UnaryClientInterceptor {
 		return invoker(ctx, method, req, reply, cc, opts...)
 	}
 }
+
+// AddAuthTokenStream – интерсептор, который добавляет token аутентикации в потоковый запрос.
+func AddAuthTokenStream(g AuthTokenGeter) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn,
+		method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		ctx = metadata.AppendToOutgoingContext(ctx, "token", g.GetAuthToken())
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+}
+
+// RefreshAuthToken – интерсептор, который при ответе Unauthenticated обновляет token аутентикации
+// и повторяет запрос с новым токеном. Должен стоять в цепочке перед AddAuthToken.
+func RefreshAuthToken(r AuthTokenRefresher) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req interface{},
+		reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		token := r.GetAuthToken()
+
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		if !needRefresh(method, token, err) {
+			return err
+		}
+
+		rerr := r.RefreshAuthToken(ctx, token)
+		if rerr != nil {
+			log.Ctx(ctx).Err(rerr).Msg("error of refresh auth token")
+			return err
+		}
+
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// RefreshAuthTokenStream – интерсептор, который обновляет token аутентикации, если потоковый запрос завершился
+// с Unauthenticated. Поток нельзя повторить прозрачно, поэтому новый токен используется при переподключении.
+func RefreshAuthTokenStream(r AuthTokenRefresher) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn,
+		method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		token := r.GetAuthToken()
+
+		s, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			refresh(ctx, r, method, token, err)
+			return nil, err
+		}
+
+		return &refreshStream{ClientStream: s, refresher: r, method: method, token: token}, nil
+	}
+}
+
+// refreshStream - поток, который обновляет token аутентикации при получении Unauthenticated.
+type refreshStream struct {
+	grpc.ClientStream
+	refresher AuthTokenRefresher
+	method    string
+	token     string
+}
+
+func (s *refreshStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	refresh(s.Context(), s.refresher, s.method, s.token, err)
+	return err //nolint:wrapcheck // error of stream must be passed as is
+}
+
+func (s *refreshStream) SendMsg(m interface{}) error {
+	err := s.ClientStream.SendMsg(m)
+	refresh(s.Context(), s.refresher, s.method, s.token, err)
+	return err //nolint:wrapcheck // error of stream must be passed as is
+}
+
+// refresh - обновить token аутентикации, если запрос завершился с Unauthenticated.
+func refresh(ctx context.Context, r AuthTokenRefresher, method, token string, err error) {
+	if !needRefresh(method, token, err) {
+		return
+	}
+
+	rerr := r.RefreshAuthToken(context.WithoutCancel(ctx), token)
+	if rerr != nil {
+		log.Ctx(ctx).Err(rerr).Msg("error of refresh auth token")
+	}
+}
+
+// needRefresh - нужно ли обновить token аутентикации после ошибки запроса.
+// Методы логина не требуют токена, поэтому их Unauthenticated не связан с истечением токена.
+func needRefresh(method, token string, err error) bool {
+	if token == "" || status.Code(err) != codes.Unauthenticated {
+		return false
+	}
+
+	switch method {
+	case pb.UsersService_Login_FullMethodName,
+		pb.UsersService_StartLogin_FullMethodName,
+		pb.UsersService_FinishLogin_FullMethodName,
+		pb.UsersService_Register_FullMethodName,
+		pb.UsersService_VerifySecondFactor_FullMethodName,
+		pb.UsersService_RefreshToken_FullMethodName,
+		pb.UsersService_StartRecovery_FullMethodName,
+		pb.UsersService_Recover_FullMethodName:
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,108 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/k0st1a/gophkeeper/internal/adapters/api/grpc/gen/proto/v1"
+)
+
+type refresher struct {
+	err       error
+	token     string
+	refreshed int
+}
+
+func (r *refresher) GetAuthToken() string {
+	return r.token
+}
+
+func (r *refresher) RefreshAuthToken(ctx context.Context, failed string) error {
+	r.refreshed++
+	if r.err != nil {
+		return r.err
+	}
+
+	r.token = "new"
+	return nil
+}
+
+func TestRefreshAuthToken(t *testing.T) {
+	unauthenticated := status.Error(codes.Unauthenticated, "expired")
+
+	tests := []struct {
+		refreshErr error
+		err        error
+		name       string
+		method     string
+		token      string
+		tokens     []string
+		refreshed  int
+	}{
+		{
+			name:      "Check retry with new token",
+			method:    pb.ItemsService_ListItems_FullMethodName,
+			token:     "old",
+			tokens:    []string{"old", "new"},
+			refreshed: 1,
+		},
+		{
+			name:       "Check refresh error",
+			method:     pb.ItemsService_ListItems_FullMethodName,
+			token:      "old",
+			refreshErr: errors.New("refresh error"),
+			tokens:     []string{"old"},
+			refreshed:  1,
+			err:        unauthenticated,
+		},
+		{
+			name:   "Check no refresh without token",
+			method: pb.ItemsService_ListItems_FullMethodName,
+			tokens: []string{""},
+			err:    unauthenticated,
+		},
+		{
+			name:   "Check no refresh of login",
+			method: pb.UsersService_Login_FullMethodName,
+			token:  "old",
+			tokens: []string{"old"},
+			err:    unauthenticated,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := &refresher{token: test.token, err: test.refreshErr}
+
+			var tokens []string
+			invoker := func(ctx context.Context, method string, req, reply interface{},
+				cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+				md, _ := metadata.FromOutgoingContext(ctx)
+				tokens = append(tokens, md.Get("token")[0])
+				if md.Get("token")[0] != "new" {
+					return unauthenticated
+				}
+				return nil
+			}
+
+			// Цепочка как у клиента: обновление токена, затем добавление токена в запрос.
+			add := AddAuthToken(r)
+			err := RefreshAuthToken(r)(context.Background(), test.method, nil, nil, nil,
+				func(ctx context.Context, method string, req, reply interface{},
+					cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+					return add(ctx, method, req, reply, cc, invoker, opts...)
+				})
+
+			require.Equal(t, test.err, err)
+			require.Equal(t, test.tokens, tokens)
+			require.Equal(t, test.refreshed, r.refreshed)
+		})
+	}
+}
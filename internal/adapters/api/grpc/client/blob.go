@@ -0,0 +1,298 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/k0st1a/gophkeeper/internal/adapters/api/grpc/gen/proto/v1"
+	"github.com/k0st1a/gophkeeper/internal/pkg/client/model"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// BlobChunkSize - размер блока файла до шифрования.
+	BlobChunkSize = 1024 * 1024 // 1MB
+	// blobAttempts - количество попыток передачи блоба, каждая следующая продолжает с места обрыва.
+	blobAttempts = 5
+	// blobRetryDelay - задержка между попытками передачи блоба.
+	blobRetryDelay = time.Second
+)
+
+var (
+	ErrBlobChecksum  = errors.New("blob chunk checksum mismatch")
+	ErrBlobCorrupted = errors.New("blob corrupted")
+)
+
+type BlobManager interface {
+	UploadBlob(ctx context.Context, r io.ReaderAt, size int64) (*model.Blob, error)
+	DownloadBlob(ctx context.Context, b *model.Blob, w io.WriterAt) error
+}
+
+// UploadBlob – загрузить содержимое размером size на сервер в новый блоб.
+// Содержимое делится на блоки по BlobChunkSize, каждый блок шифруется ключом хранилища отдельно.
+// При обрыве загрузка продолжается с первого незагруженного блока.
+func (c *client) UploadBlob(ctx context.Context, r io.ReaderAt, size int64) (*model.Blob, error) {
+	b := &model.Blob{
+		ID:   uuid.NewString(),
+		Size: size,
+	}
+	log.Ctx(ctx).Printf("UploadBlob, id:%v, size:%v", b.ID, b.Size)
+
+	var err error
+	for attempt := range blobAttempts {
+		if attempt > 0 {
+			err = sleep(ctx, blobRetryDelay)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		var next int64
+		next, err = c.uploadedChunks(ctx, b.ID)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Msgf("error of get uploaded chunks of blob(%v)", b.ID)
+			continue
+		}
+
+		err = c.uploadBlobChunks(ctx, b, r, next)
+		if err == nil {
+			log.Ctx(ctx).Printf("UploadBlob success, id:%v", b.ID)
+			return b, nil
+		}
+
+		log.Ctx(ctx).Error().Err(err).Msgf("error of upload blob(%v), attempt:%v", b.ID, attempt)
+		if !retryable(err) {
+			break
+		}
+	}
+
+	return nil, fmt.Errorf("error of upload blob:%w", err)
+}
+
+// uploadedChunks – получить количество уже загруженных блоков блоба.
+func (c *client) uploadedChunks(ctx context.Context, id string) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	resp, err := c.blobsService.GetBlob(ctx, &pb.GetBlobRequest{Id: id})
+	if status.Code(err) == codes.NotFound {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, fmt.Errorf("blobs service get error:%w", err)
+	}
+
+	return resp.Chunks, nil
+}
+
+// uploadBlobChunks – загрузить блоки блоба, начиная с блока next.
+func (c *client) uploadBlobChunks(ctx context.Context, b *model.Blob, r io.ReaderAt, next int64) error {
+	chunks := blobChunks(b.Size)
+	if next >= chunks {
+		return nil
+	}
+
+	stream, err := c.blobsService.UploadBlob(ctx)
+	if err != nil {
+		return fmt.Errorf("blobs service upload error:%w", err)
+	}
+
+	buf := make([]byte, BlobChunkSize)
+
+	for i := next; i < chunks; i++ {
+		offset := i * BlobChunkSize
+		p := buf[:min(BlobChunkSize, b.Size-offset)]
+
+		_, err := r.ReadAt(p, offset)
+		if err != nil && !(errors.Is(err, io.EOF) && len(p) == 0) {
+			return fmt.Errorf("error of read chunk(%v):%w", i, err)
+		}
+
+		last := i == chunks-1
+		data, err := c.keyring.EncryptWithAD(p, blobChunkAD(b.ID, i, last))
+		if err != nil {
+			return fmt.Errorf("error of encrypt chunk(%v):%w", i, err)
+		}
+
+		sum := sha256.Sum256(data)
+		err = stream.Send(&pb.UploadBlobRequest{
+			Id: b.ID,
+			Chunk: &pb.BlobChunk{
+				Index:  i,
+				Data:   data,
+				Sha256: sum[:],
+				Last:   last,
+			},
+		})
+		// Сервер прервал поток, причина будет в ответе.
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return fmt.Errorf("blobs service send chunk(%v) error:%w", i, err)
+		}
+	}
+
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		return fmt.Errorf("blobs service upload error:%w", err)
+	}
+
+	if !resp.Complete {
+		return fmt.Errorf("blob(%v) is not complete after upload, chunks:%v", b.ID, resp.Chunks)
+	}
+
+	return nil
+}
+
+// DownloadBlob – скачать содержимое блоба и записать его в w.
+// При обрыве скачивание продолжается с первого нескачанного блока.
+func (c *client) DownloadBlob(ctx context.Context, b *model.Blob, w io.WriterAt) error {
+	log.Ctx(ctx).Printf("DownloadBlob, id:%v, size:%v", b.ID, b.Size)
+
+	var next int64
+	var err error
+
+	for attempt := range blobAttempts {
+		if attempt > 0 {
+			err = sleep(ctx, blobRetryDelay)
+			if err != nil {
+				return err
+			}
+		}
+
+		next, err = c.downloadBlobChunks(ctx, b, w, next)
+		if err == nil {
+			log.Ctx(ctx).Printf("DownloadBlob success, id:%v", b.ID)
+			return nil
+		}
+
+		log.Ctx(ctx).Error().Err(err).Msgf("error of download blob(%v), attempt:%v, next chunk:%v", b.ID, attempt, next)
+		if !retryable(err) {
+			break
+		}
+	}
+
+	return fmt.Errorf("error of download blob:%w", err)
+}
+
+// downloadBlobChunks – скачать блоки блоба, начиная с блока next. Возвращает индекс первого нескачанного блока.
+func (c *client) downloadBlobChunks(ctx context.Context, b *model.Blob, w io.WriterAt, next int64) (int64, error) {
+	chunks := blobChunks(b.Size)
+
+	stream, err := c.blobsService.DownloadBlob(ctx, &pb.DownloadBlobRequest{
+		Id:    b.ID,
+		Index: next,
+	})
+	if err != nil {
+		return next, fmt.Errorf("blobs service download error:%w", err)
+	}
+
+	for next < chunks {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return next, fmt.Errorf("%w: blob(%v) ended on chunk(%v) of %v", ErrBlobCorrupted, b.ID, next, chunks)
+		}
+
+		if err != nil {
+			return next, fmt.Errorf("blobs service receive chunk(%v) error:%w", next, err)
+		}
+
+		p, err := c.openBlobChunk(b, resp.Chunk, next, chunks)
+		if err != nil {
+			return next, err
+		}
+
+		_, err = w.WriteAt(p, next*BlobChunkSize)
+		if err != nil {
+			return next, fmt.Errorf("error of write chunk(%v):%w", next, err)
+		}
+
+		next++
+	}
+
+	return next, nil
+}
+
+// openBlobChunk – проверить и расшифровать блок блоба с ожидаемым индексом.
+func (c *client) openBlobChunk(b *model.Blob, chunk *pb.BlobChunk, index, chunks int64) ([]byte, error) {
+	sum := sha256.Sum256(chunk.GetData())
+	if !bytes.Equal(sum[:], chunk.GetSha256()) {
+		return nil, fmt.Errorf("%w, chunk(%v)", ErrBlobChecksum, index)
+	}
+
+	if chunk.Index != index {
+		return nil, fmt.Errorf("%w: got chunk(%v), expected chunk(%v)", ErrBlobCorrupted, chunk.Index, index)
+	}
+
+	// Индекс и признак последнего блока входят в дополнительные данные шифрования,
+	// поэтому сервер не может переставить или обрезать блоки незаметно.
+	last := index == chunks-1
+	p, err := c.keyring.DecryptWithAD(chunk.Data, blobChunkAD(b.ID, index, last))
+	if err != nil {
+		return nil, fmt.Errorf("%w: error of decrypt chunk(%v):%w", ErrBlobCorrupted, index, err)
+	}
+
+	if int64(len(p)) != min(BlobChunkSize, b.Size-index*BlobChunkSize) {
+		return nil, fmt.Errorf("%w: unexpected size of chunk(%v):%v", ErrBlobCorrupted, index, len(p))
+	}
+
+	return p, nil
+}
+
+// blobChunks – количество блоков блоба, пустой блоб состоит из одного пустого блока.
+func blobChunks(size int64) int64 {
+	return max(1, (size+BlobChunkSize-1)/BlobChunkSize)
+}
+
+// blobChunkAD – дополнительные данные шифрования блока: идентификатор блоба, индекс и признак последнего блока.
+func blobChunkAD(id string, index int64, last bool) []byte {
+	ad := make([]byte, 0, len(id)+9)
+	ad = append(ad, id...)
+	ad = binary.BigEndian.AppendUint64(ad, uint64(index))
+	if last {
+		return append(ad, 1)
+	}
+	return append(ad, 0)
+}
+
+// retryable – можно ли продолжить передачу блоба после ошибки.
+func retryable(err error) bool {
+	if errors.Is(err, ErrBlobChecksum) {
+		return true
+	}
+
+	if errors.Is(err, ErrBlobCorrupted) {
+		return false
+	}
+
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Aborted, codes.Internal, codes.DataLoss,
+		codes.FailedPrecondition, codes.Unauthenticated:
+		// при Unauthenticated токен обновляется интерсептором, повтор идет уже с новым токеном
+		return true
+	default:
+		return false
+	}
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("blob transfer canceled:%w", ctx.Err())
+	case <-time.After(d):
+		return nil
+	}
+}
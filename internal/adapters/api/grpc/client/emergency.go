@@ -0,0 +1,324 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/k0st1a/gophkeeper/internal/adapters/api/grpc/gen/proto/v1"
+	"github.com/k0st1a/gophkeeper/internal/pkg/client/crypto"
+	"github.com/k0st1a/gophkeeper/internal/pkg/client/keyring"
+	"github.com/k0st1a/gophkeeper/internal/pkg/client/model"
+	"github.com/rs/zerolog/log"
+)
+
+type EmergencyManager interface {
+	// CreateEmergencyContact - назначить пользователя login доверенным лицом с доступом access, который
+	// одобряется автоматически через waitDays дней после запроса. Повторное назначение заменяет прежнее.
+	CreateEmergencyContact(ctx context.Context, login, access string, waitDays int32) error
+	// ListEmergencyContacts - доверенные лица, назначенные пользователем.
+	ListEmergencyContacts(ctx context.Context) ([]EmergencyContact, error)
+	// ListEmergencyGrantors - пользователи, назначившие пользователя доверенным лицом.
+	ListEmergencyGrantors(ctx context.Context) ([]EmergencyContact, error)
+	// DeleteEmergencyContact - удалить доверенное лицо или отказаться от назначения.
+	DeleteEmergencyContact(ctx context.Context, id int64) error
+	// RequestEmergencyAccess - запросить доступ к хранилищу владельца, возвращает время автоматического одобрения.
+	RequestEmergencyAccess(ctx context.Context, id int64) (time.Time, error)
+	// ApproveEmergencyAccess - одобрить запрос доверенного лица раньше срока.
+	ApproveEmergencyAccess(ctx context.Context, id int64) error
+	// RejectEmergencyAccess - отклонить запрос доверенного лица или отозвать одобренный доступ.
+	RejectEmergencyAccess(ctx context.Context, id int64) error
+	// GetEmergencyVault - личные предметы владельца при одобренном доступе.
+	GetEmergencyVault(ctx context.Context, id int64) ([]Item, error)
+	// TakeoverAccount - установить новый пароль владельцу owner при одобренном доступе takeover.
+	TakeoverAccount(ctx context.Context, id int64, owner, newPassword string) error
+}
+
+// CreateEmergencyContact – назначить доверенное лицо. Ключ хранилища шифруется открытым ключом доверенного
+// лица и хранится на сервере, пока доступ не одобрен. Ключ хранилища не меняется при смене пароля, поэтому
+// назначение остается действительным.
+func (c *client) CreateEmergencyContact(ctx context.Context, login, access string, waitDays int32) error {
+	log.Ctx(ctx).Printf("CreateEmergencyContact, Login:%s, access:%s, wait days:%v", login, access, waitDays)
+
+	key, err := c.keyring.Key()
+	if err != nil {
+		return fmt.Errorf("error of get vault key:%w", err)
+	}
+	defer clear(key)
+
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	pk, err := c.sharesService.GetPublicKey(ctx, &pb.GetPublicKeyRequest{Login: login})
+	if err != nil {
+		return fmt.Errorf("shares service get public key error:%w", parseShareError(err))
+	}
+
+	e, err := crypto.SealTo(pk.PublicKey, key)
+	if err != nil {
+		return fmt.Errorf("error of encrypt vault key:%w", err)
+	}
+
+	resp, err := c.emergencyService.CreateEmergencyContact(ctx, &pb.CreateEmergencyContactRequest{
+		Login:    login,
+		Access:   access,
+		WaitDays: waitDays,
+		VaultKey: e,
+	})
+	if err != nil {
+		return fmt.Errorf("emergency service create contact error:%w", parseEmergencyError(err))
+	}
+
+	log.Ctx(ctx).Printf("CreateEmergencyContact success, id:%v", resp.GetId())
+	return nil
+}
+
+// ListEmergencyContacts – получить доверенных лиц пользователя.
+func (c *client) ListEmergencyContacts(ctx context.Context) ([]EmergencyContact, error) {
+	log.Ctx(ctx).Printf("ListEmergencyContacts")
+
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	resp, err := c.emergencyService.ListEmergencyContacts(ctx, &pb.ListEmergencyContactsRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("emergency service list contacts error:%w", err)
+	}
+
+	return makeEmergencyContacts(resp.GetContacts()), nil
+}
+
+// ListEmergencyGrantors – получить назначения пользователя доверенным лицом.
+func (c *client) ListEmergencyGrantors(ctx context.Context) ([]EmergencyContact, error) {
+	log.Ctx(ctx).Printf("ListEmergencyGrantors")
+
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	resp, err := c.emergencyService.ListEmergencyGrantors(ctx, &pb.ListEmergencyGrantorsRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("emergency service list grantors error:%w", err)
+	}
+
+	return makeEmergencyContacts(resp.GetContacts()), nil
+}
+
+// DeleteEmergencyContact – удалить доверенное лицо или отказаться от назначения доверенным лицом.
+func (c *client) DeleteEmergencyContact(ctx context.Context, id int64) error {
+	log.Ctx(ctx).Printf("DeleteEmergencyContact, id:%v", id)
+
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	_, err := c.emergencyService.DeleteEmergencyContact(ctx, &pb.DeleteEmergencyContactRequest{Id: id})
+	if err != nil {
+		return fmt.Errorf("emergency service delete contact error:%w", parseEmergencyError(err))
+	}
+
+	log.Ctx(ctx).Printf("DeleteEmergencyContact success")
+	return nil
+}
+
+// RequestEmergencyAccess – запросить доступ к хранилищу владельца.
+func (c *client) RequestEmergencyAccess(ctx context.Context, id int64) (time.Time, error) {
+	log.Ctx(ctx).Printf("RequestEmergencyAccess, id:%v", id)
+
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	resp, err := c.emergencyService.RequestEmergencyAccess(ctx, &pb.RequestEmergencyAccessRequest{Id: id})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("emergency service request access error:%w", parseEmergencyError(err))
+	}
+
+	log.Ctx(ctx).Printf("RequestEmergencyAccess success")
+	return resp.GetApproveTime().AsTime(), nil
+}
+
+// ApproveEmergencyAccess – одобрить запрос доверенного лица.
+func (c *client) ApproveEmergencyAccess(ctx context.Context, id int64) error {
+	log.Ctx(ctx).Printf("ApproveEmergencyAccess, id:%v", id)
+
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	_, err := c.emergencyService.ApproveEmergencyAccess(ctx, &pb.ApproveEmergencyAccessRequest{Id: id})
+	if err != nil {
+		return fmt.Errorf("emergency service approve access error:%w", parseEmergencyError(err))
+	}
+
+	log.Ctx(ctx).Printf("ApproveEmergencyAccess success")
+	return nil
+}
+
+// RejectEmergencyAccess – отклонить запрос доверенного лица или отозвать одобренный доступ.
+func (c *client) RejectEmergencyAccess(ctx context.Context, id int64) error {
+	log.Ctx(ctx).Printf("RejectEmergencyAccess, id:%v", id)
+
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	_, err := c.emergencyService.RejectEmergencyAccess(ctx, &pb.RejectEmergencyAccessRequest{Id: id})
+	if err != nil {
+		return fmt.Errorf("emergency service reject access error:%w", parseEmergencyError(err))
+	}
+
+	log.Ctx(ctx).Printf("RejectEmergencyAccess success")
+	return nil
+}
+
+// GetEmergencyVault – получить предметы владельца и расшифровать их ключом хранилища владельца, который
+// расшифровывается закрытым ключом пользователя.
+func (c *client) GetEmergencyVault(ctx context.Context, id int64) ([]Item, error) {
+	log.Ctx(ctx).Printf("GetEmergencyVault, id:%v", id)
+
+	key, resp, err := c.emergencyVault(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(key)
+
+	items := make([]Item, 0, len(resp.GetItems()))
+	for _, i := range resp.GetItems() {
+		b, err := crypto.Decrypt(key, i.GetData())
+		if err != nil {
+			return nil, fmt.Errorf("error of decrypt item(%v):%w", i.GetId(), err)
+		}
+
+		body, err := model.Deserialize(b)
+		if err != nil {
+			return nil, fmt.Errorf("error of deserialize item(%v):%w", i.GetId(), err)
+		}
+
+		items = append(items, Item{
+			ID:         i.GetId(),
+			Body:       *body,
+			CreateTime: i.GetCreateTime().AsTime(),
+			UpdateTime: i.GetUpdateTime().AsTime(),
+			Revision:   i.GetRevision(),
+		})
+	}
+
+	log.Ctx(ctx).Printf("GetEmergencyVault success, items:%v", len(items))
+	return items, nil
+}
+
+// TakeoverAccount – установить новый пароль владельцу: ключ хранилища владельца шифруется новым паролем,
+// поэтому предметы владельца остаются доступны. После этого владелец входит с новым паролем.
+func (c *client) TakeoverAccount(ctx context.Context, id int64, owner, newPassword string) error {
+	log.Ctx(ctx).Printf("TakeoverAccount, id:%v, owner:%s", id, owner)
+
+	key, _, err := c.emergencyVault(ctx, id)
+	if err != nil {
+		return err
+	}
+	defer clear(key)
+
+	v, err := newSRPVerifier(owner, newPassword)
+	if err != nil {
+		return err
+	}
+
+	wk, err := keyring.Wrap(newPassword, key)
+	if err != nil {
+		return fmt.Errorf("error of wrap vault key:%w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	resp, err := c.emergencyService.TakeoverAccount(ctx, &pb.TakeoverAccountRequest{
+		Id:       id,
+		Verifier: v,
+		VaultKey: &pb.VaultKey{
+			Salt: wk.Salt,
+			Key:  wk.Key,
+		},
+	})
+	if err != nil {
+		if status.Code(err) == codes.Aborted {
+			return ErrPasswordChanged
+		}
+
+		return fmt.Errorf("emergency service takeover account error:%w", parseEmergencyError(err))
+	}
+
+	log.Ctx(ctx).Printf("TakeoverAccount success, revoked sessions:%v", resp.GetRevoked())
+	return nil
+}
+
+// emergencyVault – получить хранилище владельца и расшифровать его ключ хранилища.
+func (c *client) emergencyVault(ctx context.Context, id int64) ([]byte, *pb.GetEmergencyVaultResponse, error) {
+	private := c.getPrivateKey()
+	if private == nil {
+		return nil, nil, ErrNotLoggedIn
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	resp, err := c.emergencyService.GetEmergencyVault(ctx, &pb.GetEmergencyVaultRequest{Id: id})
+	if err != nil {
+		return nil, nil, fmt.Errorf("emergency service get vault error:%w", parseEmergencyError(err))
+	}
+
+	key, err := crypto.OpenFrom(private, resp.GetVaultKey())
+	if err != nil {
+		return nil, nil, fmt.Errorf("error of decrypt vault key:%w", err)
+	}
+
+	return key, resp, nil
+}
+
+func makeEmergencyContacts(l []*pb.EmergencyContact) []EmergencyContact {
+	contacts := make([]EmergencyContact, 0, len(l))
+	for _, e := range l {
+		ec := EmergencyContact{
+			CreateTime: e.GetCreateTime().AsTime(),
+			Owner:      e.GetOwner(),
+			Contact:    e.GetContact(),
+			Access:     e.GetAccess(),
+			State:      e.GetState(),
+			ID:         e.GetId(),
+			WaitDays:   e.GetWaitDays(),
+		}
+
+		if e.GetRequestTime() != nil {
+			ec.RequestTime = e.GetRequestTime().AsTime()
+		}
+
+		if e.GetApproveTime() != nil {
+			ec.ApproveTime = e.GetApproveTime().AsTime()
+		}
+
+		contacts = append(contacts, ec)
+	}
+
+	return contacts
+}
+
+// parseEmergencyError – преобразовать статус ошибки экстренного доступа в ошибку клиента.
+func parseEmergencyError(err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	switch {
+	case st.Code() == codes.FailedPrecondition && st.Message() == "user has no public key yet, user must login first":
+		return fmt.Errorf("%w:%w", ErrNoPublicKey, err)
+	case st.Code() == codes.FailedPrecondition:
+		return fmt.Errorf("%w:%w", ErrEmergencyState, err)
+	case st.Code() == codes.PermissionDenied:
+		return fmt.Errorf("%w:%w", ErrEmergencyNotApproved, err)
+	case st.Code() == codes.NotFound && st.Message() == "user not found":
+		return fmt.Errorf("%w:%w", ErrUserNotFound, err)
+	case st.Code() == codes.NotFound:
+		return fmt.Errorf("%w:%w", ErrEmergencyContactNotFound, err)
+	default:
+		return err
+	}
+}
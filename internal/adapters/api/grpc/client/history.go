@@ -0,0 +1,93 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	pb "github.com/k0st1a/gophkeeper/internal/adapters/api/grpc/gen/proto/v1"
+	"github.com/rs/zerolog/log"
+)
+
+type ItemHistory interface {
+	ListItemVersions(ctx context.Context, id int64) ([]ItemVersion, error)
+	RestoreItemVersion(ctx context.Context, id, revision, expected int64) (*Item, error)
+	SetHistoryRetention(ctx context.Context, versions int) error
+}
+
+// ListItemVersions – получить прошлые версии предмета, начиная с последней.
+func (c *client) ListItemVersions(ctx context.Context, id int64) ([]ItemVersion, error) {
+	log.Ctx(ctx).Printf("ListItemVersions, id:%v", id)
+
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	req := &pb.ListItemVersionsRequest{
+		Id: id,
+	}
+	resp, err := c.itemsService.ListItemVersions(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("items service list item versions error:%w", err)
+	}
+
+	versions := make([]ItemVersion, 0, len(resp.Versions))
+	for _, v := range resp.Versions {
+		items, err := c.openItems(ctx, []*pb.Item{v.Item})
+		if err != nil {
+			return nil, fmt.Errorf("error of open item version(%v):%w", v.Item.GetRevision(), err)
+		}
+
+		versions = append(versions, ItemVersion{
+			Item:        items[0],
+			Deleted:     v.Deleted,
+			ArchiveTime: v.ArchiveTime.AsTime(),
+		})
+	}
+
+	log.Ctx(ctx).Printf("ListItemVersions success, versions:%v", len(versions))
+	return versions, nil
+}
+
+// RestoreItemVersion – сделать версию revision текущей версией предмета.
+// Если expected не 0, то предмет на сервере не должен быть изменен после ревизии expected.
+func (c *client) RestoreItemVersion(ctx context.Context, id, revision, expected int64) (*Item, error) {
+	log.Ctx(ctx).Printf("RestoreItemVersion, id:%v, revision:%v, expected:%v", id, revision, expected)
+
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	req := &pb.RestoreItemVersionRequest{
+		Id:               id,
+		Revision:         revision,
+		ExpectedRevision: expected,
+	}
+	resp, err := c.itemsService.RestoreItemVersion(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("items service restore item version error:%w", parseUpdateItemError(err))
+	}
+
+	items, err := c.openItems(ctx, []*pb.Item{resp.Item})
+	if err != nil {
+		return nil, fmt.Errorf("error of open restored item:%w", err)
+	}
+
+	log.Ctx(ctx).Printf("RestoreItemVersion success, revision:%v", items[0].Revision)
+	return &items[0], nil
+}
+
+// SetHistoryRetention – задать количество хранимых прошлых версий каждого предмета.
+func (c *client) SetHistoryRetention(ctx context.Context, versions int) error {
+	log.Ctx(ctx).Printf("SetHistoryRetention, versions:%v", versions)
+
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	req := &pb.SetHistoryRetentionRequest{
+		Versions: int32(versions), //nolint:gosec // checked by server
+	}
+	_, err := c.itemsService.SetHistoryRetention(ctx, req)
+	if err != nil {
+		return fmt.Errorf("items service set history retention error:%w", err)
+	}
+
+	return nil
+}
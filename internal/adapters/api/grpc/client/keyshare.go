@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/k0st1a/gophkeeper/internal/pkg/client/shamir"
+	"github.com/rs/zerolog/log"
+)
+
+// KeyShareManager - разделение ключа хранилища между доверенными лицами. Работает без обращения к серверу.
+type KeyShareManager interface {
+	// SplitVaultKey - разделить ключ хранилища на n долей, любые k из которых восстанавливают ключ.
+	// Возвращает доли в текстовом виде.
+	SplitVaultKey(ctx context.Context, n, k int) ([]string, error)
+	// UnlockWithShares - восстановить ключ хранилища по долям в текстовом виде и разблокировать им хранилище.
+	UnlockWithShares(ctx context.Context, shares []string) error
+}
+
+// SplitVaultKey – разделить ключ хранилища по схеме Шамира. Доли не зависят от пароля, поэтому после смены
+// пароля остаются действительными.
+func (c *client) SplitVaultKey(ctx context.Context, n, k int) ([]string, error) {
+	log.Ctx(ctx).Printf("SplitVaultKey, n:%v, k:%v", n, k)
+
+	key, err := c.keyring.Key()
+	if err != nil {
+		return nil, fmt.Errorf("error of get vault key:%w", err)
+	}
+	defer clear(key)
+
+	shares, err := shamir.Split(key, n, k)
+	if err != nil {
+		return nil, fmt.Errorf("error of split vault key:%w", err)
+	}
+
+	texts := make([]string, 0, len(shares))
+	for _, s := range shares {
+		texts = append(texts, s.String())
+	}
+
+	return texts, nil
+}
+
+// UnlockWithShares – восстановить ключ хранилища по долям. Логина на сервере нет, поэтому доступны только
+// предметы локального хранилища.
+func (c *client) UnlockWithShares(ctx context.Context, texts []string) error {
+	log.Ctx(ctx).Printf("UnlockWithShares, shares:%v", len(texts))
+
+	shares := make([]shamir.Share, 0, len(texts))
+	for i, t := range texts {
+		s, err := shamir.Parse(t)
+		if err != nil {
+			return fmt.Errorf("share %v:%w", i+1, err)
+		}
+
+		shares = append(shares, s)
+	}
+
+	key, err := shamir.Combine(shares)
+	if err != nil {
+		return fmt.Errorf("error of combine vault key:%w", err)
+	}
+	defer clear(key)
+
+	c.keyring.Set(key)
+
+	log.Ctx(ctx).Printf("UnlockWithShares success")
+	return nil
+}
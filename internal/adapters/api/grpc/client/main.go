@@ -2,15 +2,26 @@ package client
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	pb "github.com/k0st1a/gophkeeper/internal/adapters/api/grpc/gen/proto/v1"
+	"github.com/k0st1a/gophkeeper/internal/pkg/client/crypto"
+	"github.com/k0st1a/gophkeeper/internal/pkg/client/keyring"
 	"github.com/k0st1a/gophkeeper/internal/pkg/client/model"
+	"github.com/k0st1a/gophkeeper/internal/pkg/client/recovery"
+	"github.com/k0st1a/gophkeeper/internal/pkg/srp"
 	"github.com/rs/zerolog/log"
 )
 
@@ -18,45 +29,115 @@ type Client interface {
 	UserAuthentication
 	AuthTokenGeter
 	ItemManager
+	ItemWatcher
+	ItemTrash
+	ItemHistory
+	BlobManager
+	SessionManager
+	SecondFactorManager
+	PasswordManager
+	RecoveryManager
+	KeyShareManager
+	EmergencyManager
+	ShareManager
+	OrgManager
 }
 
 type AuthTokenGeter interface {
 	GetAuthToken() string
 }
 
+// AuthTokenRefresher - обновление токена аутентификации по refresh токену.
+type AuthTokenRefresher interface {
+	AuthTokenGeter
+	// RefreshAuthToken - обновить токен, если текущий токен все еще равен failed, т.е. его не обновили параллельно.
+	RefreshAuthToken(ctx context.Context, failed string) error
+}
+
 type UserAuthentication interface {
+	// LoginUser - логин пользователя. Если у пользователя подключен второй фактор,
+	// то возвращается ErrSecondFactorRequired и логин завершается через VerifySecondFactor.
 	LoginUser(ctx context.Context, login, password string) error
-	RegisterUser(ctx context.Context, login, password string) error
+	// VerifySecondFactor - завершить логин кодом TOTP или резервным кодом.
+	VerifySecondFactor(ctx context.Context, code string) error
+	// RegisterUser - регистрация пользователя. Возвращает аварийный набор с ключом восстановления, который
+	// нужно показать пользователю: больше его получить нельзя.
+	RegisterUser(ctx context.Context, login, password string) (*recovery.Kit, error)
 	Logout(ctx context.Context)
 }
 
 type ItemManager interface {
 	GetItem(ctx context.Context, id int64) (*Item, error)
 	ListItems(ctx context.Context) ([]Item, error)
-	CreateItem(ctx context.Context, item *Item) (int64, error)
-	UpdateItem(ctx context.Context, item *Item) error
+	CreateItem(ctx context.Context, item *Item) (*Item, error)
+	UpdateItem(ctx context.Context, item *Item) (*Item, error)
 	DeleteItem(ctx context.Context, id int64) error
+	ListChanges(ctx context.Context, cursor int64) (*Changes, error)
+}
+
+type ItemWatcher interface {
+	WatchItems(ctx context.Context, notify func()) error
 }
 
 type client struct {
-	usersService   pb.UsersServiceClient
-	itemsService   pb.ItemsServiceClient
-	authToken      string
-	requestTimeout time.Duration
+	usersService     pb.UsersServiceClient
+	itemsService     pb.ItemsServiceClient
+	blobsService     pb.BlobsServiceClient
+	sharesService    pb.SharesServiceClient
+	orgsService      pb.OrgsServiceClient
+	emergencyService pb.EmergencyAccessServiceClient
+	keyring          *keyring.Keyring
+	tokenMutex       *sync.RWMutex
+	refreshMutex     *sync.Mutex
+	authToken        string
+	refreshToken     string
+	secretKey        string
+	address          string
+	// Разрешен ли логин по паролю пользователей, зарегистрированных до появления SRP
+	allowLegacyLogin bool
+	requestTimeout   time.Duration
+	// Логин, ожидающий подтверждения вторым фактором, защищен tokenMutex
+	challenge *challenge
+	// Логин пользователя, защищен tokenMutex
+	login string
+	// На сервере нет верификатора SRP пользователя, защищен tokenMutex
+	legacy bool
+	// Закрытый ключ X25519 для предметов, которыми поделились с пользователем, защищен tokenMutex
+	privateKey []byte
+	// Расшифрованные ключи коллекций организаций по идентификатору коллекции, защищены tokenMutex
+	collectionKeys map[int64][]byte
 }
 
-// New – создание клиента.
-func New(a string, rt time.Duration) (*client, error) {
-	log.Printf("New grpc client, server address:%v, request timeout:%v seconds", a, rt.Seconds())
+// New – создание клиента, где:
+//   - a - адрес сервера;
+//   - tc - TLS конфигурация соединения с сервером, если nil, то соединение не шифруется;
+//   - rt - таймаут обращения к серверу;
+//   - k - связка ключей, в которую кладется ключ хранилища после логина;
+//   - sk - мастер-пароль, если не задан, то мастер-паролем выступает пароль пользователя;
+//   - ll - разрешить логин по паролю, если логин по SRP не удался: пароль при этом передается на сервер.
+func New(a string, tc *tls.Config, rt time.Duration, k *keyring.Keyring, sk string, ll bool) (*client, error) {
+	log.Printf("New grpc client, server address:%v, tls:%v, request timeout:%v seconds", a, tc != nil, rt.Seconds())
+
+	creds := insecure.NewCredentials()
+	if tc != nil {
+		creds = credentials.NewTLS(tc)
+	}
 
 	c := &client{
-		requestTimeout: rt,
+		tokenMutex:       &sync.RWMutex{},
+		refreshMutex:     &sync.Mutex{},
+		requestTimeout:   rt,
+		keyring:          k,
+		secretKey:        sk,
+		address:          a,
+		allowLegacyLogin: ll,
 	}
 
 	cc, err := grpc.NewClient(
 		a,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithUnaryInterceptor(AddAuthToken(c)),
+		grpc.WithTransportCredentials(creds),
+		grpc.WithChainUnaryInterceptor(RefreshAuthToken(c), AddAuthToken(c)),
+		grpc.WithChainStreamInterceptor(RefreshAuthTokenStream(c), AddAuthTokenStream(c)),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("create client error:%w", err)
@@ -64,71 +145,394 @@ func New(a string, rt time.Duration) (*client, error) {
 
 	c.usersService = pb.NewUsersServiceClient(cc)
 	c.itemsService = pb.NewItemsServiceClient(cc)
+	c.blobsService = pb.NewBlobsServiceClient(cc)
+	c.sharesService = pb.NewSharesServiceClient(cc)
+	c.orgsService = pb.NewOrgsServiceClient(cc)
+	c.emergencyService = pb.NewEmergencyAccessServiceClient(cc)
 
 	return c, nil
 }
 
-// Login – логин пользователя на сервере, получение токена.
+// Login – логин пользователя на сервере по SRP, получение токена. Пароль на сервер не передается.
 func (c *client) LoginUser(ctx context.Context, login, password string) error {
 	log.Ctx(ctx).Printf("LoginUser, Login:%s", login)
 
 	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
 	defer cancel()
 
-	req := &pb.LoginRequest{
+	c.setLogin(login)
+
+	sc, err := srp.NewClient(login)
+	if err != nil {
+		return fmt.Errorf("error of create srp client:%w", err)
+	}
+
+	start, err := c.usersService.StartLogin(ctx, &pb.StartLoginRequest{
+		Login: login,
+		A:     sc.A,
+	})
+	if err != nil {
+		return fmt.Errorf("users service start login error:%w", parseLoginError(err))
+	}
+
+	proof, err := sc.Proof(password, start.GetSalt(), start.GetB())
+	if err != nil {
+		return fmt.Errorf("error of calculate srp proof:%w", err)
+	}
+
+	resp, err := c.usersService.FinishLogin(ctx, &pb.FinishLoginRequest{
+		Session: start.GetSession(),
+		Proof:   proof,
+		Device:  deviceName(),
+	})
+	if err != nil {
+		// Сервер не сообщает, есть ли у пользователя верификатор SRP, поэтому пользователь, зарегистрированный
+		// до появления SRP, может войти только по паролю, и только если это явно разрешено.
+		if c.allowLegacyLogin && status.Code(err) == codes.InvalidArgument {
+			return c.legacyLogin(ctx, login, password)
+		}
+
+		return fmt.Errorf("users service finish login error:%w", parseLoginError(err))
+	}
+
+	err = sc.VerifyServer(resp.GetServerProof())
+	if err != nil {
+		return fmt.Errorf("error of verify server:%w", err)
+	}
+
+	return c.loginVerified(ctx, password, nil, resp)
+}
+
+// legacyLogin – логин по паролю пользователя, для которого на сервере еще нет верификатора SRP.
+// Пароль передается на сервер. После логина на сервер передается верификатор, и следующий логин выполняется уже по SRP.
+func (c *client) legacyLogin(ctx context.Context, login, password string) error {
+	log.Ctx(ctx).Printf("LoginUser => no srp verifier on server, login by password")
+
+	v, err := newSRPVerifier(login, password)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.usersService.Login(ctx, &pb.LoginRequest{
 		Login:    login,
 		Password: password,
+		Device:   deviceName(),
+	})
+	if err != nil {
+		return fmt.Errorf("users service login error:%w", parseLoginError(err))
+	}
+
+	return c.loginVerified(ctx, password, v, resp)
+}
+
+// loginVerified – продолжить логин после проверки пароля сервером, где v - верификатор SRP,
+// который нужно передать на сервер после логина, если nil, то не нужно.
+func (c *client) loginVerified(ctx context.Context, password string, v *pb.SRPVerifier, resp *pb.LoginResponse) error {
+	if resp.GetChallenge() != "" {
+		log.Ctx(ctx).Printf("LoginUser => second factor required")
+		c.setChallenge(&challenge{
+			challenge: resp.GetChallenge(),
+			password:  c.masterPassword(password),
+			verifier:  v,
+		})
+		return ErrSecondFactorRequired
 	}
 
-	resp, err := c.usersService.Login(ctx, req)
+	err := c.completeLogin(ctx, c.masterPassword(password), resp)
 	if err != nil {
-		return fmt.Errorf("users service login error:%w", err)
+		return err
 	}
 
+	c.migrateSRPVerifier(ctx, v)
+
 	log.Ctx(ctx).Printf("LoginUser success")
-	c.setAuthToken(resp.Token)
+	return nil
+}
+
+// completeLogin – запомнить токены логина и расшифровать ключ хранилища мастер-паролем.
+func (c *client) completeLogin(ctx context.Context, password string, resp *pb.LoginResponse) error {
+	c.setAuthTokens(resp.Token, resp.RefreshToken)
+
+	err := c.unlockVault(ctx, password, resp.VaultKey)
+	if err != nil {
+		c.setAuthTokens("", "")
+		return fmt.Errorf("error of unlock vault:%w", err)
+	}
+
+	err = c.unlockKeyPair(ctx, resp.KeyPair)
+	if err != nil {
+		c.setAuthTokens("", "")
+		c.keyring.Lock()
+		return fmt.Errorf("error of unlock key pair:%w", err)
+	}
+
+	// Таймаут логина на проход не распространяется: у каждого запроса прохода свой таймаут.
+	err = c.encryptLegacyItems(context.WithoutCancel(ctx))
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("error of encrypt legacy items, retry on next login")
+	}
 
 	return nil
 }
 
+// parseLoginError – преобразовать статус ошибки логина в ошибку клиента.
+func parseLoginError(err error) error {
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != codes.ResourceExhausted {
+		return err
+	}
+
+	for _, d := range st.Details() {
+		if ri, ok := d.(*errdetails.RetryInfo); ok {
+			return &TooManyAttemptsError{RetryDelay: ri.GetRetryDelay().AsDuration()}
+		}
+	}
+
+	return fmt.Errorf("%w:%w", ErrTooManyAttempts, err)
+}
+
+// unlockVault – расшифровать ключ хранилища, полученный при логине.
+// Если пользователь зарегистрирован до появления шифрования, то ключ хранилища генерируется и сохраняется на сервере.
+func (c *client) unlockVault(ctx context.Context, password string, vk *pb.VaultKey) error {
+	if len(vk.GetKey()) != 0 {
+		err := c.keyring.Unlock(password, &keyring.WrappedKey{
+			Salt: vk.Salt,
+			Key:  vk.Key,
+		})
+		if err != nil {
+			return fmt.Errorf("error of unlock keyring:%w", err)
+		}
+
+		return nil
+	}
+
+	log.Ctx(ctx).Printf("No vault key => generate and set new one")
+
+	key, wk, err := keyring.NewVaultKey(password)
+	if err != nil {
+		return fmt.Errorf("error of generate vault key:%w", err)
+	}
+
+	req := &pb.SetVaultKeyRequest{
+		VaultKey: &pb.VaultKey{
+			Salt: wk.Salt,
+			Key:  wk.Key,
+		},
+	}
+
+	_, err = c.usersService.SetVaultKey(ctx, req)
+	if err != nil {
+		return fmt.Errorf("users service set vault key error:%w", err)
+	}
+
+	c.keyring.Set(key)
+
+	return nil
+}
+
+// encryptLegacyItems – зашифровать предметы, сохраненные до появления шифрования. Незашифрованные предметы
+// отклоняются, т.к. их мог подложить сервер, поэтому проход выполняется после каждой разблокировки хранилища:
+// если прошлый проход прервался, то оставшиеся предметы шифруются при следующем логине. Уже зашифрованные предметы
+// пропускаются, а ошибка одного предмета не прерывает проход.
+func (c *client) encryptLegacyItems(ctx context.Context) error {
+	lctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	resp, err := c.itemsService.ListItems(lctx, &pb.ListItemsRequest{})
+	cancel()
+	if err != nil {
+		return fmt.Errorf("items service list error:%w", err)
+	}
+
+	var errs []error
+	for _, i := range resp.GetItems() {
+		// Предметы коллекций появились после шифрования.
+		if crypto.IsEncrypted(i.GetData()) || i.GetCollectionId() != 0 {
+			continue
+		}
+
+		err = c.encryptLegacyItem(ctx, i)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// encryptLegacyItem – зашифровать предмет, сохраненный до появления шифрования.
+func (c *client) encryptLegacyItem(ctx context.Context, i *pb.Item) error {
+	log.Ctx(ctx).Printf("Encrypt legacy item(%v)", i.GetId())
+
+	body, err := model.Deserialize(i.GetData())
+	if err != nil {
+		return fmt.Errorf("error of deserialize legacy item(%v):%w", i.GetId(), err)
+	}
+
+	b, err := c.seal(ctx, body, 0)
+	if err != nil {
+		return fmt.Errorf("error of seal legacy item(%v):%w", i.GetId(), err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	_, err = c.itemsService.UpdateItem(ctx, &pb.UpdateItemRequest{
+		Item: &pb.Item{
+			Id:         i.GetId(),
+			Data:       b,
+			CreateTime: i.GetCreateTime(),
+			UpdateTime: i.GetUpdateTime(),
+			BlobIds:    body.BlobIDs(),
+		},
+		ExpectedRevision: i.GetRevision(),
+	})
+	if err != nil {
+		return fmt.Errorf("items service update legacy item(%v) error:%w", i.GetId(), parseUpdateItemError(err))
+	}
+
+	return nil
+}
+
+// masterPassword – получить мастер-пароль, из которого выводится ключ шифрования ключа хранилища.
+func (c *client) masterPassword(password string) string {
+	if c.secretKey != "" {
+		return c.secretKey
+	}
+
+	return password
+}
+
 // Logout – логаут пользователя.
 func (c *client) Logout(ctx context.Context) {
-	log.Ctx(ctx).Printf("Logout => erase auth token")
-	c.setAuthToken("")
+	log.Ctx(ctx).Printf("Logout => revoke session, erase auth tokens and vault key")
+
+	if c.GetAuthToken() != "" {
+		rctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+		_, err := c.usersService.Logout(rctx, &pb.LogoutRequest{})
+		cancel()
+		if err != nil {
+			// Локальные данные стираются в любом случае, сессия истечет сама вместе с refresh токеном.
+			log.Ctx(ctx).Error().Err(err).Msg("error of revoke session on logout")
+		}
+	}
+
+	c.setAuthTokens("", "")
+	c.setChallenge(nil)
+	c.setLogin("")
+	c.setPrivateKey(nil)
+	c.setCollectionKeys(nil)
+	c.keyring.Lock()
 }
 
 // Register – регистрация пользователя на сервере.
-func (c *client) RegisterUser(ctx context.Context, login, password string) error {
+func (c *client) RegisterUser(ctx context.Context, login, password string) (*recovery.Kit, error) {
 	log.Ctx(ctx).Printf("RegisterUser, Login:%s", login)
 
 	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
 	defer cancel()
 
+	key, wk, err := keyring.NewVaultKey(c.masterPassword(password))
+	if err != nil {
+		return nil, fmt.Errorf("error of generate vault key:%w", err)
+	}
+
+	v, err := newSRPVerifier(login, password)
+	if err != nil {
+		return nil, err
+	}
+
+	rk, kit, err := c.newRecoveryKey(login, key)
+	if err != nil {
+		return nil, err
+	}
+
 	req := &pb.RegisterRequest{
 		Login:    login,
-		Password: password,
+		Verifier: v,
+		VaultKey: &pb.VaultKey{
+			Salt: wk.Salt,
+			Key:  wk.Key,
+		},
+		RecoveryKey: rk,
 	}
-	_, err := c.usersService.Register(ctx, req)
+	_, err = c.usersService.Register(ctx, req)
 	if err != nil {
-		return fmt.Errorf("users service register error:%w", err)
+		return nil, fmt.Errorf("users service register error:%w", err)
 	}
 
 	log.Ctx(ctx).Printf("RegisterUser success")
-	return nil
+	return kit, nil
 }
 
-// setAuthToken – метод выставления AuthToken пользователя.
-func (c *client) setAuthToken(v string) {
-	log.Printf("setAuthToken")
-	c.authToken = v
+// setAuthTokens – метод выставления AuthToken и refresh токена пользователя.
+func (c *client) setAuthTokens(token, refresh string) {
+	log.Printf("setAuthTokens")
+
+	c.tokenMutex.Lock()
+	defer c.tokenMutex.Unlock()
+
+	c.authToken = token
+	c.refreshToken = refresh
 }
 
 // GetAuthToken – метод получения AuthToken пользователя.
 func (c *client) GetAuthToken() string {
 	log.Printf("GetAuthToken")
+
+	c.tokenMutex.RLock()
+	defer c.tokenMutex.RUnlock()
+
 	return c.authToken
 }
 
+// RefreshAuthToken – получить новый AuthToken по refresh токену, вместе с ним сервер выдает и новый refresh токен.
+// Параллельные запросы, получившие Unauthenticated с одним и тем же токеном, обновляют его только один раз.
+func (c *client) RefreshAuthToken(ctx context.Context, failed string) error {
+	log.Ctx(ctx).Printf("RefreshAuthToken")
+
+	c.refreshMutex.Lock()
+	defer c.refreshMutex.Unlock()
+
+	c.tokenMutex.RLock()
+	token, refresh := c.authToken, c.refreshToken
+	c.tokenMutex.RUnlock()
+
+	if token != failed {
+		log.Ctx(ctx).Printf("Auth token already refreshed")
+		return nil
+	}
+
+	if refresh == "" {
+		return ErrNoRefreshToken
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	resp, err := c.usersService.RefreshToken(ctx, &pb.RefreshTokenRequest{RefreshToken: refresh})
+	if err != nil {
+		if status.Code(err) == codes.Unauthenticated {
+			// Refresh токен отозван или истек, нужен повторный логин.
+			c.setAuthTokens(token, "")
+		}
+
+		return fmt.Errorf("users service refresh token error:%w", err)
+	}
+
+	c.tokenMutex.Lock()
+	defer c.tokenMutex.Unlock()
+
+	// Пока шел запрос, пользователь мог выйти или залогиниться заново.
+	if c.authToken != token {
+		return nil
+	}
+
+	c.authToken = resp.Token
+	c.refreshToken = resp.RefreshToken
+
+	log.Ctx(ctx).Printf("RefreshAuthToken success")
+	return nil
+}
+
 // GetItem – получить предмет пользователя.
 func (c *client) GetItem(ctx context.Context, id int64) (*Item, error) {
 	log.Ctx(ctx).Printf("GetItem, id:%v", id)
@@ -144,18 +548,13 @@ func (c *client) GetItem(ctx context.Context, id int64) (*Item, error) {
 		return nil, fmt.Errorf("items service get error:%w", err)
 	}
 
-	body, err := model.Deserialize(resp.Item.Data)
+	items, err := c.openItems(ctx, []*pb.Item{resp.Item})
 	if err != nil {
-		return nil, fmt.Errorf("error of deserialize item while get item:%w", err)
+		return nil, fmt.Errorf("error of open item while get item:%w", err)
 	}
 
 	log.Ctx(ctx).Printf("GetItem success")
-	return &Item{
-		ID:         resp.Item.Id,
-		Body:       *body,
-		CreateTime: resp.Item.CreateTime.AsTime(),
-		UpdateTime: resp.Item.UpdateTime.AsTime(),
-	}, nil
+	return &items[0], nil
 }
 
 // ListItems – получить все предметы.
@@ -171,19 +570,81 @@ func (c *client) ListItems(ctx context.Context) ([]Item, error) {
 		return nil, fmt.Errorf("items service list error:%w", err)
 	}
 
-	items := make([]Item, 0, len(resp.Items))
-	for _, i := range resp.Items {
-		body, err := model.Deserialize(i.Data)
+	items, err := c.openItems(ctx, resp.Items)
+	if err != nil {
+		return nil, fmt.Errorf("error of open items while list items:%w", err)
+	}
+
+	return items, nil
+}
+
+// ListChanges – получить изменения предметов после курсора, 0 - получить все предметы.
+func (c *client) ListChanges(ctx context.Context, cursor int64) (*Changes, error) {
+	log.Ctx(ctx).Printf("ListChanges, cursor:%v", cursor)
+
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	req := &pb.ListChangesRequest{
+		SinceCursor: cursor,
+	}
+	resp, err := c.itemsService.ListChanges(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("items service list changes error:%w", err)
+	}
+
+	items, err := c.openItems(ctx, resp.Items)
+	if err != nil {
+		return nil, fmt.Errorf("error of open items while list changes:%w", err)
+	}
+
+	log.Ctx(ctx).Printf("ListChanges success, cursor:%v", resp.Cursor)
+	return &Changes{
+		Items:      items,
+		DeletedIDs: resp.DeletedIds,
+		Cursor:     resp.Cursor,
+		Full:       resp.Full,
+	}, nil
+}
+
+// WatchItems – получать уведомления об изменениях предметов, notify вызывается на каждое уведомление.
+// Блокируется, пока поток уведомлений не прервется или не будет отменен ctx.
+func (c *client) WatchItems(ctx context.Context, notify func()) error {
+	log.Ctx(ctx).Printf("WatchItems")
+
+	stream, err := c.itemsService.WatchItems(ctx, &pb.WatchItemsRequest{})
+	if err != nil {
+		return fmt.Errorf("items service watch error:%w", err)
+	}
+
+	for {
+		_, err := stream.Recv()
 		if err != nil {
-			log.Ctx(ctx).Error().Err(err).Msgf("error of deserialize item(%v) while list items", i.Id)
-			continue
+			return fmt.Errorf("items service watch receive error:%w", err)
+		}
+
+		log.Ctx(ctx).Printf("Got items notification")
+		notify()
+	}
+}
+
+// openItems – расшифровать предметы, полученные от сервера.
+func (c *client) openItems(ctx context.Context, l []*pb.Item) ([]Item, error) {
+	items := make([]Item, 0, len(l))
+	for _, i := range l {
+		// Пропускать предмет нельзя: синхронизация считает отсутствующие в списке предметы удаленными.
+		body, err := c.open(ctx, i.Data, i.CollectionId)
+		if err != nil {
+			return nil, fmt.Errorf("error of open item(%v):%w", i.Id, err)
 		}
 
 		item := Item{
-			ID:         i.Id,
-			Body:       *body,
-			CreateTime: i.CreateTime.AsTime(),
-			UpdateTime: i.UpdateTime.AsTime(),
+			ID:           i.Id,
+			Body:         *body,
+			CreateTime:   i.CreateTime.AsTime(),
+			UpdateTime:   i.UpdateTime.AsTime(),
+			Revision:     i.Revision,
+			CollectionID: i.CollectionId,
 		}
 		items = append(items, item)
 	}
@@ -192,43 +653,55 @@ func (c *client) ListItems(ctx context.Context) ([]Item, error) {
 }
 
 // CreateItem – создать предмет.
-func (c *client) CreateItem(ctx context.Context, item *Item) (int64, error) {
+// Возвращает предмет с идентификатором, ревизией и временем обновления, назначенными сервером.
+func (c *client) CreateItem(ctx context.Context, item *Item) (*Item, error) {
 	log.Ctx(ctx).Printf("CreateItem, local id:%v", item.ID)
 
 	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
 	defer cancel()
 
-	b, err := model.Serialize(&item.Body)
+	b, err := c.seal(ctx, &item.Body, item.CollectionID)
 	if err != nil {
-		return 0, fmt.Errorf("error of serialize item(%v) while create item:%w", item.ID, err)
+		return nil, fmt.Errorf("error of seal item(%v) while create item:%w", item.ID, err)
 	}
 
 	req := &pb.CreateItemRequest{
 		Item: &pb.Item{
-			Data:       b,
-			CreateTime: timestamppb.New(item.CreateTime),
-			UpdateTime: timestamppb.New(item.UpdateTime),
+			Data:         b,
+			CreateTime:   timestamppb.New(item.CreateTime),
+			UpdateTime:   timestamppb.New(item.UpdateTime),
+			BlobIds:      item.Body.BlobIDs(),
+			CollectionId: item.CollectionID,
 		},
 	}
 	resp, err := c.itemsService.CreateItem(ctx, req)
 	if err != nil {
-		return 0, fmt.Errorf("items client create error:%w", err)
+		return nil, fmt.Errorf("items client create error:%w", parseOrgError(err))
 	}
 
 	log.Ctx(ctx).Printf("CreateItem success, remote id:%v", resp.Id)
-	return resp.Id, nil
+	return &Item{
+		ID:           resp.Id,
+		Body:         item.Body,
+		CreateTime:   item.CreateTime,
+		UpdateTime:   resp.UpdateTime.AsTime(),
+		Revision:     resp.Revision,
+		CollectionID: item.CollectionID,
+	}, nil
 }
 
-// UpdateItem – обновить предмет.
-func (c *client) UpdateItem(ctx context.Context, item *Item) error {
-	log.Ctx(ctx).Printf("UpdateItem, id:%v", item.ID)
+// UpdateItem – обновить предмет, где item.Revision - ревизия, на которой основано обновление.
+// Если предмет на сервере изменен после этой ревизии, то возвращается RevisionMismatchError.
+// Возвращает предмет с новой ревизией и временем обновления, назначенными сервером.
+func (c *client) UpdateItem(ctx context.Context, item *Item) (*Item, error) {
+	log.Ctx(ctx).Printf("UpdateItem, id:%v, revision:%v", item.ID, item.Revision)
 
 	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
 	defer cancel()
 
-	b, err := model.Serialize(&item.Body)
+	b, err := c.seal(ctx, &item.Body, item.CollectionID)
 	if err != nil {
-		return fmt.Errorf("error of serialize item(%v) while update item:%w", item.ID, err)
+		return nil, fmt.Errorf("error of seal item(%v) while update item:%w", item.ID, err)
 	}
 
 	req := &pb.UpdateItemRequest{
@@ -237,14 +710,48 @@ func (c *client) UpdateItem(ctx context.Context, item *Item) error {
 			Data:       b,
 			CreateTime: timestamppb.New(item.CreateTime),
 			UpdateTime: timestamppb.New(item.UpdateTime),
+			BlobIds:    item.Body.BlobIDs(),
 		},
+		ExpectedRevision: item.Revision,
 	}
-	_, err = c.itemsService.UpdateItem(ctx, req)
+	resp, err := c.itemsService.UpdateItem(ctx, req)
 	if err != nil {
-		return fmt.Errorf("items client update item data error:%w", err)
+		return nil, fmt.Errorf("items client update item data error:%w", parseUpdateItemError(err))
 	}
 
-	return nil
+	log.Ctx(ctx).Printf("UpdateItem success, revision:%v", resp.Revision)
+	return &Item{
+		ID:           item.ID,
+		Body:         item.Body,
+		CreateTime:   item.CreateTime,
+		UpdateTime:   resp.UpdateTime.AsTime(),
+		Revision:     resp.Revision,
+		CollectionID: item.CollectionID,
+	}, nil
+}
+
+// parseUpdateItemError – преобразовать статус ошибки обновления предмета в ошибку клиента.
+func parseUpdateItemError(err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	switch st.Code() {
+	case codes.NotFound:
+		return fmt.Errorf("%w:%w", ErrItemNotFound, err)
+	case codes.PermissionDenied:
+		return fmt.Errorf("%w:%w", ErrPermissionDenied, err)
+	case codes.Aborted:
+		for _, d := range st.Details() {
+			if rc, ok := d.(*pb.RevisionConflict); ok {
+				return &RevisionMismatchError{Revision: rc.Revision}
+			}
+		}
+		return fmt.Errorf("%w:%w", ErrRevisionMismatch, err)
+	default:
+		return err
+	}
 }
 
 // DeleteItem – удалить предмет.
@@ -265,3 +772,39 @@ func (c *client) DeleteItem(ctx context.Context, id int64) error {
 
 	return nil
 }
+
+// seal – сериализовать и зашифровать предмет ключом хранилища или, для предмета коллекции, ключом коллекции.
+func (c *client) seal(ctx context.Context, i *model.Item, collectionID int64) ([]byte, error) {
+	b, err := model.Serialize(i)
+	if err != nil {
+		return nil, fmt.Errorf("error of serialize item:%w", err)
+	}
+
+	e, err := c.encrypt(ctx, b, collectionID)
+	if err != nil {
+		return nil, fmt.Errorf("error of encrypt item:%w", err)
+	}
+
+	return e, nil
+}
+
+// open – расшифровать ключом хранилища или ключом коллекции и десериализовать предмет.
+// Незашифрованные предметы отклоняются: предметы, сохраненные до появления шифрования, зашифрованы
+// после разблокировки хранилища, см. encryptLegacyItems.
+func (c *client) open(ctx context.Context, b []byte, collectionID int64) (*model.Item, error) {
+	if !crypto.IsEncrypted(b) {
+		return nil, ErrNotEncrypted
+	}
+
+	b, err := c.decrypt(ctx, b, collectionID)
+	if err != nil {
+		return nil, fmt.Errorf("error of decrypt item:%w", err)
+	}
+
+	i, err := model.Deserialize(b)
+	if err != nil {
+		return nil, fmt.Errorf("error of deserialize item:%w", err)
+	}
+
+	return i, nil
+}
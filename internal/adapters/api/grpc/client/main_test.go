@@ -0,0 +1,170 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/k0st1a/gophkeeper/internal/adapters/api/grpc/gen/proto/v1"
+	"github.com/k0st1a/gophkeeper/internal/pkg/client/crypto"
+	"github.com/k0st1a/gophkeeper/internal/pkg/client/keyring"
+	"github.com/k0st1a/gophkeeper/internal/pkg/client/model"
+)
+
+// itemsService - сервер предметов: обновленные предметы сохраняются, обновление предмета fail отклоняется.
+type itemsService struct {
+	pb.ItemsServiceClient
+	items   []*pb.Item
+	updates []*pb.UpdateItemRequest
+	fail    int64
+}
+
+func (s *itemsService) ListItems(ctx context.Context, in *pb.ListItemsRequest,
+	opts ...grpc.CallOption) (*pb.ListItemsResponse, error) {
+	return &pb.ListItemsResponse{Items: s.items}, nil
+}
+
+func (s *itemsService) UpdateItem(ctx context.Context, in *pb.UpdateItemRequest,
+	opts ...grpc.CallOption) (*pb.UpdateItemResponse, error) {
+	s.updates = append(s.updates, in)
+	if in.GetItem().GetId() == s.fail {
+		return nil, status.Error(codes.Unavailable, "connection lost")
+	}
+
+	for n, i := range s.items {
+		if i.GetId() == in.GetItem().GetId() {
+			s.items[n] = &pb.Item{Id: i.GetId(), Data: in.GetItem().GetData(), Revision: i.GetRevision() + 1}
+		}
+	}
+
+	return &pb.UpdateItemResponse{Revision: in.GetExpectedRevision() + 1}, nil
+}
+
+func newTestClient(t *testing.T) *client {
+	t.Helper()
+
+	key, _, err := keyring.NewVaultKey("master password")
+	require.NoError(t, err)
+
+	k := keyring.New()
+	k.Set(key)
+
+	return &client{
+		keyring:    k,
+		tokenMutex: &sync.RWMutex{},
+	}
+}
+
+func TestEncryptLegacyItems(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient(t)
+
+	note := &model.Item{Note: &model.Note{Name: "Name", Body: "Body"}}
+	legacy, err := model.Serialize(note)
+	require.NoError(t, err)
+
+	sealed, err := c.seal(ctx, note, 0)
+	require.NoError(t, err)
+
+	s := &itemsService{
+		items: []*pb.Item{
+			{Id: 1, Data: legacy, Revision: 3},
+			{Id: 2, Data: sealed, Revision: 5},
+		},
+	}
+	c.itemsService = s
+
+	err = c.encryptLegacyItems(ctx)
+	require.NoError(t, err)
+
+	require.Len(t, s.updates, 1)
+	require.Equal(t, int64(1), s.updates[0].GetItem().GetId())
+	require.Equal(t, int64(3), s.updates[0].GetExpectedRevision())
+	require.True(t, crypto.IsEncrypted(s.updates[0].GetItem().GetData()))
+
+	got, err := c.open(ctx, s.updates[0].GetItem().GetData(), 0)
+	require.NoError(t, err)
+	require.Equal(t, note, got)
+}
+
+func TestEncryptLegacyItemsRetry(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient(t)
+
+	note := &model.Item{Note: &model.Note{Name: "Name", Body: "Body"}}
+	legacy, err := model.Serialize(note)
+	require.NoError(t, err)
+
+	s := &itemsService{
+		items: []*pb.Item{
+			{Id: 1, Data: legacy, Revision: 1},
+			{Id: 2, Data: legacy, Revision: 1},
+			{Id: 3, Data: legacy, Revision: 1},
+		},
+		fail: 2,
+	}
+	c.itemsService = s
+
+	// Проход прервался на втором предмете, остальные предметы все равно зашифрованы.
+	err = c.encryptLegacyItems(ctx)
+	require.Error(t, err)
+	require.Len(t, s.updates, 3)
+	require.False(t, crypto.IsEncrypted(s.items[1].GetData()))
+
+	// Следующий проход шифрует только оставшийся предмет.
+	s.updates, s.fail = nil, 0
+
+	err = c.encryptLegacyItems(ctx)
+	require.NoError(t, err)
+	require.Len(t, s.updates, 1)
+	require.Equal(t, int64(2), s.updates[0].GetItem().GetId())
+
+	for _, i := range s.items {
+		got, err := c.open(ctx, i.GetData(), 0)
+		require.NoError(t, err)
+		require.Equal(t, note, got)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient(t)
+
+	note := &model.Item{Note: &model.Note{Name: "Name", Body: "Body"}}
+	legacy, err := model.Serialize(note)
+	require.NoError(t, err)
+
+	sealed, err := c.seal(ctx, note, 0)
+	require.NoError(t, err)
+
+	tests := []struct {
+		err  error
+		want *model.Item
+		name string
+		data []byte
+	}{
+		{
+			name: "Check open encrypted item",
+			data: sealed,
+			want: note,
+		},
+		{
+			name: "Check reject not encrypted item",
+			data: legacy,
+			err:  ErrNotEncrypted,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := c.open(ctx, test.data, 0)
+			require.ErrorIs(t, err, test.err)
+			require.Equal(t, test.want, got)
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package client
 
 import (
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/k0st1a/gophkeeper/internal/pkg/client/model"
@@ -16,4 +18,230 @@ type Item struct {
 	UpdateTime time.Time
 	// Идентификатор предмета
 	ID int64
+	// Ревизия предмета на сервере
+	Revision int64
+	// Коллекция организации, 0 - личный предмет. Предмет коллекции шифруется ключом коллекции.
+	CollectionID int64
+}
+
+// TrashItem - предмет в корзине на сервере.
+type TrashItem struct {
+	// Время удаления предмета в корзину
+	DeleteTime time.Time
+	Item       Item
+}
+
+// ItemVersion - прошлая версия предмета на сервере.
+type ItemVersion struct {
+	// Время сохранения версии
+	ArchiveTime time.Time
+	// Предмет до обновления или удаления
+	Item Item
+	// Версия сохранена при удалении предмета
+	Deleted bool
+}
+
+// Share - предмет, которым пользователь поделился с другим пользователем.
+type Share struct {
+	// Время последней передачи предмета
+	UpdateTime time.Time
+	// Логин получателя
+	Recipient string
+	ID        int64
+	// Идентификатор предмета владельца
+	ItemID int64
+}
+
+// SharedItem - предмет, которым с пользователем поделился другой пользователь.
+// Это копия предмета на момент передачи, изменения владельца в нее не попадают до повторной передачи.
+type SharedItem struct {
+	// Время последней передачи предмета
+	UpdateTime time.Time
+	// Логин владельца
+	Owner string
+	Body  model.Item
+	ID    int64
+}
+
+// Роли участников организации.
+const (
+	// RoleOwner - управляет организацией и всеми участниками.
+	RoleOwner = "owner"
+	// RoleAdmin - управляет коллекциями, участниками и приглашениями, кроме владельцев и администраторов.
+	RoleAdmin = "admin"
+	// RoleMember - читает и изменяет предметы коллекций.
+	RoleMember = "member"
+	// RoleReadOnly - только читает предметы коллекций.
+	RoleReadOnly = "read-only"
+)
+
+// Org - организация, в которой состоит пользователь.
+type Org struct {
+	Name string
+	// Роль пользователя в организации
+	Role string
+	ID   int64
+}
+
+// OrgMember - участник организации.
+type OrgMember struct {
+	CreateTime time.Time
+	Login      string
+	Role       string
+}
+
+// Invitation - приглашение пользователя в организацию.
+type Invitation struct {
+	CreateTime time.Time
+	OrgName    string
+	// Логин пригласившего
+	Inviter string
+	Role    string
+	ID      int64
+	OrgID   int64
+}
+
+// Collection - коллекция предметов организации.
+type Collection struct {
+	// Расшифрованное название коллекции
+	Name  string
+	ID    int64
+	OrgID int64
+}
+
+// Доступ доверенного лица к хранилищу владельца.
+const (
+	// EmergencyAccessView - чтение личных предметов владельца.
+	EmergencyAccessView = "view"
+	// EmergencyAccessTakeover - чтение предметов и установка нового пароля владельца.
+	EmergencyAccessTakeover = "takeover"
+)
+
+// Состояния экстренного доступа.
+const (
+	// EmergencyStateIdle - доступ не запрошен.
+	EmergencyStateIdle = "idle"
+	// EmergencyStateRequested - доступ запрошен и будет одобрен автоматически в ApproveTime.
+	EmergencyStateRequested = "requested"
+	// EmergencyStateApproved - доступ одобрен.
+	EmergencyStateApproved = "approved"
+)
+
+// EmergencyContact - доверенное лицо для экстренного доступа.
+type EmergencyContact struct {
+	CreateTime time.Time
+	// Время запроса доступа, нулевое в состоянии idle
+	RequestTime time.Time
+	// Время автоматического одобрения в состоянии requested или время одобрения в состоянии approved
+	ApproveTime time.Time
+	// Логин владельца
+	Owner string
+	// Логин доверенного лица
+	Contact  string
+	Access   string
+	State    string
+	ID       int64
+	WaitDays int32
+}
+
+// Session - сессия пользователя на сервере.
+type Session struct {
+	CreateTime time.Time
+	// Время последнего обращения к серверу
+	LastSeenTime time.Time
+	// Время, после которого сессия недействительна
+	ExpireTime time.Time
+	ID         string
+	// Имя устройства, на котором выполнен логин
+	Device string
+	// IP адрес последнего обращения к серверу
+	IP string
+	// Сессия этого клиента
+	Current bool
+}
+
+// Changes - изменения предметов на сервере после курсора.
+type Changes struct {
+	// Созданные или обновленные предметы
+	Items []Item
+	// Идентификаторы удаленных предметов
+	DeletedIDs []int64
+	// Курсор для следующего запроса изменений
+	Cursor int64
+	// Items содержит все предметы, а отсутствующие в нем предметы удалены
+	Full bool
+}
+
+var (
+	ErrItemNotFound     = errors.New("item not found")
+	ErrRevisionMismatch = errors.New("revision mismatch")
+	// ErrNotEncrypted - предмет от сервера не зашифрован.
+	ErrNotEncrypted    = errors.New("item is not encrypted")
+	ErrTooManyAttempts = errors.New("too many login attempts")
+	// ErrSecondFactorRequired - пароль верный, для завершения логина нужен код второго фактора.
+	ErrSecondFactorRequired = errors.New("second factor required")
+	ErrNoLoginChallenge     = errors.New("no login waiting for second factor")
+	ErrNotLoggedIn          = errors.New("user is not logged in")
+	// ErrPasswordChanged - пароль изменен с другого устройства во время смены пароля.
+	ErrPasswordChanged = errors.New("password changed concurrently")
+	// ErrShareBlob - файлы с содержимым на сервере зашифрованы ключом хранилища владельца, ими поделиться нельзя.
+	ErrShareBlob    = errors.New("can not share file with content")
+	ErrUserNotFound = errors.New("user not found")
+	// ErrNoPublicKey - получатель еще ни разу не входил после появления обмена предметами.
+	ErrNoPublicKey   = errors.New("user has no public key")
+	ErrShareNotFound = errors.New("share not found")
+	// ErrPermissionDenied - роль пользователя в организации не позволяет выполнить действие.
+	ErrPermissionDenied   = errors.New("permission denied")
+	ErrOrgNotFound        = errors.New("organization not found")
+	ErrMemberNotFound     = errors.New("member not found")
+	ErrAlreadyOrgMember   = errors.New("user is already member of organization")
+	ErrInvitationNotFound = errors.New("invitation not found")
+	ErrCollectionNotFound = errors.New("collection not found")
+	// ErrLastOwner - у организации должен остаться хотя бы один владелец.
+	ErrLastOwner = errors.New("last owner can not leave organization")
+	// ErrInvalidRecoveryKey - ключ восстановления введен с ошибкой или не подходит к логину.
+	ErrInvalidRecoveryKey = errors.New("invalid recovery key")
+	// ErrEmergencyNotApproved - экстренный доступ не одобрен или не позволяет выполнить действие.
+	ErrEmergencyNotApproved     = errors.New("emergency access is not approved")
+	ErrEmergencyContactNotFound = errors.New("emergency contact not found")
+	// ErrEmergencyState - действие недоступно в текущем состоянии экстренного доступа.
+	ErrEmergencyState = errors.New("invalid state of emergency access")
+)
+
+// RevisionMismatchError - предмет на сервере изменен после ревизии, на которой основано обновление.
+type RevisionMismatchError struct {
+	// Текущая ревизия предмета на сервере
+	Revision int64
+}
+
+func (e *RevisionMismatchError) Error() string {
+	return fmt.Sprintf("%v, current revision:%v", ErrRevisionMismatch, e.Revision)
+}
+
+func (e *RevisionMismatchError) Unwrap() error {
+	return ErrRevisionMismatch
+}
+
+// TooManyAttemptsError - вход временно запрещен после неудачных попыток.
+type TooManyAttemptsError struct {
+	// Через сколько можно повторить попытку входа
+	RetryDelay time.Duration
+}
+
+func (e *TooManyAttemptsError) Error() string {
+	return fmt.Sprintf("%v, retry in %v", ErrTooManyAttempts, e.RetryDelay.Round(time.Second))
+}
+
+func (e *TooManyAttemptsError) Unwrap() error {
+	return ErrTooManyAttempts
+}
+
+// SecondFactorEnrollment - подключаемый второй фактор входа.
+type SecondFactorEnrollment struct {
+	// otpauth URI для приложения-аутентификатора
+	URI string
+	// Секрет TOTP в base32 для ручного ввода
+	Secret string
+	// Одноразовые резервные коды, сервер их больше не покажет
+	BackupCodes []string
 }
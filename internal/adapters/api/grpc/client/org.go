@@ -0,0 +1,497 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/k0st1a/gophkeeper/internal/adapters/api/grpc/gen/proto/v1"
+	"github.com/k0st1a/gophkeeper/internal/pkg/client/crypto"
+	"github.com/rs/zerolog/log"
+)
+
+// OrgManager - организации и их коллекции. Ключ организации шифруется открытым ключом каждого участника,
+// названия и ключи коллекций - ключом организации, а предметы коллекции - ключом коллекции.
+type OrgManager interface {
+	// CreateOrg - создать организацию, пользователь становится ее владельцем.
+	CreateOrg(ctx context.Context, name string) (int64, error)
+	ListOrgs(ctx context.Context) ([]Org, error)
+	DeleteOrg(ctx context.Context, orgID int64) error
+	ListMembers(ctx context.Context, orgID int64) ([]OrgMember, error)
+	SetMemberRole(ctx context.Context, orgID int64, login, role string) error
+	// RemoveMember - исключить участника или, если login - логин пользователя, покинуть организацию.
+	RemoveMember(ctx context.Context, orgID int64, login string) error
+	// InviteMember - пригласить пользователя, ключ организации шифруется его открытым ключом.
+	InviteMember(ctx context.Context, orgID int64, login, role string) error
+	ListInvitations(ctx context.Context) ([]Invitation, error)
+	AcceptInvitation(ctx context.Context, id int64) error
+	DeclineInvitation(ctx context.Context, id int64) error
+	CreateCollection(ctx context.Context, orgID int64, name string) (int64, error)
+	// ListCollections - коллекции всех организаций пользователя с расшифрованными названиями.
+	ListCollections(ctx context.Context) ([]Collection, error)
+	DeleteCollection(ctx context.Context, id int64) error
+}
+
+func (c *client) setCollectionKeys(keys map[int64][]byte) {
+	c.tokenMutex.Lock()
+	defer c.tokenMutex.Unlock()
+
+	c.collectionKeys = keys
+}
+
+func (c *client) getCollectionKey(id int64) ([]byte, bool) {
+	c.tokenMutex.RLock()
+	defer c.tokenMutex.RUnlock()
+
+	key, ok := c.collectionKeys[id]
+	return key, ok
+}
+
+// CreateOrg – создать организацию со случайным ключом, зашифрованным открытым ключом пользователя.
+func (c *client) CreateOrg(ctx context.Context, name string) (int64, error) {
+	log.Ctx(ctx).Printf("CreateOrg, Name:%s", name)
+
+	login := c.getLogin()
+	if login == "" {
+		return 0, ErrNotLoggedIn
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	pk, err := c.sharesService.GetPublicKey(ctx, &pb.GetPublicKeyRequest{Login: login})
+	if err != nil {
+		return 0, fmt.Errorf("shares service get public key error:%w", parseShareError(err))
+	}
+
+	key, err := crypto.NewKey()
+	if err != nil {
+		return 0, fmt.Errorf("error of generate org key:%w", err)
+	}
+
+	e, err := crypto.SealTo(pk.PublicKey, key)
+	if err != nil {
+		return 0, fmt.Errorf("error of encrypt org key:%w", err)
+	}
+
+	resp, err := c.orgsService.CreateOrg(ctx, &pb.CreateOrgRequest{Name: name, Key: e})
+	if err != nil {
+		return 0, fmt.Errorf("orgs service create org error:%w", parseOrgError(err))
+	}
+
+	log.Ctx(ctx).Printf("CreateOrg success, id:%v", resp.OrgId)
+	return resp.OrgId, nil
+}
+
+// ListOrgs – получить организации пользователя.
+func (c *client) ListOrgs(ctx context.Context) ([]Org, error) {
+	log.Ctx(ctx).Printf("ListOrgs")
+
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	resp, err := c.orgsService.ListOrgs(ctx, &pb.ListOrgsRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("orgs service list orgs error:%w", err)
+	}
+
+	orgs := make([]Org, 0, len(resp.Orgs))
+	for _, o := range resp.Orgs {
+		orgs = append(orgs, Org{
+			ID:   o.Id,
+			Name: o.Name,
+			Role: o.Role,
+		})
+	}
+
+	return orgs, nil
+}
+
+// DeleteOrg – удалить организацию вместе с ее коллекциями и предметами.
+func (c *client) DeleteOrg(ctx context.Context, orgID int64) error {
+	log.Ctx(ctx).Printf("DeleteOrg, id:%v", orgID)
+
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	_, err := c.orgsService.DeleteOrg(ctx, &pb.DeleteOrgRequest{OrgId: orgID})
+	if err != nil {
+		return fmt.Errorf("orgs service delete org error:%w", parseOrgError(err))
+	}
+
+	return nil
+}
+
+// ListMembers – получить участников организации.
+func (c *client) ListMembers(ctx context.Context, orgID int64) ([]OrgMember, error) {
+	log.Ctx(ctx).Printf("ListMembers, org id:%v", orgID)
+
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	resp, err := c.orgsService.ListMembers(ctx, &pb.ListMembersRequest{OrgId: orgID})
+	if err != nil {
+		return nil, fmt.Errorf("orgs service list members error:%w", parseOrgError(err))
+	}
+
+	members := make([]OrgMember, 0, len(resp.Members))
+	for _, m := range resp.Members {
+		members = append(members, OrgMember{
+			Login:      m.Login,
+			Role:       m.Role,
+			CreateTime: m.CreateTime.AsTime(),
+		})
+	}
+
+	return members, nil
+}
+
+// SetMemberRole – изменить роль участника организации.
+func (c *client) SetMemberRole(ctx context.Context, orgID int64, login, role string) error {
+	log.Ctx(ctx).Printf("SetMemberRole, org id:%v, Login:%s, Role:%s", orgID, login, role)
+
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	req := &pb.SetMemberRoleRequest{
+		OrgId: orgID,
+		Login: login,
+		Role:  role,
+	}
+	_, err := c.orgsService.SetMemberRole(ctx, req)
+	if err != nil {
+		return fmt.Errorf("orgs service set member role error:%w", parseOrgError(err))
+	}
+
+	return nil
+}
+
+// RemoveMember – исключить участника из организации.
+func (c *client) RemoveMember(ctx context.Context, orgID int64, login string) error {
+	log.Ctx(ctx).Printf("RemoveMember, org id:%v, Login:%s", orgID, login)
+
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	_, err := c.orgsService.RemoveMember(ctx, &pb.RemoveMemberRequest{OrgId: orgID, Login: login})
+	if err != nil {
+		return fmt.Errorf("orgs service remove member error:%w", parseOrgError(err))
+	}
+
+	return nil
+}
+
+// InviteMember – пригласить пользователя в организацию. Ключ организации расшифровывается закрытым ключом
+// пользователя и шифруется открытым ключом приглашенного, который выдает сервер.
+func (c *client) InviteMember(ctx context.Context, orgID int64, login, role string) error {
+	log.Ctx(ctx).Printf("InviteMember, org id:%v, Login:%s, Role:%s", orgID, login, role)
+
+	keys, err := c.orgKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	key, ok := keys[orgID]
+	if !ok {
+		return ErrOrgNotFound
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	pk, err := c.sharesService.GetPublicKey(ctx, &pb.GetPublicKeyRequest{Login: login})
+	if err != nil {
+		return fmt.Errorf("shares service get public key error:%w", parseShareError(err))
+	}
+
+	e, err := crypto.SealTo(pk.PublicKey, key)
+	if err != nil {
+		return fmt.Errorf("error of encrypt org key:%w", err)
+	}
+
+	req := &pb.InviteMemberRequest{
+		OrgId: orgID,
+		Login: login,
+		Role:  role,
+		Key:   e,
+	}
+	resp, err := c.orgsService.InviteMember(ctx, req)
+	if err != nil {
+		return fmt.Errorf("orgs service invite member error:%w", parseOrgError(err))
+	}
+
+	log.Ctx(ctx).Printf("InviteMember success, invitation id:%v", resp.Id)
+	return nil
+}
+
+// ListInvitations – получить приглашения пользователя.
+func (c *client) ListInvitations(ctx context.Context) ([]Invitation, error) {
+	log.Ctx(ctx).Printf("ListInvitations")
+
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	resp, err := c.orgsService.ListInvitations(ctx, &pb.ListInvitationsRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("orgs service list invitations error:%w", err)
+	}
+
+	invitations := make([]Invitation, 0, len(resp.Invitations))
+	for _, i := range resp.Invitations {
+		invitations = append(invitations, Invitation{
+			ID:         i.Id,
+			OrgID:      i.OrgId,
+			OrgName:    i.OrgName,
+			Inviter:    i.Inviter,
+			Role:       i.Role,
+			CreateTime: i.CreateTime.AsTime(),
+		})
+	}
+
+	return invitations, nil
+}
+
+// AcceptInvitation – принять приглашение, предметы коллекций организации придут со следующей синхронизацией.
+func (c *client) AcceptInvitation(ctx context.Context, id int64) error {
+	log.Ctx(ctx).Printf("AcceptInvitation, id:%v", id)
+
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	_, err := c.orgsService.AcceptInvitation(ctx, &pb.AcceptInvitationRequest{Id: id})
+	if err != nil {
+		return fmt.Errorf("orgs service accept invitation error:%w", parseOrgError(err))
+	}
+
+	return nil
+}
+
+// DeclineInvitation – отклонить приглашение.
+func (c *client) DeclineInvitation(ctx context.Context, id int64) error {
+	log.Ctx(ctx).Printf("DeclineInvitation, id:%v", id)
+
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	_, err := c.orgsService.DeclineInvitation(ctx, &pb.DeclineInvitationRequest{Id: id})
+	if err != nil {
+		return fmt.Errorf("orgs service decline invitation error:%w", parseOrgError(err))
+	}
+
+	return nil
+}
+
+// CreateCollection – создать коллекцию со случайным ключом. Название и ключ коллекции шифруются
+// ключом организации.
+func (c *client) CreateCollection(ctx context.Context, orgID int64, name string) (int64, error) {
+	log.Ctx(ctx).Printf("CreateCollection, org id:%v", orgID)
+
+	keys, err := c.orgKeys(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	orgKey, ok := keys[orgID]
+	if !ok {
+		return 0, ErrOrgNotFound
+	}
+
+	key, err := crypto.NewKey()
+	if err != nil {
+		return 0, fmt.Errorf("error of generate collection key:%w", err)
+	}
+
+	en, err := crypto.Encrypt(orgKey, []byte(name))
+	if err != nil {
+		return 0, fmt.Errorf("error of encrypt collection name:%w", err)
+	}
+
+	ek, err := crypto.Encrypt(orgKey, key)
+	if err != nil {
+		return 0, fmt.Errorf("error of encrypt collection key:%w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	resp, err := c.orgsService.CreateCollection(ctx, &pb.CreateCollectionRequest{OrgId: orgID, Name: en, Key: ek})
+	if err != nil {
+		return 0, fmt.Errorf("orgs service create collection error:%w", parseOrgError(err))
+	}
+
+	log.Ctx(ctx).Printf("CreateCollection success, id:%v", resp.Id)
+	return resp.Id, nil
+}
+
+// ListCollections – получить коллекции и расшифровать их названия и ключи, ключи запоминаются
+// для шифрования предметов коллекций.
+func (c *client) ListCollections(ctx context.Context) ([]Collection, error) {
+	log.Ctx(ctx).Printf("ListCollections")
+
+	orgKeys, err := c.orgKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	resp, err := c.orgsService.ListCollections(ctx, &pb.ListCollectionsRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("orgs service list collections error:%w", err)
+	}
+
+	collections := make([]Collection, 0, len(resp.Collections))
+	keys := make(map[int64][]byte, len(resp.Collections))
+	for _, i := range resp.Collections {
+		orgKey, ok := orgKeys[i.OrgId]
+		if !ok {
+			// Пользователь вступил в организацию между запросами.
+			continue
+		}
+
+		name, err := crypto.Decrypt(orgKey, i.Name)
+		if err != nil {
+			return nil, fmt.Errorf("error of decrypt name of collection(%v):%w", i.Id, err)
+		}
+
+		key, err := crypto.Decrypt(orgKey, i.Key)
+		if err != nil {
+			return nil, fmt.Errorf("error of decrypt key of collection(%v):%w", i.Id, err)
+		}
+
+		keys[i.Id] = key
+		collections = append(collections, Collection{
+			ID:    i.Id,
+			OrgID: i.OrgId,
+			Name:  string(name),
+		})
+	}
+	c.setCollectionKeys(keys)
+
+	log.Ctx(ctx).Printf("ListCollections success, collections:%v", len(collections))
+	return collections, nil
+}
+
+// DeleteCollection – удалить коллекцию вместе с ее предметами.
+func (c *client) DeleteCollection(ctx context.Context, id int64) error {
+	log.Ctx(ctx).Printf("DeleteCollection, id:%v", id)
+
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	_, err := c.orgsService.DeleteCollection(ctx, &pb.DeleteCollectionRequest{Id: id})
+	if err != nil {
+		return fmt.Errorf("orgs service delete collection error:%w", parseOrgError(err))
+	}
+
+	return nil
+}
+
+// orgKeys – получить ключи организаций пользователя, расшифрованные его закрытым ключом.
+func (c *client) orgKeys(ctx context.Context) (map[int64][]byte, error) {
+	private := c.getPrivateKey()
+	if private == nil {
+		return nil, ErrNotLoggedIn
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	resp, err := c.orgsService.ListOrgs(ctx, &pb.ListOrgsRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("orgs service list orgs error:%w", err)
+	}
+
+	keys := make(map[int64][]byte, len(resp.Orgs))
+	for _, o := range resp.Orgs {
+		key, err := crypto.OpenFrom(private, o.Key)
+		if err != nil {
+			return nil, fmt.Errorf("error of decrypt key of org(%v):%w", o.Id, err)
+		}
+		keys[o.Id] = key
+	}
+
+	return keys, nil
+}
+
+// collectionKey – получить ключ коллекции, неизвестные ключи загружаются с сервера.
+func (c *client) collectionKey(ctx context.Context, id int64) ([]byte, error) {
+	key, ok := c.getCollectionKey(id)
+	if ok {
+		return key, nil
+	}
+
+	_, err := c.ListCollections(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key, ok = c.getCollectionKey(id)
+	if !ok {
+		return nil, fmt.Errorf("%w:%v", ErrCollectionNotFound, id)
+	}
+
+	return key, nil
+}
+
+// encrypt – зашифровать ключом хранилища или, если collectionID не 0, ключом коллекции.
+func (c *client) encrypt(ctx context.Context, b []byte, collectionID int64) ([]byte, error) {
+	if collectionID == 0 {
+		return c.keyring.Encrypt(b) //nolint:wrapcheck // wrapped by caller
+	}
+
+	key, err := c.collectionKey(ctx, collectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return crypto.Encrypt(key, b) //nolint:wrapcheck // wrapped by caller
+}
+
+// decrypt – расшифровать ключом хранилища или, если collectionID не 0, ключом коллекции.
+func (c *client) decrypt(ctx context.Context, b []byte, collectionID int64) ([]byte, error) {
+	if collectionID == 0 {
+		return c.keyring.Decrypt(b) //nolint:wrapcheck // wrapped by caller
+	}
+
+	key, err := c.collectionKey(ctx, collectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return crypto.Decrypt(key, b) //nolint:wrapcheck // wrapped by caller
+}
+
+// parseOrgError – преобразовать статус ошибки организаций в ошибку клиента.
+func parseOrgError(err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	switch {
+	case st.Code() == codes.PermissionDenied:
+		return fmt.Errorf("%w:%w", ErrPermissionDenied, err)
+	case st.Code() == codes.AlreadyExists:
+		return fmt.Errorf("%w:%w", ErrAlreadyOrgMember, err)
+	case st.Code() == codes.FailedPrecondition && st.Message() == "user has no public key yet, user must login first":
+		return fmt.Errorf("%w:%w", ErrNoPublicKey, err)
+	case st.Code() == codes.FailedPrecondition:
+		return fmt.Errorf("%w:%w", ErrLastOwner, err)
+	case st.Code() == codes.NotFound && st.Message() == "user not found":
+		return fmt.Errorf("%w:%w", ErrUserNotFound, err)
+	case st.Code() == codes.NotFound && st.Message() == "member not found":
+		return fmt.Errorf("%w:%w", ErrMemberNotFound, err)
+	case st.Code() == codes.NotFound && st.Message() == "invitation not found":
+		return fmt.Errorf("%w:%w", ErrInvitationNotFound, err)
+	case st.Code() == codes.NotFound && st.Message() == "collection not found":
+		return fmt.Errorf("%w:%w", ErrCollectionNotFound, err)
+	case st.Code() == codes.NotFound:
+		return fmt.Errorf("%w:%w", ErrOrgNotFound, err)
+	default:
+		return err
+	}
+}
@@ -0,0 +1,173 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/k0st1a/gophkeeper/internal/adapters/api/grpc/gen/proto/v1"
+	"github.com/k0st1a/gophkeeper/internal/pkg/client/keyring"
+	"github.com/k0st1a/gophkeeper/internal/pkg/srp"
+	"github.com/rs/zerolog/log"
+)
+
+type PasswordManager interface {
+	// ChangePassword - сменить пароль пользователя, остальные сессии пользователя отзываются.
+	ChangePassword(ctx context.Context, oldPassword, newPassword string) error
+}
+
+func (c *client) setLogin(login string) {
+	c.tokenMutex.Lock()
+	defer c.tokenMutex.Unlock()
+
+	c.login = login
+	c.legacy = false
+}
+
+func (c *client) setLegacy(legacy bool) {
+	c.tokenMutex.Lock()
+	defer c.tokenMutex.Unlock()
+
+	c.legacy = legacy
+}
+
+func (c *client) isLegacy() bool {
+	c.tokenMutex.RLock()
+	defer c.tokenMutex.RUnlock()
+
+	return c.legacy
+}
+
+func (c *client) getLogin() string {
+	c.tokenMutex.RLock()
+	defer c.tokenMutex.RUnlock()
+
+	return c.login
+}
+
+// ChangePassword – сменить пароль пользователя. Старый пароль подтверждается по SRP и на сервер не передается,
+// кроме пользователей, для которых на сервере еще нет верификатора SRP.
+// Предметы зашифрованы ключом хранилища, который не меняется, поэтому перешифровывается только сам ключ
+// хранилища. Новый верификатор и ключ хранилища передаются одним запросом и заменяются на сервере атомарно,
+// поэтому при сбое на сервере остается либо старый, либо новый пароль целиком.
+func (c *client) ChangePassword(ctx context.Context, oldPassword, newPassword string) error {
+	log.Ctx(ctx).Printf("ChangePassword")
+
+	login := c.getLogin()
+	if login == "" {
+		return ErrNotLoggedIn
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	v, err := newSRPVerifier(login, newPassword)
+	if err != nil {
+		return err
+	}
+
+	req := &pb.ChangePasswordRequest{Verifier: v}
+
+	// Ключ хранилища зашифрован паролем пользователя, только если мастер-пароль не задан отдельно.
+	if c.secretKey == "" {
+		key, err := c.keyring.Key()
+		if err != nil {
+			return fmt.Errorf("error of get vault key:%w", err)
+		}
+
+		wk, err := keyring.Wrap(newPassword, key)
+		if err != nil {
+			return fmt.Errorf("error of wrap vault key:%w", err)
+		}
+
+		req.VaultKey = &pb.VaultKey{
+			Salt: wk.Salt,
+			Key:  wk.Key,
+		}
+	}
+
+	p, err := c.provePassword(ctx, login, oldPassword)
+	if err != nil {
+		return err
+	}
+
+	req.Session, req.Proof, req.OldPassword = p.session, p.proof, p.password
+
+	resp, err := c.usersService.ChangePassword(ctx, req)
+	if err != nil {
+		return changePasswordError(err)
+	}
+
+	err = p.verifyServer(resp.GetServerProof())
+	if err != nil {
+		return err
+	}
+
+	c.setLegacy(false)
+
+	log.Ctx(ctx).Printf("ChangePassword success, revoked sessions:%v", resp.GetRevoked())
+	return nil
+}
+
+// passwordProof – подтверждение текущего пароля пользователя для сервера.
+type passwordProof struct {
+	// Клиент SRP, nil, если на сервере еще нет верификатора SRP
+	sc *srp.Client
+	// Сессия из StartLogin и доказательство пароля M1
+	session string
+	proof   []byte
+	// Сам пароль, только если на сервере еще нет верификатора SRP
+	password string
+}
+
+// provePassword – подтвердить текущий пароль по SRP. Если на сервере еще нет верификатора SRP, то пароль
+// подтверждается самим паролем.
+func (c *client) provePassword(ctx context.Context, login, password string) (*passwordProof, error) {
+	if c.isLegacy() {
+		return &passwordProof{password: password}, nil
+	}
+
+	sc, err := srp.NewClient(login)
+	if err != nil {
+		return nil, fmt.Errorf("error of create srp client:%w", err)
+	}
+
+	start, err := c.usersService.StartLogin(ctx, &pb.StartLoginRequest{
+		Login: login,
+		A:     sc.A,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("users service start login error:%w", parseLoginError(err))
+	}
+
+	proof, err := sc.Proof(password, start.GetSalt(), start.GetB())
+	if err != nil {
+		return nil, fmt.Errorf("error of calculate srp proof:%w", err)
+	}
+
+	return &passwordProof{sc: sc, session: start.GetSession(), proof: proof}, nil
+}
+
+// verifyServer – проверить доказательство сервера M2, если пароль подтвержден по SRP.
+func (p *passwordProof) verifyServer(m2 []byte) error {
+	if p.sc == nil {
+		return nil
+	}
+
+	err := p.sc.VerifyServer(m2)
+	if err != nil {
+		return fmt.Errorf("error of verify server:%w", err)
+	}
+
+	return nil
+}
+
+func changePasswordError(err error) error {
+	if status.Code(err) == codes.Aborted {
+		return ErrPasswordChanged
+	}
+
+	return fmt.Errorf("users service change password error:%w", parseLoginError(err))
+}
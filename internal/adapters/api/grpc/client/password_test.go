@@ -0,0 +1,160 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/k0st1a/gophkeeper/internal/adapters/api/grpc/gen/proto/v1"
+	"github.com/k0st1a/gophkeeper/internal/pkg/client/keyring"
+	"github.com/k0st1a/gophkeeper/internal/pkg/srp"
+)
+
+// usersService - сервер смены пароля: проверяет доказательство SRP старого пароля и запоминает запрос.
+type usersService struct {
+	pb.UsersServiceClient
+	err      error
+	req      *pb.ChangePasswordRequest
+	login    string
+	salt     []byte
+	verifier []byte
+	secret   []byte
+	pubA     []byte
+	pubB     []byte
+	legacy   bool
+}
+
+func (s *usersService) StartLogin(ctx context.Context, in *pb.StartLoginRequest,
+	opts ...grpc.CallOption) (*pb.StartLoginResponse, error) {
+	if s.legacy {
+		return nil, status.Error(codes.Internal, "start login for legacy password change")
+	}
+
+	secret, pubB, err := srp.NewServer(s.verifier)
+	if err != nil {
+		return nil, err
+	}
+
+	s.secret, s.pubA, s.pubB = secret, in.GetA(), pubB
+	return &pb.StartLoginResponse{Session: "session", Salt: s.salt, B: pubB}, nil
+}
+
+func (s *usersService) ChangePassword(ctx context.Context, in *pb.ChangePasswordRequest,
+	opts ...grpc.CallOption) (*pb.ChangePasswordResponse, error) {
+	s.req = in
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	if s.legacy {
+		return &pb.ChangePasswordResponse{}, nil
+	}
+
+	m2, err := srp.VerifyClient(s.login, s.salt, s.verifier, s.secret, s.pubA, s.pubB, in.GetProof())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid credentials")
+	}
+
+	return &pb.ChangePasswordResponse{ServerProof: m2}, nil
+}
+
+func TestChangePassword(t *testing.T) {
+	salt, verifier, err := srp.NewVerifier("login", "old")
+	require.NoError(t, err)
+
+	tests := []struct {
+		err       error
+		serverErr error
+		name      string
+		login     string
+		secretKey string
+		legacy    bool
+		vaultKey  bool
+	}{
+		{
+			name:     "Check change password by srp proof",
+			login:    "login",
+			vaultKey: true,
+		},
+		{
+			name:     "Check change legacy password",
+			login:    "login",
+			legacy:   true,
+			vaultKey: true,
+		},
+		{
+			name:      "Check vault key is not changed with separate master password",
+			login:     "login",
+			secretKey: "secret key",
+		},
+		{
+			name:      "Check password changed concurrently",
+			login:     "login",
+			serverErr: status.Error(codes.Aborted, "password changed concurrently"),
+			err:       ErrPasswordChanged,
+			vaultKey:  true,
+		},
+		{
+			name: "Check not logged in",
+			err:  ErrNotLoggedIn,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			us := &usersService{
+				err:      test.serverErr,
+				login:    "login",
+				salt:     salt,
+				verifier: verifier,
+				legacy:   test.legacy,
+			}
+
+			c := newTestClient(t)
+			c.usersService = us
+			c.secretKey = test.secretKey
+			c.login = test.login
+			c.legacy = test.legacy
+
+			err := c.ChangePassword(context.Background(), "old", "new")
+			require.ErrorIs(t, err, test.err)
+
+			if test.login == "" {
+				require.Nil(t, us.req)
+				return
+			}
+
+			require.False(t, c.legacy && test.err == nil)
+			require.NotEmpty(t, us.req.GetVerifier().GetSalt())
+			require.NotEmpty(t, us.req.GetVerifier().GetVerifier())
+
+			if test.legacy {
+				require.Equal(t, "old", us.req.GetOldPassword())
+				require.Empty(t, us.req.GetProof())
+			} else {
+				require.Empty(t, us.req.GetOldPassword())
+				require.Equal(t, "session", us.req.GetSession())
+				require.NotEmpty(t, us.req.GetProof())
+			}
+
+			if !test.vaultKey {
+				require.Nil(t, us.req.GetVaultKey())
+				return
+			}
+
+			key, err := keyring.Unwrap("new", &keyring.WrappedKey{
+				Salt: us.req.GetVaultKey().GetSalt(),
+				Key:  us.req.GetVaultKey().GetKey(),
+			})
+			require.NoError(t, err)
+
+			want, err := c.keyring.Key()
+			require.NoError(t, err)
+			require.Equal(t, want, key)
+		})
+	}
+}
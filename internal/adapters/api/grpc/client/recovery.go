@@ -0,0 +1,189 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/k0st1a/gophkeeper/internal/adapters/api/grpc/gen/proto/v1"
+	"github.com/k0st1a/gophkeeper/internal/pkg/client/keyring"
+	"github.com/k0st1a/gophkeeper/internal/pkg/client/recovery"
+	"github.com/rs/zerolog/log"
+)
+
+type RecoveryManager interface {
+	// NewRecoveryKit - создать новый ключ восстановления залогиненного пользователя по его текущему паролю, прежний
+	// ключ перестает действовать, остальные сессии пользователя отзываются. Возвращает аварийный набор с новым ключом.
+	NewRecoveryKit(ctx context.Context, password string) (*recovery.Kit, error)
+	// RecoverAccount - установить новый пароль пользователя по ключу восстановления из аварийного набора.
+	// Все сессии пользователя отзываются, второй фактор отключается, после восстановления нужно залогиниться
+	// с новым паролем.
+	RecoverAccount(ctx context.Context, login, recoveryKey, newPassword string) error
+}
+
+// newRecoveryKey - сгенерировать ключ восстановления и зашифровать им ключ хранилища.
+func (c *client) newRecoveryKey(login string, vaultKey []byte) (*pb.RecoveryKey, *recovery.Kit, error) {
+	key, err := recovery.NewKey()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error of generate recovery key:%w", err)
+	}
+
+	n, err := recovery.Normalize(key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error of normalize recovery key:%w", err)
+	}
+
+	wk, err := keyring.Wrap(n, vaultKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error of wrap vault key by recovery key:%w", err)
+	}
+
+	rk := &pb.RecoveryKey{
+		Salt: wk.Salt,
+		Key:  wk.Key,
+		Auth: recovery.Auth(n),
+	}
+
+	kit := &recovery.Kit{
+		CreateTime: time.Now(),
+		Login:      login,
+		Server:     c.address,
+		Key:        key,
+	}
+
+	return rk, kit, nil
+}
+
+// NewRecoveryKit – создать новый ключ восстановления. Нужен пользователям, зарегистрированным до появления
+// ключа восстановления, и тем, у кого аварийный набор потерян или мог попасть в чужие руки. Текущий пароль
+// подтверждается так же, как при смене пароля.
+func (c *client) NewRecoveryKit(ctx context.Context, password string) (*recovery.Kit, error) {
+	log.Ctx(ctx).Printf("NewRecoveryKit")
+
+	login := c.getLogin()
+	if login == "" {
+		return nil, ErrNotLoggedIn
+	}
+
+	key, err := c.keyring.Key()
+	if err != nil {
+		return nil, fmt.Errorf("error of get vault key:%w", err)
+	}
+
+	rk, kit, err := c.newRecoveryKey(login, key)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	p, err := c.provePassword(ctx, login, password)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.usersService.SetRecoveryKey(ctx, &pb.SetRecoveryKeyRequest{
+		RecoveryKey: rk,
+		Session:     p.session,
+		Proof:       p.proof,
+		OldPassword: p.password,
+	})
+	if err != nil {
+		if status.Code(err) == codes.Aborted {
+			return nil, ErrPasswordChanged
+		}
+
+		return nil, fmt.Errorf("users service set recovery key error:%w", parseLoginError(err))
+	}
+
+	err = p.verifyServer(resp.GetServerProof())
+	if err != nil {
+		return nil, err
+	}
+
+	log.Ctx(ctx).Printf("NewRecoveryKit success, revoked sessions:%v", resp.GetRevoked())
+	return kit, nil
+}
+
+// RecoverAccount – установить новый пароль по ключу восстановления. Ключ хранилища расшифровывается ключом
+// восстановления и, если мастер-паролем выступает пароль пользователя, шифруется новым паролем, поэтому
+// предметы пользователя остаются доступны. Ключ восстановления после этого продолжает действовать.
+func (c *client) RecoverAccount(ctx context.Context, login, recoveryKey, newPassword string) error {
+	log.Ctx(ctx).Printf("RecoverAccount, Login:%s", login)
+
+	n, err := recovery.Normalize(recoveryKey)
+	if err != nil {
+		return fmt.Errorf("%w:%w", ErrInvalidRecoveryKey, err)
+	}
+
+	auth := recovery.Auth(n)
+
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	start, err := c.usersService.StartRecovery(ctx, &pb.StartRecoveryRequest{
+		Login: login,
+		Auth:  auth,
+	})
+	if err != nil {
+		return fmt.Errorf("users service start recovery error:%w", parseRecoveryError(err))
+	}
+
+	// Расшифровка заодно проверяет, что на сервере лежит ключ хранилища, зашифрованный именно этим ключом.
+	key, err := keyring.Unwrap(n, &keyring.WrappedKey{
+		Salt: start.GetRecoveryKey().GetSalt(),
+		Key:  start.GetRecoveryKey().GetKey(),
+	})
+	if err != nil {
+		return fmt.Errorf("%w:%w", ErrInvalidRecoveryKey, err)
+	}
+	defer clear(key)
+
+	v, err := newSRPVerifier(login, newPassword)
+	if err != nil {
+		return err
+	}
+
+	req := &pb.RecoverRequest{
+		Login:    login,
+		Auth:     auth,
+		Verifier: v,
+	}
+
+	// Ключ хранилища зашифрован паролем пользователя, только если мастер-пароль не задан отдельно.
+	if c.secretKey == "" {
+		wk, err := keyring.Wrap(newPassword, key)
+		if err != nil {
+			return fmt.Errorf("error of wrap vault key:%w", err)
+		}
+
+		req.VaultKey = &pb.VaultKey{
+			Salt: wk.Salt,
+			Key:  wk.Key,
+		}
+	}
+
+	resp, err := c.usersService.Recover(ctx, req)
+	if err != nil {
+		return fmt.Errorf("users service recover error:%w", parseRecoveryError(err))
+	}
+
+	log.Ctx(ctx).Printf("RecoverAccount success, revoked sessions:%v", resp.GetRevoked())
+	return nil
+}
+
+// parseRecoveryError - неверный логин или ключ восстановления сервер считает неудачной попыткой входа.
+func parseRecoveryError(err error) error {
+	switch status.Code(err) {
+	case codes.InvalidArgument:
+		return fmt.Errorf("%w:%w", ErrInvalidRecoveryKey, err)
+	case codes.Aborted:
+		return ErrPasswordChanged
+	default:
+		return parseLoginError(err)
+	}
+}
@@ -0,0 +1,123 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	pb "github.com/k0st1a/gophkeeper/internal/adapters/api/grpc/gen/proto/v1"
+	"github.com/rs/zerolog/log"
+)
+
+type SecondFactorManager interface {
+	// EnrollSecondFactor - создать секрет TOTP и резервные коды, второй фактор требуется при логине
+	// только после подтверждения кодом через ConfirmSecondFactor.
+	EnrollSecondFactor(ctx context.Context) (*SecondFactorEnrollment, error)
+	ConfirmSecondFactor(ctx context.Context, code string) error
+	// DisableSecondFactor - отключить второй фактор, code - код TOTP или резервный код.
+	DisableSecondFactor(ctx context.Context, code string) error
+}
+
+// challenge - логин, пароль которого проверен сервером, ожидающий подтверждения вторым фактором.
+type challenge struct {
+	challenge string
+	// Мастер-пароль для расшифровки ключа хранилища после подтверждения
+	password string
+	// Верификатор SRP для передачи на сервер после подтверждения, если логин выполнен по паролю
+	verifier *pb.SRPVerifier
+}
+
+func (c *client) setChallenge(ch *challenge) {
+	c.tokenMutex.Lock()
+	defer c.tokenMutex.Unlock()
+
+	c.challenge = ch
+}
+
+func (c *client) getChallenge() *challenge {
+	c.tokenMutex.RLock()
+	defer c.tokenMutex.RUnlock()
+
+	return c.challenge
+}
+
+// VerifySecondFactor – завершить логин кодом TOTP или резервным кодом.
+func (c *client) VerifySecondFactor(ctx context.Context, code string) error {
+	log.Ctx(ctx).Printf("VerifySecondFactor")
+
+	ch := c.getChallenge()
+	if ch == nil {
+		return ErrNoLoginChallenge
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	resp, err := c.usersService.VerifySecondFactor(ctx, &pb.VerifySecondFactorRequest{
+		Challenge: ch.challenge,
+		Code:      code,
+	})
+	if err != nil {
+		return fmt.Errorf("users service verify second factor error:%w", parseLoginError(err))
+	}
+
+	c.setChallenge(nil)
+
+	err = c.completeLogin(ctx, ch.password, resp)
+	if err != nil {
+		return err
+	}
+
+	c.migrateSRPVerifier(ctx, ch.verifier)
+
+	log.Ctx(ctx).Printf("VerifySecondFactor success")
+	return nil
+}
+
+// EnrollSecondFactor – создать секрет TOTP и резервные коды второго фактора.
+func (c *client) EnrollSecondFactor(ctx context.Context) (*SecondFactorEnrollment, error) {
+	log.Ctx(ctx).Printf("EnrollSecondFactor")
+
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	resp, err := c.usersService.EnrollSecondFactor(ctx, &pb.EnrollSecondFactorRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("users service enroll second factor error:%w", err)
+	}
+
+	return &SecondFactorEnrollment{
+		URI:         resp.Uri,
+		Secret:      resp.Secret,
+		BackupCodes: resp.BackupCodes,
+	}, nil
+}
+
+// ConfirmSecondFactor – подключить второй фактор, подтвердив его кодом TOTP.
+func (c *client) ConfirmSecondFactor(ctx context.Context, code string) error {
+	log.Ctx(ctx).Printf("ConfirmSecondFactor")
+
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	_, err := c.usersService.ConfirmSecondFactor(ctx, &pb.ConfirmSecondFactorRequest{Code: code})
+	if err != nil {
+		return fmt.Errorf("users service confirm second factor error:%w", err)
+	}
+
+	return nil
+}
+
+// DisableSecondFactor – отключить второй фактор.
+func (c *client) DisableSecondFactor(ctx context.Context, code string) error {
+	log.Ctx(ctx).Printf("DisableSecondFactor")
+
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	_, err := c.usersService.DisableSecondFactor(ctx, &pb.DisableSecondFactorRequest{Code: code})
+	if err != nil {
+		return fmt.Errorf("users service disable second factor error:%w", parseLoginError(err))
+	}
+
+	return nil
+}
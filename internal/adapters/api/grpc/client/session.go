@@ -0,0 +1,89 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+
+	pb "github.com/k0st1a/gophkeeper/internal/adapters/api/grpc/gen/proto/v1"
+	"github.com/rs/zerolog/log"
+)
+
+type SessionManager interface {
+	ListSessions(ctx context.Context) ([]Session, error)
+	RevokeSession(ctx context.Context, id string) error
+	RevokeOtherSessions(ctx context.Context) (int64, error)
+}
+
+// ListSessions – получить действующие сессии пользователя, начиная с последней активной.
+func (c *client) ListSessions(ctx context.Context) ([]Session, error) {
+	log.Ctx(ctx).Printf("ListSessions")
+
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	resp, err := c.usersService.ListSessions(ctx, &pb.ListSessionsRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("users service list sessions error:%w", err)
+	}
+
+	sessions := make([]Session, 0, len(resp.Sessions))
+	for _, s := range resp.Sessions {
+		sessions = append(sessions, Session{
+			CreateTime:   s.CreateTime.AsTime(),
+			LastSeenTime: s.LastSeenTime.AsTime(),
+			ExpireTime:   s.ExpireTime.AsTime(),
+			ID:           s.Id,
+			Device:       s.Device,
+			IP:           s.Ip,
+			Current:      s.Current,
+		})
+	}
+
+	log.Ctx(ctx).Printf("ListSessions success, sessions:%v", len(sessions))
+	return sessions, nil
+}
+
+// RevokeSession – отозвать сессию пользователя.
+func (c *client) RevokeSession(ctx context.Context, id string) error {
+	log.Ctx(ctx).Printf("RevokeSession, id:%v", id)
+
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	_, err := c.usersService.RevokeSession(ctx, &pb.RevokeSessionRequest{Id: id})
+	if err != nil {
+		return fmt.Errorf("users service revoke session error:%w", err)
+	}
+
+	log.Ctx(ctx).Printf("RevokeSession success")
+	return nil
+}
+
+// RevokeOtherSessions – отозвать все сессии пользователя, кроме текущей. Возвращает количество отозванных сессий.
+func (c *client) RevokeOtherSessions(ctx context.Context) (int64, error) {
+	log.Ctx(ctx).Printf("RevokeOtherSessions")
+
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	resp, err := c.usersService.RevokeOtherSessions(ctx, &pb.RevokeOtherSessionsRequest{})
+	if err != nil {
+		return 0, fmt.Errorf("users service revoke other sessions error:%w", err)
+	}
+
+	log.Ctx(ctx).Printf("RevokeOtherSessions success, revoked:%v", resp.Revoked)
+	return resp.Revoked, nil
+}
+
+// deviceName – имя устройства для списка сессий.
+func deviceName() string {
+	h, err := os.Hostname()
+	if err != nil {
+		log.Error().Err(err).Msg("error of get hostname")
+		h = "unknown"
+	}
+
+	return fmt.Sprintf("%s (%s/%s)", h, runtime.GOOS, runtime.GOARCH)
+}
@@ -0,0 +1,225 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/k0st1a/gophkeeper/internal/adapters/api/grpc/gen/proto/v1"
+	"github.com/k0st1a/gophkeeper/internal/pkg/client/crypto"
+	"github.com/k0st1a/gophkeeper/internal/pkg/client/model"
+	"github.com/rs/zerolog/log"
+)
+
+type ShareManager interface {
+	// ShareItem - поделиться копией предмета с пользователем login, повторная передача заменяет копию.
+	ShareItem(ctx context.Context, item *Item, login string) error
+	// ListShares - предметы, которыми поделился пользователь.
+	ListShares(ctx context.Context) ([]Share, error)
+	// ListSharedWithMe - предметы, которыми поделились с пользователем.
+	ListSharedWithMe(ctx context.Context) ([]SharedItem, error)
+	// RevokeShare - отозвать переданный предмет или отказаться от полученного.
+	RevokeShare(ctx context.Context, id int64) error
+}
+
+func (c *client) setPrivateKey(key []byte) {
+	c.tokenMutex.Lock()
+	defer c.tokenMutex.Unlock()
+
+	c.privateKey = key
+}
+
+func (c *client) getPrivateKey() []byte {
+	c.tokenMutex.RLock()
+	defer c.tokenMutex.RUnlock()
+
+	return c.privateKey
+}
+
+// unlockKeyPair – расшифровать ключом хранилища закрытый ключ, полученный при логине.
+// Если у пользователя еще нет пары ключей, то она генерируется и сохраняется на сервере.
+func (c *client) unlockKeyPair(ctx context.Context, kp *pb.KeyPair) error {
+	if len(kp.GetPrivateKey()) != 0 {
+		key, err := c.keyring.Decrypt(kp.PrivateKey)
+		if err != nil {
+			return fmt.Errorf("error of decrypt private key:%w", err)
+		}
+
+		c.setPrivateKey(key)
+		return nil
+	}
+
+	log.Ctx(ctx).Printf("No key pair => generate and set new one")
+
+	private, public, err := crypto.NewKeyPair()
+	if err != nil {
+		return fmt.Errorf("error of generate key pair:%w", err)
+	}
+
+	e, err := c.keyring.Encrypt(private)
+	if err != nil {
+		return fmt.Errorf("error of encrypt private key:%w", err)
+	}
+
+	req := &pb.SetKeyPairRequest{
+		KeyPair: &pb.KeyPair{
+			PublicKey:  public,
+			PrivateKey: e,
+		},
+	}
+
+	_, err = c.usersService.SetKeyPair(ctx, req)
+	if err != nil {
+		return fmt.Errorf("users service set key pair error:%w", err)
+	}
+
+	c.setPrivateKey(private)
+	return nil
+}
+
+// ShareItem – поделиться предметом. Предмет шифруется открытым ключом получателя,
+// поэтому сервер не может его прочитать. Открытый ключ получателя выдает сервер.
+func (c *client) ShareItem(ctx context.Context, item *Item, login string) error {
+	log.Ctx(ctx).Printf("ShareItem, id:%v, Login:%s", item.ID, login)
+
+	if len(item.Body.BlobIDs()) != 0 {
+		return ErrShareBlob
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	pk, err := c.sharesService.GetPublicKey(ctx, &pb.GetPublicKeyRequest{Login: login})
+	if err != nil {
+		return fmt.Errorf("shares service get public key error:%w", parseShareError(err))
+	}
+
+	b, err := model.Serialize(&item.Body)
+	if err != nil {
+		return fmt.Errorf("error of serialize item:%w", err)
+	}
+
+	data, err := crypto.SealTo(pk.PublicKey, b)
+	if err != nil {
+		return fmt.Errorf("error of encrypt item:%w", err)
+	}
+
+	req := &pb.ShareItemRequest{
+		ItemId: item.ID,
+		Login:  login,
+		Data:   data,
+	}
+	resp, err := c.sharesService.ShareItem(ctx, req)
+	if err != nil {
+		return fmt.Errorf("shares service share item error:%w", parseShareError(err))
+	}
+
+	log.Ctx(ctx).Printf("ShareItem success, share id:%v", resp.Id)
+	return nil
+}
+
+// ListShares – получить предметы, которыми поделился пользователь.
+func (c *client) ListShares(ctx context.Context) ([]Share, error) {
+	log.Ctx(ctx).Printf("ListShares")
+
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	resp, err := c.sharesService.ListShares(ctx, &pb.ListSharesRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("shares service list shares error:%w", err)
+	}
+
+	shares := make([]Share, 0, len(resp.Shares))
+	for _, s := range resp.Shares {
+		shares = append(shares, Share{
+			ID:         s.Id,
+			ItemID:     s.ItemId,
+			Recipient:  s.Recipient,
+			UpdateTime: s.UpdateTime.AsTime(),
+		})
+	}
+
+	log.Ctx(ctx).Printf("ListShares success, shares:%v", len(shares))
+	return shares, nil
+}
+
+// ListSharedWithMe – получить и расшифровать закрытым ключом пользователя предметы, которыми с ним поделились.
+func (c *client) ListSharedWithMe(ctx context.Context) ([]SharedItem, error) {
+	log.Ctx(ctx).Printf("ListSharedWithMe")
+
+	key := c.getPrivateKey()
+	if key == nil {
+		return nil, ErrNotLoggedIn
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	resp, err := c.sharesService.ListSharedWithMe(ctx, &pb.ListSharedWithMeRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("shares service list shared with me error:%w", err)
+	}
+
+	items := make([]SharedItem, 0, len(resp.Shares))
+	for _, s := range resp.Shares {
+		b, err := crypto.OpenFrom(key, s.Data)
+		if err != nil {
+			return nil, fmt.Errorf("error of decrypt shared item(%v):%w", s.Id, err)
+		}
+
+		body, err := model.Deserialize(b)
+		if err != nil {
+			return nil, fmt.Errorf("error of deserialize shared item(%v):%w", s.Id, err)
+		}
+
+		items = append(items, SharedItem{
+			ID:         s.Id,
+			Owner:      s.Owner,
+			Body:       *body,
+			UpdateTime: s.UpdateTime.AsTime(),
+		})
+	}
+
+	log.Ctx(ctx).Printf("ListSharedWithMe success, items:%v", len(items))
+	return items, nil
+}
+
+// RevokeShare – отозвать предмет у получателя или отказаться от полученного предмета.
+func (c *client) RevokeShare(ctx context.Context, id int64) error {
+	log.Ctx(ctx).Printf("RevokeShare, id:%v", id)
+
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	_, err := c.sharesService.RevokeShare(ctx, &pb.RevokeShareRequest{Id: id})
+	if err != nil {
+		return fmt.Errorf("shares service revoke share error:%w", parseShareError(err))
+	}
+
+	log.Ctx(ctx).Printf("RevokeShare success")
+	return nil
+}
+
+// parseShareError – преобразовать статус ошибки обмена предметами в ошибку клиента.
+func parseShareError(err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	switch {
+	case st.Code() == codes.FailedPrecondition:
+		return fmt.Errorf("%w:%w", ErrNoPublicKey, err)
+	case st.Code() == codes.NotFound && st.Message() == "user not found":
+		return fmt.Errorf("%w:%w", ErrUserNotFound, err)
+	case st.Code() == codes.NotFound && st.Message() == "item not found":
+		return fmt.Errorf("%w:%w", ErrItemNotFound, err)
+	case st.Code() == codes.NotFound:
+		return fmt.Errorf("%w:%w", ErrShareNotFound, err)
+	default:
+		return err
+	}
+}
@@ -0,0 +1,48 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	pb "github.com/k0st1a/gophkeeper/internal/adapters/api/grpc/gen/proto/v1"
+	"github.com/k0st1a/gophkeeper/internal/pkg/srp"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// newSRPVerifier – создать верификатор SRP пароля пользователя.
+func newSRPVerifier(login, password string) (*pb.SRPVerifier, error) {
+	salt, verifier, err := srp.NewVerifier(login, password)
+	if err != nil {
+		return nil, fmt.Errorf("error of generate srp verifier:%w", err)
+	}
+
+	return &pb.SRPVerifier{
+		Salt:     salt,
+		Verifier: verifier,
+	}, nil
+}
+
+// migrateSRPVerifier – передать на сервер верификатор SRP после логина по паролю, если v не nil.
+// Ошибка не прерывает логин: верификатор будет передан при следующем логине, а до тех пор пароль меняется
+// с передачей старого пароля на сервер.
+func (c *client) migrateSRPVerifier(ctx context.Context, v *pb.SRPVerifier) {
+	if v == nil {
+		return
+	}
+
+	log.Ctx(ctx).Printf("Set srp verifier, login by password is disabled after that")
+
+	_, err := c.usersService.SetSRPVerifier(ctx, &pb.SetSRPVerifierRequest{Verifier: v})
+	if status.Code(err) == codes.AlreadyExists {
+		// Верификатор уже передан, например другим клиентом, пароль меняется по SRP.
+		log.Ctx(ctx).Printf("Srp verifier already set")
+		return
+	}
+
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("error of set srp verifier")
+		c.setLegacy(true)
+	}
+}
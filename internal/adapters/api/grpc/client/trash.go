@@ -0,0 +1,67 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	pb "github.com/k0st1a/gophkeeper/internal/adapters/api/grpc/gen/proto/v1"
+	"github.com/rs/zerolog/log"
+)
+
+type ItemTrash interface {
+	ListTrash(ctx context.Context) ([]TrashItem, error)
+	RestoreItem(ctx context.Context, id int64) (*Item, error)
+}
+
+// ListTrash – получить предметы в корзине, начиная с последнего удаленного.
+func (c *client) ListTrash(ctx context.Context) ([]TrashItem, error) {
+	log.Ctx(ctx).Printf("ListTrash")
+
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	resp, err := c.itemsService.ListTrash(ctx, &pb.ListTrashRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("items service list trash error:%w", err)
+	}
+
+	items := make([]TrashItem, 0, len(resp.Items))
+	for _, i := range resp.Items {
+		l, err := c.openItems(ctx, []*pb.Item{i.Item})
+		if err != nil {
+			return nil, fmt.Errorf("error of open item while list trash:%w", err)
+		}
+
+		items = append(items, TrashItem{
+			Item:       l[0],
+			DeleteTime: i.DeleteTime.AsTime(),
+		})
+	}
+
+	log.Ctx(ctx).Printf("ListTrash success, items:%v", len(items))
+	return items, nil
+}
+
+// RestoreItem – восстановить предмет из корзины.
+func (c *client) RestoreItem(ctx context.Context, id int64) (*Item, error) {
+	log.Ctx(ctx).Printf("RestoreItem, id:%v", id)
+
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	req := &pb.RestoreItemRequest{
+		Id: id,
+	}
+	resp, err := c.itemsService.RestoreItem(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("items service restore item error:%w", parseUpdateItemError(err))
+	}
+
+	items, err := c.openItems(ctx, []*pb.Item{resp.Item})
+	if err != nil {
+		return nil, fmt.Errorf("error of open restored item:%w", err)
+	}
+
+	log.Ctx(ctx).Printf("RestoreItem success, revision:%v", items[0].Revision)
+	return &items[0], nil
+}
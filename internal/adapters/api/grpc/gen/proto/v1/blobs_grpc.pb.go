@@ -0,0 +1,208 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v3.6.1
+// source: blobs.proto
+
+package __
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	BlobsService_GetBlob_FullMethodName      = "/blobs.v1.BlobsService/GetBlob"
+	BlobsService_UploadBlob_FullMethodName   = "/blobs.v1.BlobsService/UploadBlob"
+	BlobsService_DownloadBlob_FullMethodName = "/blobs.v1.BlobsService/DownloadBlob"
+)
+
+// BlobsServiceClient is the client API for BlobsService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// BlobsService is service for transfer of large binary data (blobs) of user by chunks.
+// Blob is referenced by items, its content is encrypted on client side chunk by chunk.
+type BlobsServiceClient interface {
+	// GetBlob gets state of a blob, used to resume interrupted upload.
+	GetBlob(ctx context.Context, in *GetBlobRequest, opts ...grpc.CallOption) (*GetBlobResponse, error)
+	// UploadBlob uploads chunks of a blob. Blob is created by chunk with index 0.
+	// Chunks must be sent in order starting from number of already uploaded chunks.
+	UploadBlob(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UploadBlobRequest, UploadBlobResponse], error)
+	// DownloadBlob downloads chunks of uploaded blob starting from index.
+	DownloadBlob(ctx context.Context, in *DownloadBlobRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[DownloadBlobResponse], error)
+}
+
+type blobsServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBlobsServiceClient(cc grpc.ClientConnInterface) BlobsServiceClient {
+	return &blobsServiceClient{cc}
+}
+
+func (c *blobsServiceClient) GetBlob(ctx context.Context, in *GetBlobRequest, opts ...grpc.CallOption) (*GetBlobResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetBlobResponse)
+	err := c.cc.Invoke(ctx, BlobsService_GetBlob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blobsServiceClient) UploadBlob(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UploadBlobRequest, UploadBlobResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &BlobsService_ServiceDesc.Streams[0], BlobsService_UploadBlob_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[UploadBlobRequest, UploadBlobResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BlobsService_UploadBlobClient = grpc.ClientStreamingClient[UploadBlobRequest, UploadBlobResponse]
+
+func (c *blobsServiceClient) DownloadBlob(ctx context.Context, in *DownloadBlobRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[DownloadBlobResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &BlobsService_ServiceDesc.Streams[1], BlobsService_DownloadBlob_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[DownloadBlobRequest, DownloadBlobResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BlobsService_DownloadBlobClient = grpc.ServerStreamingClient[DownloadBlobResponse]
+
+// BlobsServiceServer is the server API for BlobsService service.
+// All implementations must embed UnimplementedBlobsServiceServer
+// for forward compatibility.
+//
+// BlobsService is service for transfer of large binary data (blobs) of user by chunks.
+// Blob is referenced by items, its content is encrypted on client side chunk by chunk.
+type BlobsServiceServer interface {
+	// GetBlob gets state of a blob, used to resume interrupted upload.
+	GetBlob(context.Context, *GetBlobRequest) (*GetBlobResponse, error)
+	// UploadBlob uploads chunks of a blob. Blob is created by chunk with index 0.
+	// Chunks must be sent in order starting from number of already uploaded chunks.
+	UploadBlob(grpc.ClientStreamingServer[UploadBlobRequest, UploadBlobResponse]) error
+	// DownloadBlob downloads chunks of uploaded blob starting from index.
+	DownloadBlob(*DownloadBlobRequest, grpc.ServerStreamingServer[DownloadBlobResponse]) error
+	mustEmbedUnimplementedBlobsServiceServer()
+}
+
+// UnimplementedBlobsServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedBlobsServiceServer struct{}
+
+func (UnimplementedBlobsServiceServer) GetBlob(context.Context, *GetBlobRequest) (*GetBlobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBlob not implemented")
+}
+func (UnimplementedBlobsServiceServer) UploadBlob(grpc.ClientStreamingServer[UploadBlobRequest, UploadBlobResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method UploadBlob not implemented")
+}
+func (UnimplementedBlobsServiceServer) DownloadBlob(*DownloadBlobRequest, grpc.ServerStreamingServer[DownloadBlobResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method DownloadBlob not implemented")
+}
+func (UnimplementedBlobsServiceServer) mustEmbedUnimplementedBlobsServiceServer() {}
+func (UnimplementedBlobsServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeBlobsServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BlobsServiceServer will
+// result in compilation errors.
+type UnsafeBlobsServiceServer interface {
+	mustEmbedUnimplementedBlobsServiceServer()
+}
+
+func RegisterBlobsServiceServer(s grpc.ServiceRegistrar, srv BlobsServiceServer) {
+	// If the following call pancis, it indicates UnimplementedBlobsServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&BlobsService_ServiceDesc, srv)
+}
+
+func _BlobsService_GetBlob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBlobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlobsServiceServer).GetBlob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BlobsService_GetBlob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlobsServiceServer).GetBlob(ctx, req.(*GetBlobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BlobsService_UploadBlob_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(BlobsServiceServer).UploadBlob(&grpc.GenericServerStream[UploadBlobRequest, UploadBlobResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BlobsService_UploadBlobServer = grpc.ClientStreamingServer[UploadBlobRequest, UploadBlobResponse]
+
+func _BlobsService_DownloadBlob_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DownloadBlobRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BlobsServiceServer).DownloadBlob(m, &grpc.GenericServerStream[DownloadBlobRequest, DownloadBlobResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BlobsService_DownloadBlobServer = grpc.ServerStreamingServer[DownloadBlobResponse]
+
+// BlobsService_ServiceDesc is the grpc.ServiceDesc for BlobsService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var BlobsService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "blobs.v1.BlobsService",
+	HandlerType: (*BlobsServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetBlob",
+			Handler:    _BlobsService_GetBlob_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "UploadBlob",
+			Handler:       _BlobsService_UploadBlob_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "DownloadBlob",
+			Handler:       _BlobsService_DownloadBlob_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "blobs.proto",
+}
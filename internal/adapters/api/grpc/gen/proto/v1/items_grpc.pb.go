@@ -19,11 +19,18 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	ItemsService_CreateItem_FullMethodName = "/items.v3.ItemsService/CreateItem"
-	ItemsService_UpdateItem_FullMethodName = "/items.v3.ItemsService/UpdateItem"
-	ItemsService_GetItem_FullMethodName    = "/items.v3.ItemsService/GetItem"
-	ItemsService_ListItems_FullMethodName  = "/items.v3.ItemsService/ListItems"
-	ItemsService_DeleteItem_FullMethodName = "/items.v3.ItemsService/DeleteItem"
+	ItemsService_CreateItem_FullMethodName          = "/items.v3.ItemsService/CreateItem"
+	ItemsService_UpdateItem_FullMethodName          = "/items.v3.ItemsService/UpdateItem"
+	ItemsService_GetItem_FullMethodName             = "/items.v3.ItemsService/GetItem"
+	ItemsService_ListItems_FullMethodName           = "/items.v3.ItemsService/ListItems"
+	ItemsService_DeleteItem_FullMethodName          = "/items.v3.ItemsService/DeleteItem"
+	ItemsService_ListTrash_FullMethodName           = "/items.v3.ItemsService/ListTrash"
+	ItemsService_RestoreItem_FullMethodName         = "/items.v3.ItemsService/RestoreItem"
+	ItemsService_ListChanges_FullMethodName         = "/items.v3.ItemsService/ListChanges"
+	ItemsService_WatchItems_FullMethodName          = "/items.v3.ItemsService/WatchItems"
+	ItemsService_ListItemVersions_FullMethodName    = "/items.v3.ItemsService/ListItemVersions"
+	ItemsService_RestoreItemVersion_FullMethodName  = "/items.v3.ItemsService/RestoreItemVersion"
+	ItemsService_SetHistoryRetention_FullMethodName = "/items.v3.ItemsService/SetHistoryRetention"
 )
 
 // ItemsServiceClient is the client API for ItemsService service.
@@ -34,14 +41,32 @@ const (
 type ItemsServiceClient interface {
 	// Create creates a item.
 	CreateItem(ctx context.Context, in *CreateItemRequest, opts ...grpc.CallOption) (*CreateItemResponse, error)
-	// UpdateItemData updates field data of a item.
+	// UpdateItem updates field data of a item.
+	// Returns Aborted with RevisionConflict in details, if item was changed since expected revision.
 	UpdateItem(ctx context.Context, in *UpdateItemRequest, opts ...grpc.CallOption) (*UpdateItemResponse, error)
 	// Get gets a item.
 	GetItem(ctx context.Context, in *GetItemRequest, opts ...grpc.CallOption) (*GetItemResponse, error)
 	// List gets lists of items.
 	ListItems(ctx context.Context, in *ListItemsRequest, opts ...grpc.CallOption) (*ListItemsResponse, error)
-	// Delete deletes a item.
+	// Delete moves a item to trash, item is purged after trash retention period.
 	DeleteItem(ctx context.Context, in *DeleteItemRequest, opts ...grpc.CallOption) (*DeleteItemResponse, error)
+	// ListTrash gets items in trash.
+	ListTrash(ctx context.Context, in *ListTrashRequest, opts ...grpc.CallOption) (*ListTrashResponse, error)
+	// RestoreItem restores a item from trash.
+	RestoreItem(ctx context.Context, in *RestoreItemRequest, opts ...grpc.CallOption) (*RestoreItemResponse, error)
+	// ListChanges gets items created, updated or deleted since cursor.
+	ListChanges(ctx context.Context, in *ListChangesRequest, opts ...grpc.CallOption) (*ListChangesResponse, error)
+	// WatchItems notifies about changes of items of user.
+	// First notification is sent right after subscription, so changes made before it are not missed.
+	WatchItems(ctx context.Context, in *WatchItemsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchItemsResponse], error)
+	// ListItemVersions gets previous versions of item, newest first.
+	// Versions are kept on each update and delete of item, up to history retention of user.
+	ListItemVersions(ctx context.Context, in *ListItemVersionsRequest, opts ...grpc.CallOption) (*ListItemVersionsResponse, error)
+	// RestoreItemVersion makes previous version of item its current version, deleted item is created again.
+	// Returns Aborted with RevisionConflict in details, if item was changed since expected revision.
+	RestoreItemVersion(ctx context.Context, in *RestoreItemVersionRequest, opts ...grpc.CallOption) (*RestoreItemVersionResponse, error)
+	// SetHistoryRetention sets number of previous versions kept for each item of user.
+	SetHistoryRetention(ctx context.Context, in *SetHistoryRetentionRequest, opts ...grpc.CallOption) (*SetHistoryRetentionResponse, error)
 }
 
 type itemsServiceClient struct {
@@ -102,6 +127,85 @@ func (c *itemsServiceClient) DeleteItem(ctx context.Context, in *DeleteItemReque
 	return out, nil
 }
 
+func (c *itemsServiceClient) ListTrash(ctx context.Context, in *ListTrashRequest, opts ...grpc.CallOption) (*ListTrashResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTrashResponse)
+	err := c.cc.Invoke(ctx, ItemsService_ListTrash_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *itemsServiceClient) RestoreItem(ctx context.Context, in *RestoreItemRequest, opts ...grpc.CallOption) (*RestoreItemResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RestoreItemResponse)
+	err := c.cc.Invoke(ctx, ItemsService_RestoreItem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *itemsServiceClient) ListChanges(ctx context.Context, in *ListChangesRequest, opts ...grpc.CallOption) (*ListChangesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListChangesResponse)
+	err := c.cc.Invoke(ctx, ItemsService_ListChanges_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *itemsServiceClient) WatchItems(ctx context.Context, in *WatchItemsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchItemsResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ItemsService_ServiceDesc.Streams[0], ItemsService_WatchItems_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchItemsRequest, WatchItemsResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ItemsService_WatchItemsClient = grpc.ServerStreamingClient[WatchItemsResponse]
+
+func (c *itemsServiceClient) ListItemVersions(ctx context.Context, in *ListItemVersionsRequest, opts ...grpc.CallOption) (*ListItemVersionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListItemVersionsResponse)
+	err := c.cc.Invoke(ctx, ItemsService_ListItemVersions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *itemsServiceClient) RestoreItemVersion(ctx context.Context, in *RestoreItemVersionRequest, opts ...grpc.CallOption) (*RestoreItemVersionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RestoreItemVersionResponse)
+	err := c.cc.Invoke(ctx, ItemsService_RestoreItemVersion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *itemsServiceClient) SetHistoryRetention(ctx context.Context, in *SetHistoryRetentionRequest, opts ...grpc.CallOption) (*SetHistoryRetentionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetHistoryRetentionResponse)
+	err := c.cc.Invoke(ctx, ItemsService_SetHistoryRetention_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ItemsServiceServer is the server API for ItemsService service.
 // All implementations must embed UnimplementedItemsServiceServer
 // for forward compatibility.
@@ -110,14 +214,32 @@ func (c *itemsServiceClient) DeleteItem(ctx context.Context, in *DeleteItemReque
 type ItemsServiceServer interface {
 	// Create creates a item.
 	CreateItem(context.Context, *CreateItemRequest) (*CreateItemResponse, error)
-	// UpdateItemData updates field data of a item.
+	// UpdateItem updates field data of a item.
+	// Returns Aborted with RevisionConflict in details, if item was changed since expected revision.
 	UpdateItem(context.Context, *UpdateItemRequest) (*UpdateItemResponse, error)
 	// Get gets a item.
 	GetItem(context.Context, *GetItemRequest) (*GetItemResponse, error)
 	// List gets lists of items.
 	ListItems(context.Context, *ListItemsRequest) (*ListItemsResponse, error)
-	// Delete deletes a item.
+	// Delete moves a item to trash, item is purged after trash retention period.
 	DeleteItem(context.Context, *DeleteItemRequest) (*DeleteItemResponse, error)
+	// ListTrash gets items in trash.
+	ListTrash(context.Context, *ListTrashRequest) (*ListTrashResponse, error)
+	// RestoreItem restores a item from trash.
+	RestoreItem(context.Context, *RestoreItemRequest) (*RestoreItemResponse, error)
+	// ListChanges gets items created, updated or deleted since cursor.
+	ListChanges(context.Context, *ListChangesRequest) (*ListChangesResponse, error)
+	// WatchItems notifies about changes of items of user.
+	// First notification is sent right after subscription, so changes made before it are not missed.
+	WatchItems(*WatchItemsRequest, grpc.ServerStreamingServer[WatchItemsResponse]) error
+	// ListItemVersions gets previous versions of item, newest first.
+	// Versions are kept on each update and delete of item, up to history retention of user.
+	ListItemVersions(context.Context, *ListItemVersionsRequest) (*ListItemVersionsResponse, error)
+	// RestoreItemVersion makes previous version of item its current version, deleted item is created again.
+	// Returns Aborted with RevisionConflict in details, if item was changed since expected revision.
+	RestoreItemVersion(context.Context, *RestoreItemVersionRequest) (*RestoreItemVersionResponse, error)
+	// SetHistoryRetention sets number of previous versions kept for each item of user.
+	SetHistoryRetention(context.Context, *SetHistoryRetentionRequest) (*SetHistoryRetentionResponse, error)
 	mustEmbedUnimplementedItemsServiceServer()
 }
 
@@ -143,6 +265,27 @@ func (UnimplementedItemsServiceServer) ListItems(context.Context, *ListItemsRequ
 func (UnimplementedItemsServiceServer) DeleteItem(context.Context, *DeleteItemRequest) (*DeleteItemResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteItem not implemented")
 }
+func (UnimplementedItemsServiceServer) ListTrash(context.Context, *ListTrashRequest) (*ListTrashResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTrash not implemented")
+}
+func (UnimplementedItemsServiceServer) RestoreItem(context.Context, *RestoreItemRequest) (*RestoreItemResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreItem not implemented")
+}
+func (UnimplementedItemsServiceServer) ListChanges(context.Context, *ListChangesRequest) (*ListChangesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListChanges not implemented")
+}
+func (UnimplementedItemsServiceServer) WatchItems(*WatchItemsRequest, grpc.ServerStreamingServer[WatchItemsResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchItems not implemented")
+}
+func (UnimplementedItemsServiceServer) ListItemVersions(context.Context, *ListItemVersionsRequest) (*ListItemVersionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListItemVersions not implemented")
+}
+func (UnimplementedItemsServiceServer) RestoreItemVersion(context.Context, *RestoreItemVersionRequest) (*RestoreItemVersionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreItemVersion not implemented")
+}
+func (UnimplementedItemsServiceServer) SetHistoryRetention(context.Context, *SetHistoryRetentionRequest) (*SetHistoryRetentionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetHistoryRetention not implemented")
+}
 func (UnimplementedItemsServiceServer) mustEmbedUnimplementedItemsServiceServer() {}
 func (UnimplementedItemsServiceServer) testEmbeddedByValue()                      {}
 
@@ -254,6 +397,125 @@ func _ItemsService_DeleteItem_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ItemsService_ListTrash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTrashRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ItemsServiceServer).ListTrash(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ItemsService_ListTrash_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ItemsServiceServer).ListTrash(ctx, req.(*ListTrashRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ItemsService_RestoreItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ItemsServiceServer).RestoreItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ItemsService_RestoreItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ItemsServiceServer).RestoreItem(ctx, req.(*RestoreItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ItemsService_ListChanges_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListChangesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ItemsServiceServer).ListChanges(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ItemsService_ListChanges_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ItemsServiceServer).ListChanges(ctx, req.(*ListChangesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ItemsService_WatchItems_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchItemsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ItemsServiceServer).WatchItems(m, &grpc.GenericServerStream[WatchItemsRequest, WatchItemsResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ItemsService_WatchItemsServer = grpc.ServerStreamingServer[WatchItemsResponse]
+
+func _ItemsService_ListItemVersions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListItemVersionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ItemsServiceServer).ListItemVersions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ItemsService_ListItemVersions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ItemsServiceServer).ListItemVersions(ctx, req.(*ListItemVersionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ItemsService_RestoreItemVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreItemVersionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ItemsServiceServer).RestoreItemVersion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ItemsService_RestoreItemVersion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ItemsServiceServer).RestoreItemVersion(ctx, req.(*RestoreItemVersionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ItemsService_SetHistoryRetention_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetHistoryRetentionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ItemsServiceServer).SetHistoryRetention(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ItemsService_SetHistoryRetention_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ItemsServiceServer).SetHistoryRetention(ctx, req.(*SetHistoryRetentionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ItemsService_ServiceDesc is the grpc.ServiceDesc for ItemsService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -281,7 +543,37 @@ var ItemsService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteItem",
 			Handler:    _ItemsService_DeleteItem_Handler,
 		},
+		{
+			MethodName: "ListTrash",
+			Handler:    _ItemsService_ListTrash_Handler,
+		},
+		{
+			MethodName: "RestoreItem",
+			Handler:    _ItemsService_RestoreItem_Handler,
+		},
+		{
+			MethodName: "ListChanges",
+			Handler:    _ItemsService_ListChanges_Handler,
+		},
+		{
+			MethodName: "ListItemVersions",
+			Handler:    _ItemsService_ListItemVersions_Handler,
+		},
+		{
+			MethodName: "RestoreItemVersion",
+			Handler:    _ItemsService_RestoreItemVersion_Handler,
+		},
+		{
+			MethodName: "SetHistoryRetention",
+			Handler:    _ItemsService_SetHistoryRetention_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchItems",
+			Handler:       _ItemsService_WatchItems_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "items.proto",
 }
@@ -0,0 +1,291 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v3.6.1
+// source: shares.proto
+
+package __
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	SharesService_GetPublicKey_FullMethodName     = "/shares.v1.SharesService/GetPublicKey"
+	SharesService_ShareItem_FullMethodName        = "/shares.v1.SharesService/ShareItem"
+	SharesService_ListShares_FullMethodName       = "/shares.v1.SharesService/ListShares"
+	SharesService_ListSharedWithMe_FullMethodName = "/shares.v1.SharesService/ListSharedWithMe"
+	SharesService_RevokeShare_FullMethodName      = "/shares.v1.SharesService/RevokeShare"
+)
+
+// SharesServiceClient is the client API for SharesService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// SharesService is service for sharing items with other users.
+// Shared item is a copy of the item encrypted by the public key of the recipient,
+// sharing the same item with the same user again replaces the copy.
+type SharesServiceClient interface {
+	// GetPublicKey gets the public key of a user for encrypting a copy of the item.
+	GetPublicKey(ctx context.Context, in *GetPublicKeyRequest, opts ...grpc.CallOption) (*GetPublicKeyResponse, error)
+	// ShareItem shares a copy of the item of the user with another user.
+	ShareItem(ctx context.Context, in *ShareItemRequest, opts ...grpc.CallOption) (*ShareItemResponse, error)
+	// ListShares lists shares created by the user.
+	ListShares(ctx context.Context, in *ListSharesRequest, opts ...grpc.CallOption) (*ListSharesResponse, error)
+	// ListSharedWithMe lists items shared with the user, items in trash of the owner are not listed.
+	ListSharedWithMe(ctx context.Context, in *ListSharedWithMeRequest, opts ...grpc.CallOption) (*ListSharedWithMeResponse, error)
+	// RevokeShare deletes a share, both the owner and the recipient can revoke it.
+	RevokeShare(ctx context.Context, in *RevokeShareRequest, opts ...grpc.CallOption) (*RevokeShareResponse, error)
+}
+
+type sharesServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSharesServiceClient(cc grpc.ClientConnInterface) SharesServiceClient {
+	return &sharesServiceClient{cc}
+}
+
+func (c *sharesServiceClient) GetPublicKey(ctx context.Context, in *GetPublicKeyRequest, opts ...grpc.CallOption) (*GetPublicKeyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetPublicKeyResponse)
+	err := c.cc.Invoke(ctx, SharesService_GetPublicKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sharesServiceClient) ShareItem(ctx context.Context, in *ShareItemRequest, opts ...grpc.CallOption) (*ShareItemResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ShareItemResponse)
+	err := c.cc.Invoke(ctx, SharesService_ShareItem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sharesServiceClient) ListShares(ctx context.Context, in *ListSharesRequest, opts ...grpc.CallOption) (*ListSharesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSharesResponse)
+	err := c.cc.Invoke(ctx, SharesService_ListShares_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sharesServiceClient) ListSharedWithMe(ctx context.Context, in *ListSharedWithMeRequest, opts ...grpc.CallOption) (*ListSharedWithMeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSharedWithMeResponse)
+	err := c.cc.Invoke(ctx, SharesService_ListSharedWithMe_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sharesServiceClient) RevokeShare(ctx context.Context, in *RevokeShareRequest, opts ...grpc.CallOption) (*RevokeShareResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevokeShareResponse)
+	err := c.cc.Invoke(ctx, SharesService_RevokeShare_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SharesServiceServer is the server API for SharesService service.
+// All implementations must embed UnimplementedSharesServiceServer
+// for forward compatibility.
+//
+// SharesService is service for sharing items with other users.
+// Shared item is a copy of the item encrypted by the public key of the recipient,
+// sharing the same item with the same user again replaces the copy.
+type SharesServiceServer interface {
+	// GetPublicKey gets the public key of a user for encrypting a copy of the item.
+	GetPublicKey(context.Context, *GetPublicKeyRequest) (*GetPublicKeyResponse, error)
+	// ShareItem shares a copy of the item of the user with another user.
+	ShareItem(context.Context, *ShareItemRequest) (*ShareItemResponse, error)
+	// ListShares lists shares created by the user.
+	ListShares(context.Context, *ListSharesRequest) (*ListSharesResponse, error)
+	// ListSharedWithMe lists items shared with the user, items in trash of the owner are not listed.
+	ListSharedWithMe(context.Context, *ListSharedWithMeRequest) (*ListSharedWithMeResponse, error)
+	// RevokeShare deletes a share, both the owner and the recipient can revoke it.
+	RevokeShare(context.Context, *RevokeShareRequest) (*RevokeShareResponse, error)
+	mustEmbedUnimplementedSharesServiceServer()
+}
+
+// UnimplementedSharesServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedSharesServiceServer struct{}
+
+func (UnimplementedSharesServiceServer) GetPublicKey(context.Context, *GetPublicKeyRequest) (*GetPublicKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPublicKey not implemented")
+}
+func (UnimplementedSharesServiceServer) ShareItem(context.Context, *ShareItemRequest) (*ShareItemResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ShareItem not implemented")
+}
+func (UnimplementedSharesServiceServer) ListShares(context.Context, *ListSharesRequest) (*ListSharesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListShares not implemented")
+}
+func (UnimplementedSharesServiceServer) ListSharedWithMe(context.Context, *ListSharedWithMeRequest) (*ListSharedWithMeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSharedWithMe not implemented")
+}
+func (UnimplementedSharesServiceServer) RevokeShare(context.Context, *RevokeShareRequest) (*RevokeShareResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeShare not implemented")
+}
+func (UnimplementedSharesServiceServer) mustEmbedUnimplementedSharesServiceServer() {}
+func (UnimplementedSharesServiceServer) testEmbeddedByValue()                       {}
+
+// UnsafeSharesServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SharesServiceServer will
+// result in compilation errors.
+type UnsafeSharesServiceServer interface {
+	mustEmbedUnimplementedSharesServiceServer()
+}
+
+func RegisterSharesServiceServer(s grpc.ServiceRegistrar, srv SharesServiceServer) {
+	// If the following call pancis, it indicates UnimplementedSharesServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&SharesService_ServiceDesc, srv)
+}
+
+func _SharesService_GetPublicKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPublicKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SharesServiceServer).GetPublicKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SharesService_GetPublicKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SharesServiceServer).GetPublicKey(ctx, req.(*GetPublicKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SharesService_ShareItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShareItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SharesServiceServer).ShareItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SharesService_ShareItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SharesServiceServer).ShareItem(ctx, req.(*ShareItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SharesService_ListShares_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSharesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SharesServiceServer).ListShares(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SharesService_ListShares_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SharesServiceServer).ListShares(ctx, req.(*ListSharesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SharesService_ListSharedWithMe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSharedWithMeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SharesServiceServer).ListSharedWithMe(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SharesService_ListSharedWithMe_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SharesServiceServer).ListSharedWithMe(ctx, req.(*ListSharedWithMeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SharesService_RevokeShare_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeShareRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SharesServiceServer).RevokeShare(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SharesService_RevokeShare_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SharesServiceServer).RevokeShare(ctx, req.(*RevokeShareRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// SharesService_ServiceDesc is the grpc.ServiceDesc for SharesService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SharesService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "shares.v1.SharesService",
+	HandlerType: (*SharesServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetPublicKey",
+			Handler:    _SharesService_GetPublicKey_Handler,
+		},
+		{
+			MethodName: "ShareItem",
+			Handler:    _SharesService_ShareItem_Handler,
+		},
+		{
+			MethodName: "ListShares",
+			Handler:    _SharesService_ListShares_Handler,
+		},
+		{
+			MethodName: "ListSharedWithMe",
+			Handler:    _SharesService_ListSharedWithMe_Handler,
+		},
+		{
+			MethodName: "RevokeShare",
+			Handler:    _SharesService_RevokeShare_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "shares.proto",
+}
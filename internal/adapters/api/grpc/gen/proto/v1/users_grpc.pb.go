@@ -19,8 +19,26 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	UsersService_Register_FullMethodName = "/users.v1.UsersService/Register"
-	UsersService_Login_FullMethodName    = "/users.v1.UsersService/Login"
+	UsersService_Register_FullMethodName            = "/users.v1.UsersService/Register"
+	UsersService_Login_FullMethodName               = "/users.v1.UsersService/Login"
+	UsersService_StartLogin_FullMethodName          = "/users.v1.UsersService/StartLogin"
+	UsersService_FinishLogin_FullMethodName         = "/users.v1.UsersService/FinishLogin"
+	UsersService_VerifySecondFactor_FullMethodName  = "/users.v1.UsersService/VerifySecondFactor"
+	UsersService_RefreshToken_FullMethodName        = "/users.v1.UsersService/RefreshToken"
+	UsersService_Logout_FullMethodName              = "/users.v1.UsersService/Logout"
+	UsersService_ListSessions_FullMethodName        = "/users.v1.UsersService/ListSessions"
+	UsersService_RevokeSession_FullMethodName       = "/users.v1.UsersService/RevokeSession"
+	UsersService_RevokeOtherSessions_FullMethodName = "/users.v1.UsersService/RevokeOtherSessions"
+	UsersService_SetVaultKey_FullMethodName         = "/users.v1.UsersService/SetVaultKey"
+	UsersService_SetKeyPair_FullMethodName          = "/users.v1.UsersService/SetKeyPair"
+	UsersService_SetSRPVerifier_FullMethodName      = "/users.v1.UsersService/SetSRPVerifier"
+	UsersService_ChangePassword_FullMethodName      = "/users.v1.UsersService/ChangePassword"
+	UsersService_SetRecoveryKey_FullMethodName      = "/users.v1.UsersService/SetRecoveryKey"
+	UsersService_StartRecovery_FullMethodName       = "/users.v1.UsersService/StartRecovery"
+	UsersService_Recover_FullMethodName             = "/users.v1.UsersService/Recover"
+	UsersService_EnrollSecondFactor_FullMethodName  = "/users.v1.UsersService/EnrollSecondFactor"
+	UsersService_ConfirmSecondFactor_FullMethodName = "/users.v1.UsersService/ConfirmSecondFactor"
+	UsersService_DisableSecondFactor_FullMethodName = "/users.v1.UsersService/DisableSecondFactor"
 )
 
 // UsersServiceClient is the client API for UsersService service.
@@ -29,10 +47,66 @@ const (
 //
 // UsersService is service for users managments.
 type UsersServiceClient interface {
-	// Register registers a new user.
+	// Register registers a new user by SRP verifier of the password, the password is not sent to the server.
 	Register(ctx context.Context, in *RegisterRequest, opts ...grpc.CallOption) (*RegisterResponse, error)
-	// Login logs in a user and returns an auth token.
+	// Login logs in a user by password and returns an auth token and a refresh token.
+	// If the user has enabled second factor, then only a challenge is returned, see VerifySecondFactor.
+	// Only for users registered before SRP: client should use StartLogin and FinishLogin, and after login
+	// by password it should set SRP verifier by SetSRPVerifier, after that login by password is disabled.
 	Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginResponse, error)
+	// StartLogin starts SRP-6a login: client sends A and gets salt and B. Login must be finished by FinishLogin
+	// in 5 minutes. The answer for a user without SRP verifier is the same as for an unknown login, so such
+	// users log in by Login only if the client allows it. Every call is counted against the IP address.
+	StartLogin(ctx context.Context, in *StartLoginRequest, opts ...grpc.CallOption) (*StartLoginResponse, error)
+	// FinishLogin finishes SRP-6a login by client proof M1 and returns server proof M2 together with
+	// an auth token and a refresh token, or a challenge if the user has enabled second factor.
+	FinishLogin(ctx context.Context, in *FinishLoginRequest, opts ...grpc.CallOption) (*LoginResponse, error)
+	// VerifySecondFactor completes login by a challenge from Login and a TOTP code or a backup code.
+	// Challenge expires in 5 minutes or after 5 wrong codes.
+	VerifySecondFactor(ctx context.Context, in *VerifySecondFactorRequest, opts ...grpc.CallOption) (*LoginResponse, error)
+	// RefreshToken returns a new auth token in exchange of a refresh token.
+	// Refresh token is rotated: the passed one becomes invalid and a new one is returned.
+	// Reuse of a rotated refresh token revokes all refresh tokens issued since the login.
+	RefreshToken(ctx context.Context, in *RefreshTokenRequest, opts ...grpc.CallOption) (*RefreshTokenResponse, error)
+	// Logout revokes the session of the auth token together with its refresh tokens.
+	Logout(ctx context.Context, in *LogoutRequest, opts ...grpc.CallOption) (*LogoutResponse, error)
+	// ListSessions lists active sessions of the user, most recently seen first.
+	ListSessions(ctx context.Context, in *ListSessionsRequest, opts ...grpc.CallOption) (*ListSessionsResponse, error)
+	// RevokeSession revokes a session of the user, its tokens are not accepted anymore.
+	RevokeSession(ctx context.Context, in *RevokeSessionRequest, opts ...grpc.CallOption) (*RevokeSessionResponse, error)
+	// RevokeOtherSessions revokes all sessions of the user except the session of the auth token.
+	RevokeOtherSessions(ctx context.Context, in *RevokeOtherSessionsRequest, opts ...grpc.CallOption) (*RevokeOtherSessionsResponse, error)
+	// SetVaultKey sets the wrapped vault key of a user registered without it.
+	SetVaultKey(ctx context.Context, in *SetVaultKeyRequest, opts ...grpc.CallOption) (*SetVaultKeyResponse, error)
+	// SetKeyPair sets the X25519 key pair of a user registered without it, the public key is published
+	// for sharing items with the user.
+	SetKeyPair(ctx context.Context, in *SetKeyPairRequest, opts ...grpc.CallOption) (*SetKeyPairResponse, error)
+	// SetSRPVerifier sets SRP verifier of the user password and disables login by password.
+	// Only for users registered before SRP: if the verifier is already set, then AlreadyExists is returned,
+	// the password is changed by ChangePassword.
+	SetSRPVerifier(ctx context.Context, in *SetSRPVerifierRequest, opts ...grpc.CallOption) (*SetSRPVerifierResponse, error)
+	// ChangePassword changes the password of the user. The old password is proven by SRP: client calls StartLogin
+	// and sends the proof of the old password, or the old password itself if the user has no SRP verifier yet.
+	// SRP verifier and re-wrapped vault key are replaced atomically, all other sessions of the user are revoked.
+	ChangePassword(ctx context.Context, in *ChangePasswordRequest, opts ...grpc.CallOption) (*ChangePasswordResponse, error)
+	// SetRecoveryKey sets the vault key wrapped by a new recovery key of the user, the previous recovery key
+	// stops working. The current password is confirmed as in ChangePassword, otherwise a stolen auth token would be
+	// enough to plant a known recovery key and take over the account by Recover. Other sessions are revoked.
+	SetRecoveryKey(ctx context.Context, in *SetRecoveryKeyRequest, opts ...grpc.CallOption) (*SetRecoveryKeyResponse, error)
+	// StartRecovery returns the vault key wrapped by the recovery key of the user for the user who lost the password.
+	// Failed attempts are limited as failed logins.
+	StartRecovery(ctx context.Context, in *StartRecoveryRequest, opts ...grpc.CallOption) (*StartRecoveryResponse, error)
+	// Recover sets a new password of the user by the recovery key, disables the second factor and revokes all sessions
+	// of the user.
+	// Failed attempts are limited as failed logins.
+	Recover(ctx context.Context, in *RecoverRequest, opts ...grpc.CallOption) (*RecoverResponse, error)
+	// EnrollSecondFactor creates a TOTP secret and backup codes of the user.
+	// Second factor is required on login only after confirmation by ConfirmSecondFactor.
+	EnrollSecondFactor(ctx context.Context, in *EnrollSecondFactorRequest, opts ...grpc.CallOption) (*EnrollSecondFactorResponse, error)
+	// ConfirmSecondFactor enables enrolled second factor by a TOTP code.
+	ConfirmSecondFactor(ctx context.Context, in *ConfirmSecondFactorRequest, opts ...grpc.CallOption) (*ConfirmSecondFactorResponse, error)
+	// DisableSecondFactor disables second factor by a TOTP code or a backup code.
+	DisableSecondFactor(ctx context.Context, in *DisableSecondFactorRequest, opts ...grpc.CallOption) (*DisableSecondFactorResponse, error)
 }
 
 type usersServiceClient struct {
@@ -63,16 +137,252 @@ func (c *usersServiceClient) Login(ctx context.Context, in *LoginRequest, opts .
 	return out, nil
 }
 
+func (c *usersServiceClient) StartLogin(ctx context.Context, in *StartLoginRequest, opts ...grpc.CallOption) (*StartLoginResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StartLoginResponse)
+	err := c.cc.Invoke(ctx, UsersService_StartLogin_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersServiceClient) FinishLogin(ctx context.Context, in *FinishLoginRequest, opts ...grpc.CallOption) (*LoginResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LoginResponse)
+	err := c.cc.Invoke(ctx, UsersService_FinishLogin_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersServiceClient) VerifySecondFactor(ctx context.Context, in *VerifySecondFactorRequest, opts ...grpc.CallOption) (*LoginResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LoginResponse)
+	err := c.cc.Invoke(ctx, UsersService_VerifySecondFactor_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersServiceClient) RefreshToken(ctx context.Context, in *RefreshTokenRequest, opts ...grpc.CallOption) (*RefreshTokenResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RefreshTokenResponse)
+	err := c.cc.Invoke(ctx, UsersService_RefreshToken_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersServiceClient) Logout(ctx context.Context, in *LogoutRequest, opts ...grpc.CallOption) (*LogoutResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LogoutResponse)
+	err := c.cc.Invoke(ctx, UsersService_Logout_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersServiceClient) ListSessions(ctx context.Context, in *ListSessionsRequest, opts ...grpc.CallOption) (*ListSessionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSessionsResponse)
+	err := c.cc.Invoke(ctx, UsersService_ListSessions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersServiceClient) RevokeSession(ctx context.Context, in *RevokeSessionRequest, opts ...grpc.CallOption) (*RevokeSessionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevokeSessionResponse)
+	err := c.cc.Invoke(ctx, UsersService_RevokeSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersServiceClient) RevokeOtherSessions(ctx context.Context, in *RevokeOtherSessionsRequest, opts ...grpc.CallOption) (*RevokeOtherSessionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevokeOtherSessionsResponse)
+	err := c.cc.Invoke(ctx, UsersService_RevokeOtherSessions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersServiceClient) SetVaultKey(ctx context.Context, in *SetVaultKeyRequest, opts ...grpc.CallOption) (*SetVaultKeyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetVaultKeyResponse)
+	err := c.cc.Invoke(ctx, UsersService_SetVaultKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersServiceClient) SetKeyPair(ctx context.Context, in *SetKeyPairRequest, opts ...grpc.CallOption) (*SetKeyPairResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetKeyPairResponse)
+	err := c.cc.Invoke(ctx, UsersService_SetKeyPair_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersServiceClient) SetSRPVerifier(ctx context.Context, in *SetSRPVerifierRequest, opts ...grpc.CallOption) (*SetSRPVerifierResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetSRPVerifierResponse)
+	err := c.cc.Invoke(ctx, UsersService_SetSRPVerifier_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersServiceClient) ChangePassword(ctx context.Context, in *ChangePasswordRequest, opts ...grpc.CallOption) (*ChangePasswordResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ChangePasswordResponse)
+	err := c.cc.Invoke(ctx, UsersService_ChangePassword_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersServiceClient) SetRecoveryKey(ctx context.Context, in *SetRecoveryKeyRequest, opts ...grpc.CallOption) (*SetRecoveryKeyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetRecoveryKeyResponse)
+	err := c.cc.Invoke(ctx, UsersService_SetRecoveryKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersServiceClient) StartRecovery(ctx context.Context, in *StartRecoveryRequest, opts ...grpc.CallOption) (*StartRecoveryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StartRecoveryResponse)
+	err := c.cc.Invoke(ctx, UsersService_StartRecovery_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersServiceClient) Recover(ctx context.Context, in *RecoverRequest, opts ...grpc.CallOption) (*RecoverResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RecoverResponse)
+	err := c.cc.Invoke(ctx, UsersService_Recover_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersServiceClient) EnrollSecondFactor(ctx context.Context, in *EnrollSecondFactorRequest, opts ...grpc.CallOption) (*EnrollSecondFactorResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EnrollSecondFactorResponse)
+	err := c.cc.Invoke(ctx, UsersService_EnrollSecondFactor_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersServiceClient) ConfirmSecondFactor(ctx context.Context, in *ConfirmSecondFactorRequest, opts ...grpc.CallOption) (*ConfirmSecondFactorResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ConfirmSecondFactorResponse)
+	err := c.cc.Invoke(ctx, UsersService_ConfirmSecondFactor_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersServiceClient) DisableSecondFactor(ctx context.Context, in *DisableSecondFactorRequest, opts ...grpc.CallOption) (*DisableSecondFactorResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DisableSecondFactorResponse)
+	err := c.cc.Invoke(ctx, UsersService_DisableSecondFactor_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // UsersServiceServer is the server API for UsersService service.
 // All implementations must embed UnimplementedUsersServiceServer
 // for forward compatibility.
 //
 // UsersService is service for users managments.
 type UsersServiceServer interface {
-	// Register registers a new user.
+	// Register registers a new user by SRP verifier of the password, the password is not sent to the server.
 	Register(context.Context, *RegisterRequest) (*RegisterResponse, error)
-	// Login logs in a user and returns an auth token.
+	// Login logs in a user by password and returns an auth token and a refresh token.
+	// If the user has enabled second factor, then only a challenge is returned, see VerifySecondFactor.
+	// Only for users registered before SRP: client should use StartLogin and FinishLogin, and after login
+	// by password it should set SRP verifier by SetSRPVerifier, after that login by password is disabled.
 	Login(context.Context, *LoginRequest) (*LoginResponse, error)
+	// StartLogin starts SRP-6a login: client sends A and gets salt and B. Login must be finished by FinishLogin
+	// in 5 minutes. The answer for a user without SRP verifier is the same as for an unknown login, so such
+	// users log in by Login only if the client allows it. Every call is counted against the IP address.
+	StartLogin(context.Context, *StartLoginRequest) (*StartLoginResponse, error)
+	// FinishLogin finishes SRP-6a login by client proof M1 and returns server proof M2 together with
+	// an auth token and a refresh token, or a challenge if the user has enabled second factor.
+	FinishLogin(context.Context, *FinishLoginRequest) (*LoginResponse, error)
+	// VerifySecondFactor completes login by a challenge from Login and a TOTP code or a backup code.
+	// Challenge expires in 5 minutes or after 5 wrong codes.
+	VerifySecondFactor(context.Context, *VerifySecondFactorRequest) (*LoginResponse, error)
+	// RefreshToken returns a new auth token in exchange of a refresh token.
+	// Refresh token is rotated: the passed one becomes invalid and a new one is returned.
+	// Reuse of a rotated refresh token revokes all refresh tokens issued since the login.
+	RefreshToken(context.Context, *RefreshTokenRequest) (*RefreshTokenResponse, error)
+	// Logout revokes the session of the auth token together with its refresh tokens.
+	Logout(context.Context, *LogoutRequest) (*LogoutResponse, error)
+	// ListSessions lists active sessions of the user, most recently seen first.
+	ListSessions(context.Context, *ListSessionsRequest) (*ListSessionsResponse, error)
+	// RevokeSession revokes a session of the user, its tokens are not accepted anymore.
+	RevokeSession(context.Context, *RevokeSessionRequest) (*RevokeSessionResponse, error)
+	// RevokeOtherSessions revokes all sessions of the user except the session of the auth token.
+	RevokeOtherSessions(context.Context, *RevokeOtherSessionsRequest) (*RevokeOtherSessionsResponse, error)
+	// SetVaultKey sets the wrapped vault key of a user registered without it.
+	SetVaultKey(context.Context, *SetVaultKeyRequest) (*SetVaultKeyResponse, error)
+	// SetKeyPair sets the X25519 key pair of a user registered without it, the public key is published
+	// for sharing items with the user.
+	SetKeyPair(context.Context, *SetKeyPairRequest) (*SetKeyPairResponse, error)
+	// SetSRPVerifier sets SRP verifier of the user password and disables login by password.
+	// Only for users registered before SRP: if the verifier is already set, then AlreadyExists is returned,
+	// the password is changed by ChangePassword.
+	SetSRPVerifier(context.Context, *SetSRPVerifierRequest) (*SetSRPVerifierResponse, error)
+	// ChangePassword changes the password of the user. The old password is proven by SRP: client calls StartLogin
+	// and sends the proof of the old password, or the old password itself if the user has no SRP verifier yet.
+	// SRP verifier and re-wrapped vault key are replaced atomically, all other sessions of the user are revoked.
+	ChangePassword(context.Context, *ChangePasswordRequest) (*ChangePasswordResponse, error)
+	// SetRecoveryKey sets the vault key wrapped by a new recovery key of the user, the previous recovery key
+	// stops working. The current password is confirmed as in ChangePassword, otherwise a stolen auth token would be
+	// enough to plant a known recovery key and take over the account by Recover. Other sessions are revoked.
+	SetRecoveryKey(context.Context, *SetRecoveryKeyRequest) (*SetRecoveryKeyResponse, error)
+	// StartRecovery returns the vault key wrapped by the recovery key of the user for the user who lost the password.
+	// Failed attempts are limited as failed logins.
+	StartRecovery(context.Context, *StartRecoveryRequest) (*StartRecoveryResponse, error)
+	// Recover sets a new password of the user by the recovery key, disables the second factor and revokes all sessions
+	// of the user.
+	// Failed attempts are limited as failed logins.
+	Recover(context.Context, *RecoverRequest) (*RecoverResponse, error)
+	// EnrollSecondFactor creates a TOTP secret and backup codes of the user.
+	// Second factor is required on login only after confirmation by ConfirmSecondFactor.
+	EnrollSecondFactor(context.Context, *EnrollSecondFactorRequest) (*EnrollSecondFactorResponse, error)
+	// ConfirmSecondFactor enables enrolled second factor by a TOTP code.
+	ConfirmSecondFactor(context.Context, *ConfirmSecondFactorRequest) (*ConfirmSecondFactorResponse, error)
+	// DisableSecondFactor disables second factor by a TOTP code or a backup code.
+	DisableSecondFactor(context.Context, *DisableSecondFactorRequest) (*DisableSecondFactorResponse, error)
 	mustEmbedUnimplementedUsersServiceServer()
 }
 
@@ -89,6 +399,60 @@ func (UnimplementedUsersServiceServer) Register(context.Context, *RegisterReques
 func (UnimplementedUsersServiceServer) Login(context.Context, *LoginRequest) (*LoginResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Login not implemented")
 }
+func (UnimplementedUsersServiceServer) StartLogin(context.Context, *StartLoginRequest) (*StartLoginResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartLogin not implemented")
+}
+func (UnimplementedUsersServiceServer) FinishLogin(context.Context, *FinishLoginRequest) (*LoginResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FinishLogin not implemented")
+}
+func (UnimplementedUsersServiceServer) VerifySecondFactor(context.Context, *VerifySecondFactorRequest) (*LoginResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifySecondFactor not implemented")
+}
+func (UnimplementedUsersServiceServer) RefreshToken(context.Context, *RefreshTokenRequest) (*RefreshTokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RefreshToken not implemented")
+}
+func (UnimplementedUsersServiceServer) Logout(context.Context, *LogoutRequest) (*LogoutResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Logout not implemented")
+}
+func (UnimplementedUsersServiceServer) ListSessions(context.Context, *ListSessionsRequest) (*ListSessionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSessions not implemented")
+}
+func (UnimplementedUsersServiceServer) RevokeSession(context.Context, *RevokeSessionRequest) (*RevokeSessionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeSession not implemented")
+}
+func (UnimplementedUsersServiceServer) RevokeOtherSessions(context.Context, *RevokeOtherSessionsRequest) (*RevokeOtherSessionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeOtherSessions not implemented")
+}
+func (UnimplementedUsersServiceServer) SetVaultKey(context.Context, *SetVaultKeyRequest) (*SetVaultKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetVaultKey not implemented")
+}
+func (UnimplementedUsersServiceServer) SetKeyPair(context.Context, *SetKeyPairRequest) (*SetKeyPairResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetKeyPair not implemented")
+}
+func (UnimplementedUsersServiceServer) SetSRPVerifier(context.Context, *SetSRPVerifierRequest) (*SetSRPVerifierResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetSRPVerifier not implemented")
+}
+func (UnimplementedUsersServiceServer) ChangePassword(context.Context, *ChangePasswordRequest) (*ChangePasswordResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ChangePassword not implemented")
+}
+func (UnimplementedUsersServiceServer) SetRecoveryKey(context.Context, *SetRecoveryKeyRequest) (*SetRecoveryKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetRecoveryKey not implemented")
+}
+func (UnimplementedUsersServiceServer) StartRecovery(context.Context, *StartRecoveryRequest) (*StartRecoveryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartRecovery not implemented")
+}
+func (UnimplementedUsersServiceServer) Recover(context.Context, *RecoverRequest) (*RecoverResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Recover not implemented")
+}
+func (UnimplementedUsersServiceServer) EnrollSecondFactor(context.Context, *EnrollSecondFactorRequest) (*EnrollSecondFactorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EnrollSecondFactor not implemented")
+}
+func (UnimplementedUsersServiceServer) ConfirmSecondFactor(context.Context, *ConfirmSecondFactorRequest) (*ConfirmSecondFactorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConfirmSecondFactor not implemented")
+}
+func (UnimplementedUsersServiceServer) DisableSecondFactor(context.Context, *DisableSecondFactorRequest) (*DisableSecondFactorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DisableSecondFactor not implemented")
+}
 func (UnimplementedUsersServiceServer) mustEmbedUnimplementedUsersServiceServer() {}
 func (UnimplementedUsersServiceServer) testEmbeddedByValue()                      {}
 
@@ -146,20 +510,871 @@ func _UsersService_Login_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
-// UsersService_ServiceDesc is the grpc.ServiceDesc for UsersService service.
-// It's only intended for direct use with grpc.RegisterService,
-// and not to be introspected or modified (even as a copy)
-var UsersService_ServiceDesc = grpc.ServiceDesc{
-	ServiceName: "users.v1.UsersService",
-	HandlerType: (*UsersServiceServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "Register",
-			Handler:    _UsersService_Register_Handler,
-		},
-		{
-			MethodName: "Login",
-			Handler:    _UsersService_Login_Handler,
+func _UsersService_StartLogin_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartLoginRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServiceServer).StartLogin(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UsersService_StartLogin_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServiceServer).StartLogin(ctx, req.(*StartLoginRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UsersService_FinishLogin_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FinishLoginRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServiceServer).FinishLogin(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UsersService_FinishLogin_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServiceServer).FinishLogin(ctx, req.(*FinishLoginRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UsersService_VerifySecondFactor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifySecondFactorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServiceServer).VerifySecondFactor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UsersService_VerifySecondFactor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServiceServer).VerifySecondFactor(ctx, req.(*VerifySecondFactorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UsersService_RefreshToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefreshTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServiceServer).RefreshToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UsersService_RefreshToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServiceServer).RefreshToken(ctx, req.(*RefreshTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UsersService_Logout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LogoutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServiceServer).Logout(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UsersService_Logout_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServiceServer).Logout(ctx, req.(*LogoutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UsersService_ListSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSessionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServiceServer).ListSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UsersService_ListSessions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServiceServer).ListSessions(ctx, req.(*ListSessionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UsersService_RevokeSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServiceServer).RevokeSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UsersService_RevokeSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServiceServer).RevokeSession(ctx, req.(*RevokeSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UsersService_RevokeOtherSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeOtherSessionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServiceServer).RevokeOtherSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UsersService_RevokeOtherSessions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServiceServer).RevokeOtherSessions(ctx, req.(*RevokeOtherSessionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UsersService_SetVaultKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetVaultKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServiceServer).SetVaultKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UsersService_SetVaultKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServiceServer).SetVaultKey(ctx, req.(*SetVaultKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UsersService_SetKeyPair_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetKeyPairRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServiceServer).SetKeyPair(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UsersService_SetKeyPair_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServiceServer).SetKeyPair(ctx, req.(*SetKeyPairRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UsersService_SetSRPVerifier_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetSRPVerifierRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServiceServer).SetSRPVerifier(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UsersService_SetSRPVerifier_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServiceServer).SetSRPVerifier(ctx, req.(*SetSRPVerifierRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UsersService_ChangePassword_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangePasswordRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServiceServer).ChangePassword(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UsersService_ChangePassword_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServiceServer).ChangePassword(ctx, req.(*ChangePasswordRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UsersService_SetRecoveryKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetRecoveryKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServiceServer).SetRecoveryKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UsersService_SetRecoveryKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServiceServer).SetRecoveryKey(ctx, req.(*SetRecoveryKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UsersService_StartRecovery_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartRecoveryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServiceServer).StartRecovery(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UsersService_StartRecovery_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServiceServer).StartRecovery(ctx, req.(*StartRecoveryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UsersService_Recover_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RecoverRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServiceServer).Recover(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UsersService_Recover_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServiceServer).Recover(ctx, req.(*RecoverRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UsersService_EnrollSecondFactor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EnrollSecondFactorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServiceServer).EnrollSecondFactor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UsersService_EnrollSecondFactor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServiceServer).EnrollSecondFactor(ctx, req.(*EnrollSecondFactorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UsersService_ConfirmSecondFactor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConfirmSecondFactorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServiceServer).ConfirmSecondFactor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UsersService_ConfirmSecondFactor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServiceServer).ConfirmSecondFactor(ctx, req.(*ConfirmSecondFactorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UsersService_DisableSecondFactor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DisableSecondFactorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServiceServer).DisableSecondFactor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UsersService_DisableSecondFactor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServiceServer).DisableSecondFactor(ctx, req.(*DisableSecondFactorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// UsersService_ServiceDesc is the grpc.ServiceDesc for UsersService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var UsersService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "users.v1.UsersService",
+	HandlerType: (*UsersServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Register",
+			Handler:    _UsersService_Register_Handler,
+		},
+		{
+			MethodName: "Login",
+			Handler:    _UsersService_Login_Handler,
+		},
+		{
+			MethodName: "StartLogin",
+			Handler:    _UsersService_StartLogin_Handler,
+		},
+		{
+			MethodName: "FinishLogin",
+			Handler:    _UsersService_FinishLogin_Handler,
+		},
+		{
+			MethodName: "VerifySecondFactor",
+			Handler:    _UsersService_VerifySecondFactor_Handler,
+		},
+		{
+			MethodName: "RefreshToken",
+			Handler:    _UsersService_RefreshToken_Handler,
+		},
+		{
+			MethodName: "Logout",
+			Handler:    _UsersService_Logout_Handler,
+		},
+		{
+			MethodName: "ListSessions",
+			Handler:    _UsersService_ListSessions_Handler,
+		},
+		{
+			MethodName: "RevokeSession",
+			Handler:    _UsersService_RevokeSession_Handler,
+		},
+		{
+			MethodName: "RevokeOtherSessions",
+			Handler:    _UsersService_RevokeOtherSessions_Handler,
+		},
+		{
+			MethodName: "SetVaultKey",
+			Handler:    _UsersService_SetVaultKey_Handler,
+		},
+		{
+			MethodName: "SetKeyPair",
+			Handler:    _UsersService_SetKeyPair_Handler,
+		},
+		{
+			MethodName: "SetSRPVerifier",
+			Handler:    _UsersService_SetSRPVerifier_Handler,
+		},
+		{
+			MethodName: "ChangePassword",
+			Handler:    _UsersService_ChangePassword_Handler,
+		},
+		{
+			MethodName: "SetRecoveryKey",
+			Handler:    _UsersService_SetRecoveryKey_Handler,
+		},
+		{
+			MethodName: "StartRecovery",
+			Handler:    _UsersService_StartRecovery_Handler,
+		},
+		{
+			MethodName: "Recover",
+			Handler:    _UsersService_Recover_Handler,
+		},
+		{
+			MethodName: "EnrollSecondFactor",
+			Handler:    _UsersService_EnrollSecondFactor_Handler,
+		},
+		{
+			MethodName: "ConfirmSecondFactor",
+			Handler:    _UsersService_ConfirmSecondFactor_Handler,
+		},
+		{
+			MethodName: "DisableSecondFactor",
+			Handler:    _UsersService_DisableSecondFactor_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "users.proto",
+}
+
+const (
+	EmergencyAccessService_CreateEmergencyContact_FullMethodName = "/users.v1.EmergencyAccessService/CreateEmergencyContact"
+	EmergencyAccessService_ListEmergencyContacts_FullMethodName  = "/users.v1.EmergencyAccessService/ListEmergencyContacts"
+	EmergencyAccessService_ListEmergencyGrantors_FullMethodName  = "/users.v1.EmergencyAccessService/ListEmergencyGrantors"
+	EmergencyAccessService_DeleteEmergencyContact_FullMethodName = "/users.v1.EmergencyAccessService/DeleteEmergencyContact"
+	EmergencyAccessService_RequestEmergencyAccess_FullMethodName = "/users.v1.EmergencyAccessService/RequestEmergencyAccess"
+	EmergencyAccessService_ApproveEmergencyAccess_FullMethodName = "/users.v1.EmergencyAccessService/ApproveEmergencyAccess"
+	EmergencyAccessService_RejectEmergencyAccess_FullMethodName  = "/users.v1.EmergencyAccessService/RejectEmergencyAccess"
+	EmergencyAccessService_GetEmergencyVault_FullMethodName      = "/users.v1.EmergencyAccessService/GetEmergencyVault"
+	EmergencyAccessService_TakeoverAccount_FullMethodName        = "/users.v1.EmergencyAccessService/TakeoverAccount"
+)
+
+// EmergencyAccessServiceClient is the client API for EmergencyAccessService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// EmergencyAccessService is service for emergency access of trusted contacts to the vault of the user.
+// The owner designates a contact and encrypts the vault key by the public key of the contact, server keeps it
+// and gives it to the contact only after access is approved. The contact requests access, the request is approved
+// automatically after the wait time unless the owner rejects it, the owner can also approve it earlier.
+//
+// States of emergency access:
+//   - idle - contact is designated, access is not requested;
+//   - requested - contact requested access, it is approved automatically at approve_time;
+//   - approved - contact has access until the owner rejects it or deletes the contact.
+//
+// Access of contact:
+//   - view - contact reads personal items of the owner;
+//   - takeover - contact also sets a new password of the owner, second factor of the owner is disabled.
+type EmergencyAccessServiceClient interface {
+	// CreateEmergencyContact designates a contact of the user, designating the same contact again replaces it.
+	CreateEmergencyContact(ctx context.Context, in *CreateEmergencyContactRequest, opts ...grpc.CallOption) (*CreateEmergencyContactResponse, error)
+	// ListEmergencyContacts lists contacts designated by the user.
+	ListEmergencyContacts(ctx context.Context, in *ListEmergencyContactsRequest, opts ...grpc.CallOption) (*ListEmergencyContactsResponse, error)
+	// ListEmergencyGrantors lists users who designated the user as a contact.
+	ListEmergencyGrantors(ctx context.Context, in *ListEmergencyGrantorsRequest, opts ...grpc.CallOption) (*ListEmergencyGrantorsResponse, error)
+	// DeleteEmergencyContact deletes a contact, both the owner and the contact can delete it.
+	DeleteEmergencyContact(ctx context.Context, in *DeleteEmergencyContactRequest, opts ...grpc.CallOption) (*DeleteEmergencyContactResponse, error)
+	// RequestEmergencyAccess requests access to the vault of the owner by the contact.
+	RequestEmergencyAccess(ctx context.Context, in *RequestEmergencyAccessRequest, opts ...grpc.CallOption) (*RequestEmergencyAccessResponse, error)
+	// ApproveEmergencyAccess approves requested access before the wait time, only by the owner.
+	ApproveEmergencyAccess(ctx context.Context, in *ApproveEmergencyAccessRequest, opts ...grpc.CallOption) (*ApproveEmergencyAccessResponse, error)
+	// RejectEmergencyAccess rejects requested or revokes approved access, only by the owner.
+	RejectEmergencyAccess(ctx context.Context, in *RejectEmergencyAccessRequest, opts ...grpc.CallOption) (*RejectEmergencyAccessResponse, error)
+	// GetEmergencyVault gets the vault key and personal items of the owner by the contact with approved access.
+	GetEmergencyVault(ctx context.Context, in *GetEmergencyVaultRequest, opts ...grpc.CallOption) (*GetEmergencyVaultResponse, error)
+	// TakeoverAccount sets a new password of the owner by the contact with approved takeover access.
+	// All sessions of the owner are revoked and second factor of the owner is disabled.
+	TakeoverAccount(ctx context.Context, in *TakeoverAccountRequest, opts ...grpc.CallOption) (*TakeoverAccountResponse, error)
+}
+
+type emergencyAccessServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewEmergencyAccessServiceClient(cc grpc.ClientConnInterface) EmergencyAccessServiceClient {
+	return &emergencyAccessServiceClient{cc}
+}
+
+func (c *emergencyAccessServiceClient) CreateEmergencyContact(ctx context.Context, in *CreateEmergencyContactRequest, opts ...grpc.CallOption) (*CreateEmergencyContactResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateEmergencyContactResponse)
+	err := c.cc.Invoke(ctx, EmergencyAccessService_CreateEmergencyContact_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *emergencyAccessServiceClient) ListEmergencyContacts(ctx context.Context, in *ListEmergencyContactsRequest, opts ...grpc.CallOption) (*ListEmergencyContactsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListEmergencyContactsResponse)
+	err := c.cc.Invoke(ctx, EmergencyAccessService_ListEmergencyContacts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *emergencyAccessServiceClient) ListEmergencyGrantors(ctx context.Context, in *ListEmergencyGrantorsRequest, opts ...grpc.CallOption) (*ListEmergencyGrantorsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListEmergencyGrantorsResponse)
+	err := c.cc.Invoke(ctx, EmergencyAccessService_ListEmergencyGrantors_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *emergencyAccessServiceClient) DeleteEmergencyContact(ctx context.Context, in *DeleteEmergencyContactRequest, opts ...grpc.CallOption) (*DeleteEmergencyContactResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteEmergencyContactResponse)
+	err := c.cc.Invoke(ctx, EmergencyAccessService_DeleteEmergencyContact_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *emergencyAccessServiceClient) RequestEmergencyAccess(ctx context.Context, in *RequestEmergencyAccessRequest, opts ...grpc.CallOption) (*RequestEmergencyAccessResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RequestEmergencyAccessResponse)
+	err := c.cc.Invoke(ctx, EmergencyAccessService_RequestEmergencyAccess_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *emergencyAccessServiceClient) ApproveEmergencyAccess(ctx context.Context, in *ApproveEmergencyAccessRequest, opts ...grpc.CallOption) (*ApproveEmergencyAccessResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ApproveEmergencyAccessResponse)
+	err := c.cc.Invoke(ctx, EmergencyAccessService_ApproveEmergencyAccess_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *emergencyAccessServiceClient) RejectEmergencyAccess(ctx context.Context, in *RejectEmergencyAccessRequest, opts ...grpc.CallOption) (*RejectEmergencyAccessResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RejectEmergencyAccessResponse)
+	err := c.cc.Invoke(ctx, EmergencyAccessService_RejectEmergencyAccess_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *emergencyAccessServiceClient) GetEmergencyVault(ctx context.Context, in *GetEmergencyVaultRequest, opts ...grpc.CallOption) (*GetEmergencyVaultResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetEmergencyVaultResponse)
+	err := c.cc.Invoke(ctx, EmergencyAccessService_GetEmergencyVault_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *emergencyAccessServiceClient) TakeoverAccount(ctx context.Context, in *TakeoverAccountRequest, opts ...grpc.CallOption) (*TakeoverAccountResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TakeoverAccountResponse)
+	err := c.cc.Invoke(ctx, EmergencyAccessService_TakeoverAccount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// EmergencyAccessServiceServer is the server API for EmergencyAccessService service.
+// All implementations must embed UnimplementedEmergencyAccessServiceServer
+// for forward compatibility.
+//
+// EmergencyAccessService is service for emergency access of trusted contacts to the vault of the user.
+// The owner designates a contact and encrypts the vault key by the public key of the contact, server keeps it
+// and gives it to the contact only after access is approved. The contact requests access, the request is approved
+// automatically after the wait time unless the owner rejects it, the owner can also approve it earlier.
+//
+// States of emergency access:
+//   - idle - contact is designated, access is not requested;
+//   - requested - contact requested access, it is approved automatically at approve_time;
+//   - approved - contact has access until the owner rejects it or deletes the contact.
+//
+// Access of contact:
+//   - view - contact reads personal items of the owner;
+//   - takeover - contact also sets a new password of the owner, second factor of the owner is disabled.
+type EmergencyAccessServiceServer interface {
+	// CreateEmergencyContact designates a contact of the user, designating the same contact again replaces it.
+	CreateEmergencyContact(context.Context, *CreateEmergencyContactRequest) (*CreateEmergencyContactResponse, error)
+	// ListEmergencyContacts lists contacts designated by the user.
+	ListEmergencyContacts(context.Context, *ListEmergencyContactsRequest) (*ListEmergencyContactsResponse, error)
+	// ListEmergencyGrantors lists users who designated the user as a contact.
+	ListEmergencyGrantors(context.Context, *ListEmergencyGrantorsRequest) (*ListEmergencyGrantorsResponse, error)
+	// DeleteEmergencyContact deletes a contact, both the owner and the contact can delete it.
+	DeleteEmergencyContact(context.Context, *DeleteEmergencyContactRequest) (*DeleteEmergencyContactResponse, error)
+	// RequestEmergencyAccess requests access to the vault of the owner by the contact.
+	RequestEmergencyAccess(context.Context, *RequestEmergencyAccessRequest) (*RequestEmergencyAccessResponse, error)
+	// ApproveEmergencyAccess approves requested access before the wait time, only by the owner.
+	ApproveEmergencyAccess(context.Context, *ApproveEmergencyAccessRequest) (*ApproveEmergencyAccessResponse, error)
+	// RejectEmergencyAccess rejects requested or revokes approved access, only by the owner.
+	RejectEmergencyAccess(context.Context, *RejectEmergencyAccessRequest) (*RejectEmergencyAccessResponse, error)
+	// GetEmergencyVault gets the vault key and personal items of the owner by the contact with approved access.
+	GetEmergencyVault(context.Context, *GetEmergencyVaultRequest) (*GetEmergencyVaultResponse, error)
+	// TakeoverAccount sets a new password of the owner by the contact with approved takeover access.
+	// All sessions of the owner are revoked and second factor of the owner is disabled.
+	TakeoverAccount(context.Context, *TakeoverAccountRequest) (*TakeoverAccountResponse, error)
+	mustEmbedUnimplementedEmergencyAccessServiceServer()
+}
+
+// UnimplementedEmergencyAccessServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedEmergencyAccessServiceServer struct{}
+
+func (UnimplementedEmergencyAccessServiceServer) CreateEmergencyContact(context.Context, *CreateEmergencyContactRequest) (*CreateEmergencyContactResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateEmergencyContact not implemented")
+}
+func (UnimplementedEmergencyAccessServiceServer) ListEmergencyContacts(context.Context, *ListEmergencyContactsRequest) (*ListEmergencyContactsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListEmergencyContacts not implemented")
+}
+func (UnimplementedEmergencyAccessServiceServer) ListEmergencyGrantors(context.Context, *ListEmergencyGrantorsRequest) (*ListEmergencyGrantorsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListEmergencyGrantors not implemented")
+}
+func (UnimplementedEmergencyAccessServiceServer) DeleteEmergencyContact(context.Context, *DeleteEmergencyContactRequest) (*DeleteEmergencyContactResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteEmergencyContact not implemented")
+}
+func (UnimplementedEmergencyAccessServiceServer) RequestEmergencyAccess(context.Context, *RequestEmergencyAccessRequest) (*RequestEmergencyAccessResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequestEmergencyAccess not implemented")
+}
+func (UnimplementedEmergencyAccessServiceServer) ApproveEmergencyAccess(context.Context, *ApproveEmergencyAccessRequest) (*ApproveEmergencyAccessResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApproveEmergencyAccess not implemented")
+}
+func (UnimplementedEmergencyAccessServiceServer) RejectEmergencyAccess(context.Context, *RejectEmergencyAccessRequest) (*RejectEmergencyAccessResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RejectEmergencyAccess not implemented")
+}
+func (UnimplementedEmergencyAccessServiceServer) GetEmergencyVault(context.Context, *GetEmergencyVaultRequest) (*GetEmergencyVaultResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEmergencyVault not implemented")
+}
+func (UnimplementedEmergencyAccessServiceServer) TakeoverAccount(context.Context, *TakeoverAccountRequest) (*TakeoverAccountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TakeoverAccount not implemented")
+}
+func (UnimplementedEmergencyAccessServiceServer) mustEmbedUnimplementedEmergencyAccessServiceServer() {
+}
+func (UnimplementedEmergencyAccessServiceServer) testEmbeddedByValue() {}
+
+// UnsafeEmergencyAccessServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to EmergencyAccessServiceServer will
+// result in compilation errors.
+type UnsafeEmergencyAccessServiceServer interface {
+	mustEmbedUnimplementedEmergencyAccessServiceServer()
+}
+
+func RegisterEmergencyAccessServiceServer(s grpc.ServiceRegistrar, srv EmergencyAccessServiceServer) {
+	// If the following call pancis, it indicates UnimplementedEmergencyAccessServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&EmergencyAccessService_ServiceDesc, srv)
+}
+
+func _EmergencyAccessService_CreateEmergencyContact_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateEmergencyContactRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmergencyAccessServiceServer).CreateEmergencyContact(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmergencyAccessService_CreateEmergencyContact_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmergencyAccessServiceServer).CreateEmergencyContact(ctx, req.(*CreateEmergencyContactRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmergencyAccessService_ListEmergencyContacts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListEmergencyContactsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmergencyAccessServiceServer).ListEmergencyContacts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmergencyAccessService_ListEmergencyContacts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmergencyAccessServiceServer).ListEmergencyContacts(ctx, req.(*ListEmergencyContactsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmergencyAccessService_ListEmergencyGrantors_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListEmergencyGrantorsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmergencyAccessServiceServer).ListEmergencyGrantors(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmergencyAccessService_ListEmergencyGrantors_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmergencyAccessServiceServer).ListEmergencyGrantors(ctx, req.(*ListEmergencyGrantorsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmergencyAccessService_DeleteEmergencyContact_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteEmergencyContactRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmergencyAccessServiceServer).DeleteEmergencyContact(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmergencyAccessService_DeleteEmergencyContact_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmergencyAccessServiceServer).DeleteEmergencyContact(ctx, req.(*DeleteEmergencyContactRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmergencyAccessService_RequestEmergencyAccess_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestEmergencyAccessRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmergencyAccessServiceServer).RequestEmergencyAccess(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmergencyAccessService_RequestEmergencyAccess_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmergencyAccessServiceServer).RequestEmergencyAccess(ctx, req.(*RequestEmergencyAccessRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmergencyAccessService_ApproveEmergencyAccess_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApproveEmergencyAccessRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmergencyAccessServiceServer).ApproveEmergencyAccess(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmergencyAccessService_ApproveEmergencyAccess_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmergencyAccessServiceServer).ApproveEmergencyAccess(ctx, req.(*ApproveEmergencyAccessRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmergencyAccessService_RejectEmergencyAccess_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RejectEmergencyAccessRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmergencyAccessServiceServer).RejectEmergencyAccess(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmergencyAccessService_RejectEmergencyAccess_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmergencyAccessServiceServer).RejectEmergencyAccess(ctx, req.(*RejectEmergencyAccessRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmergencyAccessService_GetEmergencyVault_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEmergencyVaultRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmergencyAccessServiceServer).GetEmergencyVault(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmergencyAccessService_GetEmergencyVault_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmergencyAccessServiceServer).GetEmergencyVault(ctx, req.(*GetEmergencyVaultRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmergencyAccessService_TakeoverAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TakeoverAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmergencyAccessServiceServer).TakeoverAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmergencyAccessService_TakeoverAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmergencyAccessServiceServer).TakeoverAccount(ctx, req.(*TakeoverAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// EmergencyAccessService_ServiceDesc is the grpc.ServiceDesc for EmergencyAccessService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var EmergencyAccessService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "users.v1.EmergencyAccessService",
+	HandlerType: (*EmergencyAccessServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateEmergencyContact",
+			Handler:    _EmergencyAccessService_CreateEmergencyContact_Handler,
+		},
+		{
+			MethodName: "ListEmergencyContacts",
+			Handler:    _EmergencyAccessService_ListEmergencyContacts_Handler,
+		},
+		{
+			MethodName: "ListEmergencyGrantors",
+			Handler:    _EmergencyAccessService_ListEmergencyGrantors_Handler,
+		},
+		{
+			MethodName: "DeleteEmergencyContact",
+			Handler:    _EmergencyAccessService_DeleteEmergencyContact_Handler,
+		},
+		{
+			MethodName: "RequestEmergencyAccess",
+			Handler:    _EmergencyAccessService_RequestEmergencyAccess_Handler,
+		},
+		{
+			MethodName: "ApproveEmergencyAccess",
+			Handler:    _EmergencyAccessService_ApproveEmergencyAccess_Handler,
+		},
+		{
+			MethodName: "RejectEmergencyAccess",
+			Handler:    _EmergencyAccessService_RejectEmergencyAccess_Handler,
+		},
+		{
+			MethodName: "GetEmergencyVault",
+			Handler:    _EmergencyAccessService_GetEmergencyVault_Handler,
+		},
+		{
+			MethodName: "TakeoverAccount",
+			Handler:    _EmergencyAccessService_TakeoverAccount_Handler,
 		},
 	},
 	Streams:  []grpc.StreamDesc{},
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/k0st1a/gophkeeper/internal/adapters/api/grpc/gen/proto/v1"
+	"github.com/k0st1a/gophkeeper/internal/ports/server"
+	"github.com/rs/zerolog/log"
+)
+
+type AdminServer struct {
+	// нужно встраивать тип pb.Unimplemented<TypeName>
+	// для совместимости с будущими версиями
+	pb.UnimplementedAdminServiceServer
+	Attempts server.LoginFailureStorage
+}
+
+func (s *AdminServer) ListLockouts(ctx context.Context, req *pb.ListLockoutsRequest) (*pb.ListLockoutsResponse,
+	error) {
+	log.Ctx(ctx).Printf("ListLockouts, all:%v", req.GetAll())
+
+	l, err := s.Attempts.ListLockouts(ctx, req.GetAll())
+	if err != nil {
+		log.Error().Err(err).Ctx(ctx).Msg("error of list lockouts")
+		return nil, status.Errorf(codes.Internal, "list lockouts error")
+	}
+
+	lockouts := make([]*pb.Lockout, 0, len(l))
+	for _, i := range l {
+		lo := &pb.Lockout{
+			Id:          i.ID,
+			Kind:        i.Key.Kind,
+			Value:       i.Key.Value,
+			Failures:    int32(i.Failures), //nolint:gosec // number of failures is small
+			CreateTime:  timestamppb.New(i.CreateTime),
+			LockedUntil: timestamppb.New(i.LockedUntil),
+		}
+
+		if i.ClearTime != nil {
+			lo.ClearTime = timestamppb.New(*i.ClearTime)
+		}
+
+		lockouts = append(lockouts, lo)
+	}
+
+	log.Ctx(ctx).Printf("ListLockouts success, lockouts:%v", len(lockouts))
+	return &pb.ListLockoutsResponse{Lockouts: lockouts}, nil
+}
+
+func (s *AdminServer) ClearLockout(ctx context.Context, req *pb.ClearLockoutRequest) (*pb.ClearLockoutResponse,
+	error) {
+	log.Ctx(ctx).Printf("ClearLockout, id:%v", req.GetId())
+
+	err := s.Attempts.ClearLockout(ctx, req.GetId())
+	if err != nil {
+		if errors.Is(err, server.ErrLockoutNotFound) {
+			return nil, status.Errorf(codes.NotFound, "lockout not found")
+		}
+
+		log.Error().Err(err).Ctx(ctx).Msg("error of clear lockout")
+		return nil, status.Errorf(codes.Internal, "clear lockout error")
+	}
+
+	log.Ctx(ctx).Printf("ClearLockout success")
+	return &pb.ClearLockoutResponse{}, nil
+}
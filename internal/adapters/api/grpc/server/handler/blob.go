@@ -0,0 +1,242 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"io"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/k0st1a/gophkeeper/internal/adapters/api/grpc/gen/proto/v1"
+	"github.com/k0st1a/gophkeeper/internal/pkg/userid"
+	"github.com/k0st1a/gophkeeper/internal/ports/server"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// MaxBlobChunkSize - максимальный размер блока, с запасом меньше ограничения gRPC на размер сообщения в 4MB.
+	MaxBlobChunkSize = 2 * 1024 * 1024 // 2MB
+	// MaxBlobSize - максимальный размер блоба.
+	MaxBlobSize = 16 * 1024 * 1024 * 1024 // 16GB
+)
+
+type BlobServer struct {
+	// нужно встраивать тип auth.Unimplemented<TypeName>
+	// для совместимости с будущими версиями
+	pb.UnimplementedBlobsServiceServer
+	Storage server.BlobStorage
+	// Quota - максимальный суммарный размер блобов пользователя, 0 - без ограничения.
+	Quota int64
+}
+
+func (s *BlobServer) GetBlob(ctx context.Context, req *pb.GetBlobRequest) (*pb.GetBlobResponse, error) {
+	log.Ctx(ctx).Printf("Get blob, id:%v", req.Id)
+
+	userID, ok := userid.Get(ctx)
+	if !ok {
+		log.Ctx(ctx).Printf(ErrNoUserID.Error())
+		//nolint:wrapcheck // not need wrap error from status package
+		return nil, status.Error(codes.Unauthenticated, ErrNoUserID.Error())
+	}
+
+	b, err := s.Storage.GetBlob(ctx, userID, req.Id)
+	if err != nil {
+		log.Error().Err(err).Ctx(ctx).Msg("get blob error")
+		return nil, blobError(err)
+	}
+
+	resp := pb.GetBlobResponse{
+		Id:       b.ID,
+		Chunks:   b.Chunks,
+		Size:     b.Size,
+		Complete: b.Complete,
+	}
+
+	log.Ctx(ctx).Printf("Get blob success")
+	return &resp, nil
+}
+
+func (s *BlobServer) UploadBlob(stream pb.BlobsService_UploadBlobServer) error {
+	ctx := stream.Context()
+	log.Ctx(ctx).Printf("Upload blob")
+
+	userID, ok := userid.Get(ctx)
+	if !ok {
+		log.Ctx(ctx).Printf(ErrNoUserID.Error())
+		//nolint:wrapcheck // not need wrap error from status package
+		return status.Error(codes.Unauthenticated, ErrNoUserID.Error())
+	}
+
+	var b *server.Blob
+
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			log.Error().Err(err).Ctx(ctx).Msg("receive blob chunk error")
+			//nolint:wrapcheck // error from grpc stream
+			return err
+		}
+
+		err = validateBlobChunk(req)
+		if err != nil {
+			log.Error().Err(err).Ctx(ctx).Msg("invalid blob chunk")
+			return err
+		}
+
+		if b == nil || b.ID != req.Id {
+			b, err = s.uploadedBlob(ctx, userID, req)
+			if err != nil {
+				log.Error().Err(err).Ctx(ctx).Msg("get blob error")
+				return blobError(err)
+			}
+		}
+
+		if b.Size+int64(len(req.Chunk.Data)) > MaxBlobSize {
+			//nolint:wrapcheck // not need wrap error from status package
+			return status.Error(codes.ResourceExhausted, "blob is too large")
+		}
+
+		b, err = s.Storage.AddBlobChunk(ctx, userID, req.Id, &server.BlobChunk{
+			Data:  req.Chunk.Data,
+			Index: req.Chunk.Index,
+			Last:  req.Chunk.Last,
+		}, s.Quota)
+		if err != nil {
+			log.Error().Err(err).Ctx(ctx).Msg("add blob chunk error")
+			return blobError(err)
+		}
+	}
+
+	if b == nil {
+		//nolint:wrapcheck // not need wrap error from status package
+		return status.Error(codes.InvalidArgument, "no blob chunks")
+	}
+
+	log.Ctx(ctx).Printf("Upload blob success, id:%v, chunks:%v, complete:%v", b.ID, b.Chunks, b.Complete)
+	//nolint:wrapcheck // error from grpc stream
+	return stream.SendAndClose(&pb.UploadBlobResponse{
+		Chunks:   b.Chunks,
+		Complete: b.Complete,
+	})
+}
+
+// uploadedBlob - получить уже загруженную часть блоба, в который добавляется блок.
+func (s *BlobServer) uploadedBlob(ctx context.Context, userID int64, req *pb.UploadBlobRequest) (*server.Blob, error) {
+	if req.Chunk.Index == 0 {
+		return &server.Blob{ID: req.Id}, nil
+	}
+
+	//nolint:wrapcheck // error is converted to status by caller
+	return s.Storage.GetBlob(ctx, userID, req.Id)
+}
+
+func (s *BlobServer) DownloadBlob(req *pb.DownloadBlobRequest, stream pb.BlobsService_DownloadBlobServer) error {
+	ctx := stream.Context()
+	log.Ctx(ctx).Printf("Download blob, id:%v, index:%v", req.Id, req.Index)
+
+	userID, ok := userid.Get(ctx)
+	if !ok {
+		log.Ctx(ctx).Printf(ErrNoUserID.Error())
+		//nolint:wrapcheck // not need wrap error from status package
+		return status.Error(codes.Unauthenticated, ErrNoUserID.Error())
+	}
+
+	b, err := s.Storage.GetBlob(ctx, userID, req.Id)
+	if err != nil {
+		log.Error().Err(err).Ctx(ctx).Msg("get blob error")
+		return blobError(err)
+	}
+
+	if !b.Complete {
+		//nolint:wrapcheck // not need wrap error from status package
+		return status.Error(codes.FailedPrecondition, "blob upload is not complete")
+	}
+
+	if req.Index < 0 || req.Index >= b.Chunks {
+		//nolint:wrapcheck // not need wrap error from status package
+		return status.Error(codes.OutOfRange, "chunk index is out of range")
+	}
+
+	// Блоки читаются из базы по одному, чтобы не держать весь блоб в памяти.
+	for i := req.Index; i < b.Chunks; i++ {
+		c, err := s.Storage.GetBlobChunk(ctx, userID, req.Id, i)
+		if err != nil {
+			log.Error().Err(err).Ctx(ctx).Msg("get blob chunk error")
+			return blobError(err)
+		}
+
+		sum := sha256.Sum256(c.Data)
+		err = stream.Send(&pb.DownloadBlobResponse{
+			Chunk: &pb.BlobChunk{
+				Index:  c.Index,
+				Data:   c.Data,
+				Sha256: sum[:],
+				Last:   c.Last,
+			},
+		})
+		if err != nil {
+			log.Error().Err(err).Ctx(ctx).Msg("send blob chunk error")
+			//nolint:wrapcheck // error from grpc stream
+			return err
+		}
+	}
+
+	log.Ctx(ctx).Printf("Download blob success")
+	return nil
+}
+
+// validateBlobChunk - проверить идентификатор блоба, размер и контрольную сумму блока.
+func validateBlobChunk(req *pb.UploadBlobRequest) error {
+	_, err := uuid.Parse(req.Id)
+	if err != nil {
+		//nolint:wrapcheck // not need wrap error from status package
+		return status.Error(codes.InvalidArgument, "blob id is not uuid")
+	}
+
+	if req.Chunk == nil {
+		//nolint:wrapcheck // not need wrap error from status package
+		return status.Error(codes.InvalidArgument, "no blob chunk")
+	}
+
+	if len(req.Chunk.Data) > MaxBlobChunkSize {
+		//nolint:wrapcheck // not need wrap error from status package
+		return status.Error(codes.InvalidArgument, "blob chunk is too large")
+	}
+
+	sum := sha256.Sum256(req.Chunk.Data)
+	if !bytes.Equal(sum[:], req.Chunk.Sha256) {
+		//nolint:wrapcheck // not need wrap error from status package
+		return status.Error(codes.DataLoss, "blob chunk checksum mismatch")
+	}
+
+	return nil
+}
+
+// blobError - преобразовать ошибку хранилища блобов в статус.
+func blobError(err error) error {
+	switch {
+	case errors.Is(err, server.ErrBlobNotFound):
+		//nolint:wrapcheck // not need wrap error from status package
+		return status.Error(codes.NotFound, "blob not found")
+	case errors.Is(err, server.ErrBlobComplete):
+		//nolint:wrapcheck // not need wrap error from status package
+		return status.Error(codes.AlreadyExists, "blob already uploaded")
+	case errors.Is(err, server.ErrUnexpectedBlobChunk):
+		//nolint:wrapcheck // not need wrap error from status package
+		return status.Error(codes.FailedPrecondition, "unexpected blob chunk index")
+	case errors.Is(err, server.ErrBlobQuotaExceeded):
+		//nolint:wrapcheck // not need wrap error from status package
+		return status.Error(codes.ResourceExhausted, "blob quota exceeded")
+	default:
+		//nolint:wrapcheck // not need wrap error from status package
+		return status.Error(codes.Internal, "blob error")
+	}
+}
@@ -0,0 +1,372 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/k0st1a/gophkeeper/internal/adapters/api/grpc/gen/proto/v1"
+	"github.com/k0st1a/gophkeeper/internal/pkg/userid"
+	"github.com/k0st1a/gophkeeper/internal/ports/server"
+	"github.com/rs/zerolog/log"
+)
+
+// maxEmergencyWaitDays - наибольшее время ожидания автоматического одобрения экстренного доступа.
+const maxEmergencyWaitDays = 90
+
+type EmergencyServer struct {
+	// нужно встраивать тип auth.Unimplemented<TypeName>
+	// для совместимости с будущими версиями
+	pb.UnimplementedEmergencyAccessServiceServer
+	Users     server.UserStorage
+	Items     server.ItemStorage
+	Emergency server.EmergencyStorage
+}
+
+func (s *EmergencyServer) CreateEmergencyContact(ctx context.Context, req *pb.CreateEmergencyContactRequest) (
+	*pb.CreateEmergencyContactResponse, error) {
+	log.Ctx(ctx).Printf("CreateEmergencyContact, Login:%s, access:%s", req.GetLogin(), req.GetAccess())
+
+	userID, ok := userid.Get(ctx)
+	if !ok {
+		log.Ctx(ctx).Printf(ErrNoUserID.Error())
+		//nolint:wrapcheck // not need wrap error from status package
+		return nil, status.Error(codes.Unauthenticated, ErrNoUserID.Error())
+	}
+
+	access := server.EmergencyAccess(req.GetAccess())
+	if access != server.EmergencyAccessView && access != server.EmergencyAccessTakeover {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid access:%s", req.GetAccess())
+	}
+
+	if req.GetWaitDays() < 1 || req.GetWaitDays() > maxEmergencyWaitDays {
+		return nil, status.Errorf(codes.InvalidArgument, "wait days must be from 1 to %d", maxEmergencyWaitDays)
+	}
+
+	if len(req.GetVaultKey()) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "empty vault key")
+	}
+
+	user, err := s.Users.GetUser(ctx, req.GetLogin())
+	if err != nil {
+		if errors.Is(err, server.ErrUserNotFound) {
+			return nil, status.Errorf(codes.NotFound, "user not found")
+		}
+
+		log.Error().Err(err).Ctx(ctx).Msg("error of get user")
+		return nil, status.Errorf(codes.Internal, "create emergency contact error")
+	}
+
+	if user.ID == userID {
+		return nil, status.Errorf(codes.InvalidArgument, "can not designate yourself")
+	}
+
+	// Ключ хранилища шифруется открытым ключом доверенного лица, без него назначить нельзя.
+	if len(user.KeyPair.Public) == 0 {
+		return nil, status.Errorf(codes.FailedPrecondition, "user has no public key yet, user must login first")
+	}
+
+	id, err := s.Emergency.CreateEmergencyContact(ctx, &server.EmergencyContact{
+		OwnerID:   userID,
+		GranteeID: user.ID,
+		Access:    access,
+		WaitDays:  req.GetWaitDays(),
+		VaultKey:  req.GetVaultKey(),
+	})
+	if err != nil {
+		log.Error().Err(err).Ctx(ctx).Msg("error of create emergency contact")
+		return nil, status.Errorf(codes.Internal, "create emergency contact error")
+	}
+
+	log.Ctx(ctx).Printf("CreateEmergencyContact success, id:%v", id)
+	return &pb.CreateEmergencyContactResponse{Id: id}, nil
+}
+
+func (s *EmergencyServer) ListEmergencyContacts(ctx context.Context, req *pb.ListEmergencyContactsRequest) (
+	*pb.ListEmergencyContactsResponse, error) {
+	log.Ctx(ctx).Printf("ListEmergencyContacts")
+
+	userID, ok := userid.Get(ctx)
+	if !ok {
+		log.Ctx(ctx).Printf(ErrNoUserID.Error())
+		//nolint:wrapcheck // not need wrap error from status package
+		return nil, status.Error(codes.Unauthenticated, ErrNoUserID.Error())
+	}
+
+	l, err := s.Emergency.ListEmergencyContacts(ctx, userID)
+	if err != nil {
+		log.Error().Err(err).Ctx(ctx).Msg("error of list emergency contacts")
+		return nil, status.Errorf(codes.Internal, "list emergency contacts error")
+	}
+
+	return &pb.ListEmergencyContactsResponse{Contacts: emergencyContactsToProto(l)}, nil
+}
+
+func (s *EmergencyServer) ListEmergencyGrantors(ctx context.Context, req *pb.ListEmergencyGrantorsRequest) (
+	*pb.ListEmergencyGrantorsResponse, error) {
+	log.Ctx(ctx).Printf("ListEmergencyGrantors")
+
+	userID, ok := userid.Get(ctx)
+	if !ok {
+		log.Ctx(ctx).Printf(ErrNoUserID.Error())
+		//nolint:wrapcheck // not need wrap error from status package
+		return nil, status.Error(codes.Unauthenticated, ErrNoUserID.Error())
+	}
+
+	l, err := s.Emergency.ListEmergencyGrantors(ctx, userID)
+	if err != nil {
+		log.Error().Err(err).Ctx(ctx).Msg("error of list emergency grantors")
+		return nil, status.Errorf(codes.Internal, "list emergency grantors error")
+	}
+
+	return &pb.ListEmergencyGrantorsResponse{Contacts: emergencyContactsToProto(l)}, nil
+}
+
+func (s *EmergencyServer) DeleteEmergencyContact(ctx context.Context, req *pb.DeleteEmergencyContactRequest) (
+	*pb.DeleteEmergencyContactResponse, error) {
+	log.Ctx(ctx).Printf("DeleteEmergencyContact, id:%v", req.GetId())
+
+	userID, ok := userid.Get(ctx)
+	if !ok {
+		log.Ctx(ctx).Printf(ErrNoUserID.Error())
+		//nolint:wrapcheck // not need wrap error from status package
+		return nil, status.Error(codes.Unauthenticated, ErrNoUserID.Error())
+	}
+
+	err := s.Emergency.DeleteEmergencyContact(ctx, userID, req.GetId())
+	if err != nil {
+		if errors.Is(err, server.ErrEmergencyContactNotFound) {
+			return nil, status.Errorf(codes.NotFound, "emergency contact not found")
+		}
+
+		log.Error().Err(err).Ctx(ctx).Msg("error of delete emergency contact")
+		return nil, status.Errorf(codes.Internal, "delete emergency contact error")
+	}
+
+	log.Ctx(ctx).Printf("DeleteEmergencyContact success")
+	return &pb.DeleteEmergencyContactResponse{}, nil
+}
+
+func (s *EmergencyServer) RequestEmergencyAccess(ctx context.Context, req *pb.RequestEmergencyAccessRequest) (
+	*pb.RequestEmergencyAccessResponse, error) {
+	log.Ctx(ctx).Printf("RequestEmergencyAccess, id:%v", req.GetId())
+
+	_, err := s.contact(ctx, req.GetId(), false)
+	if err != nil {
+		return nil, err
+	}
+
+	approve, err := s.Emergency.RequestEmergencyAccess(ctx, req.GetId())
+	if err != nil {
+		if errors.Is(err, server.ErrEmergencyStateChanged) {
+			return nil, status.Errorf(codes.FailedPrecondition, "access already requested")
+		}
+
+		log.Error().Err(err).Ctx(ctx).Msg("error of request emergency access")
+		return nil, status.Errorf(codes.Internal, "request emergency access error")
+	}
+
+	log.Ctx(ctx).Printf("RequestEmergencyAccess success, approve time:%v", approve)
+	return &pb.RequestEmergencyAccessResponse{ApproveTime: timestamppb.New(approve)}, nil
+}
+
+func (s *EmergencyServer) ApproveEmergencyAccess(ctx context.Context, req *pb.ApproveEmergencyAccessRequest) (
+	*pb.ApproveEmergencyAccessResponse, error) {
+	log.Ctx(ctx).Printf("ApproveEmergencyAccess, id:%v", req.GetId())
+
+	_, err := s.contact(ctx, req.GetId(), true)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.Emergency.ApproveEmergencyAccess(ctx, req.GetId())
+	if err != nil {
+		if errors.Is(err, server.ErrEmergencyStateChanged) {
+			return nil, status.Errorf(codes.FailedPrecondition, "access is not requested")
+		}
+
+		log.Error().Err(err).Ctx(ctx).Msg("error of approve emergency access")
+		return nil, status.Errorf(codes.Internal, "approve emergency access error")
+	}
+
+	log.Ctx(ctx).Printf("ApproveEmergencyAccess success")
+	return &pb.ApproveEmergencyAccessResponse{}, nil
+}
+
+func (s *EmergencyServer) RejectEmergencyAccess(ctx context.Context, req *pb.RejectEmergencyAccessRequest) (
+	*pb.RejectEmergencyAccessResponse, error) {
+	log.Ctx(ctx).Printf("RejectEmergencyAccess, id:%v", req.GetId())
+
+	_, err := s.contact(ctx, req.GetId(), true)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.Emergency.RejectEmergencyAccess(ctx, req.GetId())
+	if err != nil {
+		if errors.Is(err, server.ErrEmergencyStateChanged) {
+			return nil, status.Errorf(codes.FailedPrecondition, "access is not requested")
+		}
+
+		log.Error().Err(err).Ctx(ctx).Msg("error of reject emergency access")
+		return nil, status.Errorf(codes.Internal, "reject emergency access error")
+	}
+
+	log.Ctx(ctx).Printf("RejectEmergencyAccess success")
+	return &pb.RejectEmergencyAccessResponse{}, nil
+}
+
+func (s *EmergencyServer) GetEmergencyVault(ctx context.Context, req *pb.GetEmergencyVaultRequest) (
+	*pb.GetEmergencyVaultResponse, error) {
+	log.Ctx(ctx).Printf("GetEmergencyVault, id:%v", req.GetId())
+
+	c, err := s.approved(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := s.Items.ListItems(ctx, c.OwnerID)
+	if err != nil {
+		log.Error().Err(err).Ctx(ctx).Msg("error of list items")
+		return nil, status.Errorf(codes.Internal, "get emergency vault error")
+	}
+
+	// Предметы коллекций зашифрованы ключами коллекций, доступными только участникам организации.
+	items := make([]*pb.Item, 0, len(l))
+	for i := range l {
+		if l[i].CollectionID == 0 {
+			items = append(items, makeItem(&l[i]))
+		}
+	}
+
+	log.Ctx(ctx).Printf("GetEmergencyVault success, items:%v", len(items))
+	return &pb.GetEmergencyVaultResponse{
+		VaultKey: c.VaultKey,
+		Items:    items,
+	}, nil
+}
+
+func (s *EmergencyServer) TakeoverAccount(ctx context.Context, req *pb.TakeoverAccountRequest) (
+	*pb.TakeoverAccountResponse, error) {
+	log.Ctx(ctx).Printf("TakeoverAccount, id:%v", req.GetId())
+
+	if len(req.GetVerifier().GetSalt()) == 0 || len(req.GetVerifier().GetVerifier()) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "empty srp verifier")
+	}
+
+	if len(req.GetVaultKey().GetKey()) == 0 || len(req.GetVaultKey().GetSalt()) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "empty vault key")
+	}
+
+	c, err := s.approved(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	if c.Access != server.EmergencyAccessTakeover {
+		return nil, status.Errorf(codes.PermissionDenied, "takeover is not allowed")
+	}
+
+	owner, err := s.Users.GetUserByID(ctx, c.OwnerID)
+	if err != nil {
+		log.Error().Err(err).Ctx(ctx).Msg("error of get user")
+		return nil, status.Errorf(codes.Internal, "takeover account error")
+	}
+
+	revoked, err := s.Emergency.TakeoverAccount(ctx, c.ID, owner, &server.PasswordChange{
+		VaultKey: &server.VaultKey{
+			Salt: req.GetVaultKey().GetSalt(),
+			Key:  req.GetVaultKey().GetKey(),
+		},
+		Verifier: server.SRPVerifier{
+			Salt:     req.GetVerifier().GetSalt(),
+			Verifier: req.GetVerifier().GetVerifier(),
+		},
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, server.ErrEmergencyStateChanged):
+			return nil, status.Errorf(codes.PermissionDenied, "access is not approved")
+		case errors.Is(err, server.ErrPasswordChanged):
+			return nil, status.Errorf(codes.Aborted, "password changed concurrently")
+		}
+
+		log.Error().Err(err).Ctx(ctx).Msg("error of takeover account")
+		return nil, status.Errorf(codes.Internal, "takeover account error")
+	}
+
+	log.Ctx(ctx).Printf("TakeoverAccount success, owner UserId:%d, revoked sessions:%d", owner.ID, revoked)
+	return &pb.TakeoverAccountResponse{Revoked: revoked}, nil
+}
+
+// contact - получить доверенное лицо, если пользователь - его владелец (owner) или само доверенное лицо.
+// Чужие доверенные лица не отличаются от несуществующих.
+func (s *EmergencyServer) contact(ctx context.Context, id int64, owner bool) (*server.EmergencyContact, error) {
+	userID, ok := userid.Get(ctx)
+	if !ok {
+		log.Ctx(ctx).Printf(ErrNoUserID.Error())
+		//nolint:wrapcheck // not need wrap error from status package
+		return nil, status.Error(codes.Unauthenticated, ErrNoUserID.Error())
+	}
+
+	c, err := s.Emergency.GetEmergencyContact(ctx, id)
+	if err != nil {
+		if errors.Is(err, server.ErrEmergencyContactNotFound) {
+			return nil, status.Errorf(codes.NotFound, "emergency contact not found")
+		}
+
+		log.Error().Err(err).Ctx(ctx).Msg("error of get emergency contact")
+		return nil, status.Errorf(codes.Internal, "get emergency contact error")
+	}
+
+	if owner && c.OwnerID != userID || !owner && c.GranteeID != userID {
+		return nil, status.Errorf(codes.NotFound, "emergency contact not found")
+	}
+
+	return c, nil
+}
+
+// approved - получить доверенное лицо пользователя с одобренным доступом.
+func (s *EmergencyServer) approved(ctx context.Context, id int64) (*server.EmergencyContact, error) {
+	c, err := s.contact(ctx, id, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.State != server.EmergencyStateApproved {
+		return nil, status.Errorf(codes.PermissionDenied, "access is not approved")
+	}
+
+	return c, nil
+}
+
+// emergencyContactsToProto - ключ хранилища выдается только через GetEmergencyVault, в списках его нет.
+func emergencyContactsToProto(l []server.EmergencyContact) []*pb.EmergencyContact {
+	contacts := make([]*pb.EmergencyContact, 0, len(l))
+	for i := range l {
+		c := &pb.EmergencyContact{
+			Id:         l[i].ID,
+			Owner:      l[i].Owner,
+			Contact:    l[i].Grantee,
+			Access:     string(l[i].Access),
+			WaitDays:   l[i].WaitDays,
+			State:      string(l[i].State),
+			CreateTime: timestamppb.New(l[i].CreateTime),
+		}
+
+		if !l[i].RequestTime.IsZero() {
+			c.RequestTime = timestamppb.New(l[i].RequestTime)
+		}
+
+		if !l[i].ApproveTime.IsZero() {
+			c.ApproveTime = timestamppb.New(l[i].ApproveTime)
+		}
+
+		contacts = append(contacts, c)
+	}
+
+	return contacts
+}
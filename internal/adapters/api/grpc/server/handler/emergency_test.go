@@ -0,0 +1,402 @@
+package handler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/k0st1a/gophkeeper/internal/adapters/api/grpc/gen/proto/v1"
+	"github.com/k0st1a/gophkeeper/internal/pkg/userid"
+	"github.com/k0st1a/gophkeeper/internal/ports/server"
+)
+
+const (
+	// Доверенное лицо bob с захватом, доступ не запрошен
+	testContactIdle = 1
+	// Доверенное лицо bob с захватом, доступ запрошен
+	testContactRequested = 2
+	// Доверенное лицо bob с захватом, доступ одобрен
+	testContactTakeover = 3
+	// Доверенное лицо bob с чтением, доступ одобрен
+	testContactView = 4
+)
+
+// emergencyUsers - пользователи с открытыми ключами и владелец alice для захвата.
+type emergencyUsers struct {
+	shareUsers
+}
+
+func (s *emergencyUsers) GetUserByID(ctx context.Context, id int64) (*server.User, error) {
+	return &server.User{ID: id, Login: "alice"}, nil
+}
+
+// emergencyItems - личный предмет и предмет коллекции владельца.
+type emergencyItems struct {
+	server.ItemStorage
+}
+
+func (s *emergencyItems) ListItems(ctx context.Context, userID int64) ([]server.Item, error) {
+	return []server.Item{
+		{ID: 1},
+		{ID: 2, CollectionID: 1},
+	}, nil
+}
+
+// emergencyStorage - доверенные лица alice с переходами состояний, как в хранилище.
+type emergencyStorage struct {
+	server.EmergencyStorage
+	contacts map[int64]*server.EmergencyContact
+}
+
+func newEmergencyStorage() *emergencyStorage {
+	contact := func(id int64, access server.EmergencyAccess, state server.EmergencyState) *server.EmergencyContact {
+		return &server.EmergencyContact{
+			ID:        id,
+			OwnerID:   testAlice,
+			GranteeID: testBob,
+			Access:    access,
+			State:     state,
+			WaitDays:  1,
+			VaultKey:  []byte("vault key"),
+		}
+	}
+
+	return &emergencyStorage{
+		contacts: map[int64]*server.EmergencyContact{
+			testContactIdle:      contact(testContactIdle, server.EmergencyAccessTakeover, server.EmergencyStateIdle),
+			testContactRequested: contact(testContactRequested, server.EmergencyAccessTakeover, server.EmergencyStateRequested),
+			testContactTakeover:  contact(testContactTakeover, server.EmergencyAccessTakeover, server.EmergencyStateApproved),
+			testContactView:      contact(testContactView, server.EmergencyAccessView, server.EmergencyStateApproved),
+		},
+	}
+}
+
+func (s *emergencyStorage) GetEmergencyContact(ctx context.Context, id int64) (*server.EmergencyContact, error) {
+	c, ok := s.contacts[id]
+	if !ok {
+		return nil, server.ErrEmergencyContactNotFound
+	}
+
+	cc := *c
+	return &cc, nil
+}
+
+// change - перевести доступ id из состояния from в состояние to.
+func (s *emergencyStorage) change(id int64, to server.EmergencyState, from ...server.EmergencyState) error {
+	c := s.contacts[id]
+	for _, f := range from {
+		if c.State == f {
+			c.State = to
+			return nil
+		}
+	}
+
+	return server.ErrEmergencyStateChanged
+}
+
+func (s *emergencyStorage) RequestEmergencyAccess(ctx context.Context, id int64) (time.Time, error) {
+	err := s.change(id, server.EmergencyStateRequested, server.EmergencyStateIdle)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Now().AddDate(0, 0, int(s.contacts[id].WaitDays)), nil
+}
+
+func (s *emergencyStorage) ApproveEmergencyAccess(ctx context.Context, id int64) error {
+	return s.change(id, server.EmergencyStateApproved, server.EmergencyStateRequested)
+}
+
+func (s *emergencyStorage) RejectEmergencyAccess(ctx context.Context, id int64) error {
+	return s.change(id, server.EmergencyStateIdle, server.EmergencyStateRequested, server.EmergencyStateApproved)
+}
+
+func (s *emergencyStorage) TakeoverAccount(ctx context.Context, id int64, old *server.User,
+	c *server.PasswordChange) (int64, error) {
+	err := s.change(id, server.EmergencyStateIdle, server.EmergencyStateApproved)
+	if err != nil {
+		return 0, err
+	}
+
+	return 2, nil
+}
+
+func newEmergencyServer() (*EmergencyServer, *emergencyStorage) {
+	es := newEmergencyStorage()
+	return &EmergencyServer{
+		Users:     &emergencyUsers{},
+		Items:     &emergencyItems{},
+		Emergency: es,
+	}, es
+}
+
+func TestEmergencyAccessState(t *testing.T) {
+	request := func(s *EmergencyServer, ctx context.Context, id int64) error {
+		_, err := s.RequestEmergencyAccess(ctx, &pb.RequestEmergencyAccessRequest{Id: id})
+		return err
+	}
+
+	approve := func(s *EmergencyServer, ctx context.Context, id int64) error {
+		_, err := s.ApproveEmergencyAccess(ctx, &pb.ApproveEmergencyAccessRequest{Id: id})
+		return err
+	}
+
+	reject := func(s *EmergencyServer, ctx context.Context, id int64) error {
+		_, err := s.RejectEmergencyAccess(ctx, &pb.RejectEmergencyAccessRequest{Id: id})
+		return err
+	}
+
+	tests := []struct {
+		name   string
+		action func(s *EmergencyServer, ctx context.Context, id int64) error
+		want   server.EmergencyState
+		caller int64
+		id     int64
+		code   codes.Code
+	}{
+		{
+			name:   "Check request by contact",
+			action: request,
+			caller: testBob,
+			id:     testContactIdle,
+			want:   server.EmergencyStateRequested,
+		},
+		{
+			name:   "Check request twice",
+			action: request,
+			caller: testBob,
+			id:     testContactRequested,
+			code:   codes.FailedPrecondition,
+			want:   server.EmergencyStateRequested,
+		},
+		{
+			name:   "Check request by owner",
+			action: request,
+			caller: testAlice,
+			id:     testContactIdle,
+			code:   codes.NotFound,
+			want:   server.EmergencyStateIdle,
+		},
+		{
+			name:   "Check request by foreign user",
+			action: request,
+			caller: testEve,
+			id:     testContactIdle,
+			code:   codes.NotFound,
+			want:   server.EmergencyStateIdle,
+		},
+		{
+			name:   "Check request unknown contact",
+			action: request,
+			caller: testBob,
+			id:     100,
+			code:   codes.NotFound,
+		},
+		{
+			name:   "Check approve by owner",
+			action: approve,
+			caller: testAlice,
+			id:     testContactRequested,
+			want:   server.EmergencyStateApproved,
+		},
+		{
+			name:   "Check approve by contact",
+			action: approve,
+			caller: testBob,
+			id:     testContactRequested,
+			code:   codes.NotFound,
+			want:   server.EmergencyStateRequested,
+		},
+		{
+			name:   "Check approve not requested",
+			action: approve,
+			caller: testAlice,
+			id:     testContactIdle,
+			code:   codes.FailedPrecondition,
+			want:   server.EmergencyStateIdle,
+		},
+		{
+			name:   "Check reject request by owner",
+			action: reject,
+			caller: testAlice,
+			id:     testContactRequested,
+			want:   server.EmergencyStateIdle,
+		},
+		{
+			name:   "Check reject approved access by owner",
+			action: reject,
+			caller: testAlice,
+			id:     testContactTakeover,
+			want:   server.EmergencyStateIdle,
+		},
+		{
+			name:   "Check reject by contact",
+			action: reject,
+			caller: testBob,
+			id:     testContactTakeover,
+			code:   codes.NotFound,
+			want:   server.EmergencyStateApproved,
+		},
+		{
+			name:   "Check reject not requested",
+			action: reject,
+			caller: testAlice,
+			id:     testContactIdle,
+			code:   codes.FailedPrecondition,
+			want:   server.EmergencyStateIdle,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s, es := newEmergencyServer()
+
+			err := test.action(s, userid.Set(context.Background(), test.caller), test.id)
+			require.Equal(t, test.code, status.Code(err))
+
+			if c, ok := es.contacts[test.id]; ok {
+				require.Equal(t, test.want, c.State)
+			}
+		})
+	}
+}
+
+func TestGetEmergencyVault(t *testing.T) {
+	tests := []struct {
+		name   string
+		caller int64
+		id     int64
+		code   codes.Code
+	}{
+		{
+			name:   "Check view access",
+			caller: testBob,
+			id:     testContactView,
+		},
+		{
+			name:   "Check takeover access",
+			caller: testBob,
+			id:     testContactTakeover,
+		},
+		{
+			name:   "Check not requested access",
+			caller: testBob,
+			id:     testContactIdle,
+			code:   codes.PermissionDenied,
+		},
+		{
+			name:   "Check not approved access",
+			caller: testBob,
+			id:     testContactRequested,
+			code:   codes.PermissionDenied,
+		},
+		{
+			name:   "Check owner can not get vault by contact",
+			caller: testAlice,
+			id:     testContactView,
+			code:   codes.NotFound,
+		},
+		{
+			name:   "Check foreign contact",
+			caller: testEve,
+			id:     testContactView,
+			code:   codes.NotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s, _ := newEmergencyServer()
+
+			resp, err := s.GetEmergencyVault(userid.Set(context.Background(), test.caller),
+				&pb.GetEmergencyVaultRequest{Id: test.id})
+			require.Equal(t, test.code, status.Code(err))
+
+			if test.code == codes.OK {
+				require.Equal(t, []byte("vault key"), resp.GetVaultKey())
+				require.Len(t, resp.GetItems(), 1)
+				require.Equal(t, int64(1), resp.GetItems()[0].GetId())
+			}
+		})
+	}
+}
+
+func TestTakeoverAccount(t *testing.T) {
+	tests := []struct {
+		name     string
+		verifier *pb.SRPVerifier
+		want     server.EmergencyState
+		caller   int64
+		id       int64
+		code     codes.Code
+	}{
+		{
+			name:     "Check takeover",
+			caller:   testBob,
+			id:       testContactTakeover,
+			verifier: &pb.SRPVerifier{Salt: []byte("salt"), Verifier: []byte("verifier")},
+			want:     server.EmergencyStateIdle,
+		},
+		{
+			name:     "Check takeover with view access",
+			caller:   testBob,
+			id:       testContactView,
+			verifier: &pb.SRPVerifier{Salt: []byte("salt"), Verifier: []byte("verifier")},
+			code:     codes.PermissionDenied,
+			want:     server.EmergencyStateApproved,
+		},
+		{
+			name:     "Check takeover not approved",
+			caller:   testBob,
+			id:       testContactRequested,
+			verifier: &pb.SRPVerifier{Salt: []byte("salt"), Verifier: []byte("verifier")},
+			code:     codes.PermissionDenied,
+			want:     server.EmergencyStateRequested,
+		},
+		{
+			name:     "Check takeover by owner",
+			caller:   testAlice,
+			id:       testContactTakeover,
+			verifier: &pb.SRPVerifier{Salt: []byte("salt"), Verifier: []byte("verifier")},
+			code:     codes.NotFound,
+			want:     server.EmergencyStateApproved,
+		},
+		{
+			name:     "Check takeover by foreign user",
+			caller:   testEve,
+			id:       testContactTakeover,
+			verifier: &pb.SRPVerifier{Salt: []byte("salt"), Verifier: []byte("verifier")},
+			code:     codes.NotFound,
+			want:     server.EmergencyStateApproved,
+		},
+		{
+			name:   "Check takeover without verifier",
+			caller: testBob,
+			id:     testContactTakeover,
+			code:   codes.InvalidArgument,
+			want:   server.EmergencyStateApproved,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s, es := newEmergencyServer()
+
+			resp, err := s.TakeoverAccount(userid.Set(context.Background(), test.caller), &pb.TakeoverAccountRequest{
+				Id:       test.id,
+				Verifier: test.verifier,
+				VaultKey: &pb.VaultKey{Salt: []byte("salt"), Key: []byte("key")},
+			})
+			require.Equal(t, test.code, status.Code(err))
+			require.Equal(t, test.want, es.contacts[test.id].State)
+
+			if test.code == codes.OK {
+				require.Equal(t, int64(2), resp.GetRevoked())
+			}
+		})
+	}
+}
@@ -3,5 +3,6 @@ package handler
 import "errors"
 
 var (
-	ErrNoUserID = errors.New("no user id")
+	ErrNoUserID    = errors.New("no user id")
+	ErrNoSessionID = errors.New("no session id")
 )
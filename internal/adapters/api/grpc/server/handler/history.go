@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/k0st1a/gophkeeper/internal/adapters/api/grpc/gen/proto/v1"
+	"github.com/k0st1a/gophkeeper/internal/pkg/userid"
+	"github.com/k0st1a/gophkeeper/internal/ports/server"
+	"github.com/rs/zerolog/log"
+)
+
+// MaxHistoryRetention - максимальное количество хранимых прошлых версий предмета.
+const MaxHistoryRetention = 1000
+
+func (s *ItemServer) ListItemVersions(ctx context.Context, req *pb.ListItemVersionsRequest) (
+	*pb.ListItemVersionsResponse, error) {
+	log.Ctx(ctx).Printf("List item versions, id:%v", req.Id)
+
+	userID, ok := userid.Get(ctx)
+	if !ok {
+		log.Ctx(ctx).Printf(ErrNoUserID.Error())
+		//nolint:wrapcheck // not need wrap error from status package
+		return nil, status.Error(codes.Unauthenticated, ErrNoUserID.Error())
+	}
+
+	l, err := s.History.ListItemVersions(ctx, userID, req.Id)
+	if err != nil {
+		log.Error().Err(err).Ctx(ctx).Msg("list item versions error")
+		//nolint:wrapcheck // not need wrap error from status package
+		return nil, status.Error(codes.Internal, "list item versions error")
+	}
+
+	versions := make([]*pb.ItemVersion, 0, len(l))
+	for _, v := range l {
+		versions = append(versions, &pb.ItemVersion{
+			Item:        makeItem(&v.Item),
+			Deleted:     v.Deleted,
+			ArchiveTime: timestamppb.New(v.ArchiveTime),
+		})
+	}
+
+	log.Ctx(ctx).Printf("List item versions success, versions:%v", len(versions))
+	return &pb.ListItemVersionsResponse{Versions: versions}, nil
+}
+
+func (s *ItemServer) RestoreItemVersion(ctx context.Context, req *pb.RestoreItemVersionRequest) (
+	*pb.RestoreItemVersionResponse, error) {
+	log.Ctx(ctx).Printf("Restore item version, id:%v, revision:%v, expected revision:%v",
+		req.Id, req.Revision, req.ExpectedRevision)
+
+	userID, ok := userid.Get(ctx)
+	if !ok {
+		log.Ctx(ctx).Printf(ErrNoUserID.Error())
+		//nolint:wrapcheck // not need wrap error from status package
+		return nil, status.Error(codes.Unauthenticated, ErrNoUserID.Error())
+	}
+
+	i, err := s.History.RestoreItemVersion(ctx, userID, req.Id, req.Revision, req.ExpectedRevision)
+	if errors.Is(err, server.ErrVersionNotFound) {
+		//nolint:wrapcheck // not need wrap error from status package
+		return nil, status.Error(codes.NotFound, "item version not found")
+	}
+
+	if err != nil {
+		log.Error().Err(err).Ctx(ctx).Msg("restore item version error")
+		return nil, updateItemError(req.Id, err)
+	}
+	s.Notifier.Notify(userID)
+
+	log.Ctx(ctx).Printf("Restore item version success, revision:%v", i.Revision)
+	return &pb.RestoreItemVersionResponse{Item: makeItem(i)}, nil
+}
+
+func (s *ItemServer) SetHistoryRetention(ctx context.Context, req *pb.SetHistoryRetentionRequest) (
+	*pb.SetHistoryRetentionResponse, error) {
+	log.Ctx(ctx).Printf("Set history retention, versions:%v", req.Versions)
+
+	userID, ok := userid.Get(ctx)
+	if !ok {
+		log.Ctx(ctx).Printf(ErrNoUserID.Error())
+		//nolint:wrapcheck // not need wrap error from status package
+		return nil, status.Error(codes.Unauthenticated, ErrNoUserID.Error())
+	}
+
+	if req.Versions < 0 || req.Versions > MaxHistoryRetention {
+		//nolint:wrapcheck // not need wrap error from status package
+		return nil, status.Errorf(codes.InvalidArgument, "versions must be in range [0, %v]", MaxHistoryRetention)
+	}
+
+	err := s.History.SetHistoryRetention(ctx, userID, int(req.Versions))
+	if err != nil {
+		log.Error().Err(err).Ctx(ctx).Msg("set history retention error")
+		//nolint:wrapcheck // not need wrap error from status package
+		return nil, status.Error(codes.Internal, "set history retention error")
+	}
+
+	log.Ctx(ctx).Printf("Set history retention success")
+	return &pb.SetHistoryRetentionResponse{}, nil
+}
@@ -3,6 +3,8 @@ package handler
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -18,7 +20,17 @@ type ItemServer struct {
 	// нужно встраивать тип auth.Unimplemented<TypeName>
 	// для совместимости с будущими версиями
 	pb.UnimplementedItemsServiceServer
-	Storage server.ItemStorage // YAGNI - без промежуточного сервиса логики над item.
+	Storage  server.ItemStorage // YAGNI - без промежуточного сервиса логики над item.
+	Trash    server.ItemTrashStorage
+	History  server.ItemHistoryStorage
+	Orgs     server.OrgStorage
+	Notifier ItemNotifier
+}
+
+// ItemNotifier - уведомления об изменениях предметов пользователя.
+type ItemNotifier interface {
+	Subscribe(userID int64) (<-chan struct{}, func())
+	Notify(userID int64)
 }
 
 func (s *ItemServer) CreateItem(ctx context.Context, req *pb.CreateItemRequest) (*pb.CreateItemResponse, error) {
@@ -31,20 +43,25 @@ func (s *ItemServer) CreateItem(ctx context.Context, req *pb.CreateItemRequest)
 		return nil, status.Error(codes.Unauthenticated, ErrNoUserID.Error())
 	}
 
+	// Время обновления назначает сервер: часы клиентов могут расходиться.
 	item := &server.Item{
-		Data:       req.Item.Data,
-		CreateTime: req.Item.CreateTime.AsTime(),
-		UpdateTime: req.Item.UpdateTime.AsTime(),
+		Data:         req.Item.Data,
+		CreateTime:   req.Item.CreateTime.AsTime(),
+		UpdateTime:   time.Now().UTC(),
+		BlobIDs:      req.Item.BlobIds,
+		CollectionID: req.Item.CollectionId,
 	}
 	id, err := s.Storage.CreateItem(ctx, userID, item)
 	if err != nil {
 		log.Error().Err(err).Ctx(ctx).Msg("create item error")
-		//nolint:wrapcheck // not need wrap error from status package
-		return nil, status.Error(codes.Internal, "create item error")
+		return nil, itemError(err, "create item error")
 	}
+	s.notify(ctx, userID, item.CollectionID)
 
 	resp := pb.CreateItemResponse{
-		Id: id,
+		Id:         id,
+		Revision:   server.InitialRevision,
+		UpdateTime: timestamppb.New(item.UpdateTime),
 	}
 
 	log.Ctx(ctx).Printf("Create item success")
@@ -52,7 +69,7 @@ func (s *ItemServer) CreateItem(ctx context.Context, req *pb.CreateItemRequest)
 }
 
 func (s *ItemServer) UpdateItem(ctx context.Context, req *pb.UpdateItemRequest) (*pb.UpdateItemResponse, error) {
-	log.Ctx(ctx).Printf("Update item, id:%v", req.Item.Id)
+	log.Ctx(ctx).Printf("Update item, id:%v, expected revision:%v", req.Item.Id, req.ExpectedRevision)
 
 	userID, ok := userid.Get(ctx)
 	if !ok {
@@ -61,20 +78,77 @@ func (s *ItemServer) UpdateItem(ctx context.Context, req *pb.UpdateItemRequest)
 		return nil, status.Error(codes.Unauthenticated, ErrNoUserID.Error())
 	}
 
+	if req.ExpectedRevision <= 0 {
+		//nolint:wrapcheck // not need wrap error from status package
+		return nil, status.Error(codes.InvalidArgument, "expected revision is not set")
+	}
+
 	item := &server.Item{
 		ID:         req.Item.Id,
 		Data:       req.Item.Data,
 		CreateTime: req.Item.CreateTime.AsTime(),
-		UpdateTime: req.Item.UpdateTime.AsTime()}
-	err := s.Storage.UpdateItem(ctx, userID, item)
+		UpdateTime: time.Now().UTC(),
+		Revision:   req.ExpectedRevision,
+		BlobIDs:    req.Item.BlobIds,
+	}
+	revision, err := s.Storage.UpdateItem(ctx, userID, item)
 	if err != nil {
 		log.Error().Err(err).Ctx(ctx).Msg("update item error")
+		return nil, updateItemError(req.Item.Id, err)
+	}
+	s.notify(ctx, userID, item.CollectionID)
+
+	resp := pb.UpdateItemResponse{
+		Revision:   revision,
+		UpdateTime: timestamppb.New(item.UpdateTime),
+	}
+
+	log.Ctx(ctx).Printf("Update item data success, revision:%v", revision)
+	return &resp, nil
+}
+
+// updateItemError - преобразовать ошибку обновления предмета в статус.
+// При несовпадении ревизий в детали статуса кладется текущая ревизия предмета.
+func updateItemError(id int64, err error) error {
+	var rme *server.RevisionMismatchError
+	if !errors.As(err, &rme) {
+		return itemError(err, "update item error")
+	}
+
+	st, derr := status.New(codes.Aborted, "item was changed since expected revision").
+		WithDetails(&pb.RevisionConflict{
+			Id:       id,
+			Revision: rme.Revision,
+		})
+	if derr != nil {
+		log.Error().Err(derr).Msg("error of add details to status")
 		//nolint:wrapcheck // not need wrap error from status package
-		return nil, status.Error(codes.Internal, "update item error")
+		return status.Error(codes.Aborted, "item was changed since expected revision")
 	}
 
-	log.Ctx(ctx).Printf("Update item data success")
-	return &pb.UpdateItemResponse{}, nil
+	//nolint:wrapcheck // not need wrap error from status package
+	return st.Err()
+}
+
+// itemError - преобразовать ошибку изменения предмета в статус, msg - сообщение внутренней ошибки.
+func itemError(err error, msg string) error {
+	switch {
+	case errors.Is(err, server.ErrItemNotFound):
+		//nolint:wrapcheck // not need wrap error from status package
+		return status.Error(codes.NotFound, "item not found")
+	case errors.Is(err, server.ErrCollectionNotFound):
+		//nolint:wrapcheck // not need wrap error from status package
+		return status.Error(codes.NotFound, "collection not found")
+	case errors.Is(err, server.ErrItemReadOnly):
+		//nolint:wrapcheck // not need wrap error from status package
+		return status.Error(codes.PermissionDenied, "role can not change items of collection")
+	case errors.Is(err, server.ErrCollectionItemBlobs):
+		//nolint:wrapcheck // not need wrap error from status package
+		return status.Error(codes.InvalidArgument, "items of collection can not have blobs")
+	default:
+		//nolint:wrapcheck // not need wrap error from status package
+		return status.Error(codes.Internal, msg)
+	}
 }
 
 func (s *ItemServer) GetItem(ctx context.Context, req *pb.GetItemRequest) (*pb.GetItemResponse, error) {
@@ -89,18 +163,18 @@ func (s *ItemServer) GetItem(ctx context.Context, req *pb.GetItemRequest) (*pb.G
 
 	i, err := s.Storage.GetItem(ctx, userID, req.Id)
 	if err != nil {
+		if errors.Is(err, server.ErrItemNotFound) {
+			//nolint:wrapcheck // not need wrap error from status package
+			return nil, status.Error(codes.NotFound, "item not found")
+		}
+
 		log.Error().Err(err).Ctx(ctx).Msg("get item error")
 		//nolint:wrapcheck // not need wrap error from status package
 		return nil, status.Error(codes.Internal, "get item error")
 	}
 
 	resp := pb.GetItemResponse{
-		Item: &pb.Item{
-			Id:         i.ID,
-			Data:       i.Data,
-			CreateTime: timestamppb.New(i.CreateTime),
-			UpdateTime: timestamppb.New(i.UpdateTime),
-		},
+		Item: makeItem(i),
 	}
 
 	log.Ctx(ctx).Printf("Get item success")
@@ -124,19 +198,8 @@ func (s *ItemServer) ListItems(ctx context.Context, req *pb.ListItemsRequest) (*
 		return nil, status.Error(codes.Internal, "list item error")
 	}
 
-	items := make([]*pb.Item, 0, len(l))
-	for _, i := range l {
-		d := &pb.Item{
-			Id:         i.ID,
-			Data:       i.Data,
-			CreateTime: timestamppb.New(i.CreateTime),
-			UpdateTime: timestamppb.New(i.UpdateTime),
-		}
-		items = append(items, d)
-	}
-
 	resp := pb.ListItemsResponse{
-		Items: items,
+		Items: makeItems(l),
 	}
 
 	log.Ctx(ctx).Printf("Get item success")
@@ -153,13 +216,121 @@ func (s *ItemServer) DeleteItem(ctx context.Context, req *pb.DeleteItemRequest)
 		return nil, status.Error(codes.Unauthenticated, ErrNoUserID.Error())
 	}
 
-	err := s.Storage.DeleteItem(ctx, userID, req.Id)
+	collectionID, err := s.Storage.DeleteItem(ctx, userID, req.Id)
 	if err != nil {
 		log.Error().Err(err).Ctx(ctx).Msg("delete item error")
-		//nolint:wrapcheck // not need wrap error from status package
-		return nil, status.Error(codes.Internal, "delte item error")
+		return nil, itemError(err, "delete item error")
 	}
+	s.notify(ctx, userID, collectionID)
 
 	log.Ctx(ctx).Printf("Delete item success")
 	return &pb.DeleteItemResponse{}, nil
 }
+
+func (s *ItemServer) ListChanges(ctx context.Context, req *pb.ListChangesRequest) (*pb.ListChangesResponse, error) {
+	log.Ctx(ctx).Printf("List changes, since cursor:%v", req.SinceCursor)
+
+	userID, ok := userid.Get(ctx)
+	if !ok {
+		log.Ctx(ctx).Printf(ErrNoUserID.Error())
+		//nolint:wrapcheck // not need wrap error from status package
+		return nil, status.Error(codes.Unauthenticated, ErrNoUserID.Error())
+	}
+
+	c, err := s.Storage.ListChanges(ctx, userID, req.SinceCursor)
+	if err != nil {
+		log.Error().Err(err).Ctx(ctx).Msg("list changes error")
+		//nolint:wrapcheck // not need wrap error from status package
+		return nil, status.Error(codes.Internal, "list changes error")
+	}
+
+	resp := pb.ListChangesResponse{
+		Items:      makeItems(c.Items),
+		DeletedIds: c.DeletedIDs,
+		Cursor:     c.Cursor,
+		Full:       c.Full,
+	}
+
+	log.Ctx(ctx).Printf("List changes success, cursor:%v", c.Cursor)
+	return &resp, nil
+}
+
+func (s *ItemServer) WatchItems(req *pb.WatchItemsRequest, stream pb.ItemsService_WatchItemsServer) error {
+	ctx := stream.Context()
+	log.Ctx(ctx).Printf("Watch items")
+
+	userID, ok := userid.Get(ctx)
+	if !ok {
+		log.Ctx(ctx).Printf(ErrNoUserID.Error())
+		//nolint:wrapcheck // not need wrap error from status package
+		return status.Error(codes.Unauthenticated, ErrNoUserID.Error())
+	}
+
+	ch, unsubscribe := s.Notifier.Subscribe(userID)
+	defer unsubscribe()
+
+	// Первое уведомление сразу после подписки: клиент мог пропустить изменения, пока не был подписан.
+	notified := true
+
+	for {
+		if notified {
+			err := stream.Send(&pb.WatchItemsResponse{})
+			if err != nil {
+				log.Error().Err(err).Ctx(ctx).Msg("send item notification error")
+				//nolint:wrapcheck // error from grpc stream
+				return err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Ctx(ctx).Printf("Watch items done")
+			return nil
+		case _, notified = <-ch:
+			if !notified {
+				log.Ctx(ctx).Printf("Watch items closed by server")
+				//nolint:wrapcheck // not need wrap error from status package
+				return status.Error(codes.Unavailable, "server is shutting down")
+			}
+		}
+	}
+}
+
+// notify - уведомить об изменении предмета пользователя или, для предмета коллекции, всех участников организации.
+func (s *ItemServer) notify(ctx context.Context, userID, collectionID int64) {
+	if collectionID == 0 {
+		s.Notifier.Notify(userID)
+		return
+	}
+
+	members, err := s.Orgs.ListCollectionMembers(ctx, collectionID)
+	if err != nil {
+		log.Error().Err(err).Ctx(ctx).Msg("error of list collection members")
+		s.Notifier.Notify(userID)
+		return
+	}
+
+	for _, id := range members {
+		s.Notifier.Notify(id)
+	}
+}
+
+func makeItems(l []server.Item) []*pb.Item {
+	items := make([]*pb.Item, 0, len(l))
+	for _, i := range l {
+		items = append(items, makeItem(&i))
+	}
+	return items
+}
+
+func makeItem(i *server.Item) *pb.Item {
+	return &pb.Item{
+		Id:           i.ID,
+		Data:         i.Data,
+		CreateTime:   timestamppb.New(i.CreateTime),
+		UpdateTime:   timestamppb.New(i.UpdateTime),
+		Revision:     i.Revision,
+		BlobIds:      i.BlobIDs,
+		CollectionId: i.CollectionID,
+	}
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/k0st1a/gophkeeper/internal/adapters/api/grpc/gen/proto/v1"
+	"github.com/k0st1a/gophkeeper/internal/pkg/userid"
+	"github.com/k0st1a/gophkeeper/internal/ports/server"
+)
+
+// itemStorage - проверяет роль в организации коллекции 1 так же, как хранилище в транзакции записи.
+type itemStorage struct {
+	server.ItemStorage
+	orgs    *orgStorage
+	created int
+}
+
+func (s *itemStorage) CreateItem(ctx context.Context, userID int64, item *server.Item) (int64, error) {
+	if item.CollectionID != 0 {
+		if len(item.BlobIDs) != 0 {
+			return 0, server.ErrCollectionItemBlobs
+		}
+
+		role, err := s.orgs.GetRole(ctx, 1, userID)
+		if err != nil || item.CollectionID != 1 {
+			return 0, server.ErrCollectionNotFound
+		}
+
+		if role == server.RoleReadOnly {
+			return 0, server.ErrItemReadOnly
+		}
+	}
+
+	s.created++
+	return int64(s.created), nil
+}
+
+func TestCreateCollectionItem(t *testing.T) {
+	tests := []struct {
+		name         string
+		blobs        []string
+		caller       int64
+		collectionID int64
+		code         codes.Code
+		notified     int
+	}{
+		{
+			name:         "Check member creates collection item",
+			caller:       testMember,
+			collectionID: 1,
+			notified:     5,
+		},
+		{
+			name:         "Check read-only can not create collection item",
+			caller:       testReader,
+			collectionID: 1,
+			code:         codes.PermissionDenied,
+		},
+		{
+			name:         "Check collection of other organization",
+			caller:       testOutsider,
+			collectionID: 1,
+			code:         codes.NotFound,
+		},
+		{
+			name:         "Check collection item with blobs",
+			caller:       testMember,
+			collectionID: 1,
+			blobs:        []string{"blob"},
+			code:         codes.InvalidArgument,
+		},
+		{
+			name:     "Check personal item of outsider",
+			caller:   testOutsider,
+			notified: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			orgs := newOrgStorage(nil)
+			n := &notifier{}
+			s := &ItemServer{
+				Storage:  &itemStorage{orgs: orgs},
+				Orgs:     orgs,
+				Notifier: n,
+			}
+
+			_, err := s.CreateItem(userid.Set(context.Background(), test.caller), &pb.CreateItemRequest{
+				Item: &pb.Item{
+					Data:         []byte("data"),
+					CreateTime:   timestamppb.Now(),
+					BlobIds:      test.blobs,
+					CollectionId: test.collectionID,
+				},
+			})
+			require.Equal(t, test.code, status.Code(err))
+			require.Len(t, n.notified, test.notified)
+		})
+	}
+}
@@ -0,0 +1,601 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/k0st1a/gophkeeper/internal/adapters/api/grpc/gen/proto/v1"
+	"github.com/k0st1a/gophkeeper/internal/pkg/userid"
+	"github.com/k0st1a/gophkeeper/internal/ports/server"
+	"github.com/rs/zerolog/log"
+)
+
+type OrgServer struct {
+	// нужно встраивать тип auth.Unimplemented<TypeName>
+	// для совместимости с будущими версиями
+	pb.UnimplementedOrgsServiceServer
+	Users    server.UserStorage
+	Orgs     server.OrgStorage
+	Notifier ItemNotifier
+}
+
+func (s *OrgServer) CreateOrg(ctx context.Context, req *pb.CreateOrgRequest) (*pb.CreateOrgResponse, error) {
+	log.Ctx(ctx).Printf("CreateOrg, Name:%s", req.GetName())
+
+	userID, ok := userid.Get(ctx)
+	if !ok {
+		log.Ctx(ctx).Printf(ErrNoUserID.Error())
+		//nolint:wrapcheck // not need wrap error from status package
+		return nil, status.Error(codes.Unauthenticated, ErrNoUserID.Error())
+	}
+
+	if req.GetName() == "" || len(req.GetKey()) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "empty name or key")
+	}
+
+	id, err := s.Orgs.CreateOrg(ctx, &server.Org{Name: req.GetName()}, &server.OrgMember{
+		UserID: userID,
+		OrgKey: req.GetKey(),
+	})
+	if err != nil {
+		log.Error().Err(err).Ctx(ctx).Msg("error of create org")
+		return nil, status.Errorf(codes.Internal, "create org error")
+	}
+
+	log.Ctx(ctx).Printf("CreateOrg success, id:%v", id)
+	return &pb.CreateOrgResponse{OrgId: id}, nil
+}
+
+func (s *OrgServer) ListOrgs(ctx context.Context, req *pb.ListOrgsRequest) (*pb.ListOrgsResponse, error) {
+	log.Ctx(ctx).Printf("ListOrgs")
+
+	userID, ok := userid.Get(ctx)
+	if !ok {
+		log.Ctx(ctx).Printf(ErrNoUserID.Error())
+		//nolint:wrapcheck // not need wrap error from status package
+		return nil, status.Error(codes.Unauthenticated, ErrNoUserID.Error())
+	}
+
+	l, err := s.Orgs.ListOrgs(ctx, userID)
+	if err != nil {
+		log.Error().Err(err).Ctx(ctx).Msg("error of list orgs")
+		return nil, status.Errorf(codes.Internal, "list orgs error")
+	}
+
+	orgs := make([]*pb.Org, 0, len(l))
+	for _, m := range l {
+		orgs = append(orgs, &pb.Org{
+			Id:   m.OrgID,
+			Name: m.OrgName,
+			Role: string(m.Role),
+			Key:  m.OrgKey,
+		})
+	}
+
+	return &pb.ListOrgsResponse{Orgs: orgs}, nil
+}
+
+func (s *OrgServer) DeleteOrg(ctx context.Context, req *pb.DeleteOrgRequest) (*pb.DeleteOrgResponse, error) {
+	log.Ctx(ctx).Printf("DeleteOrg, id:%v", req.GetOrgId())
+
+	userID, ok := userid.Get(ctx)
+	if !ok {
+		log.Ctx(ctx).Printf(ErrNoUserID.Error())
+		//nolint:wrapcheck // not need wrap error from status package
+		return nil, status.Error(codes.Unauthenticated, ErrNoUserID.Error())
+	}
+
+	role, err := s.role(ctx, req.GetOrgId(), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if role != server.RoleOwner {
+		return nil, status.Errorf(codes.PermissionDenied, "only owner can delete organization")
+	}
+
+	members, err := s.Orgs.ListMembers(ctx, req.GetOrgId())
+	if err != nil {
+		log.Error().Err(err).Ctx(ctx).Msg("error of list members")
+		return nil, status.Errorf(codes.Internal, "delete org error")
+	}
+
+	err = s.Orgs.DeleteOrg(ctx, req.GetOrgId())
+	if err != nil {
+		if errors.Is(err, server.ErrOrgNotFound) {
+			return nil, status.Errorf(codes.NotFound, "organization not found")
+		}
+
+		log.Error().Err(err).Ctx(ctx).Msg("error of delete org")
+		return nil, status.Errorf(codes.Internal, "delete org error")
+	}
+
+	for _, m := range members {
+		s.Notifier.Notify(m.UserID)
+	}
+
+	log.Ctx(ctx).Printf("DeleteOrg success")
+	return &pb.DeleteOrgResponse{}, nil
+}
+
+func (s *OrgServer) ListMembers(ctx context.Context, req *pb.ListMembersRequest) (*pb.ListMembersResponse, error) {
+	log.Ctx(ctx).Printf("ListMembers, org id:%v", req.GetOrgId())
+
+	userID, ok := userid.Get(ctx)
+	if !ok {
+		log.Ctx(ctx).Printf(ErrNoUserID.Error())
+		//nolint:wrapcheck // not need wrap error from status package
+		return nil, status.Error(codes.Unauthenticated, ErrNoUserID.Error())
+	}
+
+	_, err := s.role(ctx, req.GetOrgId(), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := s.Orgs.ListMembers(ctx, req.GetOrgId())
+	if err != nil {
+		log.Error().Err(err).Ctx(ctx).Msg("error of list members")
+		return nil, status.Errorf(codes.Internal, "list members error")
+	}
+
+	members := make([]*pb.Member, 0, len(l))
+	for _, m := range l {
+		members = append(members, &pb.Member{
+			Login:      m.Login,
+			Role:       string(m.Role),
+			CreateTime: timestamppb.New(m.CreateTime),
+		})
+	}
+
+	return &pb.ListMembersResponse{Members: members}, nil
+}
+
+func (s *OrgServer) SetMemberRole(ctx context.Context, req *pb.SetMemberRoleRequest) (*pb.SetMemberRoleResponse,
+	error) {
+	log.Ctx(ctx).Printf("SetMemberRole, org id:%v, Login:%s, Role:%s", req.GetOrgId(), req.GetLogin(), req.GetRole())
+
+	userID, ok := userid.Get(ctx)
+	if !ok {
+		log.Ctx(ctx).Printf(ErrNoUserID.Error())
+		//nolint:wrapcheck // not need wrap error from status package
+		return nil, status.Error(codes.Unauthenticated, ErrNoUserID.Error())
+	}
+
+	newRole := server.Role(req.GetRole())
+	if !validRole(newRole) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid role")
+	}
+
+	role, err := s.role(ctx, req.GetOrgId(), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	member, memberRole, err := s.member(ctx, req.GetOrgId(), req.GetLogin())
+	if err != nil {
+		return nil, err
+	}
+
+	if member.ID == userID {
+		return nil, status.Errorf(codes.InvalidArgument, "can not change own role")
+	}
+
+	if !canManage(role, memberRole) || !canManage(role, newRole) {
+		return nil, status.Errorf(codes.PermissionDenied, "not enough rights to change role")
+	}
+
+	err = s.Orgs.SetMemberRole(ctx, req.GetOrgId(), member.ID, newRole)
+	if err != nil {
+		if errors.Is(err, server.ErrNotOrgMember) {
+			return nil, status.Errorf(codes.NotFound, "member not found")
+		}
+
+		if errors.Is(err, server.ErrLastOwner) {
+			return nil, status.Errorf(codes.FailedPrecondition, "organization must have an owner")
+		}
+
+		log.Error().Err(err).Ctx(ctx).Msg("error of set member role")
+		return nil, status.Errorf(codes.Internal, "set member role error")
+	}
+
+	log.Ctx(ctx).Printf("SetMemberRole success")
+	return &pb.SetMemberRoleResponse{}, nil
+}
+
+func (s *OrgServer) RemoveMember(ctx context.Context, req *pb.RemoveMemberRequest) (*pb.RemoveMemberResponse, error) {
+	log.Ctx(ctx).Printf("RemoveMember, org id:%v, Login:%s", req.GetOrgId(), req.GetLogin())
+
+	userID, ok := userid.Get(ctx)
+	if !ok {
+		log.Ctx(ctx).Printf(ErrNoUserID.Error())
+		//nolint:wrapcheck // not need wrap error from status package
+		return nil, status.Error(codes.Unauthenticated, ErrNoUserID.Error())
+	}
+
+	role, err := s.role(ctx, req.GetOrgId(), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	member, memberRole, err := s.member(ctx, req.GetOrgId(), req.GetLogin())
+	if err != nil {
+		return nil, err
+	}
+
+	// Покинуть организацию может любой участник, кроме последнего владельца, это проверяется при удалении.
+	if member.ID != userID && !canManage(role, memberRole) {
+		return nil, status.Errorf(codes.PermissionDenied, "not enough rights to remove member")
+	}
+
+	err = s.Orgs.DeleteMember(ctx, req.GetOrgId(), member.ID)
+	if err != nil {
+		if errors.Is(err, server.ErrNotOrgMember) {
+			return nil, status.Errorf(codes.NotFound, "member not found")
+		}
+
+		if errors.Is(err, server.ErrLastOwner) {
+			return nil, status.Errorf(codes.FailedPrecondition,
+				"last owner can not leave organization, delete it instead")
+		}
+
+		log.Error().Err(err).Ctx(ctx).Msg("error of delete member")
+		return nil, status.Errorf(codes.Internal, "remove member error")
+	}
+	s.Notifier.Notify(member.ID)
+
+	log.Ctx(ctx).Printf("RemoveMember success")
+	return &pb.RemoveMemberResponse{}, nil
+}
+
+func (s *OrgServer) InviteMember(ctx context.Context, req *pb.InviteMemberRequest) (*pb.InviteMemberResponse, error) {
+	log.Ctx(ctx).Printf("InviteMember, org id:%v, Login:%s, Role:%s", req.GetOrgId(), req.GetLogin(), req.GetRole())
+
+	userID, ok := userid.Get(ctx)
+	if !ok {
+		log.Ctx(ctx).Printf(ErrNoUserID.Error())
+		//nolint:wrapcheck // not need wrap error from status package
+		return nil, status.Error(codes.Unauthenticated, ErrNoUserID.Error())
+	}
+
+	invitedRole := server.Role(req.GetRole())
+	if !validRole(invitedRole) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid role")
+	}
+
+	if len(req.GetKey()) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "empty key")
+	}
+
+	role, err := s.role(ctx, req.GetOrgId(), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !canManage(role, invitedRole) {
+		return nil, status.Errorf(codes.PermissionDenied, "not enough rights to invite with role %s", invitedRole)
+	}
+
+	user, err := s.Users.GetUser(ctx, req.GetLogin())
+	if err != nil {
+		if errors.Is(err, server.ErrUserNotFound) {
+			return nil, status.Errorf(codes.NotFound, "user not found")
+		}
+
+		log.Error().Err(err).Ctx(ctx).Msg("error of get user")
+		return nil, status.Errorf(codes.Internal, "invite member error")
+	}
+
+	if len(user.KeyPair.Public) == 0 {
+		return nil, status.Errorf(codes.FailedPrecondition, "user has no public key yet, user must login first")
+	}
+
+	id, err := s.Orgs.CreateInvitation(ctx, &server.Invitation{
+		OrgID:     req.GetOrgId(),
+		UserID:    user.ID,
+		InviterID: userID,
+		Role:      invitedRole,
+		OrgKey:    req.GetKey(),
+	})
+	if err != nil {
+		if errors.Is(err, server.ErrAlreadyOrgMember) {
+			return nil, status.Errorf(codes.AlreadyExists, "user is already member of organization")
+		}
+
+		log.Error().Err(err).Ctx(ctx).Msg("error of create invitation")
+		return nil, status.Errorf(codes.Internal, "invite member error")
+	}
+
+	log.Ctx(ctx).Printf("InviteMember success, invitation id:%v", id)
+	return &pb.InviteMemberResponse{Id: id}, nil
+}
+
+func (s *OrgServer) ListInvitations(ctx context.Context, req *pb.ListInvitationsRequest) (
+	*pb.ListInvitationsResponse, error) {
+	log.Ctx(ctx).Printf("ListInvitations")
+
+	userID, ok := userid.Get(ctx)
+	if !ok {
+		log.Ctx(ctx).Printf(ErrNoUserID.Error())
+		//nolint:wrapcheck // not need wrap error from status package
+		return nil, status.Error(codes.Unauthenticated, ErrNoUserID.Error())
+	}
+
+	l, err := s.Orgs.ListInvitations(ctx, userID)
+	if err != nil {
+		log.Error().Err(err).Ctx(ctx).Msg("error of list invitations")
+		return nil, status.Errorf(codes.Internal, "list invitations error")
+	}
+
+	invitations := make([]*pb.Invitation, 0, len(l))
+	for _, i := range l {
+		invitations = append(invitations, &pb.Invitation{
+			Id:         i.ID,
+			OrgId:      i.OrgID,
+			OrgName:    i.OrgName,
+			Inviter:    i.Inviter,
+			Role:       string(i.Role),
+			CreateTime: timestamppb.New(i.CreateTime),
+		})
+	}
+
+	return &pb.ListInvitationsResponse{Invitations: invitations}, nil
+}
+
+func (s *OrgServer) AcceptInvitation(ctx context.Context, req *pb.AcceptInvitationRequest) (
+	*pb.AcceptInvitationResponse, error) {
+	log.Ctx(ctx).Printf("AcceptInvitation, id:%v", req.GetId())
+
+	userID, ok := userid.Get(ctx)
+	if !ok {
+		log.Ctx(ctx).Printf(ErrNoUserID.Error())
+		//nolint:wrapcheck // not need wrap error from status package
+		return nil, status.Error(codes.Unauthenticated, ErrNoUserID.Error())
+	}
+
+	err := s.Orgs.AcceptInvitation(ctx, userID, req.GetId())
+	if err != nil {
+		if errors.Is(err, server.ErrInvitationNotFound) {
+			return nil, status.Errorf(codes.NotFound, "invitation not found")
+		}
+
+		log.Error().Err(err).Ctx(ctx).Msg("error of accept invitation")
+		return nil, status.Errorf(codes.Internal, "accept invitation error")
+	}
+	s.Notifier.Notify(userID)
+
+	log.Ctx(ctx).Printf("AcceptInvitation success")
+	return &pb.AcceptInvitationResponse{}, nil
+}
+
+func (s *OrgServer) DeclineInvitation(ctx context.Context, req *pb.DeclineInvitationRequest) (
+	*pb.DeclineInvitationResponse, error) {
+	log.Ctx(ctx).Printf("DeclineInvitation, id:%v", req.GetId())
+
+	userID, ok := userid.Get(ctx)
+	if !ok {
+		log.Ctx(ctx).Printf(ErrNoUserID.Error())
+		//nolint:wrapcheck // not need wrap error from status package
+		return nil, status.Error(codes.Unauthenticated, ErrNoUserID.Error())
+	}
+
+	err := s.Orgs.DeleteInvitation(ctx, userID, req.GetId())
+	if err != nil {
+		if errors.Is(err, server.ErrInvitationNotFound) {
+			return nil, status.Errorf(codes.NotFound, "invitation not found")
+		}
+
+		log.Error().Err(err).Ctx(ctx).Msg("error of delete invitation")
+		return nil, status.Errorf(codes.Internal, "decline invitation error")
+	}
+
+	log.Ctx(ctx).Printf("DeclineInvitation success")
+	return &pb.DeclineInvitationResponse{}, nil
+}
+
+func (s *OrgServer) CreateCollection(ctx context.Context, req *pb.CreateCollectionRequest) (
+	*pb.CreateCollectionResponse, error) {
+	log.Ctx(ctx).Printf("CreateCollection, org id:%v", req.GetOrgId())
+
+	userID, ok := userid.Get(ctx)
+	if !ok {
+		log.Ctx(ctx).Printf(ErrNoUserID.Error())
+		//nolint:wrapcheck // not need wrap error from status package
+		return nil, status.Error(codes.Unauthenticated, ErrNoUserID.Error())
+	}
+
+	if len(req.GetName()) == 0 || len(req.GetKey()) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "empty name or key")
+	}
+
+	role, err := s.role(ctx, req.GetOrgId(), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !canManageCollections(role) {
+		return nil, status.Errorf(codes.PermissionDenied, "not enough rights to create collection")
+	}
+
+	id, err := s.Orgs.CreateCollection(ctx, &server.Collection{
+		OrgID: req.GetOrgId(),
+		Name:  req.GetName(),
+		Key:   req.GetKey(),
+	})
+	if err != nil {
+		log.Error().Err(err).Ctx(ctx).Msg("error of create collection")
+		return nil, status.Errorf(codes.Internal, "create collection error")
+	}
+
+	log.Ctx(ctx).Printf("CreateCollection success, id:%v", id)
+	return &pb.CreateCollectionResponse{Id: id}, nil
+}
+
+func (s *OrgServer) ListCollections(ctx context.Context, req *pb.ListCollectionsRequest) (
+	*pb.ListCollectionsResponse, error) {
+	log.Ctx(ctx).Printf("ListCollections")
+
+	userID, ok := userid.Get(ctx)
+	if !ok {
+		log.Ctx(ctx).Printf(ErrNoUserID.Error())
+		//nolint:wrapcheck // not need wrap error from status package
+		return nil, status.Error(codes.Unauthenticated, ErrNoUserID.Error())
+	}
+
+	l, err := s.Orgs.ListCollections(ctx, userID)
+	if err != nil {
+		log.Error().Err(err).Ctx(ctx).Msg("error of list collections")
+		return nil, status.Errorf(codes.Internal, "list collections error")
+	}
+
+	collections := make([]*pb.Collection, 0, len(l))
+	for _, c := range l {
+		collections = append(collections, &pb.Collection{
+			Id:    c.ID,
+			OrgId: c.OrgID,
+			Name:  c.Name,
+			Key:   c.Key,
+		})
+	}
+
+	return &pb.ListCollectionsResponse{Collections: collections}, nil
+}
+
+func (s *OrgServer) DeleteCollection(ctx context.Context, req *pb.DeleteCollectionRequest) (
+	*pb.DeleteCollectionResponse, error) {
+	log.Ctx(ctx).Printf("DeleteCollection, id:%v", req.GetId())
+
+	userID, ok := userid.Get(ctx)
+	if !ok {
+		log.Ctx(ctx).Printf(ErrNoUserID.Error())
+		//nolint:wrapcheck // not need wrap error from status package
+		return nil, status.Error(codes.Unauthenticated, ErrNoUserID.Error())
+	}
+
+	role, err := collectionRole(ctx, s.Orgs, userID, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	if !canManageCollections(role) {
+		return nil, status.Errorf(codes.PermissionDenied, "not enough rights to delete collection")
+	}
+
+	members, err := s.Orgs.ListCollectionMembers(ctx, req.GetId())
+	if err != nil {
+		log.Error().Err(err).Ctx(ctx).Msg("error of list collection members")
+		return nil, status.Errorf(codes.Internal, "delete collection error")
+	}
+
+	err = s.Orgs.DeleteCollection(ctx, req.GetId())
+	if err != nil {
+		if errors.Is(err, server.ErrCollectionNotFound) {
+			return nil, status.Errorf(codes.NotFound, "collection not found")
+		}
+
+		log.Error().Err(err).Ctx(ctx).Msg("error of delete collection")
+		return nil, status.Errorf(codes.Internal, "delete collection error")
+	}
+
+	for _, id := range members {
+		s.Notifier.Notify(id)
+	}
+
+	log.Ctx(ctx).Printf("DeleteCollection success")
+	return &pb.DeleteCollectionResponse{}, nil
+}
+
+// role - роль пользователя в организации. Организация, в которой пользователь не состоит, для него не существует.
+func (s *OrgServer) role(ctx context.Context, orgID, userID int64) (server.Role, error) {
+	role, err := s.Orgs.GetRole(ctx, orgID, userID)
+	if err != nil {
+		if errors.Is(err, server.ErrNotOrgMember) {
+			return "", status.Errorf(codes.NotFound, "organization not found")
+		}
+
+		log.Error().Err(err).Ctx(ctx).Msg("error of get role")
+		return "", status.Errorf(codes.Internal, "get role error")
+	}
+
+	return role, nil
+}
+
+// member - получить участника организации по логину и его роль.
+func (s *OrgServer) member(ctx context.Context, orgID int64, login string) (*server.User, server.Role, error) {
+	user, err := s.Users.GetUser(ctx, login)
+	if err != nil {
+		if errors.Is(err, server.ErrUserNotFound) {
+			return nil, "", status.Errorf(codes.NotFound, "member not found")
+		}
+
+		log.Error().Err(err).Ctx(ctx).Msg("error of get user")
+		return nil, "", status.Errorf(codes.Internal, "get user error")
+	}
+
+	role, err := s.Orgs.GetRole(ctx, orgID, user.ID)
+	if err != nil {
+		if errors.Is(err, server.ErrNotOrgMember) {
+			return nil, "", status.Errorf(codes.NotFound, "member not found")
+		}
+
+		log.Error().Err(err).Ctx(ctx).Msg("error of get role")
+		return nil, "", status.Errorf(codes.Internal, "get role error")
+	}
+
+	return user, role, nil
+}
+
+// collectionRole - роль пользователя в организации коллекции. Коллекция организации, в которой пользователь
+// не состоит, для него не существует.
+func collectionRole(ctx context.Context, orgs server.OrgStorage, userID, collectionID int64) (server.Role, error) {
+	c, err := orgs.GetCollection(ctx, collectionID)
+	if err != nil {
+		if errors.Is(err, server.ErrCollectionNotFound) {
+			return "", status.Errorf(codes.NotFound, "collection not found")
+		}
+
+		log.Error().Err(err).Ctx(ctx).Msg("error of get collection")
+		return "", status.Errorf(codes.Internal, "get collection error")
+	}
+
+	role, err := orgs.GetRole(ctx, c.OrgID, userID)
+	if err != nil {
+		if errors.Is(err, server.ErrNotOrgMember) {
+			return "", status.Errorf(codes.NotFound, "collection not found")
+		}
+
+		log.Error().Err(err).Ctx(ctx).Msg("error of get role")
+		return "", status.Errorf(codes.Internal, "get role error")
+	}
+
+	return role, nil
+}
+
+func validRole(r server.Role) bool {
+	switch r {
+	case server.RoleOwner, server.RoleAdmin, server.RoleMember, server.RoleReadOnly:
+		return true
+	default:
+		return false
+	}
+}
+
+// canManage - может ли участник с ролью r приглашать, исключать участников с ролью target и назначать им роль.
+func canManage(r, target server.Role) bool {
+	switch r {
+	case server.RoleOwner:
+		return true
+	case server.RoleAdmin:
+		return target == server.RoleMember || target == server.RoleReadOnly
+	default:
+		return false
+	}
+}
+
+func canManageCollections(r server.Role) bool {
+	return r == server.RoleOwner || r == server.RoleAdmin
+}
@@ -0,0 +1,488 @@
+package handler
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/k0st1a/gophkeeper/internal/adapters/api/grpc/gen/proto/v1"
+	"github.com/k0st1a/gophkeeper/internal/pkg/userid"
+	"github.com/k0st1a/gophkeeper/internal/ports/server"
+)
+
+// Участники тестовой организации 1.
+const (
+	testOwner    int64 = 1
+	testOwner2   int64 = 2
+	testAdmin    int64 = 3
+	testMember   int64 = 4
+	testReader   int64 = 5
+	testOutsider int64 = 6
+)
+
+// loginUsers - пользователи по логину.
+type loginUsers struct {
+	server.UserStorage
+}
+
+var testLogins = map[string]int64{
+	"owner":    testOwner,
+	"owner2":   testOwner2,
+	"admin":    testAdmin,
+	"member":   testMember,
+	"reader":   testReader,
+	"outsider": testOutsider,
+}
+
+func (s *loginUsers) GetUser(ctx context.Context, login string) (*server.User, error) {
+	id, ok := testLogins[login]
+	if !ok {
+		return nil, server.ErrUserNotFound
+	}
+
+	return &server.User{ID: id, Login: login}, nil
+}
+
+// orgStorage - организация 1 с коллекцией 1. Как и в хранилище, последнего владельца нельзя исключить
+// или снять с него роль владельца.
+type orgStorage struct {
+	server.OrgStorage
+	members map[int64]server.Role
+	// Ошибка изменения участника, например, если другой владелец одновременно покинул организацию
+	err               error
+	collectionDeleted bool
+}
+
+func newOrgStorage(members map[int64]server.Role) *orgStorage {
+	if members == nil {
+		members = map[int64]server.Role{
+			testOwner:  server.RoleOwner,
+			testOwner2: server.RoleOwner,
+			testAdmin:  server.RoleAdmin,
+			testMember: server.RoleMember,
+			testReader: server.RoleReadOnly,
+		}
+	}
+
+	return &orgStorage{members: members}
+}
+
+func (s *orgStorage) GetRole(ctx context.Context, orgID, userID int64) (server.Role, error) {
+	role, ok := s.members[userID]
+	if orgID != 1 || !ok {
+		return "", server.ErrNotOrgMember
+	}
+
+	return role, nil
+}
+
+func (s *orgStorage) lastOwner(userID int64) bool {
+	if s.members[userID] != server.RoleOwner {
+		return false
+	}
+
+	owners := 0
+	for _, r := range s.members {
+		if r == server.RoleOwner {
+			owners++
+		}
+	}
+
+	return owners == 1
+}
+
+func (s *orgStorage) SetMemberRole(ctx context.Context, orgID, userID int64, role server.Role) error {
+	if s.err != nil {
+		return s.err
+	}
+
+	if role != server.RoleOwner && s.lastOwner(userID) {
+		return server.ErrLastOwner
+	}
+
+	s.members[userID] = role
+	return nil
+}
+
+func (s *orgStorage) DeleteMember(ctx context.Context, orgID, userID int64) error {
+	if s.err != nil {
+		return s.err
+	}
+
+	if s.lastOwner(userID) {
+		return server.ErrLastOwner
+	}
+
+	delete(s.members, userID)
+	return nil
+}
+
+func (s *orgStorage) GetCollection(ctx context.Context, collectionID int64) (*server.Collection, error) {
+	if collectionID != 1 {
+		return nil, server.ErrCollectionNotFound
+	}
+
+	return &server.Collection{ID: 1, OrgID: 1}, nil
+}
+
+func (s *orgStorage) ListCollectionMembers(ctx context.Context, collectionID int64) ([]int64, error) {
+	ids := make([]int64, 0, len(s.members))
+	for id := range s.members {
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+func (s *orgStorage) DeleteCollection(ctx context.Context, collectionID int64) error {
+	s.collectionDeleted = true
+	return nil
+}
+
+// notifier - запоминает уведомленных пользователей.
+type notifier struct {
+	notified []int64
+}
+
+func (n *notifier) Subscribe(userID int64) (<-chan struct{}, func()) {
+	return nil, func() {}
+}
+
+func (n *notifier) Notify(userID int64) {
+	n.notified = append(n.notified, userID)
+}
+
+func newOrgServer(orgs *orgStorage) *OrgServer {
+	return &OrgServer{
+		Users:    &loginUsers{},
+		Orgs:     orgs,
+		Notifier: &notifier{},
+	}
+}
+
+func TestCanManage(t *testing.T) {
+	roles := []server.Role{server.RoleOwner, server.RoleAdmin, server.RoleMember, server.RoleReadOnly}
+
+	tests := []struct {
+		role    server.Role
+		targets []server.Role
+	}{
+		{
+			role:    server.RoleOwner,
+			targets: roles,
+		},
+		{
+			role:    server.RoleAdmin,
+			targets: []server.Role{server.RoleMember, server.RoleReadOnly},
+		},
+		{
+			role: server.RoleMember,
+		},
+		{
+			role: server.RoleReadOnly,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.role), func(t *testing.T) {
+			for _, target := range roles {
+				require.Equal(t, slices.Contains(test.targets, target), canManage(test.role, target), target)
+			}
+		})
+	}
+}
+
+func TestSetMemberRole(t *testing.T) {
+	tests := []struct {
+		storageErr error
+		members    map[int64]server.Role
+		name       string
+		login      string
+		role       server.Role
+		want       server.Role
+		caller     int64
+		code       codes.Code
+	}{
+		{
+			name:   "Check owner makes member admin",
+			caller: testOwner,
+			login:  "member",
+			role:   server.RoleAdmin,
+			want:   server.RoleAdmin,
+		},
+		{
+			name:   "Check owner demotes other owner",
+			caller: testOwner,
+			login:  "owner2",
+			role:   server.RoleMember,
+			want:   server.RoleMember,
+		},
+		{
+			name:   "Check admin makes member read-only",
+			caller: testAdmin,
+			login:  "member",
+			role:   server.RoleReadOnly,
+			want:   server.RoleReadOnly,
+		},
+		{
+			name:   "Check admin can not make member admin",
+			caller: testAdmin,
+			login:  "member",
+			role:   server.RoleAdmin,
+			want:   server.RoleMember,
+			code:   codes.PermissionDenied,
+		},
+		{
+			name:   "Check admin can not make member owner",
+			caller: testAdmin,
+			login:  "member",
+			role:   server.RoleOwner,
+			want:   server.RoleMember,
+			code:   codes.PermissionDenied,
+		},
+		{
+			name:   "Check admin can not demote owner",
+			caller: testAdmin,
+			login:  "owner",
+			role:   server.RoleMember,
+			want:   server.RoleOwner,
+			code:   codes.PermissionDenied,
+		},
+		{
+			name:   "Check member can not change role",
+			caller: testMember,
+			login:  "reader",
+			role:   server.RoleMember,
+			want:   server.RoleReadOnly,
+			code:   codes.PermissionDenied,
+		},
+		{
+			name:   "Check read-only can not change role",
+			caller: testReader,
+			login:  "member",
+			role:   server.RoleReadOnly,
+			want:   server.RoleMember,
+			code:   codes.PermissionDenied,
+		},
+		{
+			name:   "Check owner can not change own role",
+			caller: testOwner,
+			login:  "owner",
+			role:   server.RoleMember,
+			want:   server.RoleOwner,
+			code:   codes.InvalidArgument,
+		},
+		{
+			name:   "Check admin can not change own role",
+			caller: testAdmin,
+			login:  "admin",
+			role:   server.RoleOwner,
+			want:   server.RoleAdmin,
+			code:   codes.InvalidArgument,
+		},
+		{
+			name:   "Check invalid role",
+			caller: testOwner,
+			login:  "member",
+			role:   "root",
+			want:   server.RoleMember,
+			code:   codes.InvalidArgument,
+		},
+		{
+			name:   "Check not member of organization",
+			caller: testOutsider,
+			login:  "member",
+			role:   server.RoleReadOnly,
+			want:   server.RoleMember,
+			code:   codes.NotFound,
+		},
+		{
+			name:   "Check role of not member",
+			caller: testOwner,
+			login:  "outsider",
+			role:   server.RoleReadOnly,
+			code:   codes.NotFound,
+		},
+		{
+			name:       "Check other owner left organization concurrently",
+			caller:     testOwner,
+			login:      "owner2",
+			role:       server.RoleMember,
+			storageErr: server.ErrLastOwner,
+			want:       server.RoleOwner,
+			code:       codes.FailedPrecondition,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			orgs := newOrgStorage(test.members)
+			orgs.err = test.storageErr
+			s := newOrgServer(orgs)
+
+			_, err := s.SetMemberRole(userid.Set(context.Background(), test.caller), &pb.SetMemberRoleRequest{
+				OrgId: 1,
+				Login: test.login,
+				Role:  string(test.role),
+			})
+			require.Equal(t, test.code, status.Code(err))
+			require.Equal(t, test.want, orgs.members[testLogins[test.login]])
+		})
+	}
+}
+
+func TestRemoveMember(t *testing.T) {
+	oneOwner := map[int64]server.Role{
+		testOwner:  server.RoleOwner,
+		testAdmin:  server.RoleAdmin,
+		testMember: server.RoleMember,
+	}
+
+	tests := []struct {
+		members map[int64]server.Role
+		name    string
+		login   string
+		caller  int64
+		code    codes.Code
+		removed bool
+	}{
+		{
+			name:    "Check owner removes admin",
+			caller:  testOwner,
+			login:   "admin",
+			removed: true,
+		},
+		{
+			name:    "Check owner removes other owner",
+			caller:  testOwner,
+			login:   "owner2",
+			removed: true,
+		},
+		{
+			name:    "Check admin removes member",
+			caller:  testAdmin,
+			login:   "member",
+			removed: true,
+		},
+		{
+			name:   "Check admin can not remove owner",
+			caller: testAdmin,
+			login:  "owner",
+			code:   codes.PermissionDenied,
+		},
+		{
+			name:   "Check member can not remove read-only",
+			caller: testMember,
+			login:  "reader",
+			code:   codes.PermissionDenied,
+		},
+		{
+			name:    "Check member leaves organization",
+			caller:  testMember,
+			login:   "member",
+			removed: true,
+		},
+		{
+			name:    "Check read-only leaves organization",
+			caller:  testReader,
+			login:   "reader",
+			removed: true,
+		},
+		{
+			name:    "Check owner leaves organization with other owner",
+			caller:  testOwner,
+			login:   "owner",
+			removed: true,
+		},
+		{
+			name:    "Check last owner can not leave organization",
+			members: oneOwner,
+			caller:  testOwner,
+			login:   "owner",
+			code:    codes.FailedPrecondition,
+		},
+		{
+			name:   "Check not member of organization",
+			caller: testOutsider,
+			login:  "member",
+			code:   codes.NotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			orgs := newOrgStorage(test.members)
+			s := newOrgServer(orgs)
+
+			_, err := s.RemoveMember(userid.Set(context.Background(), test.caller), &pb.RemoveMemberRequest{
+				OrgId: 1,
+				Login: test.login,
+			})
+			require.Equal(t, test.code, status.Code(err))
+
+			_, ok := orgs.members[testLogins[test.login]]
+			require.Equal(t, test.removed, !ok)
+		})
+	}
+}
+
+func TestDeleteCollection(t *testing.T) {
+	tests := []struct {
+		name         string
+		caller       int64
+		collectionID int64
+		code         codes.Code
+	}{
+		{
+			name:         "Check owner deletes collection",
+			caller:       testOwner,
+			collectionID: 1,
+		},
+		{
+			name:         "Check admin deletes collection",
+			caller:       testAdmin,
+			collectionID: 1,
+		},
+		{
+			name:         "Check member can not delete collection",
+			caller:       testMember,
+			collectionID: 1,
+			code:         codes.PermissionDenied,
+		},
+		{
+			name:         "Check read-only can not delete collection",
+			caller:       testReader,
+			collectionID: 1,
+			code:         codes.PermissionDenied,
+		},
+		{
+			name:         "Check collection of other organization",
+			caller:       testOutsider,
+			collectionID: 1,
+			code:         codes.NotFound,
+		},
+		{
+			name:         "Check unknown collection",
+			caller:       testOwner,
+			collectionID: 2,
+			code:         codes.NotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			orgs := newOrgStorage(nil)
+			s := newOrgServer(orgs)
+
+			_, err := s.DeleteCollection(userid.Set(context.Background(), test.caller), &pb.DeleteCollectionRequest{
+				Id: test.collectionID,
+			})
+			require.Equal(t, test.code, status.Code(err))
+			require.Equal(t, test.code == codes.OK, orgs.collectionDeleted)
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/k0st1a/gophkeeper/internal/adapters/api/grpc/gen/proto/v1"
+	"github.com/k0st1a/gophkeeper/internal/pkg/auth"
+	"github.com/k0st1a/gophkeeper/internal/pkg/srp"
+	"github.com/k0st1a/gophkeeper/internal/ports/server"
+	"github.com/rs/zerolog/log"
+)
+
+func (s *UserServer) ChangePassword(ctx context.Context, req *pb.ChangePasswordRequest) (*pb.ChangePasswordResponse,
+	error) {
+	log.Ctx(ctx).Printf("ChangePassword")
+
+	userID, sessionID, err := getSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(req.GetVerifier().GetSalt()) == 0 || len(req.GetVerifier().GetVerifier()) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "empty srp verifier")
+	}
+
+	user, err := s.Storage.GetUserByID(ctx, userID)
+	if err != nil {
+		log.Error().Err(err).Ctx(ctx).Msg("error of get user")
+		return nil, status.Errorf(codes.Internal, "change password error")
+	}
+
+	keys := loginKeys(ctx, user.Login)
+
+	err = s.checkLogin(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	m2, err := s.checkOldPassword(ctx, user, keys, req)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &server.PasswordChange{
+		Verifier: server.SRPVerifier{
+			Salt:     req.GetVerifier().GetSalt(),
+			Verifier: req.GetVerifier().GetVerifier(),
+		},
+	}
+
+	if req.GetVaultKey() != nil {
+		if len(req.GetVaultKey().GetKey()) == 0 || len(req.GetVaultKey().GetSalt()) == 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "empty vault key")
+		}
+
+		c.VaultKey = &server.VaultKey{
+			Salt: req.GetVaultKey().GetSalt(),
+			Key:  req.GetVaultKey().GetKey(),
+		}
+	}
+
+	revoked, err := s.Storage.ChangePassword(ctx, user, sessionID, c)
+	if err != nil {
+		if errors.Is(err, server.ErrPasswordChanged) {
+			return nil, status.Errorf(codes.Aborted, "password changed concurrently")
+		}
+
+		log.Error().Err(err).Ctx(ctx).Msg("error of change password")
+		return nil, status.Errorf(codes.Internal, "change password error")
+	}
+
+	log.Ctx(ctx).Printf("ChangePassword success, UserId:%d, revoked sessions:%d", userID, revoked)
+	return &pb.ChangePasswordResponse{
+		ServerProof: m2,
+		Revoked:     revoked,
+	}, nil
+}
+
+// passwordProof - подтверждение текущего пароля в запросе: доказательство SRP по сессии из StartLogin или,
+// если у пользователя еще нет верификатора SRP, сам пароль.
+type passwordProof interface {
+	GetSession() string
+	GetProof() []byte
+	GetOldPassword() string
+}
+
+// checkOldPassword - проверить старый пароль: по доказательству SRP или, если у пользователя еще нет
+// верификатора SRP, по хэшу пароля. Возвращает доказательство сервера M2.
+func (s *UserServer) checkOldPassword(ctx context.Context, user *server.User, keys []server.LoginKey,
+	req passwordProof) ([]byte, error) {
+	if len(user.SRP.Verifier) == 0 {
+		if req.GetOldPassword() == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "empty old password")
+		}
+
+		_, err := s.Auth.CheckPasswordHash(req.GetOldPassword(), user.Password)
+		if err != nil {
+			return nil, s.loginFailed(ctx, keys)
+		}
+
+		return nil, nil
+	}
+
+	if req.GetSession() == "" || len(req.GetProof()) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "empty session or proof")
+	}
+
+	l, err := s.SRPLogins.TakeSRPLogin(ctx, auth.HashChallenge(req.GetSession()))
+	if err != nil {
+		if errors.Is(err, server.ErrSRPLoginNotFound) {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid or expired login session")
+		}
+
+		log.Error().Err(err).Ctx(ctx).Msg("error of take srp login")
+		return nil, status.Errorf(codes.Internal, "check old password error")
+	}
+
+	if l.UserID != user.ID {
+		return nil, status.Errorf(codes.InvalidArgument, "login session of another user")
+	}
+
+	m2, err := srp.VerifyClient(user.Login, user.SRP.Salt, user.SRP.Verifier, l.Secret, l.PublicA, l.PublicB,
+		req.GetProof())
+	if err != nil {
+		log.Ctx(ctx).Printf("Check old password => %v", err)
+		return nil, s.loginFailed(ctx, keys)
+	}
+
+	return m2, nil
+}
@@ -0,0 +1,321 @@
+package handler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/k0st1a/gophkeeper/internal/adapters/api/grpc/gen/proto/v1"
+	"github.com/k0st1a/gophkeeper/internal/pkg/auth"
+	"github.com/k0st1a/gophkeeper/internal/pkg/lockout"
+	"github.com/k0st1a/gophkeeper/internal/pkg/sessionid"
+	"github.com/k0st1a/gophkeeper/internal/pkg/srp"
+	"github.com/k0st1a/gophkeeper/internal/pkg/userid"
+	"github.com/k0st1a/gophkeeper/internal/ports/server"
+)
+
+// testHasher - быстрое хэширование паролей для тестов.
+var testHasher = auth.NewArgon2Hasher(auth.Argon2Params{Time: 1, Memory: 1024, Threads: 1})
+
+type userStorage struct {
+	server.UserStorage
+	err      error
+	user     *server.User
+	change   *server.PasswordChange
+	recovery *server.RecoveryKey
+}
+
+func (s *userStorage) GetUser(ctx context.Context, login string) (*server.User, error) {
+	if s.user == nil || s.user.Login != login {
+		return nil, server.ErrUserNotFound
+	}
+
+	return s.user, nil
+}
+
+func (s *userStorage) GetUserByID(ctx context.Context, id int64) (*server.User, error) {
+	return s.user, nil
+}
+
+func (s *userStorage) SetSRPVerifier(ctx context.Context, userID int64, v *server.SRPVerifier) error {
+	if len(s.user.SRP.Verifier) != 0 {
+		return server.ErrSRPVerifierAlreadySet
+	}
+
+	s.user.SRP = *v
+	s.user.Password = ""
+	return nil
+}
+
+func (s *userStorage) ChangePassword(ctx context.Context, old *server.User, sessionID string,
+	c *server.PasswordChange) (int64, error) {
+	if s.err != nil {
+		return 0, s.err
+	}
+
+	s.change = c
+	return 1, nil
+}
+
+func (s *userStorage) SetRecoveryKey(ctx context.Context, old *server.User, sessionID string,
+	key *server.RecoveryKey) (int64, error) {
+	if s.err != nil {
+		return 0, s.err
+	}
+
+	s.recovery = key
+	return 1, nil
+}
+
+type loginFailures struct {
+	server.LoginFailureStorage
+	failures int
+}
+
+func (s *loginFailures) CheckLogin(ctx context.Context, keys []server.LoginKey) (time.Time, error) {
+	return time.Time{}, nil
+}
+
+func (s *loginFailures) AddLoginFailure(ctx context.Context, key server.LoginKey, window time.Duration,
+	delay func(failures int) (time.Duration, bool)) (time.Time, error) {
+	s.failures++
+	return time.Time{}, nil
+}
+
+func (s *loginFailures) ResetLoginFailures(ctx context.Context, key server.LoginKey) error {
+	return nil
+}
+
+type srpLogins struct {
+	server.SRPLoginStorage
+	login *server.SRPLogin
+}
+
+func (s *srpLogins) CreateSRPLogin(ctx context.Context, l *server.SRPLogin) error {
+	s.login = l
+	return nil
+}
+
+func (s *srpLogins) TakeSRPLogin(ctx context.Context, hash []byte) (*server.SRPLogin, error) {
+	if s.login == nil {
+		return nil, server.ErrSRPLoginNotFound
+	}
+
+	l := s.login
+	s.login = nil
+	return l, nil
+}
+
+func newUserServer(t *testing.T, user *server.User) (*UserServer, *userStorage, *loginFailures, *srpLogins) {
+	t.Helper()
+
+	us := &userStorage{user: user}
+	lf := &loginFailures{}
+	sl := &srpLogins{}
+
+	return &UserServer{
+		Storage:      us,
+		Attempts:     lf,
+		SRPLogins:    sl,
+		Auth:         auth.New("secret", auth.NewKeySet("secret"), testHasher),
+		Lockout:      lockout.New(),
+		StartLockout: lockout.NewStart(),
+	}, us, lf, sl
+}
+
+// startSRPLogin - начать вход по SRP, как это делает StartLogin, и вычислить доказательство клиента.
+func startSRPLogin(t *testing.T, user *server.User, password string, sl *srpLogins) (*srp.Client, []byte) {
+	t.Helper()
+
+	sc, err := srp.NewClient(user.Login)
+	require.NoError(t, err)
+
+	secret, pubB, err := srp.NewServer(user.SRP.Verifier)
+	require.NoError(t, err)
+
+	sl.login = &server.SRPLogin{
+		Login:   user.Login,
+		Secret:  secret,
+		PublicA: sc.A,
+		PublicB: pubB,
+		UserID:  user.ID,
+	}
+
+	proof, err := sc.Proof(password, user.SRP.Salt, pubB)
+	require.NoError(t, err)
+
+	return sc, proof
+}
+
+func TestCheckOldPassword(t *testing.T) {
+	salt, verifier, err := srp.NewVerifier("login", "password")
+	require.NoError(t, err)
+
+	hash, err := testHasher.Hash("password")
+	require.NoError(t, err)
+
+	legacy := &server.User{ID: 1, Login: "login", Password: hash}
+	user := &server.User{ID: 1, Login: "login", SRP: server.SRPVerifier{Salt: salt, Verifier: verifier}}
+
+	tests := []struct {
+		user     *server.User
+		name     string
+		password string
+		session  string
+		loginOf  int64
+		code     codes.Code
+		failures int
+		noLogin  bool
+	}{
+		{
+			name:     "Check legacy password",
+			user:     legacy,
+			password: "password",
+		},
+		{
+			name:     "Check wrong legacy password",
+			user:     legacy,
+			password: "wrong",
+			code:     codes.InvalidArgument,
+			failures: 1,
+		},
+		{
+			name: "Check empty legacy password",
+			user: legacy,
+			code: codes.InvalidArgument,
+		},
+		{
+			name:     "Check srp proof",
+			user:     user,
+			password: "password",
+			session:  "session",
+		},
+		{
+			name:     "Check wrong srp proof",
+			user:     user,
+			password: "wrong",
+			session:  "session",
+			code:     codes.InvalidArgument,
+			failures: 1,
+		},
+		{
+			name:     "Check srp login of another user",
+			user:     user,
+			password: "password",
+			session:  "session",
+			loginOf:  2,
+			code:     codes.InvalidArgument,
+		},
+		{
+			name:     "Check expired srp login",
+			user:     user,
+			password: "password",
+			session:  "session",
+			noLogin:  true,
+			code:     codes.InvalidArgument,
+		},
+		{
+			name:     "Check empty srp session",
+			user:     user,
+			password: "password",
+			code:     codes.InvalidArgument,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			s, _, lf, sl := newUserServer(t, test.user)
+
+			req := &pb.ChangePasswordRequest{Session: test.session}
+
+			var sc *srp.Client
+			if len(test.user.SRP.Verifier) == 0 {
+				req.OldPassword = test.password
+			} else {
+				sc, req.Proof = startSRPLogin(t, test.user, test.password, sl)
+				if test.loginOf != 0 {
+					sl.login.UserID = test.loginOf
+				}
+				if test.noLogin {
+					sl.login = nil
+				}
+			}
+
+			m2, err := s.checkOldPassword(ctx, test.user, loginKeys(ctx, test.user.Login), req)
+			require.Equal(t, test.code, status.Code(err))
+			require.Equal(t, test.failures, lf.failures)
+
+			if err == nil && sc != nil {
+				require.NoError(t, sc.VerifyServer(m2))
+			}
+		})
+	}
+}
+
+func TestChangePassword(t *testing.T) {
+	hash, err := testHasher.Hash("password")
+	require.NoError(t, err)
+
+	verifier := &pb.SRPVerifier{Salt: []byte("salt"), Verifier: []byte("verifier")}
+	vaultKey := &pb.VaultKey{Salt: []byte("salt"), Key: []byte("key")}
+
+	tests := []struct {
+		storageErr error
+		req        *pb.ChangePasswordRequest
+		vaultKey   *server.VaultKey
+		name       string
+		code       codes.Code
+	}{
+		{
+			name:     "Check change password with vault key",
+			req:      &pb.ChangePasswordRequest{OldPassword: "password", Verifier: verifier, VaultKey: vaultKey},
+			vaultKey: &server.VaultKey{Salt: vaultKey.Salt, Key: vaultKey.Key},
+		},
+		{
+			name: "Check change password with separate master password",
+			req:  &pb.ChangePasswordRequest{OldPassword: "password", Verifier: verifier},
+		},
+		{
+			name: "Check empty vault key",
+			req: &pb.ChangePasswordRequest{OldPassword: "password", Verifier: verifier,
+				VaultKey: &pb.VaultKey{}},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "Check empty verifier",
+			req:  &pb.ChangePasswordRequest{OldPassword: "password"},
+			code: codes.InvalidArgument,
+		},
+		{
+			name:       "Check password changed concurrently",
+			req:        &pb.ChangePasswordRequest{OldPassword: "password", Verifier: verifier},
+			storageErr: server.ErrPasswordChanged,
+			code:       codes.Aborted,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := sessionid.Set(userid.Set(context.Background(), 1), "session")
+			s, us, _, _ := newUserServer(t, &server.User{ID: 1, Login: "login", Password: hash})
+			us.err = test.storageErr
+
+			_, err := s.ChangePassword(ctx, test.req)
+			require.Equal(t, test.code, status.Code(err))
+
+			if test.code != codes.OK {
+				require.Nil(t, us.change)
+				return
+			}
+
+			require.Equal(t, test.req.GetVerifier().GetSalt(), us.change.Verifier.Salt)
+			require.Equal(t, test.req.GetVerifier().GetVerifier(), us.change.Verifier.Verifier)
+			require.Equal(t, test.vaultKey, us.change.VaultKey)
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package handler
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/k0st1a/gophkeeper/internal/adapters/api/grpc/gen/proto/v1"
+	"github.com/k0st1a/gophkeeper/internal/ports/server"
+	"github.com/rs/zerolog/log"
+)
+
+// SetRecoveryKey - заменить ключ восстановления. Текущий пароль подтверждается так же, как при смене пароля:
+// иначе по украденному токену можно было бы сохранить известный ключ восстановления и через Recover сменить
+// пароль. Остальные сессии пользователя отзываются.
+func (s *UserServer) SetRecoveryKey(ctx context.Context, req *pb.SetRecoveryKeyRequest) (*pb.SetRecoveryKeyResponse,
+	error) {
+	log.Ctx(ctx).Printf("SetRecoveryKey")
+
+	userID, sessionID, err := getSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetRecoveryKey() == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "empty recovery key")
+	}
+
+	key, err := makeRecoveryKey(req.GetRecoveryKey())
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.Storage.GetUserByID(ctx, userID)
+	if err != nil {
+		log.Error().Err(err).Ctx(ctx).Msg("error of get user")
+		return nil, status.Errorf(codes.Internal, "set recovery key error")
+	}
+
+	keys := loginKeys(ctx, user.Login)
+
+	err = s.checkLogin(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	m2, err := s.checkOldPassword(ctx, user, keys, req)
+	if err != nil {
+		return nil, err
+	}
+
+	revoked, err := s.Storage.SetRecoveryKey(ctx, user, sessionID, &key)
+	if err != nil {
+		if errors.Is(err, server.ErrPasswordChanged) {
+			return nil, status.Errorf(codes.Aborted, "password changed concurrently")
+		}
+
+		log.Error().Err(err).Ctx(ctx).Msg("error of set recovery key")
+		return nil, status.Errorf(codes.Internal, "set recovery key error")
+	}
+
+	log.Ctx(ctx).Printf("SetRecoveryKey success, UserId:%d, revoked sessions:%d", userID, revoked)
+	return &pb.SetRecoveryKeyResponse{
+		ServerProof: m2,
+		Revoked:     revoked,
+	}, nil
+}
+
+func (s *UserServer) StartRecovery(ctx context.Context, req *pb.StartRecoveryRequest) (*pb.StartRecoveryResponse,
+	error) {
+	log.Ctx(ctx).Printf("StartRecovery, Login:%s", req.GetLogin())
+
+	user, keys, err := s.checkRecovery(ctx, req.GetLogin(), req.GetAuth())
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.Attempts.ResetLoginFailures(ctx, keys[0])
+	if err != nil {
+		log.Error().Err(err).Ctx(ctx).Msg("error of reset login failures")
+	}
+
+	log.Ctx(ctx).Printf("StartRecovery success, UserId:%d", user.ID)
+	return &pb.StartRecoveryResponse{
+		RecoveryKey: &pb.VaultKey{
+			Salt: user.Recovery.Salt,
+			Key:  user.Recovery.Key,
+		},
+	}, nil
+}
+
+// Recover - установить новый пароль по ключу восстановления. Второй фактор не проверяется и отключается вместе
+// со сменой пароля: ключ восстановления сам по себе заменяет и пароль, и второй фактор, потерянные вместе
+// с устройством. Второй фактор можно подключить заново после входа.
+func (s *UserServer) Recover(ctx context.Context, req *pb.RecoverRequest) (*pb.RecoverResponse, error) {
+	log.Ctx(ctx).Printf("Recover, Login:%s", req.GetLogin())
+
+	if len(req.GetVerifier().GetSalt()) == 0 || len(req.GetVerifier().GetVerifier()) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "empty srp verifier")
+	}
+
+	c := &server.PasswordChange{
+		Verifier: server.SRPVerifier{
+			Salt:     req.GetVerifier().GetSalt(),
+			Verifier: req.GetVerifier().GetVerifier(),
+		},
+		ResetSecondFactor: true,
+	}
+
+	if req.GetVaultKey() != nil {
+		if len(req.GetVaultKey().GetKey()) == 0 || len(req.GetVaultKey().GetSalt()) == 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "empty vault key")
+		}
+
+		c.VaultKey = &server.VaultKey{
+			Salt: req.GetVaultKey().GetSalt(),
+			Key:  req.GetVaultKey().GetKey(),
+		}
+	}
+
+	user, keys, err := s.checkRecovery(ctx, req.GetLogin(), req.GetAuth())
+	if err != nil {
+		return nil, err
+	}
+
+	// Пустая сессия - отозвать все сессии пользователя, в том числе на потерянных устройствах.
+	revoked, err := s.Storage.ChangePassword(ctx, user, "", c)
+	if err != nil {
+		if errors.Is(err, server.ErrPasswordChanged) {
+			return nil, status.Errorf(codes.Aborted, "password changed concurrently")
+		}
+
+		log.Error().Err(err).Ctx(ctx).Msg("error of change password")
+		return nil, status.Errorf(codes.Internal, "recover error")
+	}
+
+	err = s.Attempts.ResetLoginFailures(ctx, keys[0])
+	if err != nil {
+		log.Error().Err(err).Ctx(ctx).Msg("error of reset login failures")
+	}
+
+	log.Ctx(ctx).Printf("Recover success, UserId:%d, revoked sessions:%d", user.ID, revoked)
+	return &pb.RecoverResponse{Revoked: revoked}, nil
+}
+
+// checkRecovery - проверить значение, которым клиент доказывает знание ключа восстановления пользователя login.
+// Неудачные попытки учитываются так же, как неудачные попытки входа.
+func (s *UserServer) checkRecovery(ctx context.Context, login string, auth []byte) (*server.User,
+	[]server.LoginKey, error) {
+	if login == "" || len(auth) == 0 {
+		return nil, nil, status.Errorf(codes.InvalidArgument, "empty login or auth")
+	}
+
+	keys := loginKeys(ctx, login)
+
+	err := s.checkLogin(ctx, keys)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	user, err := s.Storage.GetUser(ctx, login)
+	if err != nil {
+		if errors.Is(err, server.ErrUserNotFound) {
+			return nil, nil, s.loginFailed(ctx, keys)
+		}
+
+		log.Error().Err(err).Ctx(ctx).Msg("error of get user")
+		return nil, nil, status.Errorf(codes.Internal, "recovery error")
+	}
+
+	// Без ключа восстановления хэш пустой и не совпадет ни с каким значением.
+	hash := sha256.Sum256(auth)
+	if subtle.ConstantTimeCompare(hash[:], user.Recovery.Hash) != 1 {
+		return nil, nil, s.loginFailed(ctx, keys)
+	}
+
+	return user, keys, nil
+}
+
+// makeRecoveryKey - ключ восстановления для сохранения: вместо значения auth сервер хранит только его хэш.
+func makeRecoveryKey(k *pb.RecoveryKey) (server.RecoveryKey, error) {
+	if len(k.GetSalt()) == 0 || len(k.GetKey()) == 0 || len(k.GetAuth()) == 0 {
+		//nolint:wrapcheck // not need wrap error from status package
+		return server.RecoveryKey{}, status.Error(codes.InvalidArgument, "empty recovery key")
+	}
+
+	hash := sha256.Sum256(k.GetAuth())
+
+	return server.RecoveryKey{
+		Salt: k.GetSalt(),
+		Key:  k.GetKey(),
+		Hash: hash[:],
+	}, nil
+}
@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"context"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/k0st1a/gophkeeper/internal/adapters/api/grpc/gen/proto/v1"
+	"github.com/k0st1a/gophkeeper/internal/pkg/sessionid"
+	"github.com/k0st1a/gophkeeper/internal/pkg/srp"
+	"github.com/k0st1a/gophkeeper/internal/pkg/userid"
+	"github.com/k0st1a/gophkeeper/internal/ports/server"
+)
+
+func TestSetRecoveryKey(t *testing.T) {
+	salt, verifier, err := srp.NewVerifier("login", "password")
+	require.NoError(t, err)
+
+	rk := &pb.RecoveryKey{Salt: []byte("salt"), Key: []byte("key"), Auth: []byte("auth")}
+
+	tests := []struct {
+		storageErr error
+		name       string
+		password   string
+		code       codes.Code
+		noProof    bool
+	}{
+		{
+			name:     "Check set recovery key with srp proof",
+			password: "password",
+		},
+		{
+			name:     "Check set recovery key with wrong password",
+			password: "wrong",
+			code:     codes.InvalidArgument,
+		},
+		{
+			name:    "Check set recovery key without proof of password",
+			noProof: true,
+			code:    codes.InvalidArgument,
+		},
+		{
+			name:       "Check password changed concurrently",
+			password:   "password",
+			storageErr: server.ErrPasswordChanged,
+			code:       codes.Aborted,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := sessionid.Set(userid.Set(context.Background(), 1), "session")
+			user := &server.User{ID: 1, Login: "login", SRP: server.SRPVerifier{Salt: salt, Verifier: verifier}}
+			s, us, _, sl := newUserServer(t, user)
+			us.err = test.storageErr
+
+			req := &pb.SetRecoveryKeyRequest{RecoveryKey: rk}
+
+			var sc *srp.Client
+			if !test.noProof {
+				req.Session = "session"
+				sc, req.Proof = startSRPLogin(t, user, test.password, sl)
+			}
+
+			resp, err := s.SetRecoveryKey(ctx, req)
+			require.Equal(t, test.code, status.Code(err))
+
+			if test.code != codes.OK {
+				require.Nil(t, us.recovery)
+				return
+			}
+
+			require.NoError(t, sc.VerifyServer(resp.GetServerProof()))
+			require.Equal(t, int64(1), resp.GetRevoked())
+
+			hash := sha256.Sum256(rk.Auth)
+			require.Equal(t, &server.RecoveryKey{Salt: rk.Salt, Key: rk.Key, Hash: hash[:]}, us.recovery)
+		})
+	}
+}
+
+func TestRecover(t *testing.T) {
+	hash := sha256.Sum256([]byte("auth"))
+	verifier := &pb.SRPVerifier{Salt: []byte("salt"), Verifier: []byte("verifier")}
+
+	tests := []struct {
+		name string
+		auth []byte
+		code codes.Code
+	}{
+		{
+			name: "Check recover resets second factor",
+			auth: []byte("auth"),
+		},
+		{
+			name: "Check recover with wrong recovery key",
+			auth: []byte("wrong"),
+			code: codes.InvalidArgument,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			user := &server.User{ID: 1, Login: "login", Recovery: server.RecoveryKey{Hash: hash[:]}}
+			s, us, _, _ := newUserServer(t, user)
+
+			_, err := s.Recover(context.Background(), &pb.RecoverRequest{
+				Login:    "login",
+				Auth:     test.auth,
+				Verifier: verifier,
+			})
+			require.Equal(t, test.code, status.Code(err))
+
+			if test.code != codes.OK {
+				require.Nil(t, us.change)
+				return
+			}
+
+			require.True(t, us.change.ResetSecondFactor)
+			require.Equal(t, verifier.GetVerifier(), us.change.Verifier.Verifier)
+		})
+	}
+}
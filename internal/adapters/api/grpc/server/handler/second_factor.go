@@ -0,0 +1,302 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/k0st1a/gophkeeper/internal/adapters/api/grpc/gen/proto/v1"
+	"github.com/k0st1a/gophkeeper/internal/pkg/auth"
+	"github.com/k0st1a/gophkeeper/internal/pkg/userid"
+	"github.com/k0st1a/gophkeeper/internal/ports/server"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// challengeTTL - время на подтверждение входа вторым фактором после проверки пароля.
+	challengeTTL = 5 * time.Minute
+	// challengeAttempts - количество неверных кодов, после которого подтверждение входа нужно начинать заново.
+	challengeAttempts = 5
+)
+
+// challenge - начать вход, ожидающий подтверждения вторым фактором.
+func (s *UserServer) challenge(ctx context.Context, userID int64, device string) (*pb.LoginResponse, error) {
+	c, hash, err := auth.NewChallenge()
+	if err != nil {
+		log.Error().Err(err).Ctx(ctx).Msg("error of generate challenge")
+		return nil, status.Errorf(codes.Internal, "login user error")
+	}
+
+	err = s.SecondFactors.CreateLoginChallenge(ctx, &server.LoginChallenge{
+		ExpireTime: time.Now().Add(challengeTTL),
+		Device:     device,
+		Hash:       hash,
+		UserID:     userID,
+	})
+	if err != nil {
+		log.Error().Err(err).Ctx(ctx).Msg("error of create login challenge")
+		return nil, status.Errorf(codes.Internal, "login user error")
+	}
+
+	log.Ctx(ctx).Printf("Second factor required, UserId:%d", userID)
+	return &pb.LoginResponse{Challenge: c}, nil
+}
+
+func (s *UserServer) VerifySecondFactor(ctx context.Context, req *pb.VerifySecondFactorRequest) (*pb.LoginResponse,
+	error) {
+	log.Ctx(ctx).Printf("VerifySecondFactor")
+
+	if req.GetChallenge() == "" || req.GetCode() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "empty challenge or code")
+	}
+
+	hash := auth.HashChallenge(req.GetChallenge())
+
+	c, err := s.SecondFactors.GetLoginChallenge(ctx, hash)
+	if err != nil {
+		if errors.Is(err, server.ErrLoginChallengeNotFound) {
+			return nil, status.Errorf(codes.Unauthenticated, "invalid or expired challenge")
+		}
+
+		log.Error().Err(err).Ctx(ctx).Msg("error of get login challenge")
+		return nil, status.Errorf(codes.Internal, "verify second factor error")
+	}
+
+	keys := loginKeys(ctx, c.Login)
+
+	err = s.checkLogin(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := s.checkSecondFactor(ctx, c.UserID, req.GetCode())
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		err = s.SecondFactors.FailLoginChallenge(ctx, hash, challengeAttempts)
+		if err != nil {
+			log.Error().Err(err).Ctx(ctx).Msg("error of fail login challenge")
+			return nil, status.Errorf(codes.Internal, "verify second factor error")
+		}
+
+		return nil, s.loginFailed(ctx, keys)
+	}
+
+	err = s.SecondFactors.DeleteLoginChallenge(ctx, hash)
+	if err != nil {
+		log.Error().Err(err).Ctx(ctx).Msg("error of delete login challenge")
+		return nil, status.Errorf(codes.Internal, "verify second factor error")
+	}
+
+	err = s.Attempts.ResetLoginFailures(ctx, keys[0])
+	if err != nil {
+		log.Error().Err(err).Ctx(ctx).Msg("error of reset login failures")
+		return nil, status.Errorf(codes.Internal, "verify second factor error")
+	}
+
+	user, err := s.Storage.GetUserByID(ctx, c.UserID)
+	if err != nil {
+		log.Error().Err(err).Ctx(ctx).Msg("error of get user")
+		return nil, status.Errorf(codes.Internal, "verify second factor error")
+	}
+
+	resp, err := s.startSession(ctx, user, c.Device)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Ctx(ctx).Printf("Success login with second factor, Login:%s, UserId:%d", user.Login, user.ID)
+	return resp, nil
+}
+
+// checkSecondFactor - проверить код TOTP или резервный код подключенного второго фактора пользователя
+// и погасить его, чтобы код нельзя было использовать повторно.
+func (s *UserServer) checkSecondFactor(ctx context.Context, userID int64, code string) (bool, error) {
+	sf, err := s.SecondFactors.GetSecondFactor(ctx, userID)
+	if err != nil {
+		if errors.Is(err, server.ErrSecondFactorNotFound) {
+			return false, nil
+		}
+
+		log.Error().Err(err).Ctx(ctx).Msg("error of get second factor")
+		//nolint:wrapcheck // not need wrap error from status package
+		return false, status.Error(codes.Internal, "check second factor error")
+	}
+
+	if !sf.Enabled {
+		return false, nil
+	}
+
+	step, ok := auth.CheckTOTP(sf.Secret, code, time.Now(), sf.LastStep)
+	if ok {
+		err = s.SecondFactors.UseTOTPStep(ctx, userID, step)
+	} else {
+		err = s.SecondFactors.UseBackupCode(ctx, userID, auth.HashBackupCode(code))
+	}
+
+	if errors.Is(err, server.ErrCodeUsed) {
+		return false, nil
+	}
+
+	if err != nil {
+		log.Error().Err(err).Ctx(ctx).Msg("error of use second factor code")
+		//nolint:wrapcheck // not need wrap error from status package
+		return false, status.Error(codes.Internal, "check second factor error")
+	}
+
+	return true, nil
+}
+
+func (s *UserServer) EnrollSecondFactor(ctx context.Context, req *pb.EnrollSecondFactorRequest) (
+	*pb.EnrollSecondFactorResponse, error) {
+	log.Ctx(ctx).Printf("EnrollSecondFactor")
+
+	userID, ok := userid.Get(ctx)
+	if !ok {
+		log.Ctx(ctx).Printf(ErrNoUserID.Error())
+		//nolint:wrapcheck // not need wrap error from status package
+		return nil, status.Error(codes.Unauthenticated, ErrNoUserID.Error())
+	}
+
+	user, err := s.Storage.GetUserByID(ctx, userID)
+	if err != nil {
+		log.Error().Err(err).Ctx(ctx).Msg("error of get user")
+		return nil, status.Errorf(codes.Internal, "enroll second factor error")
+	}
+
+	secret, err := auth.NewTOTPSecret()
+	if err != nil {
+		log.Error().Err(err).Ctx(ctx).Msg("error of generate totp secret")
+		return nil, status.Errorf(codes.Internal, "enroll second factor error")
+	}
+
+	bc, hashes, err := auth.NewBackupCodes()
+	if err != nil {
+		log.Error().Err(err).Ctx(ctx).Msg("error of generate backup codes")
+		return nil, status.Errorf(codes.Internal, "enroll second factor error")
+	}
+
+	err = s.SecondFactors.EnrollSecondFactor(ctx, userID, secret, hashes)
+	if err != nil {
+		if errors.Is(err, server.ErrSecondFactorEnabled) {
+			return nil, status.Errorf(codes.FailedPrecondition, "second factor already enabled")
+		}
+
+		log.Error().Err(err).Ctx(ctx).Msg("error of enroll second factor")
+		return nil, status.Errorf(codes.Internal, "enroll second factor error")
+	}
+
+	log.Ctx(ctx).Printf("EnrollSecondFactor success")
+	return &pb.EnrollSecondFactorResponse{
+		Uri:         auth.TOTPURI(user.Login, secret),
+		Secret:      secret,
+		BackupCodes: bc,
+	}, nil
+}
+
+func (s *UserServer) ConfirmSecondFactor(ctx context.Context, req *pb.ConfirmSecondFactorRequest) (
+	*pb.ConfirmSecondFactorResponse, error) {
+	log.Ctx(ctx).Printf("ConfirmSecondFactor")
+
+	userID, ok := userid.Get(ctx)
+	if !ok {
+		log.Ctx(ctx).Printf(ErrNoUserID.Error())
+		//nolint:wrapcheck // not need wrap error from status package
+		return nil, status.Error(codes.Unauthenticated, ErrNoUserID.Error())
+	}
+
+	sf, err := s.SecondFactors.GetSecondFactor(ctx, userID)
+	if err != nil {
+		if errors.Is(err, server.ErrSecondFactorNotFound) {
+			return nil, status.Errorf(codes.FailedPrecondition, "second factor not enrolled")
+		}
+
+		log.Error().Err(err).Ctx(ctx).Msg("error of get second factor")
+		return nil, status.Errorf(codes.Internal, "confirm second factor error")
+	}
+
+	if sf.Enabled {
+		return nil, status.Errorf(codes.FailedPrecondition, "second factor already enabled")
+	}
+
+	step, ok := auth.CheckTOTP(sf.Secret, req.GetCode(), time.Now(), sf.LastStep)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid code")
+	}
+
+	err = s.SecondFactors.EnableSecondFactor(ctx, userID, step)
+	if err != nil {
+		if errors.Is(err, server.ErrCodeUsed) {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid code")
+		}
+
+		log.Error().Err(err).Ctx(ctx).Msg("error of enable second factor")
+		return nil, status.Errorf(codes.Internal, "confirm second factor error")
+	}
+
+	log.Ctx(ctx).Printf("ConfirmSecondFactor success")
+	return &pb.ConfirmSecondFactorResponse{}, nil
+}
+
+func (s *UserServer) DisableSecondFactor(ctx context.Context, req *pb.DisableSecondFactorRequest) (
+	*pb.DisableSecondFactorResponse, error) {
+	log.Ctx(ctx).Printf("DisableSecondFactor")
+
+	userID, ok := userid.Get(ctx)
+	if !ok {
+		log.Ctx(ctx).Printf(ErrNoUserID.Error())
+		//nolint:wrapcheck // not need wrap error from status package
+		return nil, status.Error(codes.Unauthenticated, ErrNoUserID.Error())
+	}
+
+	user, err := s.Storage.GetUserByID(ctx, userID)
+	if err != nil {
+		log.Error().Err(err).Ctx(ctx).Msg("error of get user")
+		return nil, status.Errorf(codes.Internal, "disable second factor error")
+	}
+
+	// Неверные коды учитываются как неудачные попытки входа, чтобы по украденному токену
+	// нельзя было подобрать код и отключить второй фактор.
+	keys := loginKeys(ctx, user.Login)
+
+	err = s.checkLogin(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	sf, err := s.SecondFactors.GetSecondFactor(ctx, userID)
+	if err != nil {
+		if errors.Is(err, server.ErrSecondFactorNotFound) {
+			return nil, status.Errorf(codes.FailedPrecondition, "second factor not enrolled")
+		}
+
+		log.Error().Err(err).Ctx(ctx).Msg("error of get second factor")
+		return nil, status.Errorf(codes.Internal, "disable second factor error")
+	}
+
+	// Неподтвержденный второй фактор не требуется при входе, поэтому удаляется без кода.
+	if sf.Enabled {
+		ok, err = s.checkSecondFactor(ctx, userID, req.GetCode())
+		if err != nil {
+			return nil, err
+		}
+
+		if !ok {
+			return nil, s.loginFailed(ctx, keys)
+		}
+	}
+
+	err = s.SecondFactors.DeleteSecondFactor(ctx, userID)
+	if err != nil && !errors.Is(err, server.ErrSecondFactorNotFound) {
+		log.Error().Err(err).Ctx(ctx).Msg("error of delete second factor")
+		return nil, status.Errorf(codes.Internal, "disable second factor error")
+	}
+
+	log.Ctx(ctx).Printf("DisableSecondFactor success")
+	return &pb.DisableSecondFactorResponse{}, nil
+}
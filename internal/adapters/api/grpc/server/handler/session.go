@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/k0st1a/gophkeeper/internal/adapters/api/grpc/gen/proto/v1"
+	"github.com/k0st1a/gophkeeper/internal/pkg/sessionid"
+	"github.com/k0st1a/gophkeeper/internal/pkg/userid"
+	"github.com/k0st1a/gophkeeper/internal/ports/server"
+	"github.com/rs/zerolog/log"
+)
+
+func (s *UserServer) Logout(ctx context.Context, req *pb.LogoutRequest) (*pb.LogoutResponse, error) {
+	log.Ctx(ctx).Printf("Logout")
+
+	userID, sessionID, err := getSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.Sessions.RevokeSession(ctx, userID, sessionID)
+	if err != nil && !errors.Is(err, server.ErrSessionNotFound) {
+		log.Error().Err(err).Ctx(ctx).Msg("error of revoke session")
+		return nil, status.Errorf(codes.Internal, "logout error")
+	}
+
+	log.Ctx(ctx).Printf("Logout success")
+	return &pb.LogoutResponse{}, nil
+}
+
+func (s *UserServer) ListSessions(ctx context.Context, req *pb.ListSessionsRequest) (*pb.ListSessionsResponse, error) {
+	log.Ctx(ctx).Printf("ListSessions")
+
+	userID, sessionID, err := getSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := s.Sessions.ListSessions(ctx, userID)
+	if err != nil {
+		log.Error().Err(err).Ctx(ctx).Msg("error of list sessions")
+		return nil, status.Errorf(codes.Internal, "list sessions error")
+	}
+
+	sessions := make([]*pb.Session, 0, len(l))
+	for _, i := range l {
+		sessions = append(sessions, &pb.Session{
+			Id:           i.ID,
+			Device:       i.Device,
+			Ip:           i.IP,
+			CreateTime:   timestamppb.New(i.CreateTime),
+			LastSeenTime: timestamppb.New(i.LastSeenTime),
+			ExpireTime:   timestamppb.New(i.ExpireTime),
+			Current:      i.ID == sessionID,
+		})
+	}
+
+	log.Ctx(ctx).Printf("ListSessions success, sessions:%v", len(sessions))
+	return &pb.ListSessionsResponse{Sessions: sessions}, nil
+}
+
+func (s *UserServer) RevokeSession(ctx context.Context, req *pb.RevokeSessionRequest) (*pb.RevokeSessionResponse,
+	error) {
+	log.Ctx(ctx).Printf("RevokeSession, id:%v", req.GetId())
+
+	userID, _, err := getSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid session id")
+	}
+
+	err = s.Sessions.RevokeSession(ctx, userID, req.GetId())
+	if err != nil {
+		if errors.Is(err, server.ErrSessionNotFound) {
+			return nil, status.Errorf(codes.NotFound, "session not found")
+		}
+
+		log.Error().Err(err).Ctx(ctx).Msg("error of revoke session")
+		return nil, status.Errorf(codes.Internal, "revoke session error")
+	}
+
+	log.Ctx(ctx).Printf("RevokeSession success")
+	return &pb.RevokeSessionResponse{}, nil
+}
+
+func (s *UserServer) RevokeOtherSessions(ctx context.Context, req *pb.RevokeOtherSessionsRequest) (
+	*pb.RevokeOtherSessionsResponse, error) {
+	log.Ctx(ctx).Printf("RevokeOtherSessions")
+
+	userID, sessionID, err := getSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := s.Sessions.RevokeOtherSessions(ctx, userID, sessionID)
+	if err != nil {
+		log.Error().Err(err).Ctx(ctx).Msg("error of revoke other sessions")
+		return nil, status.Errorf(codes.Internal, "revoke other sessions error")
+	}
+
+	log.Ctx(ctx).Printf("RevokeOtherSessions success, revoked:%v", n)
+	return &pb.RevokeOtherSessionsResponse{Revoked: n}, nil
+}
+
+// getSession - получить пользователя и сессию запроса из контекста.
+func getSession(ctx context.Context) (int64, string, error) {
+	userID, ok := userid.Get(ctx)
+	if !ok {
+		log.Ctx(ctx).Printf(ErrNoUserID.Error())
+		//nolint:wrapcheck // not need wrap error from status package
+		return 0, "", status.Error(codes.Unauthenticated, ErrNoUserID.Error())
+	}
+
+	sessionID, ok := sessionid.Get(ctx)
+	if !ok {
+		log.Ctx(ctx).Printf(ErrNoSessionID.Error())
+		//nolint:wrapcheck // not need wrap error from status package
+		return 0, "", status.Error(codes.Unauthenticated, ErrNoSessionID.Error())
+	}
+
+	return userID, sessionID, nil
+}
@@ -0,0 +1,193 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/k0st1a/gophkeeper/internal/adapters/api/grpc/gen/proto/v1"
+	"github.com/k0st1a/gophkeeper/internal/pkg/userid"
+	"github.com/k0st1a/gophkeeper/internal/ports/server"
+	"github.com/rs/zerolog/log"
+)
+
+// publicKeySize - размер открытого ключа X25519.
+const publicKeySize = 32
+
+type ShareServer struct {
+	// нужно встраивать тип auth.Unimplemented<TypeName>
+	// для совместимости с будущими версиями
+	pb.UnimplementedSharesServiceServer
+	Users  server.UserStorage
+	Shares server.ShareStorage
+}
+
+func (s *ShareServer) GetPublicKey(ctx context.Context, req *pb.GetPublicKeyRequest) (*pb.GetPublicKeyResponse,
+	error) {
+	log.Ctx(ctx).Printf("GetPublicKey, Login:%s", req.GetLogin())
+
+	_, ok := userid.Get(ctx)
+	if !ok {
+		log.Ctx(ctx).Printf(ErrNoUserID.Error())
+		//nolint:wrapcheck // not need wrap error from status package
+		return nil, status.Error(codes.Unauthenticated, ErrNoUserID.Error())
+	}
+
+	user, err := s.recipient(ctx, req.GetLogin())
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.GetPublicKeyResponse{PublicKey: user.KeyPair.Public}, nil
+}
+
+func (s *ShareServer) ShareItem(ctx context.Context, req *pb.ShareItemRequest) (*pb.ShareItemResponse, error) {
+	log.Ctx(ctx).Printf("ShareItem, id:%v, Login:%s", req.GetItemId(), req.GetLogin())
+
+	userID, ok := userid.Get(ctx)
+	if !ok {
+		log.Ctx(ctx).Printf(ErrNoUserID.Error())
+		//nolint:wrapcheck // not need wrap error from status package
+		return nil, status.Error(codes.Unauthenticated, ErrNoUserID.Error())
+	}
+
+	if len(req.GetData()) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "empty data")
+	}
+
+	user, err := s.recipient(ctx, req.GetLogin())
+	if err != nil {
+		return nil, err
+	}
+
+	if user.ID == userID {
+		return nil, status.Errorf(codes.InvalidArgument, "can not share item with yourself")
+	}
+
+	id, err := s.Shares.CreateShare(ctx, &server.Share{
+		ItemID:      req.GetItemId(),
+		OwnerID:     userID,
+		RecipientID: user.ID,
+		Data:        req.GetData(),
+	})
+	if err != nil {
+		if errors.Is(err, server.ErrItemNotFound) {
+			return nil, status.Errorf(codes.NotFound, "item not found")
+		}
+
+		log.Error().Err(err).Ctx(ctx).Msg("error of create share")
+		return nil, status.Errorf(codes.Internal, "share item error")
+	}
+
+	log.Ctx(ctx).Printf("ShareItem success, share id:%v", id)
+	return &pb.ShareItemResponse{Id: id}, nil
+}
+
+func (s *ShareServer) ListShares(ctx context.Context, req *pb.ListSharesRequest) (*pb.ListSharesResponse, error) {
+	log.Ctx(ctx).Printf("ListShares")
+
+	userID, ok := userid.Get(ctx)
+	if !ok {
+		log.Ctx(ctx).Printf(ErrNoUserID.Error())
+		//nolint:wrapcheck // not need wrap error from status package
+		return nil, status.Error(codes.Unauthenticated, ErrNoUserID.Error())
+	}
+
+	l, err := s.Shares.ListShares(ctx, userID)
+	if err != nil {
+		log.Error().Err(err).Ctx(ctx).Msg("error of list shares")
+		return nil, status.Errorf(codes.Internal, "list shares error")
+	}
+
+	shares := make([]*pb.Share, 0, len(l))
+	for i := range l {
+		// Копия зашифрована для получателя, владельцу она не нужна.
+		l[i].Data = nil
+		shares = append(shares, shareToProto(&l[i]))
+	}
+
+	return &pb.ListSharesResponse{Shares: shares}, nil
+}
+
+func (s *ShareServer) ListSharedWithMe(ctx context.Context, req *pb.ListSharedWithMeRequest) (
+	*pb.ListSharedWithMeResponse, error) {
+	log.Ctx(ctx).Printf("ListSharedWithMe")
+
+	userID, ok := userid.Get(ctx)
+	if !ok {
+		log.Ctx(ctx).Printf(ErrNoUserID.Error())
+		//nolint:wrapcheck // not need wrap error from status package
+		return nil, status.Error(codes.Unauthenticated, ErrNoUserID.Error())
+	}
+
+	l, err := s.Shares.ListSharedWithMe(ctx, userID)
+	if err != nil {
+		log.Error().Err(err).Ctx(ctx).Msg("error of list shared with me")
+		return nil, status.Errorf(codes.Internal, "list shared with me error")
+	}
+
+	shares := make([]*pb.Share, 0, len(l))
+	for i := range l {
+		shares = append(shares, shareToProto(&l[i]))
+	}
+
+	return &pb.ListSharedWithMeResponse{Shares: shares}, nil
+}
+
+func (s *ShareServer) RevokeShare(ctx context.Context, req *pb.RevokeShareRequest) (*pb.RevokeShareResponse, error) {
+	log.Ctx(ctx).Printf("RevokeShare, id:%v", req.GetId())
+
+	userID, ok := userid.Get(ctx)
+	if !ok {
+		log.Ctx(ctx).Printf(ErrNoUserID.Error())
+		//nolint:wrapcheck // not need wrap error from status package
+		return nil, status.Error(codes.Unauthenticated, ErrNoUserID.Error())
+	}
+
+	err := s.Shares.DeleteShare(ctx, userID, req.GetId())
+	if err != nil {
+		if errors.Is(err, server.ErrShareNotFound) {
+			return nil, status.Errorf(codes.NotFound, "share not found")
+		}
+
+		log.Error().Err(err).Ctx(ctx).Msg("error of delete share")
+		return nil, status.Errorf(codes.Internal, "revoke share error")
+	}
+
+	log.Ctx(ctx).Printf("RevokeShare success")
+	return &pb.RevokeShareResponse{}, nil
+}
+
+// recipient - получить пользователя, которому можно передать предмет.
+func (s *ShareServer) recipient(ctx context.Context, login string) (*server.User, error) {
+	user, err := s.Users.GetUser(ctx, login)
+	if err != nil {
+		if errors.Is(err, server.ErrUserNotFound) {
+			return nil, status.Errorf(codes.NotFound, "user not found")
+		}
+
+		log.Error().Err(err).Ctx(ctx).Msg("error of get user")
+		return nil, status.Errorf(codes.Internal, "get user error")
+	}
+
+	if len(user.KeyPair.Public) == 0 {
+		return nil, status.Errorf(codes.FailedPrecondition, "user has no public key yet, user must login first")
+	}
+
+	return user, nil
+}
+
+func shareToProto(s *server.Share) *pb.Share {
+	return &pb.Share{
+		Id:         s.ID,
+		ItemId:     s.ItemID,
+		Owner:      s.Owner,
+		Recipient:  s.Recipient,
+		Data:       s.Data,
+		CreateTime: timestamppb.New(s.CreateTime),
+		UpdateTime: timestamppb.New(s.UpdateTime),
+	}
+}
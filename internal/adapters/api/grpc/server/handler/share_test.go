@@ -0,0 +1,220 @@
+package handler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/k0st1a/gophkeeper/internal/adapters/api/grpc/gen/proto/v1"
+	"github.com/k0st1a/gophkeeper/internal/pkg/userid"
+	"github.com/k0st1a/gophkeeper/internal/ports/server"
+)
+
+const (
+	testAlice = 1
+	testBob   = 2
+	testEve   = 3
+	testNoKey = 4
+)
+
+// shareUsers - пользователи с открытыми ключами, кроме nokey, который еще не входил.
+type shareUsers struct {
+	server.UserStorage
+}
+
+func (s *shareUsers) GetUser(ctx context.Context, login string) (*server.User, error) {
+	users := map[string]int64{"alice": testAlice, "bob": testBob, "eve": testEve, "nokey": testNoKey}
+
+	id, ok := users[login]
+	if !ok {
+		return nil, server.ErrUserNotFound
+	}
+
+	user := &server.User{ID: id, Login: login}
+	if id != testNoKey {
+		user.KeyPair.Public = make([]byte, publicKeySize)
+	}
+
+	return user, nil
+}
+
+// shareItem - владелец предмета и коллекция, если это предмет организации.
+type shareItem struct {
+	userID       int64
+	collectionID int64
+}
+
+// shareStorage - как и хранилище, делится только личными предметами владельца, а отозвать доступ
+// может владелец или получатель.
+type shareStorage struct {
+	server.ShareStorage
+	items  map[int64]shareItem
+	shares map[int64]*server.Share
+}
+
+func newShareStorage() *shareStorage {
+	return &shareStorage{
+		items: map[int64]shareItem{
+			1: {userID: testAlice},
+			2: {userID: testAlice, collectionID: 1},
+			3: {userID: testEve},
+		},
+		shares: map[int64]*server.Share{
+			1: {ID: 1, ItemID: 1, OwnerID: testAlice, RecipientID: testBob},
+		},
+	}
+}
+
+func (s *shareStorage) CreateShare(ctx context.Context, share *server.Share) (int64, error) {
+	item, ok := s.items[share.ItemID]
+	if !ok || item.userID != share.OwnerID || item.collectionID != 0 {
+		return 0, server.ErrItemNotFound
+	}
+
+	id := int64(len(s.shares) + 1)
+	s.shares[id] = share
+	return id, nil
+}
+
+func (s *shareStorage) DeleteShare(ctx context.Context, userID, shareID int64) error {
+	share, ok := s.shares[shareID]
+	if !ok || (share.OwnerID != userID && share.RecipientID != userID) {
+		return server.ErrShareNotFound
+	}
+
+	delete(s.shares, shareID)
+	return nil
+}
+
+func TestShareItem(t *testing.T) {
+	tests := []struct {
+		name   string
+		login  string
+		data   []byte
+		caller int64
+		itemID int64
+		code   codes.Code
+	}{
+		{
+			name:   "Check share own item",
+			caller: testAlice,
+			itemID: 1,
+			login:  "eve",
+			data:   []byte("data"),
+		},
+		{
+			name:   "Check share item of other user",
+			caller: testBob,
+			itemID: 1,
+			login:  "eve",
+			data:   []byte("data"),
+			code:   codes.NotFound,
+		},
+		{
+			name:   "Check share collection item",
+			caller: testAlice,
+			itemID: 2,
+			login:  "bob",
+			data:   []byte("data"),
+			code:   codes.NotFound,
+		},
+		{
+			name:   "Check share with yourself",
+			caller: testAlice,
+			itemID: 1,
+			login:  "alice",
+			data:   []byte("data"),
+			code:   codes.InvalidArgument,
+		},
+		{
+			name:   "Check share with unknown user",
+			caller: testAlice,
+			itemID: 1,
+			login:  "mallory",
+			data:   []byte("data"),
+			code:   codes.NotFound,
+		},
+		{
+			name:   "Check share with user without public key",
+			caller: testAlice,
+			itemID: 1,
+			login:  "nokey",
+			data:   []byte("data"),
+			code:   codes.FailedPrecondition,
+		},
+		{
+			name:   "Check share empty data",
+			caller: testAlice,
+			itemID: 1,
+			login:  "bob",
+			code:   codes.InvalidArgument,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			shares := newShareStorage()
+			s := &ShareServer{Users: &shareUsers{}, Shares: shares}
+
+			_, err := s.ShareItem(userid.Set(context.Background(), test.caller), &pb.ShareItemRequest{
+				ItemId: test.itemID,
+				Login:  test.login,
+				Data:   test.data,
+			})
+			require.Equal(t, test.code, status.Code(err))
+
+			if test.code != codes.OK {
+				require.Len(t, shares.shares, 1)
+			}
+		})
+	}
+}
+
+func TestRevokeShare(t *testing.T) {
+	tests := []struct {
+		name    string
+		caller  int64
+		shareID int64
+		code    codes.Code
+	}{
+		{
+			name:    "Check revoke by owner",
+			caller:  testAlice,
+			shareID: 1,
+		},
+		{
+			name:    "Check revoke by recipient",
+			caller:  testBob,
+			shareID: 1,
+		},
+		{
+			name:    "Check revoke by third party",
+			caller:  testEve,
+			shareID: 1,
+			code:    codes.NotFound,
+		},
+		{
+			name:    "Check revoke unknown share",
+			caller:  testAlice,
+			shareID: 2,
+			code:    codes.NotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			shares := newShareStorage()
+			s := &ShareServer{Users: &shareUsers{}, Shares: shares}
+
+			_, err := s.RevokeShare(userid.Set(context.Background(), test.caller),
+				&pb.RevokeShareRequest{Id: test.shareID})
+			require.Equal(t, test.code, status.Code(err))
+
+			_, ok := shares.shares[1]
+			require.Equal(t, test.code != codes.OK, ok)
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/k0st1a/gophkeeper/internal/adapters/api/grpc/gen/proto/v1"
+	"github.com/k0st1a/gophkeeper/internal/pkg/auth"
+	"github.com/k0st1a/gophkeeper/internal/pkg/peerip"
+	"github.com/k0st1a/gophkeeper/internal/pkg/srp"
+	"github.com/k0st1a/gophkeeper/internal/pkg/userid"
+	"github.com/k0st1a/gophkeeper/internal/ports/server"
+	"github.com/rs/zerolog/log"
+)
+
+// srpLoginTTL - время на завершение входа по SRP после его начала.
+const srpLoginTTL = 5 * time.Minute
+
+func (s *UserServer) StartLogin(ctx context.Context, req *pb.StartLoginRequest) (*pb.StartLoginResponse, error) {
+	log.Ctx(ctx).Printf("StartLogin, Login:%s", req.GetLogin())
+
+	if req.GetLogin() == "" || len(req.GetA()) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "empty login or public value")
+	}
+
+	keys := loginKeys(ctx, req.GetLogin())
+
+	// Каждое начало входа сохраняется до его истечения, поэтому число начатых входов с IP адреса ограничено.
+	var start *server.LoginKey
+	if ip := peerip.Get(ctx); ip != "" {
+		start = &server.LoginKey{Kind: server.LoginKeyStart, Value: ip}
+		keys = append(keys, *start)
+	}
+
+	err := s.checkLogin(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	if start != nil {
+		_, err = s.Attempts.AddLoginFailure(ctx, *start, s.StartLockout.Window, s.StartLockout.Delay)
+		if err != nil {
+			log.Error().Err(err).Ctx(ctx).Msg("error of count start login")
+			return nil, status.Errorf(codes.Internal, "start login error")
+		}
+	}
+
+	var user *server.User
+	user, err = s.Storage.GetUser(ctx, req.GetLogin())
+	if err != nil && !errors.Is(err, server.ErrUserNotFound) {
+		log.Error().Err(err).Ctx(ctx).Msg("error of get user")
+		return nil, status.Errorf(codes.Internal, "start login error")
+	}
+
+	l := &server.SRPLogin{
+		ExpireTime: time.Now().Add(srpLoginTTL),
+		Login:      req.GetLogin(),
+		PublicA:    req.GetA(),
+	}
+
+	// Для незарегистрированного логина ответ выглядит так же, как для зарегистрированного,
+	// но вход не завершится успешно. Так же отвечается и пользователю без верификатора SRP: иначе по ответу
+	// можно было бы найти логины, которые еще входят по паролю.
+	var verifier []byte
+	salt := s.Auth.FakeSRPSalt(req.GetLogin())
+
+	if user != nil && len(user.SRP.Verifier) != 0 {
+		l.UserID = user.ID
+		salt = user.SRP.Salt
+		verifier = user.SRP.Verifier
+	}
+
+	l.Secret, l.PublicB, err = srp.NewServer(verifier)
+	if err != nil {
+		log.Error().Err(err).Ctx(ctx).Msg("error of generate srp public value")
+		return nil, status.Errorf(codes.Internal, "start login error")
+	}
+
+	session, hash, err := auth.NewChallenge()
+	if err != nil {
+		log.Error().Err(err).Ctx(ctx).Msg("error of generate srp login session")
+		return nil, status.Errorf(codes.Internal, "start login error")
+	}
+
+	l.Hash = hash
+
+	err = s.SRPLogins.CreateSRPLogin(ctx, l)
+	if err != nil {
+		log.Error().Err(err).Ctx(ctx).Msg("error of create srp login")
+		return nil, status.Errorf(codes.Internal, "start login error")
+	}
+
+	return &pb.StartLoginResponse{
+		Session: session,
+		Salt:    salt,
+		B:       l.PublicB,
+	}, nil
+}
+
+func (s *UserServer) FinishLogin(ctx context.Context, req *pb.FinishLoginRequest) (*pb.LoginResponse, error) {
+	log.Ctx(ctx).Printf("FinishLogin")
+
+	if req.GetSession() == "" || len(req.GetProof()) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "empty session or proof")
+	}
+
+	l, err := s.SRPLogins.TakeSRPLogin(ctx, auth.HashChallenge(req.GetSession()))
+	if err != nil {
+		if errors.Is(err, server.ErrSRPLoginNotFound) {
+			return nil, status.Errorf(codes.Unauthenticated, "invalid or expired login session")
+		}
+
+		log.Error().Err(err).Ctx(ctx).Msg("error of take srp login")
+		return nil, status.Errorf(codes.Internal, "finish login error")
+	}
+
+	keys := loginKeys(ctx, l.Login)
+
+	err = s.checkLogin(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	if l.UserID == 0 {
+		return nil, s.loginFailed(ctx, keys)
+	}
+
+	user, err := s.Storage.GetUserByID(ctx, l.UserID)
+	if err != nil {
+		log.Error().Err(err).Ctx(ctx).Msg("error of get user")
+		return nil, status.Errorf(codes.Internal, "finish login error")
+	}
+
+	m2, err := srp.VerifyClient(user.Login, user.SRP.Salt, user.SRP.Verifier, l.Secret, l.PublicA, l.PublicB,
+		req.GetProof())
+	if err != nil {
+		log.Ctx(ctx).Printf("FinishLogin => %v", err)
+		return nil, s.loginFailed(ctx, keys)
+	}
+
+	resp, err := s.passwordVerified(ctx, user, keys, req.GetDevice())
+	if err != nil {
+		return nil, err
+	}
+
+	resp.ServerProof = m2
+
+	log.Ctx(ctx).Printf("Success login, Login:%s, UserId:%d", user.Login, user.ID)
+	return resp, nil
+}
+
+func (s *UserServer) SetSRPVerifier(ctx context.Context, req *pb.SetSRPVerifierRequest) (*pb.SetSRPVerifierResponse,
+	error) {
+	log.Ctx(ctx).Printf("SetSRPVerifier")
+
+	userID, ok := userid.Get(ctx)
+	if !ok {
+		log.Ctx(ctx).Printf(ErrNoUserID.Error())
+		//nolint:wrapcheck // not need wrap error from status package
+		return nil, status.Error(codes.Unauthenticated, ErrNoUserID.Error())
+	}
+
+	if len(req.GetVerifier().GetSalt()) == 0 || len(req.GetVerifier().GetVerifier()) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "empty srp verifier")
+	}
+
+	err := s.Storage.SetSRPVerifier(ctx, userID, &server.SRPVerifier{
+		Salt:     req.GetVerifier().GetSalt(),
+		Verifier: req.GetVerifier().GetVerifier(),
+	})
+	if err != nil {
+		if errors.Is(err, server.ErrSRPVerifierAlreadySet) {
+			return nil, status.Errorf(codes.AlreadyExists, "srp verifier already set")
+		}
+
+		log.Error().Err(err).Ctx(ctx).Msg("error of set srp verifier")
+		return nil, status.Errorf(codes.Internal, "set srp verifier error")
+	}
+
+	log.Ctx(ctx).Printf("SetSRPVerifier success")
+	return &pb.SetSRPVerifierResponse{}, nil
+}
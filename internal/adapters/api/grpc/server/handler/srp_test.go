@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/k0st1a/gophkeeper/internal/adapters/api/grpc/gen/proto/v1"
+	"github.com/k0st1a/gophkeeper/internal/pkg/srp"
+	"github.com/k0st1a/gophkeeper/internal/pkg/userid"
+	"github.com/k0st1a/gophkeeper/internal/ports/server"
+)
+
+func TestStartLogin(t *testing.T) {
+	salt, verifier, err := srp.NewVerifier("login", "password")
+	require.NoError(t, err)
+
+	hash, err := testHasher.Hash("password")
+	require.NoError(t, err)
+
+	tests := []struct {
+		user     *server.User
+		name     string
+		salt     []byte
+		userID   int64
+		failures int
+		peer     bool
+	}{
+		{
+			name: "Check unknown login",
+		},
+		{
+			name: "Check legacy user looks like unknown login",
+			user: &server.User{ID: 1, Login: "login", Password: hash},
+		},
+		{
+			name:   "Check srp user",
+			user:   &server.User{ID: 1, Login: "login", SRP: server.SRPVerifier{Salt: salt, Verifier: verifier}},
+			salt:   salt,
+			userID: 1,
+		},
+		{
+			name:     "Check start login is counted by ip",
+			peer:     true,
+			failures: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s, _, lf, sl := newUserServer(t, test.user)
+
+			ctx := context.Background()
+			if test.peer {
+				ctx = peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 1}})
+			}
+
+			sc, err := srp.NewClient("login")
+			require.NoError(t, err)
+
+			resp, err := s.StartLogin(ctx, &pb.StartLoginRequest{Login: "login", A: sc.A})
+			require.NoError(t, err)
+			require.False(t, resp.GetLegacy())
+			require.NotEmpty(t, resp.GetSession())
+			require.NotEmpty(t, resp.GetB())
+			require.Equal(t, test.failures, lf.failures)
+
+			want := test.salt
+			if want == nil {
+				want = s.Auth.FakeSRPSalt("login")
+			}
+
+			require.Equal(t, want, resp.GetSalt())
+			require.Equal(t, test.userID, sl.login.UserID)
+		})
+	}
+}
+
+func TestSetSRPVerifier(t *testing.T) {
+	salt, verifier, err := srp.NewVerifier("login", "password")
+	require.NoError(t, err)
+
+	hash, err := testHasher.Hash("password")
+	require.NoError(t, err)
+
+	newSalt, newVerifier, err := srp.NewVerifier("login", "new")
+	require.NoError(t, err)
+
+	tests := []struct {
+		user *server.User
+		name string
+		want server.SRPVerifier
+		code codes.Code
+	}{
+		{
+			name: "Check set verifier of legacy user",
+			user: &server.User{ID: 1, Login: "login", Password: hash},
+			want: server.SRPVerifier{Salt: newSalt, Verifier: newVerifier},
+		},
+		{
+			name: "Check verifier is not overwritten",
+			user: &server.User{ID: 1, Login: "login", SRP: server.SRPVerifier{Salt: salt, Verifier: verifier}},
+			want: server.SRPVerifier{Salt: salt, Verifier: verifier},
+			code: codes.AlreadyExists,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s, us, _, _ := newUserServer(t, test.user)
+
+			_, err := s.SetSRPVerifier(userid.Set(context.Background(), test.user.ID), &pb.SetSRPVerifierRequest{
+				Verifier: &pb.SRPVerifier{Salt: newSalt, Verifier: newVerifier},
+			})
+			require.Equal(t, test.code, status.Code(err))
+			require.Equal(t, test.want, us.user.SRP)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/k0st1a/gophkeeper/internal/adapters/api/grpc/gen/proto/v1"
+	"github.com/k0st1a/gophkeeper/internal/pkg/userid"
+	"github.com/k0st1a/gophkeeper/internal/ports/server"
+	"github.com/rs/zerolog/log"
+)
+
+func (s *ItemServer) ListTrash(ctx context.Context, req *pb.ListTrashRequest) (*pb.ListTrashResponse, error) {
+	log.Ctx(ctx).Printf("List trash")
+
+	userID, ok := userid.Get(ctx)
+	if !ok {
+		log.Ctx(ctx).Printf(ErrNoUserID.Error())
+		//nolint:wrapcheck // not need wrap error from status package
+		return nil, status.Error(codes.Unauthenticated, ErrNoUserID.Error())
+	}
+
+	l, err := s.Trash.ListTrash(ctx, userID)
+	if err != nil {
+		log.Error().Err(err).Ctx(ctx).Msg("list trash error")
+		//nolint:wrapcheck // not need wrap error from status package
+		return nil, status.Error(codes.Internal, "list trash error")
+	}
+
+	items := make([]*pb.TrashItem, 0, len(l))
+	for _, i := range l {
+		items = append(items, &pb.TrashItem{
+			Item:       makeItem(&i.Item),
+			DeleteTime: timestamppb.New(i.DeleteTime),
+		})
+	}
+
+	log.Ctx(ctx).Printf("List trash success, items:%v", len(items))
+	return &pb.ListTrashResponse{Items: items}, nil
+}
+
+func (s *ItemServer) RestoreItem(ctx context.Context, req *pb.RestoreItemRequest) (*pb.RestoreItemResponse, error) {
+	log.Ctx(ctx).Printf("Restore item, id:%v", req.Id)
+
+	userID, ok := userid.Get(ctx)
+	if !ok {
+		log.Ctx(ctx).Printf(ErrNoUserID.Error())
+		//nolint:wrapcheck // not need wrap error from status package
+		return nil, status.Error(codes.Unauthenticated, ErrNoUserID.Error())
+	}
+
+	i, err := s.Trash.RestoreItem(ctx, userID, req.Id)
+	if errors.Is(err, server.ErrItemNotFound) {
+		//nolint:wrapcheck // not need wrap error from status package
+		return nil, status.Error(codes.NotFound, "item not found in trash")
+	}
+
+	if err != nil {
+		log.Error().Err(err).Ctx(ctx).Msg("restore item error")
+		//nolint:wrapcheck // not need wrap error from status package
+		return nil, status.Error(codes.Internal, "restore item error")
+	}
+	s.Notifier.Notify(userID)
+
+	log.Ctx(ctx).Printf("Restore item success, revision:%v", i.Revision)
+	return &pb.RestoreItemResponse{Item: makeItem(i)}, nil
+}
@@ -4,12 +4,18 @@ package handler
 import (
 	"context"
 	"errors"
+	"time"
 
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 
 	pb "github.com/k0st1a/gophkeeper/internal/adapters/api/grpc/gen/proto/v1"
 	"github.com/k0st1a/gophkeeper/internal/pkg/auth"
+	"github.com/k0st1a/gophkeeper/internal/pkg/lockout"
+	"github.com/k0st1a/gophkeeper/internal/pkg/peerip"
+	"github.com/k0st1a/gophkeeper/internal/pkg/userid"
 	"github.com/k0st1a/gophkeeper/internal/ports/server"
 	"github.com/rs/zerolog/log"
 )
@@ -18,20 +24,58 @@ type UserServer struct {
 	// нужно встраивать тип auth.Unimplemented<TypeName>
 	// для совместимости с будущими версиями
 	pb.UnimplementedUsersServiceServer
-	Storage server.UserStorage
-	Auth    auth.UserAuthentication
+	Storage  server.UserStorage
+	Sessions server.SessionStorage
+	Tokens   server.RefreshTokenStorage
+	Attempts server.LoginFailureStorage
+	// Второй фактор входа
+	SecondFactors server.SecondFactorStorage
+	// Начатые входы по SRP
+	SRPLogins server.SRPLoginStorage
+	Auth      auth.UserAuthentication
+	// Политика задержек и блокировки входа после неудачных попыток
+	Lockout *lockout.Policy
+	// Политика задержек и блокировки начатых входов по SRP с одного IP адреса
+	StartLockout *lockout.Policy
+	// Срок действия refresh токена
+	RefreshTokenTTL time.Duration
 }
 
 func (s *UserServer) Register(ctx context.Context, req *pb.RegisterRequest) (*pb.RegisterResponse, error) {
 	log.Ctx(ctx).Printf("Register, Login:%s", req.Login)
 
-	passwordHash, err := s.Auth.GeneratePasswordHash(req.Password)
-	if err != nil {
-		log.Error().Err(err).Ctx(ctx).Msg("error of generate password pash")
-		return nil, status.Errorf(codes.Internal, "create user error")
+	// Пароль не должен покидать клиента, сервер хранит только верификатор SRP.
+	if req.GetPassword() != "" {
+		return nil, status.Errorf(codes.InvalidArgument, "password is not accepted, send srp verifier")
+	}
+
+	if len(req.GetVerifier().GetSalt()) == 0 || len(req.GetVerifier().GetVerifier()) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "empty srp verifier")
+	}
+
+	user := &server.User{
+		Login: req.Login,
+		VaultKey: server.VaultKey{
+			Salt: req.GetVaultKey().GetSalt(),
+			Key:  req.GetVaultKey().GetKey(),
+		},
+		SRP: server.SRPVerifier{
+			Salt:     req.GetVerifier().GetSalt(),
+			Verifier: req.GetVerifier().GetVerifier(),
+		},
+	}
+
+	// Ключ восстановления необязателен, его можно создать позже через SetRecoveryKey.
+	if req.GetRecoveryKey() != nil {
+		k, err := makeRecoveryKey(req.GetRecoveryKey())
+		if err != nil {
+			return nil, err
+		}
+
+		user.Recovery = k
 	}
 
-	id, err := s.Storage.CreateUser(ctx, req.Login, passwordHash)
+	id, err := s.Storage.CreateUser(ctx, user)
 	if err != nil {
 		if errors.Is(err, server.ErrLoginAlreadyBusy) {
 			return nil, status.Errorf(codes.AlreadyExists, "user already exists")
@@ -50,31 +94,301 @@ func (s *UserServer) Register(ctx context.Context, req *pb.RegisterRequest) (*pb
 func (s *UserServer) Login(ctx context.Context, req *pb.LoginRequest) (*pb.LoginResponse, error) {
 	log.Ctx(ctx).Printf("Login, Login:%s", req.Login)
 
-	userID, password, err := s.Storage.GetUserIDAndPassword(ctx, req.Login)
+	keys := loginKeys(ctx, req.GetLogin())
+
+	err := s.checkLogin(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.Storage.GetUser(ctx, req.Login)
 	if err != nil {
 		if errors.Is(err, server.ErrUserNotFound) {
-			return nil, status.Errorf(codes.InvalidArgument, "invalid credentials")
+			return nil, s.loginFailed(ctx, keys)
 		}
 
-		log.Error().Err(err).Ctx(ctx).Msg("error of get user id and password")
+		log.Error().Err(err).Ctx(ctx).Msg("error of get user")
 		return nil, status.Errorf(codes.Internal, "login user error")
 	}
 
-	err = s.Auth.CheckPasswordHash(req.Password, password)
+	// У пользователя с верификатором SRP хэш пароля пустой, и вход по паролю для него невозможен.
+	rehash, err := s.Auth.CheckPasswordHash(req.Password, user.Password)
 	if err != nil {
-		return nil, status.Errorf(codes.InvalidArgument, "invalid credentials")
+		return nil, s.loginFailed(ctx, keys)
+	}
+
+	if rehash {
+		s.rehashPassword(ctx, user, req.Password)
 	}
 
-	t, err := s.Auth.GenerateToken(userID)
+	resp, err := s.passwordVerified(ctx, user, keys, req.GetDevice())
+	if err != nil {
+		return nil, err
+	}
+
+	log.Ctx(ctx).Printf("Success login, Login:%s, UserId:%d", req.Login, user.ID)
+	return resp, nil
+}
+
+// rehashPassword - пересчитать хэш пароля, полученный устаревшим алгоритмом или с устаревшими параметрами.
+// Ошибка не прерывает вход, хэш будет пересчитан при следующем входе.
+func (s *UserServer) rehashPassword(ctx context.Context, user *server.User, password string) {
+	log.Ctx(ctx).Printf("Outdated password hash => rehash, UserId:%d", user.ID)
+
+	hash, err := s.Auth.GeneratePasswordHash(password)
+	if err != nil {
+		log.Error().Err(err).Ctx(ctx).Msg("error of generate password hash")
+		return
+	}
+
+	err = s.Storage.UpdatePasswordHash(ctx, user.ID, user.Password, hash)
+	if err != nil {
+		log.Error().Err(err).Ctx(ctx).Msg("error of update password hash")
+	}
+}
+
+// passwordVerified - продолжить вход после проверки пароля: начать подтверждение вторым фактором,
+// если он подключен, иначе создать сессию.
+func (s *UserServer) passwordVerified(ctx context.Context, user *server.User, keys []server.LoginKey,
+	device string) (*pb.LoginResponse, error) {
+	sf, err := s.SecondFactors.GetSecondFactor(ctx, user.ID)
+	if err != nil && !errors.Is(err, server.ErrSecondFactorNotFound) {
+		log.Error().Err(err).Ctx(ctx).Msg("error of get second factor")
+		return nil, status.Errorf(codes.Internal, "login user error")
+	}
+
+	if sf != nil && sf.Enabled {
+		return s.challenge(ctx, user.ID, device)
+	}
+
+	// Неудачные попытки с IP адреса не сбрасываются, иначе перебор паролей разных логинов
+	// можно чередовать со входом под своим логином.
+	err = s.Attempts.ResetLoginFailures(ctx, keys[0])
+	if err != nil {
+		log.Error().Err(err).Ctx(ctx).Msg("error of reset login failures")
+		return nil, status.Errorf(codes.Internal, "login user error")
+	}
+
+	return s.startSession(ctx, user, device)
+}
+
+// startSession - создать сессию пользователя после успешного входа и выдать ее токены.
+func (s *UserServer) startSession(ctx context.Context, user *server.User, device string) (*pb.LoginResponse, error) {
+	rt, hash, err := auth.NewRefreshToken()
+	if err != nil {
+		log.Error().Err(err).Ctx(ctx).Msg("error of generate refresh token")
+		return nil, status.Errorf(codes.Internal, "login user error")
+	}
+
+	sessionID, err := s.Sessions.CreateSession(ctx, &server.Session{
+		Device: device,
+		IP:     peerip.Get(ctx),
+		UserID: user.ID,
+	}, &server.RefreshToken{
+		ExpireTime: time.Now().Add(s.RefreshTokenTTL),
+		Hash:       hash,
+	})
+	if err != nil {
+		log.Error().Err(err).Ctx(ctx).Msg("error of create session")
+		return nil, status.Errorf(codes.Internal, "login user error")
+	}
+
+	t, err := s.Auth.GenerateToken(user.ID, sessionID)
 	if err != nil {
 		log.Error().Err(err).Ctx(ctx).Msg("error of generate token")
 		return nil, status.Errorf(codes.Internal, "login user error")
 	}
 
-	resp := pb.LoginResponse{
+	return &pb.LoginResponse{
 		Token: t,
+		VaultKey: &pb.VaultKey{
+			Salt: user.VaultKey.Salt,
+			Key:  user.VaultKey.Key,
+		},
+		RefreshToken: rt,
+		KeyPair: &pb.KeyPair{
+			PublicKey:  user.KeyPair.Public,
+			PrivateKey: user.KeyPair.Private,
+		},
+	}, nil
+}
+
+// loginKeys - ключи, по которым считаются неудачные попытки входа: логин и IP адрес клиента, если он известен.
+func loginKeys(ctx context.Context, login string) []server.LoginKey {
+	keys := []server.LoginKey{{Kind: server.LoginKeyLogin, Value: login}}
+	if ip := peerip.Get(ctx); ip != "" {
+		keys = append(keys, server.LoginKey{Kind: server.LoginKeyIP, Value: ip})
 	}
 
-	log.Ctx(ctx).Printf("Success login, Login:%s, UserId:%d", req.Login, userID)
-	return &resp, nil
+	return keys
+}
+
+// checkLogin - проверить, что вход по ключам не запрещен после неудачных попыток.
+func (s *UserServer) checkLogin(ctx context.Context, keys []server.LoginKey) error {
+	until, err := s.Attempts.CheckLogin(ctx, keys)
+	if err != nil {
+		log.Error().Err(err).Ctx(ctx).Msg("error of check login")
+		//nolint:wrapcheck // not need wrap error from status package
+		return status.Error(codes.Internal, "login user error")
+	}
+
+	if !until.IsZero() {
+		log.Ctx(ctx).Printf("Login blocked until:%v", until)
+		return tooManyAttempts(ctx, until)
+	}
+
+	return nil
+}
+
+// loginFailed - учесть неудачную попытку входа по всем ключам. Если после нее вход запрещен,
+// то вернуть ошибку с временем до следующей попытки, иначе ошибку неверных учетных данных.
+func (s *UserServer) loginFailed(ctx context.Context, keys []server.LoginKey) error {
+	var until time.Time
+
+	for _, k := range keys {
+		u, err := s.Attempts.AddLoginFailure(ctx, k, s.Lockout.Window, s.Lockout.Delay)
+		if err != nil {
+			log.Error().Err(err).Ctx(ctx).Msg("error of add login failure")
+			//nolint:wrapcheck // not need wrap error from status package
+			return status.Error(codes.Internal, "login user error")
+		}
+
+		if u.After(until) {
+			until = u
+		}
+	}
+
+	if until.IsZero() {
+		//nolint:wrapcheck // not need wrap error from status package
+		return status.Error(codes.InvalidArgument, "invalid credentials")
+	}
+
+	return tooManyAttempts(ctx, until)
+}
+
+// tooManyAttempts - ошибка запрета входа до времени until, с RetryInfo о том, через сколько повторить попытку.
+func tooManyAttempts(ctx context.Context, until time.Time) error {
+	st := status.New(codes.ResourceExhausted, "too many login attempts")
+
+	d := time.Until(until)
+	if d < 0 {
+		d = 0
+	}
+
+	ds, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(d)})
+	if err != nil {
+		log.Error().Err(err).Ctx(ctx).Msg("error of add retry info")
+		return st.Err() //nolint:wrapcheck // not need wrap error from status package
+	}
+
+	return ds.Err() //nolint:wrapcheck // not need wrap error from status package
+}
+
+func (s *UserServer) RefreshToken(ctx context.Context, req *pb.RefreshTokenRequest) (
+	*pb.RefreshTokenResponse, error) {
+	log.Ctx(ctx).Printf("RefreshToken")
+
+	if req.GetRefreshToken() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "empty refresh token")
+	}
+
+	rt, hash, err := auth.NewRefreshToken()
+	if err != nil {
+		log.Error().Err(err).Ctx(ctx).Msg("error of generate refresh token")
+		return nil, status.Errorf(codes.Internal, "refresh token error")
+	}
+
+	next := &server.RefreshToken{
+		ExpireTime: time.Now().Add(s.RefreshTokenTTL),
+		Hash:       hash,
+	}
+
+	err = s.Tokens.RotateRefreshToken(ctx, auth.HashRefreshToken(req.GetRefreshToken()), next)
+	if err != nil {
+		if errors.Is(err, server.ErrRefreshTokenNotFound) ||
+			errors.Is(err, server.ErrRefreshTokenExpired) ||
+			errors.Is(err, server.ErrRefreshTokenReused) {
+			log.Ctx(ctx).Printf("Invalid refresh token:%v", err)
+			return nil, status.Errorf(codes.Unauthenticated, "invalid refresh token")
+		}
+
+		log.Error().Err(err).Ctx(ctx).Msg("error of rotate refresh token")
+		return nil, status.Errorf(codes.Internal, "refresh token error")
+	}
+
+	t, err := s.Auth.GenerateToken(next.UserID, next.SessionID)
+	if err != nil {
+		log.Error().Err(err).Ctx(ctx).Msg("error of generate token")
+		return nil, status.Errorf(codes.Internal, "refresh token error")
+	}
+
+	log.Ctx(ctx).Printf("Success refresh token, UserId:%d", next.UserID)
+	return &pb.RefreshTokenResponse{
+		Token:        t,
+		RefreshToken: rt,
+	}, nil
+}
+
+func (s *UserServer) SetVaultKey(ctx context.Context, req *pb.SetVaultKeyRequest) (*pb.SetVaultKeyResponse, error) {
+	log.Ctx(ctx).Printf("Set vault key")
+
+	userID, ok := userid.Get(ctx)
+	if !ok {
+		log.Ctx(ctx).Printf(ErrNoUserID.Error())
+		//nolint:wrapcheck // not need wrap error from status package
+		return nil, status.Error(codes.Unauthenticated, ErrNoUserID.Error())
+	}
+
+	if len(req.GetVaultKey().GetKey()) == 0 || len(req.GetVaultKey().GetSalt()) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "empty vault key")
+	}
+
+	key := &server.VaultKey{
+		Salt: req.VaultKey.Salt,
+		Key:  req.VaultKey.Key,
+	}
+
+	err := s.Storage.SetVaultKey(ctx, userID, key)
+	if err != nil {
+		if errors.Is(err, server.ErrVaultKeyAlreadySet) {
+			return nil, status.Errorf(codes.AlreadyExists, "vault key already set")
+		}
+
+		log.Error().Err(err).Ctx(ctx).Msg("error of set vault key")
+		return nil, status.Errorf(codes.Internal, "set vault key error")
+	}
+
+	log.Ctx(ctx).Printf("Set vault key success")
+	return &pb.SetVaultKeyResponse{}, nil
+}
+
+func (s *UserServer) SetKeyPair(ctx context.Context, req *pb.SetKeyPairRequest) (*pb.SetKeyPairResponse, error) {
+	log.Ctx(ctx).Printf("Set key pair")
+
+	userID, ok := userid.Get(ctx)
+	if !ok {
+		log.Ctx(ctx).Printf(ErrNoUserID.Error())
+		//nolint:wrapcheck // not need wrap error from status package
+		return nil, status.Error(codes.Unauthenticated, ErrNoUserID.Error())
+	}
+
+	if len(req.GetKeyPair().GetPublicKey()) != publicKeySize || len(req.GetKeyPair().GetPrivateKey()) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "bad key pair")
+	}
+
+	err := s.Storage.SetKeyPair(ctx, userID, &server.KeyPair{
+		Public:  req.GetKeyPair().GetPublicKey(),
+		Private: req.GetKeyPair().GetPrivateKey(),
+	})
+	if err != nil {
+		if errors.Is(err, server.ErrKeyPairAlreadySet) {
+			return nil, status.Errorf(codes.AlreadyExists, "key pair already set")
+		}
+
+		log.Error().Err(err).Ctx(ctx).Msg("error of set key pair")
+		return nil, status.Errorf(codes.Internal, "set key pair error")
+	}
+
+	log.Ctx(ctx).Printf("Set key pair success")
+	return &pb.SetKeyPairResponse{}, nil
 }
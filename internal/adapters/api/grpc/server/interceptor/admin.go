@@ -0,0 +1,45 @@
+package interceptor
+
+import (
+	"context"
+	"crypto/subtle"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/k0st1a/gophkeeper/internal/adapters/api/grpc/gen/proto/v1"
+	"github.com/rs/zerolog/log"
+)
+
+// isAdmin - запрос к сервису администрирования, который аутентифицируется токеном администратора.
+func isAdmin(method string) bool {
+	return strings.HasPrefix(method, "/"+pb.AdminService_ServiceDesc.ServiceName+"/")
+}
+
+// AuthenticateAdmin - проверка токена администратора из метаданных admin-token у запросов к сервису
+// администрирования. Если token пустой, то такие запросы отклоняются.
+func AuthenticateAdmin(token string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, r interface{}, i *grpc.UnaryServerInfo, h grpc.UnaryHandler) (interface{}, error) {
+		if !isAdmin(i.FullMethod) {
+			return h(ctx, r)
+		}
+
+		var t string
+		if meta, ok := metadata.FromIncomingContext(ctx); ok {
+			values := meta.Get("admin-token")
+			if len(values) > 0 {
+				t = values[0]
+			}
+		}
+
+		if token == "" || subtle.ConstantTimeCompare([]byte(t), []byte(token)) != 1 {
+			log.Ctx(ctx).Printf("invalid admin token")
+			return nil, status.Errorf(codes.Unauthenticated, "invalid admin token")
+		}
+
+		return h(ctx, r)
+	}
+}
@@ -2,6 +2,7 @@ package interceptor
 
 import (
 	"context"
+	"errors"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -10,38 +11,99 @@ import (
 
 	pb "github.com/k0st1a/gophkeeper/internal/adapters/api/grpc/gen/proto/v1"
 	"github.com/k0st1a/gophkeeper/internal/pkg/auth"
+	"github.com/k0st1a/gophkeeper/internal/pkg/peerip"
+	"github.com/k0st1a/gophkeeper/internal/pkg/sessionid"
 	"github.com/k0st1a/gophkeeper/internal/pkg/userid"
+	"github.com/k0st1a/gophkeeper/internal/ports/server"
 	"github.com/rs/zerolog/log"
 )
 
-func Authenticate(auth auth.UserAuthentication) grpc.UnaryServerInterceptor {
+// SessionChecker - проверка, что сессия токена не отозвана.
+type SessionChecker interface {
+	CheckSession(ctx context.Context, userID int64, sessionID, ip string) error
+}
+
+func Authenticate(auth auth.UserAuthentication, sc SessionChecker) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, r interface{}, i *grpc.UnaryServerInfo, h grpc.UnaryHandler) (interface{}, error) {
 		if i.FullMethod == pb.UsersService_Register_FullMethodName ||
-			i.FullMethod == pb.UsersService_Login_FullMethodName {
+			i.FullMethod == pb.UsersService_Login_FullMethodName ||
+			i.FullMethod == pb.UsersService_StartLogin_FullMethodName ||
+			i.FullMethod == pb.UsersService_FinishLogin_FullMethodName ||
+			i.FullMethod == pb.UsersService_VerifySecondFactor_FullMethodName ||
+			i.FullMethod == pb.UsersService_RefreshToken_FullMethodName ||
+			i.FullMethod == pb.UsersService_StartRecovery_FullMethodName ||
+			i.FullMethod == pb.UsersService_Recover_FullMethodName ||
+			isAdmin(i.FullMethod) {
 			return h(ctx, r)
 		}
 
-		var token string
-		if meta, ok := metadata.FromIncomingContext(ctx); ok {
-			values := meta.Get("token")
-			if len(values) > 0 {
-				token = values[0]
-			}
+		CtxWithUserID, err := authenticate(ctx, auth, sc)
+		if err != nil {
+			return nil, err
 		}
 
-		if len(token) == 0 {
-			log.Ctx(ctx).Printf("no token")
-			return nil, status.Errorf(codes.Unauthenticated, "no token")
-		}
+		return h(CtxWithUserID, r)
+	}
+}
 
-		userID, err := auth.GetUserID(token)
+// AuthenticateStream - аутентификация потоковых вызовов.
+func AuthenticateStream(auth auth.UserAuthentication, sc SessionChecker) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, i *grpc.StreamServerInfo, h grpc.StreamHandler) error {
+		CtxWithUserID, err := authenticate(ss.Context(), auth, sc)
 		if err != nil {
-			log.Ctx(ctx).Err(err).Msg("error of get userID")
-			return nil, status.Errorf(codes.Unauthenticated, "no user id in token")
+			return err
 		}
 
-		CtxWithUserID := userid.Set(ctx, userID)
+		return h(srv, &serverStream{ServerStream: ss, ctx: CtxWithUserID})
+	}
+}
 
-		return h(CtxWithUserID, r)
+// serverStream - поток с контекстом, в который добавлен userID.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// authenticate - получить userID и сессию из токена в метаданных запроса, проверить сессию и добавить их в контекст.
+func authenticate(ctx context.Context, auth auth.UserAuthentication, sc SessionChecker) (context.Context, error) {
+	var token string
+	if meta, ok := metadata.FromIncomingContext(ctx); ok {
+		values := meta.Get("token")
+		if len(values) > 0 {
+			token = values[0]
+		}
+	}
+
+	if len(token) == 0 {
+		log.Ctx(ctx).Printf("no token")
+		return nil, status.Errorf(codes.Unauthenticated, "no token")
 	}
+
+	claims, err := auth.ParseToken(token)
+	if err != nil {
+		log.Ctx(ctx).Err(err).Msg("error of get userID")
+		return nil, status.Errorf(codes.Unauthenticated, "no user id in token")
+	}
+
+	if claims.Id == "" {
+		log.Ctx(ctx).Printf("no session in token")
+		return nil, status.Errorf(codes.Unauthenticated, "no session in token")
+	}
+
+	err = sc.CheckSession(ctx, claims.UserID, claims.Id, peerip.Get(ctx))
+	if err != nil {
+		if errors.Is(err, server.ErrSessionNotFound) {
+			log.Ctx(ctx).Printf("session(%v) revoked or expired", claims.Id)
+			return nil, status.Errorf(codes.Unauthenticated, "session revoked")
+		}
+
+		log.Ctx(ctx).Err(err).Msg("error of check session")
+		return nil, status.Errorf(codes.Internal, "check session error")
+	}
+
+	return sessionid.Set(userid.Set(ctx, claims.UserID), claims.Id), nil
 }
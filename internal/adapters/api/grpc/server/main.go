@@ -2,6 +2,7 @@
 package server
 
 import (
+	"crypto/tls"
 	"fmt"
 
 	"google.golang.org/grpc"
@@ -12,32 +13,110 @@ import (
 	"github.com/k0st1a/gophkeeper/internal/application/server/config"
 	"github.com/k0st1a/gophkeeper/internal/pkg/auth"
 	"github.com/k0st1a/gophkeeper/internal/pkg/grpcserver"
+	"github.com/k0st1a/gophkeeper/internal/pkg/lockout"
+	"github.com/k0st1a/gophkeeper/internal/pkg/tlsconfig"
 	"github.com/k0st1a/gophkeeper/internal/ports/server"
+	"github.com/rs/zerolog/log"
 )
 
-func New(cfg *config.Config, u server.UserStorage, a auth.UserAuthentication,
-	i server.ItemStorage) (*grpcserver.Server, error) {
+// New - создать gRPC сервер, tc - TLS конфигурация сервера, если nil, то соединения принимаются без шифрования.
+func New(cfg *config.Config, tc *tls.Config, u server.UserStorage, ss server.SessionStorage,
+	r server.RefreshTokenStorage, l server.LoginFailureStorage, sf server.SecondFactorStorage,
+	sl server.SRPLoginStorage, a auth.UserAuthentication,
+	i server.ItemStorage, t server.ItemTrashStorage, h server.ItemHistoryStorage, n handler.ItemNotifier,
+	b server.BlobStorage, c server.ShareStorage, o server.OrgStorage,
+	e server.EmergencyStorage) (*grpcserver.Server, error) {
 	// создаём gRPC-сервер без зарегистрированной службы
-	s := grpc.NewServer(grpc.ChainUnaryInterceptor(
-		interceptor.Authenticate(a),
-	))
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			interceptor.Authenticate(a, ss),
+			interceptor.AuthenticateAdmin(cfg.AdminToken),
+		),
+		grpc.ChainStreamInterceptor(
+			interceptor.AuthenticateStream(a, ss),
+		),
+	)
 
 	uh := &handler.UserServer{
-		Storage: u,
-		Auth:    a,
+		Storage:         u,
+		Sessions:        ss,
+		Tokens:          r,
+		Attempts:        l,
+		SecondFactors:   sf,
+		SRPLogins:       sl,
+		Auth:            a,
+		Lockout:         lockout.New(),
+		StartLockout:    lockout.NewStart(),
+		RefreshTokenTTL: cfg.RefreshTokenTTL,
 	}
 
 	pb.RegisterUsersServiceServer(s, uh)
 
 	ih := &handler.ItemServer{
-		Storage: i,
+		Storage:  i,
+		Trash:    t,
+		History:  h,
+		Orgs:     o,
+		Notifier: n,
 	}
 	pb.RegisterItemsServiceServer(s, ih)
 
-	srv, err := grpcserver.New(cfg.Address, s)
+	bh := &handler.BlobServer{
+		Storage: b,
+		Quota:   cfg.BlobQuota,
+	}
+	pb.RegisterBlobsServiceServer(s, bh)
+
+	sh := &handler.ShareServer{
+		Users:  u,
+		Shares: c,
+	}
+	pb.RegisterSharesServiceServer(s, sh)
+
+	oh := &handler.OrgServer{
+		Users:    u,
+		Orgs:     o,
+		Notifier: n,
+	}
+	pb.RegisterOrgsServiceServer(s, oh)
+
+	eh := &handler.EmergencyServer{
+		Users:     u,
+		Items:     i,
+		Emergency: e,
+	}
+	pb.RegisterEmergencyAccessServiceServer(s, eh)
+
+	if cfg.AdminToken != "" {
+		ah := &handler.AdminServer{
+			Attempts: l,
+		}
+		pb.RegisterAdminServiceServer(s, ah)
+	} else {
+		log.Printf("Admin token is not set, admin service disabled")
+	}
+
+	srv, err := grpcserver.New(cfg.Address, s, tc)
 	if err != nil {
 		return nil, fmt.Errorf("grpc server new error:%w", err)
 	}
 
 	return srv, nil
 }
+
+// TLSConfig - TLS сервера, если в конфигурации задан сертификат, иначе nil и соединения принимаются без шифрования.
+func TLSConfig(cfg *config.Config) (*tls.Config, error) {
+	if cfg.TLSCert == "" {
+		log.Warn().Msg("TLS certificate is not set, server accepts plain text connections")
+		return nil, nil
+	}
+
+	tc, err := tlsconfig.NewServer(cfg.TLSCert, cfg.TLSKey, cfg.TLSClientCA)
+	if err != nil {
+		return nil, fmt.Errorf("tls config error:%w", err)
+	}
+
+	log.Printf("TLS enabled, mutual TLS:%v", cfg.TLSClientCA != "")
+
+	return tc, nil
+}
@@ -2,16 +2,23 @@ package tui
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"reflect"
 	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
 	gclient "github.com/k0st1a/gophkeeper/internal/adapters/api/grpc/client"
 	"github.com/k0st1a/gophkeeper/internal/adapters/api/tui/storage"
 	"github.com/k0st1a/gophkeeper/internal/pkg/client/model"
+	"github.com/k0st1a/gophkeeper/internal/pkg/client/otp"
+	"github.com/k0st1a/gophkeeper/internal/pkg/client/recovery"
 	"github.com/k0st1a/gophkeeper/internal/pkg/job"
 
 	"github.com/gdamore/tcell/v2"
@@ -39,11 +46,58 @@ const (
 	pageNameUpdateFile = "update file"
 	pageNameAddFile    = "add file"
 
+	pageNameUpdateOTP = "update otp"
+	pageNameAddOTP    = "add otp"
+
+	pageNameHistory  = "history"
+	pageNameTrash    = "trash"
+	pageNameSessions = "sessions"
+
+	pageNameSecondFactor       = "second factor"
+	pageNameEnrollSecondFactor = "enroll second factor"
+	pageNameChangePassword     = "change password"
+	pageNameNewRecoveryKit     = "new recovery kit"
+	pageNameRecoveryKit        = "recovery kit"
+	pageNameRecover            = "recover"
+	pageNameSplitKey           = "split key"
+	pageNameKeyShares          = "key shares"
+	pageNameUnlockShares       = "unlock shares"
+
+	pageNameShareItem    = "share item"
+	pageNameShares       = "shares"
+	pageNameSharedWithMe = "shared with me"
+
+	pageNameOrgs             = "orgs"
+	pageNameCreateOrg        = "create org"
+	pageNameMembers          = "members"
+	pageNameInviteMember     = "invite member"
+	pageNameSetMemberRole    = "set member role"
+	pageNameInvitations      = "invitations"
+	pageNameCollections      = "collections"
+	pageNameCreateCollection = "create collection"
+
+	pageNameEmergencyContacts   = "emergency contacts"
+	pageNameAddEmergencyContact = "add emergency contact"
+	pageNameEmergencyGrantors   = "emergency grantors"
+	pageNameEmergencyVault      = "emergency vault"
+	pageNameTakeover            = "takeover"
+
 	// Имена кнопок.
-	buttonNameCancel = "Cancel"
-	buttonNameOk     = "Ok"
-	buttonNameUpdate = "Update"
-	buttonNameDelete = "Delete"
+	buttonNameCancel  = "Cancel"
+	buttonNameOk      = "Ok"
+	buttonNameUpdate  = "Update"
+	buttonNameDelete  = "Delete"
+	buttonNameHistory = "History"
+	buttonNameRestore = "Restore"
+	buttonNameRevoke  = "Revoke"
+	buttonNameOthers  = "Revoke others"
+	buttonNameEnable  = "Enable"
+	buttonNameDisable = "Disable"
+	buttonNameRemove  = "Remove"
+	buttonNameAccept  = "Accept"
+	buttonNameDecline = "Decline"
+	buttonNameApprove = "Approve"
+	buttonNameReject  = "Reject"
 
 	// Имена надписей.
 	labelName                  = "Name"
@@ -56,13 +110,38 @@ const (
 	labelCardExpires           = "Card expires"
 	labelCardHolder            = "Card holder"
 	labelNote                  = "Note"
+	labelOTPURI                = "otpauth URI"
+	labelOTPIssuer             = "Issuer"
+	labelOTPAccount            = "Account"
+	labelOTPSecret             = "Secret"
+	labelOTPCode               = "Code"
+	labelBackupCodes           = "Backup codes"
+	labelOldPassword           = "Old password"
+	labelNewPassword           = "New password"
+	labelRepeatPassword        = "Repeat new password"
+	labelLogin                 = "Login"
 	labelAdd                   = "Add"
+	labelRole                  = "Role"
+	labelCollection            = "Collection"
+	labelRecoveryKey           = "Recovery key"
+	labelKitFile               = "Save to file (.txt or .pdf)"
+	labelShares                = "Shares"
+	labelThreshold             = "Threshold"
+	labelSharesDir             = "Save to directory"
+	labelAccess                = "Access"
+	labelWaitDays              = "Wait days"
 
 	defaultFieldWidth  = 30
 	defaultFieldHeight = 5
 	defaultMaxLength   = 255
 )
 
+// orgRoles - роли участников организации для выбора, первая - роль по умолчанию.
+var orgRoles = []string{gclient.RoleMember, gclient.RoleReadOnly, gclient.RoleAdmin, gclient.RoleOwner}
+
+// emergencyAccesses - доступы доверенного лица для выбора, первый - доступ по умолчанию.
+var emergencyAccesses = []string{gclient.EmergencyAccessView, gclient.EmergencyAccessTakeover}
+
 const (
 	columnName = iota
 	columnType
@@ -70,15 +149,17 @@ const (
 )
 
 type client struct {
-	grpc    gclient.UserAuthentication
+	grpc    gclient.Client
 	storage storage.ItemStorage
 	sync    job.StartStopper
 	cancel  func()
 	app     *tview.Application
 	pages   *tview.Pages
+	// Остановить обратный отсчет открытой страницы OTP, вызывается только в обработчиках tview
+	stopOTP func()
 }
 
-func New(c gclient.UserAuthentication, s storage.ItemStorage, j job.StartStopper, cn func()) *client {
+func New(c gclient.Client, s storage.ItemStorage, j job.StartStopper, cn func()) *client {
 	app := tview.NewApplication()
 	pages := tview.NewPages()
 
@@ -125,6 +206,14 @@ func (c *client) WelcomePage(ctx context.Context) {
 			c.pages.RemovePage(pageNameWelcome)
 			c.RegisterPage(ctx)
 		}).
+		AddItem("Forgot password", "", '3', func() {
+			c.pages.RemovePage(pageNameWelcome)
+			c.RecoverPage(ctx)
+		}).
+		AddItem("Unlock with key shares (offline)", "", '4', func() {
+			c.pages.RemovePage(pageNameWelcome)
+			c.UnlockSharesPage(ctx)
+		}).
 		AddItem("Quit", "", 'q', func() {
 			c.Stop(ctx)
 		})
@@ -209,7 +298,7 @@ func (c *client) RegisterPage(ctx context.Context) {
 				return
 			}
 
-			err := c.grpc.RegisterUser(ctx, email, password)
+			kit, err := c.grpc.RegisterUser(ctx, email, password)
 			if err != nil {
 				c.NotifyPage(err.Error())
 				return
@@ -217,8 +306,10 @@ func (c *client) RegisterPage(ctx context.Context) {
 
 			c.pages.RemovePage(pageNameRegister)
 
-			c.NotifyAndSwitch2Page("Success register", func() {
-				c.WelcomePage(ctx)
+			c.RecoveryKitPage(kit, func() {
+				c.NotifyAndSwitch2Page("Success register", func() {
+					c.WelcomePage(ctx)
+				})
 			})
 		}).
 		AddButton("Cancel", func() {
@@ -264,20 +355,19 @@ func (c *client) LoginPage(ctx context.Context) {
 			}
 
 			err := c.grpc.LoginUser(ctx, email, password)
+			if errors.Is(err, gclient.ErrSecondFactorRequired) {
+				c.pages.RemovePage(pageNameLogin)
+				c.SecondFactorPage(ctx, email)
+				return
+			}
+
 			if err != nil {
 				c.NotifyPage(err.Error())
 				return
 			}
 
 			c.pages.RemovePage(pageNameLogin)
-			log.Printf("Success login fast")
-
-			c.NotifyAndSwitch2Page("Success login", func() {
-				c.pages.RemovePage(pageNameLogin)
-				c.StartSync(ctx)
-				c.ItemsPage(ctx)
-			})
-			log.Printf("Success login fast")
+			c.loginSuccess(ctx, email)
 		}).
 		AddButton("Cancel", func() {
 			c.pages.RemovePage(pageNameLogin)
@@ -296,6 +386,60 @@ func (c *client) LoginPage(ctx context.Context) {
 	c.pages.AddPage(pageNameLogin, loginFlexBox, true, true)
 }
 
+// loginSuccess – открыть локальное хранилище пользователя login, сообщить об успешном логине и перейти к предметам.
+func (c *client) loginSuccess(ctx context.Context, login string) {
+	log.Printf("Success login fast")
+
+	c.storage.Open(ctx, login)
+
+	c.NotifyAndSwitch2Page("Success login", func() {
+		c.StartSync(ctx)
+		c.ItemsPage(ctx)
+	})
+}
+
+// SecondFactorPage – ввод кода второго фактора после проверки пароля пользователя login.
+func (c *client) SecondFactorPage(ctx context.Context, login string) {
+	log.Printf("Invoked Second factor Page")
+
+	var code string
+	form := tview.NewForm().
+		AddInputField(labelOTPCode, "", defaultFieldWidth, nil, func(text string) {
+			code = text
+		}).
+		AddButton("Verify", func() {
+			if code == "" {
+				c.NotifyPage("Code is empty")
+				return
+			}
+
+			err := c.grpc.VerifySecondFactor(ctx, code)
+			if err != nil {
+				c.NotifyPage(err.Error())
+				return
+			}
+
+			c.pages.RemovePage(pageNameSecondFactor)
+			c.loginSuccess(ctx, login)
+		}).
+		AddButton(buttonNameCancel, func() {
+			c.grpc.Logout(ctx)
+			c.pages.RemovePage(pageNameSecondFactor)
+			c.WelcomePage(ctx)
+		})
+
+	form.
+		SetTitle("Enter code of authenticator app or backup code").
+		SetBorder(true).
+		SetBorderColor(tcell.ColorSteelBlue)
+
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(form, 0, 1, true)
+
+	c.pages.AddPage(pageNameSecondFactor, flex, true, true)
+}
+
 func (c *client) ItemsPage(ctx context.Context) {
 	log.Printf("Invoked Items Page")
 
@@ -335,8 +479,13 @@ func (c *client) ItemsPage(ctx context.Context) {
 
 		row := i + 1
 
+		color := tcell.ColorWhite
+		if item.Conflict != "" {
+			color = tcell.ColorRed
+		}
+
 		table.
-			SetCell(row, columnName, tview.NewTableCell(name).SetTextColor(tcell.ColorWhite).SetReference(item)).
+			SetCell(row, columnName, tview.NewTableCell(name).SetTextColor(color).SetReference(item)).
 			SetCell(row, columnType, tview.NewTableCell(itype).SetTextColor(tcell.ColorWhite)).
 			SetCell(row, columnUpdateTime, newTableCellTime(item.UpdateTime).SetSelectable(false))
 	}
@@ -368,6 +517,9 @@ func (c *client) ItemsPage(ctx context.Context) {
 		AddButton("Add file", func() {
 			c.AddFilePage(ctx)
 		}).
+		AddButton("Add OTP", func() {
+			c.AddOTPPage(ctx)
+		}).
 		AddButton("Delete", func() {
 			row, _ := table.GetSelection()
 			item, ok := table.GetCell(row, columnName).GetReference().(storage.Item)
@@ -381,6 +533,46 @@ func (c *client) ItemsPage(ctx context.Context) {
 
 			c.DeleteItemPage(ctx, &item, name, itype)
 		}).
+		AddButton("Share", func() {
+			row, _ := table.GetSelection()
+			item, ok := table.GetCell(row, columnName).GetReference().(storage.Item)
+			if !ok {
+				log.Error().Msgf("error of get item by reference while share item, row:%v", row)
+				return
+			}
+
+			c.ShareItemPage(ctx, &item)
+		}).
+		AddButton("My shares", func() {
+			c.SharesPage(ctx)
+		}).
+		AddButton("Shared with me", func() {
+			c.SharedWithMePage(ctx)
+		}).
+		AddButton("Orgs", func() {
+			c.OrgsPage(ctx)
+		}).
+		AddButton("Emergency", func() {
+			c.EmergencyContactsPage(ctx)
+		}).
+		AddButton("Trash", func() {
+			c.TrashPage(ctx)
+		}).
+		AddButton("Sessions", func() {
+			c.SessionsPage(ctx)
+		}).
+		AddButton("2FA", func() {
+			c.SecondFactorSettingsPage(ctx)
+		}).
+		AddButton("Change password", func() {
+			c.ChangePasswordPage(ctx)
+		}).
+		AddButton("Key shares", func() {
+			c.SplitKeyPage(ctx)
+		}).
+		AddButton("Recovery kit", func() {
+			c.NewRecoveryKitPage(ctx)
+		}).
 		AddButton("Refresh", func() {
 			c.ItemsPage(ctx)
 		}).
@@ -456,6 +648,9 @@ func (c *client) UpdatePasswordPage(ctx context.Context, i *storage.Item, p *sto
 
 			c.pages.RemovePage(pageNameUpdatePassword)
 		}).
+		AddButton(buttonNameHistory, func() {
+			c.HistoryPage(ctx, i, pageNameUpdatePassword)
+		}).
 		AddButton(buttonNameCancel, func() {
 			c.pages.RemovePage(pageNameUpdatePassword)
 		})
@@ -477,9 +672,11 @@ func (c *client) AddPasswordPage(ctx context.Context) {
 
 	p := &storage.Password{}
 	m := storage.Meta{}
+	collection, collectionID := c.collectionDropDown(ctx)
 
 	//nolint:dupl // not need check for tview.NewForm
 	form := tview.NewForm().
+		AddFormItem(collection).
 		AddInputField(labelResource, p.UserName, defaultFieldWidth, nil, func(text string) {
 			p.Resource = text
 		}).
@@ -498,7 +695,7 @@ func (c *client) AddPasswordPage(ctx context.Context) {
 				m.Set(model.MetaKeyAdditionalInformation, text)
 			}).
 		AddButton(buttonNameOk, func() {
-			_, err := c.storage.CreateItem(ctx, p, m)
+			_, err := c.storage.CreateCollectionItem(ctx, collectionID(), p, m)
 			if err != nil {
 				log.Error().Err(err).Msg("Item add error while add password")
 				c.NotifyPage(err.Error())
@@ -554,6 +751,9 @@ func (c *client) UpdateCardPage(ctx context.Context, i *storage.Item, cd *storag
 
 			c.pages.RemovePage(pageNameUpdateCard)
 		}).
+		AddButton(buttonNameHistory, func() {
+			c.HistoryPage(ctx, i, pageNameUpdateCard)
+		}).
 		AddButton(buttonNameCancel, func() {
 			c.pages.RemovePage(pageNameUpdateCard)
 		})
@@ -575,9 +775,11 @@ func (c *client) AddCardPage(ctx context.Context) {
 
 	cd := &storage.Card{}
 	m := storage.Meta{}
+	collection, collectionID := c.collectionDropDown(ctx)
 
 	//nolint:dupl // not need check for tview.NewForm
 	form := tview.NewForm().
+		AddFormItem(collection).
 		AddInputField(labelCardNumber, cd.Number, defaultFieldWidth, nil, func(text string) {
 			cd.Number = text
 		}).
@@ -596,7 +798,7 @@ func (c *client) AddCardPage(ctx context.Context) {
 				m.Set(model.MetaKeyAdditionalInformation, text)
 			}).
 		AddButton(buttonNameOk, func() {
-			_, err := c.storage.CreateItem(ctx, cd, m)
+			_, err := c.storage.CreateCollectionItem(ctx, collectionID(), cd, m)
 			if err != nil {
 				log.Error().Err(err).Msg("Item add error while add card")
 				c.NotifyPage(err.Error())
@@ -650,6 +852,9 @@ func (c *client) UpdateNotePage(ctx context.Context, i *storage.Item, n *storage
 
 			c.pages.RemovePage(pageNameUpdateNote)
 		}).
+		AddButton(buttonNameHistory, func() {
+			c.HistoryPage(ctx, i, pageNameUpdateNote)
+		}).
 		AddButton(buttonNameCancel, func() {
 			c.pages.RemovePage(pageNameUpdateNote)
 		})
@@ -671,8 +876,10 @@ func (c *client) AddNotePage(ctx context.Context) {
 
 	n := &storage.Note{}
 	m := storage.Meta{}
+	collection, collectionID := c.collectionDropDown(ctx)
 
 	form := tview.NewForm().
+		AddFormItem(collection).
 		AddInputField(labelName, n.Name, defaultFieldWidth, nil, func(text string) {
 			n.Name = text
 		}).
@@ -688,7 +895,7 @@ func (c *client) AddNotePage(ctx context.Context) {
 				m.Set(model.MetaKeyAdditionalInformation, text)
 			}).
 		AddButton(buttonNameOk, func() {
-			_, err := c.storage.CreateItem(ctx, n, m)
+			_, err := c.storage.CreateCollectionItem(ctx, collectionID(), n, m)
 			if err != nil {
 				log.Error().Err(err).Msg("Item add error while add note")
 				c.NotifyPage(err.Error())
@@ -734,12 +941,16 @@ func (c *client) UpdateFilePage(ctx context.Context, i *storage.Item, f *storage
 				i.Meta.Set(model.MetaKeyAdditionalInformation, text)
 			}).
 		AddButton("Download", func() {
-			if err := os.WriteFile(path, f.Body, syscall.S_IRUSR|syscall.S_IWUSR); err != nil {
+			if err := c.downloadFile(ctx, f, path); err != nil {
+				log.Error().Err(err).Msg("File download error")
 				c.NotifyPage(err.Error())
 				return
 			}
 			c.pages.RemovePage(pageNameUpdateFile)
 		}).
+		AddButton(buttonNameHistory, func() {
+			c.HistoryPage(ctx, i, pageNameUpdateFile)
+		}).
 		AddButton(buttonNameCancel, func() {
 			c.pages.RemovePage(pageNameUpdateFile)
 		})
@@ -777,41 +988,297 @@ func (c *client) AddFilePage(ctx context.Context) {
 				m.Set(model.MetaKeyAdditionalInformation, text)
 			}).
 		AddButton(labelAdd, func() {
-			d, err := os.ReadFile(path)
+			err := c.uploadFile(ctx, f, path)
+			if err != nil {
+				log.Error().Err(err).Msg("File upload error")
+				c.NotifyPage(err.Error())
+				return
+			}
+
+			_, err = c.storage.CreateItem(ctx, f, m)
+			if err != nil {
+				log.Error().Err(err).Msg("Item add error while add file")
+				c.NotifyPage(err.Error())
+				return
+			}
+
+			c.pages.RemovePage(pageNameAddFile)
+			c.ItemsPage(ctx)
+		}).
+		AddButton(buttonNameCancel, func() {
+			c.pages.RemovePage(pageNameAddFile)
+		})
+
+	form.
+		SetTitle("Add file").
+		SetBorder(true).
+		SetBorderColor(tcell.ColorSteelBlue)
+
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(form, 0, 1, true)
+
+	c.pages.AddPage(pageNameAddFile, flex, true, true)
+}
+
+// uploadFile – загрузить содержимое файла path на сервер и заполнить ссылку на него в f.
+func (c *client) uploadFile(ctx context.Context, f *storage.File, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("error of open file:%w", err)
+	}
+	defer file.Close() //nolint:errcheck // read only
+
+	s, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("error of stat file:%w", err)
+	}
+
+	b, err := c.grpc.UploadBlob(ctx, file, s.Size())
+	if err != nil {
+		return fmt.Errorf("error of upload file:%w", err)
+	}
+
+	f.Name = s.Name()
+	f.BlobID = b.ID
+	f.Size = b.Size
+
+	return nil
+}
+
+// downloadFile – скачать содержимое файла f в файл path.
+func (c *client) downloadFile(ctx context.Context, f *storage.File, path string) error {
+	if f.BlobID == "" {
+		return os.WriteFile(path, f.Body, syscall.S_IRUSR|syscall.S_IWUSR)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, syscall.S_IRUSR|syscall.S_IWUSR)
+	if err != nil {
+		return fmt.Errorf("error of open file:%w", err)
+	}
+
+	err = c.grpc.DownloadBlob(ctx, &model.Blob{ID: f.BlobID, Size: f.Size}, file)
+	if err != nil {
+		_ = file.Close()
+		_ = os.Remove(path)
+		return fmt.Errorf("error of download file:%w", err)
+	}
+
+	err = file.Close()
+	if err != nil {
+		return fmt.Errorf("error of close file:%w", err)
+	}
+
+	return nil
+}
+
+func (c *client) UpdateOTPPage(ctx context.Context, i *storage.Item, o *storage.OTP) {
+	log.Printf("Invoked Update otp Page, item(%v)", i.ID)
+
+	form := tview.NewForm().
+		AddTextView(labelOTPCode, otpText(o), defaultFieldWidth, 1, false, false).
+		AddInputField(labelOTPIssuer, o.Issuer, defaultFieldWidth, nil, func(text string) {
+			o.Issuer = text
+		}).
+		AddInputField(labelOTPAccount, o.Account, defaultFieldWidth, nil, func(text string) {
+			o.Account = text
+		}).
+		AddTextArea(labelDescription, i.Meta.Get(model.MetaKeyDescription), defaultFieldWidth,
+			defaultFieldHeight, defaultMaxLength, func(text string) {
+				i.Meta.Set(model.MetaKeyDescription, text)
+			}).
+		AddTextArea(labelAdditionalInformation, i.Meta.Get(model.MetaKeyAdditionalInformation), defaultFieldWidth,
+			defaultFieldHeight, defaultMaxLength, func(text string) {
+				i.Meta.Set(model.MetaKeyAdditionalInformation, text)
+			})
+
+	code, ok := form.GetFormItemByLabel(labelOTPCode).(*tview.TextView)
+	if !ok {
+		log.Error().Msg("error of get otp code view")
+		return
+	}
+
+	// У каждой открытой страницы свой обратный отсчет. Отсчет прошлой страницы останавливается и тогда,
+	// когда она закрыта не кнопками самой страницы, например после восстановления версии предмета.
+	if c.stopOTP != nil {
+		c.stopOTP()
+	}
+
+	done := make(chan struct{})
+	stop := sync.OnceFunc(func() {
+		close(done)
+	})
+	c.stopOTP = stop
+
+	if o.Type == model.OTPTypeHOTP {
+		// Счетчик сохраняется сразу, иначе после перезапуска клиента будет выдан тот же пароль.
+		form.AddButton("Next code", func() {
+			o.Counter++
+			err := c.storage.UpdateItem(ctx, i)
 			if err != nil {
+				o.Counter--
+				log.Error().Err(err).Msg("Item update error while next otp code")
 				c.NotifyPage(err.Error())
 				return
 			}
 
-			s, err := os.Stat(path)
+			code.SetText(otpText(o))
+		})
+	}
+
+	form.
+		AddButton(buttonNameUpdate, func() {
+			err := c.storage.UpdateItem(ctx, i)
 			if err != nil {
+				log.Error().Err(err).Msg("Item update error while update otp")
 				c.NotifyPage(err.Error())
 				return
 			}
 
-			if s.Size() > int64(storage.MaxFileSize) {
-				c.NotifyPage(storage.ErrLargeFile.Error())
+			stop()
+			c.pages.RemovePage(pageNameUpdateOTP)
+		}).
+		AddButton(buttonNameHistory, func() {
+			c.HistoryPage(ctx, i, pageNameUpdateOTP)
+		}).
+		AddButton(buttonNameCancel, func() {
+			stop()
+			c.pages.RemovePage(pageNameUpdateOTP)
+		})
+
+	form.
+		SetTitle("Update OTP").
+		SetBorder(true).
+		SetBorderColor(tcell.ColorSteelBlue)
+
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(form, 0, 1, true)
+
+	c.pages.AddPage(pageNameUpdateOTP, flex, true, true)
+
+	if o.Type == model.OTPTypeTOTP {
+		go c.otpCountdown(ctx, done, stop, o, code)
+	}
+}
+
+// otpCountdown – обновлять пароль и обратный отсчет до его истечения, пока не закрыт done, где stop закрывает
+// done, если страница предмета уже закрыта.
+func (c *client) otpCountdown(ctx context.Context, done <-chan struct{}, stop func(), o *storage.OTP,
+	code *tview.TextView) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-done:
+			return
+		case <-ticker.C:
+			c.app.QueueUpdateDraw(func() {
+				if !c.pages.HasPage(pageNameUpdateOTP) {
+					stop()
+					return
+				}
+
+				code.SetText(otpText(o))
+			})
+		}
+	}
+}
+
+// otpText – текущий одноразовый пароль и время до его истечения.
+func otpText(o *storage.OTP) string {
+	code, remaining, err := otp.Code(&model.OTP{
+		Type:      o.Type,
+		Secret:    o.Secret,
+		Algorithm: o.Algorithm,
+		Digits:    o.Digits,
+		Period:    o.Period,
+		Counter:   o.Counter,
+	}, time.Now())
+	if err != nil {
+		log.Error().Err(err).Msg("error of generate otp code")
+		return err.Error()
+	}
+
+	if o.Type == model.OTPTypeHOTP {
+		return fmt.Sprintf("%s (counter %d)", code, o.Counter)
+	}
+
+	return fmt.Sprintf("%s (expires in %ds)", code, int(remaining.Seconds()))
+}
+
+func (c *client) AddOTPPage(ctx context.Context) {
+	log.Printf("Invoked Add otp Page")
+
+	m := storage.Meta{}
+	collection, collectionID := c.collectionDropDown(ctx)
+
+	var uri, issuer, account, secret string
+
+	form := tview.NewForm().
+		AddFormItem(collection).
+		AddInputField(labelOTPURI, uri, defaultFieldWidth, nil, func(text string) {
+			uri = text
+		}).
+		AddInputField(labelOTPIssuer, issuer, defaultFieldWidth, nil, func(text string) {
+			issuer = text
+		}).
+		AddInputField(labelOTPAccount, account, defaultFieldWidth, nil, func(text string) {
+			account = text
+		}).
+		AddPasswordField(labelOTPSecret, secret, defaultFieldWidth, '*', func(text string) {
+			secret = text
+		}).
+		AddTextArea(labelDescription, "", defaultFieldWidth, defaultFieldHeight, defaultMaxLength,
+			func(text string) {
+				m.Set(model.MetaKeyDescription, text)
+			}).
+		AddTextArea(labelAdditionalInformation, "", defaultFieldWidth, defaultFieldHeight, defaultMaxLength,
+			func(text string) {
+				m.Set(model.MetaKeyAdditionalInformation, text)
+			}).
+		AddButton(buttonNameOk, func() {
+			// URI содержит все параметры, иначе создается TOTP с параметрами по умолчанию.
+			var o *model.OTP
+			var err error
+			if uri != "" {
+				o, err = otp.ParseURI(uri)
+			} else {
+				o, err = otp.New(issuer, account, secret)
+			}
+			if err != nil {
+				c.NotifyPage(err.Error())
 				return
 			}
-			f.Name = s.Name()
-			f.Body = d
 
-			_, err = c.storage.CreateItem(ctx, f, m)
+			_, err = c.storage.CreateCollectionItem(ctx, collectionID(), &storage.OTP{
+				Issuer:    o.Issuer,
+				Account:   o.Account,
+				Type:      o.Type,
+				Secret:    o.Secret,
+				Algorithm: o.Algorithm,
+				Digits:    o.Digits,
+				Period:    o.Period,
+				Counter:   o.Counter,
+			}, m)
 			if err != nil {
-				log.Error().Err(err).Msg("Item add error while add file")
+				log.Error().Err(err).Msg("Item add error while add otp")
 				c.NotifyPage(err.Error())
 				return
 			}
 
-			c.pages.RemovePage(pageNameAddFile)
+			c.pages.RemovePage(pageNameAddOTP)
 			c.ItemsPage(ctx)
 		}).
 		AddButton(buttonNameCancel, func() {
-			c.pages.RemovePage(pageNameAddFile)
+			c.pages.RemovePage(pageNameAddOTP)
 		})
 
 	form.
-		SetTitle("Add file").
+		SetTitle("Add OTP, fill otpauth URI or issuer, account and secret").
 		SetBorder(true).
 		SetBorderColor(tcell.ColorSteelBlue)
 
@@ -819,7 +1286,7 @@ func (c *client) AddFilePage(ctx context.Context) {
 		SetDirection(tview.FlexRow).
 		AddItem(form, 0, 1, true)
 
-	c.pages.AddPage(pageNameAddFile, flex, true, true)
+	c.pages.AddPage(pageNameAddOTP, flex, true, true)
 }
 
 func (c *client) UpdateItemPage(ctx context.Context, item *storage.Item) {
@@ -834,38 +1301,1893 @@ func (c *client) UpdateItemPage(ctx context.Context, item *storage.Item) {
 		c.UpdateNotePage(ctx, item, t)
 	case *storage.File:
 		c.UpdateFilePage(ctx, item, t)
+	case *storage.OTP:
+		c.UpdateOTPPage(ctx, item, t)
 	default:
 		log.Error().Msgf("Unknown item body type:%v", reflect.TypeOf(t))
+		return
+	}
+
+	if item.Conflict != "" {
+		c.NotifyPage("Sync conflict: " + item.Conflict + ".\nUpdate the item to resolve the conflict.")
 	}
 }
 
-func (c *client) DeleteItemPage(ctx context.Context, i *storage.Item, name, itype string) {
-	log.Printf("Invoked Delete item page, item(%v)", i.ID)
+// HistoryPage – страница прошлых версий предмета, выбранную версию можно восстановить.
+// После восстановления закрывается и страница предмета parent, т.к. ее содержимое устарело.
+func (c *client) HistoryPage(ctx context.Context, i *storage.Item, parent string) {
+	log.Printf("Invoked History page, item(%v)", i.ID)
 
-	text := fmt.Sprintf("Delete %s %s?", itype, name)
+	if i.RemoteID == 0 {
+		c.NotifyPage("Item is not synced with server yet, there is no history.")
+		return
+	}
 
-	modal := tview.NewModal().
-		SetText(text).
-		AddButtons([]string{buttonNameDelete}).
-		AddButtons([]string{buttonNameCancel}).
-		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
-			if buttonLabel == buttonNameCancel {
-				c.pages.RemovePage(pageNameNotify)
-				return
-			}
-			if buttonLabel == buttonNameDelete {
-				err := c.storage.DeleteItem(ctx, i.ID)
-				if err != nil {
-					c.NotifyPage("error of delete item" + err.Error())
-					return
-				}
+	versions, err := c.grpc.ListItemVersions(ctx, i.RemoteID)
+	if err != nil {
+		log.Error().Err(err).Msg("error of list item versions")
+		c.NotifyPage(err.Error())
+		return
+	}
 
-				c.pages.RemovePage(pageNameNotify)
-				c.ItemsPage(ctx)
-			}
-		})
+	if len(versions) == 0 {
+		c.NotifyPage("Item has no previous versions.")
+		return
+	}
 
-	c.pages.AddPage(pageNameNotify, modal, true, true)
+	table := tview.NewTable().
+		SetFixed(1, 1).
+		SetSelectable(true, false).
+		SetSeparator(' ').
+		SetCell(0, columnName, tview.NewTableCell("Name").SetSelectable(false).SetTextColor(tcell.ColorYellow)).
+		SetCell(0, columnType, tview.NewTableCell("Revision").SetSelectable(false).SetTextColor(tcell.ColorYellow)).
+		SetCell(0, columnUpdateTime, tview.NewTableCell("Update time").SetSelectable(false).SetTextColor(tcell.ColorYellow))
+
+	for row, v := range versions {
+		name, err := v.Item.Body.GetName()
+		if err != nil {
+			log.Error().Err(err).Msg("error of get item version name")
+		}
+
+		revision := strconv.FormatInt(v.Item.Revision, 10)
+		if v.Deleted {
+			revision += " (deleted)"
+		}
+
+		table.
+			SetCell(row+1, columnName, tview.NewTableCell(name).SetTextColor(tcell.ColorWhite).SetReference(v)).
+			SetCell(row+1, columnType, tview.NewTableCell(revision).SetTextColor(tcell.ColorWhite)).
+			SetCell(row+1, columnUpdateTime, newTableCellTime(v.Item.UpdateTime).SetSelectable(false))
+	}
+
+	table.SetSelectedFunc(func(row, column int) {
+		v, ok := table.GetCell(row, columnName).GetReference().(gclient.ItemVersion)
+		if !ok {
+			log.Error().Msgf("error of get item version by reference while selected, row:%v", row)
+			return
+		}
+
+		c.RestoreVersionPage(ctx, i, &v, parent)
+	})
+
+	table.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEscape {
+			c.pages.RemovePage(pageNameHistory)
+		}
+	})
+
+	table.
+		SetTitle("History, Enter - restore version, Esc - back").
+		SetBorder(true).
+		SetBorderColor(tcell.ColorSteelBlue)
+
+	c.pages.AddPage(pageNameHistory, table, true, true)
+}
+
+// RestoreVersionPage – подтверждение восстановления прошлой версии предмета.
+func (c *client) RestoreVersionPage(ctx context.Context, i *storage.Item, v *gclient.ItemVersion, parent string) {
+	log.Printf("Invoked Restore version page, item(%v), revision:%v", i.ID, v.Item.Revision)
+
+	text := fmt.Sprintf("Restore revision %v from %v?\nLocal changes not synced yet will be kept as conflict.",
+		v.Item.Revision, v.Item.UpdateTime.Local().Format(time.RFC3339))
+
+	modal := tview.NewModal().
+		SetText(text).
+		AddButtons([]string{buttonNameRestore, buttonNameCancel}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			c.pages.RemovePage(pageNameNotify)
+			if buttonLabel != buttonNameRestore {
+				return
+			}
+
+			_, err := c.grpc.RestoreItemVersion(ctx, i.RemoteID, v.Item.Revision, i.Revision)
+			if err != nil {
+				log.Error().Err(err).Msg("error of restore item version")
+				c.NotifyPage("error of restore item version:" + err.Error())
+				return
+			}
+
+			c.pages.RemovePage(pageNameHistory)
+			c.pages.RemovePage(parent)
+			c.NotifyAndSwitch2Page("Version restored, item will be updated on next sync.", func() {
+				c.ItemsPage(ctx)
+			})
+		})
+
+	c.pages.AddPage(pageNameNotify, modal, true, true)
+}
+
+// TrashPage – страница предметов в корзине на сервере, выбранный предмет можно восстановить.
+func (c *client) TrashPage(ctx context.Context) {
+	log.Printf("Invoked Trash page")
+
+	items, err := c.grpc.ListTrash(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("error of list trash")
+		c.NotifyPage(err.Error())
+		return
+	}
+
+	if len(items) == 0 {
+		c.NotifyPage("Trash is empty.")
+		return
+	}
+
+	table := tview.NewTable().
+		SetFixed(1, 1).
+		SetSelectable(true, false).
+		SetSeparator(' ').
+		SetCell(0, columnName, tview.NewTableCell("Name").SetSelectable(false).SetTextColor(tcell.ColorYellow)).
+		SetCell(0, columnType, tview.NewTableCell("Delete time").SetSelectable(false).SetTextColor(tcell.ColorYellow))
+
+	for row, i := range items {
+		name, err := i.Item.Body.GetName()
+		if err != nil {
+			log.Error().Err(err).Msg("error of get trash item name")
+		}
+
+		table.
+			SetCell(row+1, columnName, tview.NewTableCell(name).SetTextColor(tcell.ColorWhite).SetReference(i)).
+			SetCell(row+1, columnType, newTableCellTime(i.DeleteTime).SetSelectable(false))
+	}
+
+	table.SetSelectedFunc(func(row, column int) {
+		i, ok := table.GetCell(row, columnName).GetReference().(gclient.TrashItem)
+		if !ok {
+			log.Error().Msgf("error of get trash item by reference while selected, row:%v", row)
+			return
+		}
+
+		c.RestoreItemPage(ctx, &i, table.GetCell(row, columnName).Text)
+	})
+
+	table.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEscape {
+			c.pages.RemovePage(pageNameTrash)
+		}
+	})
+
+	table.
+		SetTitle("Trash, Enter - restore item, Esc - back").
+		SetBorder(true).
+		SetBorderColor(tcell.ColorSteelBlue)
+
+	c.pages.AddPage(pageNameTrash, table, true, true)
+}
+
+// RestoreItemPage – подтверждение восстановления предмета из корзины.
+func (c *client) RestoreItemPage(ctx context.Context, i *gclient.TrashItem, name string) {
+	log.Printf("Invoked Restore item page, item(%v)", i.Item.ID)
+
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("Restore %s from trash?", name)).
+		AddButtons([]string{buttonNameRestore, buttonNameCancel}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			c.pages.RemovePage(pageNameNotify)
+			if buttonLabel != buttonNameRestore {
+				return
+			}
+
+			_, err := c.grpc.RestoreItem(ctx, i.Item.ID)
+			if err != nil {
+				log.Error().Err(err).Msg("error of restore item")
+				c.NotifyPage("error of restore item:" + err.Error())
+				return
+			}
+
+			c.pages.RemovePage(pageNameTrash)
+			c.NotifyAndSwitch2Page("Item restored, it will appear on next sync.", func() {
+				c.ItemsPage(ctx)
+			})
+		})
+
+	c.pages.AddPage(pageNameNotify, modal, true, true)
+}
+
+// SessionsPage – страница сессий пользователя, выбранную сессию или все остальные сессии можно отозвать.
+func (c *client) SessionsPage(ctx context.Context) {
+	log.Printf("Invoked Sessions page")
+
+	sessions, err := c.grpc.ListSessions(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("error of list sessions")
+		c.NotifyPage(err.Error())
+		return
+	}
+
+	table := tview.NewTable().
+		SetFixed(1, 1).
+		SetSelectable(true, false).
+		SetSeparator(' ').
+		SetCell(0, columnName, tview.NewTableCell("Device").SetSelectable(false).SetTextColor(tcell.ColorYellow)).
+		SetCell(0, columnType, tview.NewTableCell("IP").SetSelectable(false).SetTextColor(tcell.ColorYellow)).
+		SetCell(0, columnUpdateTime,
+			tview.NewTableCell("Last seen").SetSelectable(false).SetTextColor(tcell.ColorYellow))
+
+	for row, s := range sessions {
+		device := s.Device
+		if s.Current {
+			device += " (current)"
+		}
+
+		table.
+			SetCell(row+1, columnName, tview.NewTableCell(device).SetTextColor(tcell.ColorWhite).SetReference(s)).
+			SetCell(row+1, columnType, tview.NewTableCell(s.IP).SetSelectable(false)).
+			SetCell(row+1, columnUpdateTime, newTableCellTime(s.LastSeenTime).SetSelectable(false))
+	}
+
+	table.SetSelectedFunc(func(row, column int) {
+		s, ok := table.GetCell(row, columnName).GetReference().(gclient.Session)
+		if !ok {
+			log.Error().Msgf("error of get session by reference while selected, row:%v", row)
+			return
+		}
+
+		c.RevokeSessionPage(ctx, &s)
+	})
+
+	table.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEscape {
+			c.pages.RemovePage(pageNameSessions)
+		}
+	})
+
+	table.
+		SetTitle("Sessions, Enter - revoke session, Esc - back").
+		SetBorder(true).
+		SetBorderColor(tcell.ColorSteelBlue)
+
+	c.pages.AddPage(pageNameSessions, table, true, true)
+}
+
+// RevokeSessionPage – подтверждение отзыва сессии или всех сессий, кроме текущей.
+// Текущую сессию отзывает Logout, поэтому для нее доступен только отзыв остальных сессий.
+func (c *client) RevokeSessionPage(ctx context.Context, s *gclient.Session) {
+	log.Printf("Invoked Revoke session page, session(%v)", s.ID)
+
+	text := fmt.Sprintf("Revoke session of %s?", s.Device)
+	buttons := []string{buttonNameRevoke, buttonNameOthers, buttonNameCancel}
+	if s.Current {
+		text = "It is session of this client, use Logout to revoke it. Revoke all other sessions?"
+		buttons = []string{buttonNameOthers, buttonNameCancel}
+	}
+
+	modal := tview.NewModal().
+		SetText(text).
+		AddButtons(buttons).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			c.pages.RemovePage(pageNameNotify)
+
+			switch buttonLabel {
+			case buttonNameRevoke:
+				err := c.grpc.RevokeSession(ctx, s.ID)
+				if err != nil {
+					log.Error().Err(err).Msg("error of revoke session")
+					c.NotifyPage("error of revoke session:" + err.Error())
+					return
+				}
+			case buttonNameOthers:
+				n, err := c.grpc.RevokeOtherSessions(ctx)
+				if err != nil {
+					log.Error().Err(err).Msg("error of revoke other sessions")
+					c.NotifyPage("error of revoke other sessions:" + err.Error())
+					return
+				}
+
+				log.Printf("Revoked %v other sessions", n)
+			default:
+				return
+			}
+
+			c.pages.RemovePage(pageNameSessions)
+			c.SessionsPage(ctx)
+		})
+
+	c.pages.AddPage(pageNameNotify, modal, true, true)
+}
+
+func (c *client) DeleteItemPage(ctx context.Context, i *storage.Item, name, itype string) {
+	log.Printf("Invoked Delete item page, item(%v)", i.ID)
+
+	text := fmt.Sprintf("Move %s %s to trash?", itype, name)
+
+	modal := tview.NewModal().
+		SetText(text).
+		AddButtons([]string{buttonNameDelete}).
+		AddButtons([]string{buttonNameCancel}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			if buttonLabel == buttonNameCancel {
+				c.pages.RemovePage(pageNameNotify)
+				return
+			}
+			if buttonLabel == buttonNameDelete {
+				err := c.storage.DeleteItem(ctx, i.ID)
+				if err != nil {
+					c.NotifyPage("error of delete item" + err.Error())
+					return
+				}
+
+				c.pages.RemovePage(pageNameNotify)
+				c.ItemsPage(ctx)
+			}
+		})
+
+	c.pages.AddPage(pageNameNotify, modal, true, true)
+}
+
+// SecondFactorSettingsPage – подключение или отключение второго фактора входа.
+func (c *client) SecondFactorSettingsPage(ctx context.Context) {
+	log.Printf("Invoked Second factor settings page")
+
+	modal := tview.NewModal().
+		SetText("Second factor (TOTP) of login").
+		AddButtons([]string{buttonNameEnable, buttonNameDisable, buttonNameCancel}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			c.pages.RemovePage(pageNameNotify)
+
+			switch buttonLabel {
+			case buttonNameEnable:
+				e, err := c.grpc.EnrollSecondFactor(ctx)
+				if err != nil {
+					log.Error().Err(err).Msg("error of enroll second factor")
+					c.NotifyPage("error of enroll second factor:" + err.Error())
+					return
+				}
+
+				c.EnrollSecondFactorPage(ctx, e)
+			case buttonNameDisable:
+				c.DisableSecondFactorPage(ctx)
+			}
+		})
+
+	c.pages.AddPage(pageNameNotify, modal, true, true)
+}
+
+// EnrollSecondFactorPage – показать секрет и резервные коды второго фактора и подтвердить его кодом.
+func (c *client) EnrollSecondFactorPage(ctx context.Context, e *gclient.SecondFactorEnrollment) {
+	log.Printf("Invoked Enroll second factor page")
+
+	var code string
+	form := tview.NewForm().
+		AddTextArea(labelOTPURI, e.URI, 0, 3, 0, nil).
+		AddTextView(labelOTPSecret, e.Secret, 0, 1, false, false).
+		AddTextView(labelBackupCodes, strings.Join(e.BackupCodes, " "), 0, 2, true, false).
+		AddInputField(labelOTPCode, "", defaultFieldWidth, nil, func(text string) {
+			code = text
+		}).
+		AddButton("Confirm", func() {
+			err := c.grpc.ConfirmSecondFactor(ctx, code)
+			if err != nil {
+				log.Error().Err(err).Msg("error of confirm second factor")
+				c.NotifyPage(err.Error())
+				return
+			}
+
+			c.pages.RemovePage(pageNameEnrollSecondFactor)
+			c.NotifyPage("Second factor enabled")
+		}).
+		AddButton(buttonNameCancel, func() {
+			c.pages.RemovePage(pageNameEnrollSecondFactor)
+		})
+
+	form.
+		SetTitle("Add secret to authenticator app, save backup codes and enter code to confirm").
+		SetBorder(true).
+		SetBorderColor(tcell.ColorSteelBlue)
+
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(form, 0, 1, true)
+
+	c.pages.AddPage(pageNameEnrollSecondFactor, flex, true, true)
+}
+
+// DisableSecondFactorPage – отключить второй фактор кодом приложения-аутентификатора или резервным кодом.
+func (c *client) DisableSecondFactorPage(ctx context.Context) {
+	log.Printf("Invoked Disable second factor page")
+
+	var code string
+	form := tview.NewForm().
+		AddInputField(labelOTPCode, "", defaultFieldWidth, nil, func(text string) {
+			code = text
+		}).
+		AddButton(buttonNameDisable, func() {
+			err := c.grpc.DisableSecondFactor(ctx, code)
+			if err != nil {
+				log.Error().Err(err).Msg("error of disable second factor")
+				c.NotifyPage(err.Error())
+				return
+			}
+
+			c.pages.RemovePage(pageNameSecondFactor)
+			c.NotifyPage("Second factor disabled")
+		}).
+		AddButton(buttonNameCancel, func() {
+			c.pages.RemovePage(pageNameSecondFactor)
+		})
+
+	form.
+		SetTitle("Enter code of authenticator app or backup code").
+		SetBorder(true).
+		SetBorderColor(tcell.ColorSteelBlue)
+
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(form, 0, 1, true)
+
+	c.pages.AddPage(pageNameSecondFactor, flex, true, true)
+}
+
+// ChangePasswordPage – сменить пароль пользователя, остальные сессии пользователя отзываются.
+func (c *client) ChangePasswordPage(ctx context.Context) {
+	log.Printf("Invoked Change password page")
+
+	var oldPassword, newPassword, repeatPassword string
+	form := tview.NewForm().
+		AddPasswordField(labelOldPassword, "", defaultFieldWidth, '*', func(text string) {
+			oldPassword = text
+		}).
+		AddPasswordField(labelNewPassword, "", defaultFieldWidth, '*', func(text string) {
+			newPassword = text
+		}).
+		AddPasswordField(labelRepeatPassword, "", defaultFieldWidth, '*', func(text string) {
+			repeatPassword = text
+		}).
+		AddButton(buttonNameOk, func() {
+			if newPassword == "" {
+				c.NotifyPage("New password is empty")
+				return
+			}
+
+			if newPassword != repeatPassword {
+				c.NotifyPage("New passwords do not match")
+				return
+			}
+
+			err := c.grpc.ChangePassword(ctx, oldPassword, newPassword)
+			if err != nil {
+				log.Error().Err(err).Msg("error of change password")
+				c.NotifyPage(err.Error())
+				return
+			}
+
+			c.pages.RemovePage(pageNameChangePassword)
+			c.NotifyPage("Password changed, other sessions are revoked")
+		}).
+		AddButton(buttonNameCancel, func() {
+			c.pages.RemovePage(pageNameChangePassword)
+		})
+
+	form.
+		SetTitle("Change password").
+		SetBorder(true).
+		SetBorderColor(tcell.ColorSteelBlue)
+
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(form, 0, 1, true)
+
+	c.pages.AddPage(pageNameChangePassword, flex, true, true)
+}
+
+// NewRecoveryKitPage – создать новый ключ восстановления по текущему паролю пользователя.
+func (c *client) NewRecoveryKitPage(ctx context.Context) {
+	log.Printf("Invoked New recovery kit page")
+
+	var password string
+	form := tview.NewForm().
+		AddTextView("", "The current emergency kit stops working, other sessions are revoked.", 0, 1, false,
+			false).
+		AddPasswordField(labelPassword, "", defaultFieldWidth, '*', func(text string) {
+			password = text
+		}).
+		AddButton(buttonNameOk, func() {
+			kit, err := c.grpc.NewRecoveryKit(ctx, password)
+			if err != nil {
+				log.Error().Err(err).Msg("error of create recovery kit")
+				c.NotifyPage(err.Error())
+				return
+			}
+
+			c.pages.RemovePage(pageNameNewRecoveryKit)
+			c.RecoveryKitPage(kit, func() {})
+		}).
+		AddButton(buttonNameCancel, func() {
+			c.pages.RemovePage(pageNameNewRecoveryKit)
+		})
+
+	form.
+		SetTitle("New recovery key").
+		SetBorder(true).
+		SetBorderColor(tcell.ColorSteelBlue)
+
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(form, 0, 1, true)
+
+	c.pages.AddPage(pageNameNewRecoveryKit, flex, true, true)
+}
+
+// RecoveryKitPage – показать аварийный набор и сохранить его в текстовый файл или PDF для печати.
+// Ключ восстановления показывается только один раз, после закрытия страницы вызывается done.
+func (c *client) RecoveryKitPage(kit *recovery.Kit, done func()) {
+	log.Printf("Invoked Recovery kit page")
+
+	path := "gophkeeper-emergency-kit.pdf"
+	form := tview.NewForm().
+		AddTextView(labelRecoveryKey, kit.Key, 0, 1, false, false).
+		AddTextView("", kit.Text(), 0, len(kit.Lines())+1, true, true).
+		AddInputField(labelKitFile, path, defaultFieldWidth, nil, func(text string) {
+			path = text
+		}).
+		AddButton("Save", func() {
+			data := []byte(kit.Text())
+			if strings.HasSuffix(strings.ToLower(path), ".pdf") {
+				data = kit.PDF()
+			}
+
+			err := os.WriteFile(path, data, syscall.S_IRUSR|syscall.S_IWUSR)
+			if err != nil {
+				log.Error().Err(err).Msg("error of save emergency kit")
+				c.NotifyPage(err.Error())
+				return
+			}
+
+			c.NotifyPage("Emergency kit saved to " + path + ", print it and keep apart from your devices")
+		}).
+		AddButton(buttonNameOk, func() {
+			c.pages.RemovePage(pageNameRecoveryKit)
+			done()
+		})
+
+	c.addFormPage(pageNameRecoveryKit, "Save emergency kit, recovery key is shown only once", form)
+}
+
+// RecoverPage – установить новый пароль по ключу восстановления из аварийного набора.
+func (c *client) RecoverPage(ctx context.Context) {
+	log.Printf("Invoked Recover page")
+
+	var email, key, newPassword, repeatPassword string
+	form := tview.NewForm().
+		AddInputField("Email", "", defaultFieldWidth, nil, func(text string) {
+			email = text
+		}).
+		AddInputField(labelRecoveryKey, "", defaultFieldWidth+10, nil, func(text string) {
+			key = text
+		}).
+		AddPasswordField(labelNewPassword, "", defaultFieldWidth, '*', func(text string) {
+			newPassword = text
+		}).
+		AddPasswordField(labelRepeatPassword, "", defaultFieldWidth, '*', func(text string) {
+			repeatPassword = text
+		}).
+		AddButton(buttonNameOk, func() {
+			if email == "" {
+				c.NotifyPage("Email is empty")
+				return
+			}
+
+			if newPassword == "" {
+				c.NotifyPage("New password is empty")
+				return
+			}
+
+			if newPassword != repeatPassword {
+				c.NotifyPage("New passwords do not match")
+				return
+			}
+
+			err := c.grpc.RecoverAccount(ctx, email, key, newPassword)
+			if err != nil {
+				log.Error().Err(err).Msg("error of recover account")
+				c.NotifyPage(err.Error())
+				return
+			}
+
+			c.pages.RemovePage(pageNameRecover)
+			text := "Password changed, all sessions are revoked and 2FA is disabled, login with new password"
+			c.NotifyAndSwitch2Page(text, func() {
+				c.WelcomePage(ctx)
+			})
+		}).
+		AddButton(buttonNameCancel, func() {
+			c.pages.RemovePage(pageNameRecover)
+			c.WelcomePage(ctx)
+		})
+
+	c.addFormPage(pageNameRecover, "Recover account with recovery key", form)
+}
+
+// SplitKeyPage – разделить ключ хранилища на доли для доверенных лиц.
+func (c *client) SplitKeyPage(ctx context.Context) {
+	log.Printf("Invoked Split key page")
+
+	n, k := "3", "2"
+	form := tview.NewForm().
+		AddInputField(labelShares, n, defaultFieldWidth, tview.InputFieldInteger, func(text string) {
+			n = text
+		}).
+		AddInputField(labelThreshold, k, defaultFieldWidth, tview.InputFieldInteger, func(text string) {
+			k = text
+		}).
+		AddButton(buttonNameOk, func() {
+			total, err := strconv.Atoi(n)
+			if err != nil {
+				c.NotifyPage("Invalid number of shares")
+				return
+			}
+
+			threshold, err := strconv.Atoi(k)
+			if err != nil {
+				c.NotifyPage("Invalid threshold")
+				return
+			}
+
+			shares, err := c.grpc.SplitVaultKey(ctx, total, threshold)
+			if err != nil {
+				log.Error().Err(err).Msg("error of split vault key")
+				c.NotifyPage(err.Error())
+				return
+			}
+
+			c.pages.RemovePage(pageNameSplitKey)
+			c.KeySharesPage(shares, threshold)
+		}).
+		AddButton(buttonNameCancel, func() {
+			c.pages.RemovePage(pageNameSplitKey)
+		})
+
+	c.addFormPage(pageNameSplitKey, "Split vault key, any threshold shares unlock the vault", form)
+}
+
+// KeySharesPage – показать доли ключа хранилища и сохранить каждую в отдельный файл для передачи доверенным лицам.
+func (c *client) KeySharesPage(shares []string, threshold int) {
+	log.Printf("Invoked Key shares page")
+
+	dir := "."
+	form := tview.NewForm().
+		AddTextView(labelShares, strings.Join(shares, "\n"), 0, len(shares)+1, true, true).
+		AddInputField(labelSharesDir, dir, defaultFieldWidth, nil, func(text string) {
+			dir = text
+		}).
+		AddButton("Save", func() {
+			for i, s := range shares {
+				path := filepath.Join(dir, fmt.Sprintf("gophkeeper-share-%d.txt", i+1))
+
+				err := os.WriteFile(path, []byte(s+"\n"), syscall.S_IRUSR|syscall.S_IWUSR)
+				if err != nil {
+					log.Error().Err(err).Msg("error of save key share")
+					c.NotifyPage(err.Error())
+					return
+				}
+			}
+
+			c.NotifyPage(fmt.Sprintf("%d shares saved to %s, give each to its trustee", len(shares), dir))
+		}).
+		AddButton(buttonNameOk, func() {
+			c.pages.RemovePage(pageNameKeyShares)
+		})
+
+	c.addFormPage(pageNameKeyShares,
+		fmt.Sprintf("Give one share to each trustee, any %d of %d shares unlock the vault", threshold, len(shares)),
+		form)
+}
+
+// UnlockSharesPage – разблокировать хранилище долями ключа без сервера. Доступны предметы локального хранилища,
+// синхронизация не запускается.
+func (c *client) UnlockSharesPage(ctx context.Context) {
+	log.Printf("Invoked Unlock shares page")
+
+	var email, text string
+	form := tview.NewForm().
+		AddInputField("Email", "", defaultFieldWidth, nil, func(t string) {
+			email = t
+		}).
+		AddTextArea(labelShares, "", 0, defaultFieldHeight*2, 0, func(t string) {
+			text = t
+		}).
+		AddButton("Unlock", func() {
+			if email == "" {
+				c.NotifyPage("Email is empty")
+				return
+			}
+
+			var shares []string
+			for _, l := range strings.Split(text, "\n") {
+				if strings.TrimSpace(l) != "" {
+					shares = append(shares, l)
+				}
+			}
+
+			err := c.grpc.UnlockWithShares(ctx, shares)
+			if err != nil {
+				log.Error().Err(err).Msg("error of unlock with shares")
+				c.NotifyPage(err.Error())
+				return
+			}
+
+			c.storage.Open(ctx, email)
+
+			c.pages.RemovePage(pageNameUnlockShares)
+			c.NotifyAndSwitch2Page("Vault unlocked offline, only local items are available", func() {
+				c.ItemsPage(ctx)
+			})
+		}).
+		AddButton(buttonNameCancel, func() {
+			c.pages.RemovePage(pageNameUnlockShares)
+			c.WelcomePage(ctx)
+		})
+
+	c.addFormPage(pageNameUnlockShares, "Enter key shares, one per line", form)
+}
+
+// ShareItemPage – поделиться копией предмета с другим пользователем.
+func (c *client) ShareItemPage(ctx context.Context, i *storage.Item) {
+	log.Printf("Invoked Share item page, item(%v)", i.ID)
+
+	if i.RemoteID == 0 {
+		c.NotifyPage("Item is not synced with server yet, try later.")
+		return
+	}
+
+	if i.CollectionID != 0 {
+		c.NotifyPage("Item of collection is available to members of organization, invite user instead.")
+		return
+	}
+
+	var login string
+	form := tview.NewForm().
+		AddInputField(labelLogin, "", defaultFieldWidth, nil, func(text string) {
+			login = text
+		}).
+		AddButton(buttonNameOk, func() {
+			if login == "" {
+				c.NotifyPage("Login is empty")
+				return
+			}
+
+			body, err := storage.ToModel(i)
+			if err != nil {
+				log.Error().Err(err).Msg("error of convert item while share item")
+				c.NotifyPage(err.Error())
+				return
+			}
+
+			err = c.grpc.ShareItem(ctx, &gclient.Item{ID: i.RemoteID, Body: *body}, login)
+			if err != nil {
+				log.Error().Err(err).Msg("error of share item")
+				c.NotifyPage("error of share item:" + err.Error())
+				return
+			}
+
+			c.pages.RemovePage(pageNameShareItem)
+			c.NotifyPage(fmt.Sprintf("Item shared with %s. Later changes are shared only when item is shared again.",
+				login))
+		}).
+		AddButton(buttonNameCancel, func() {
+			c.pages.RemovePage(pageNameShareItem)
+		})
+
+	form.
+		SetTitle("Share item").
+		SetBorder(true).
+		SetBorderColor(tcell.ColorSteelBlue)
+
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(form, 0, 1, true)
+
+	c.pages.AddPage(pageNameShareItem, flex, true, true)
+}
+
+// SharesPage – предметы, которыми поделился пользователь.
+func (c *client) SharesPage(ctx context.Context) {
+	log.Printf("Invoked Shares page")
+
+	shares, err := c.grpc.ListShares(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("error of list shares")
+		c.NotifyPage(err.Error())
+		return
+	}
+
+	if len(shares) == 0 {
+		c.NotifyPage("No shared items.")
+		return
+	}
+
+	l, err := c.storage.ListItems(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("error of list local items")
+	}
+
+	names := make(map[int64]string, len(l))
+	for _, i := range l {
+		name, err := i.GetName()
+		if err != nil {
+			log.Error().Err(err).Msg("error of get item name")
+			continue
+		}
+
+		names[i.RemoteID] = name
+	}
+
+	table := tview.NewTable().
+		SetFixed(1, 1).
+		SetSelectable(true, false).
+		SetSeparator(' ').
+		SetCell(0, columnName, tview.NewTableCell("Name").SetSelectable(false).SetTextColor(tcell.ColorYellow)).
+		SetCell(0, columnType, tview.NewTableCell("Recipient").SetSelectable(false).SetTextColor(tcell.ColorYellow)).
+		SetCell(0, columnUpdateTime,
+			tview.NewTableCell("Share time").SetSelectable(false).SetTextColor(tcell.ColorYellow))
+
+	for row, s := range shares {
+		table.
+			SetCell(row+1, columnName, tview.NewTableCell(names[s.ItemID]).SetTextColor(tcell.ColorWhite).SetReference(s)).
+			SetCell(row+1, columnType, tview.NewTableCell(s.Recipient).SetSelectable(false)).
+			SetCell(row+1, columnUpdateTime, newTableCellTime(s.UpdateTime).SetSelectable(false))
+	}
+
+	table.SetSelectedFunc(func(row, column int) {
+		s, ok := table.GetCell(row, columnName).GetReference().(gclient.Share)
+		if !ok {
+			log.Error().Msgf("error of get share by reference while selected, row:%v", row)
+			return
+		}
+
+		c.RevokeSharePage(ctx, s.ID, fmt.Sprintf("Revoke %s from %s?", table.GetCell(row, columnName).Text,
+			s.Recipient), buttonNameRevoke, pageNameShares)
+	})
+
+	table.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEscape {
+			c.pages.RemovePage(pageNameShares)
+		}
+	})
+
+	table.
+		SetTitle("My shares, Enter - revoke share, Esc - back").
+		SetBorder(true).
+		SetBorderColor(tcell.ColorSteelBlue)
+
+	c.pages.AddPage(pageNameShares, table, true, true)
+}
+
+// SharedWithMePage – предметы, которыми поделились с пользователем. Предметы доступны только для чтения.
+func (c *client) SharedWithMePage(ctx context.Context) {
+	log.Printf("Invoked Shared with me page")
+
+	items, err := c.grpc.ListSharedWithMe(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("error of list shared with me")
+		c.NotifyPage(err.Error())
+		return
+	}
+
+	if len(items) == 0 {
+		c.NotifyPage("Nobody shared items with you.")
+		return
+	}
+
+	table := tview.NewTable().
+		SetFixed(1, 1).
+		SetSelectable(true, false).
+		SetSeparator(' ').
+		SetCell(0, columnName, tview.NewTableCell("Name").SetSelectable(false).SetTextColor(tcell.ColorYellow)).
+		SetCell(0, columnType, tview.NewTableCell("Type").SetSelectable(false).SetTextColor(tcell.ColorYellow)).
+		SetCell(0, columnUpdateTime, tview.NewTableCell("Owner").SetSelectable(false).SetTextColor(tcell.ColorYellow))
+
+	for row, i := range items {
+		item, err := storage.FromModel(&i.Body)
+		if err != nil {
+			log.Error().Err(err).Msg("error of parse shared item")
+			continue
+		}
+
+		name, err := item.GetName()
+		if err != nil {
+			log.Error().Err(err).Msg("error of get shared item name")
+		}
+
+		itype, err := item.GetType()
+		if err != nil {
+			log.Error().Err(err).Msg("error of get shared item type")
+		}
+
+		table.
+			SetCell(row+1, columnName, tview.NewTableCell(name).SetTextColor(tcell.ColorWhite).SetReference(*item)).
+			SetCell(row+1, columnType, tview.NewTableCell(itype).SetTextColor(tcell.ColorWhite)).
+			SetCell(row+1, columnUpdateTime, tview.NewTableCell(i.Owner).SetTextColor(tcell.ColorWhite).
+				SetReference(i.ID))
+	}
+
+	table.SetSelectedFunc(func(row, column int) {
+		item, ok := table.GetCell(row, columnName).GetReference().(storage.Item)
+		if !ok {
+			log.Error().Msgf("error of get shared item by reference while selected, row:%v", row)
+			return
+		}
+
+		id, ok := table.GetCell(row, columnUpdateTime).GetReference().(int64)
+		if !ok {
+			log.Error().Msgf("error of get share id by reference while selected, row:%v", row)
+			return
+		}
+
+		text := fmt.Sprintf("Shared by %s\n\n%s", table.GetCell(row, columnUpdateTime).Text, sharedItemText(&item))
+		c.RevokeSharePage(ctx, id, text, buttonNameRemove, pageNameSharedWithMe)
+	})
+
+	table.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEscape {
+			c.pages.RemovePage(pageNameSharedWithMe)
+		}
+	})
+
+	table.
+		SetTitle("Shared with me, Enter - show item, Esc - back").
+		SetBorder(true).
+		SetBorderColor(tcell.ColorSteelBlue)
+
+	c.pages.AddPage(pageNameSharedWithMe, table, true, true)
+}
+
+// RevokeSharePage – подтверждение отзыва переданного предмета или отказа от полученного.
+// После отзыва страница parent закрывается, т.к. ее список устарел.
+func (c *client) RevokeSharePage(ctx context.Context, id int64, text, button, parent string) {
+	log.Printf("Invoked Revoke share page, share(%v)", id)
+
+	modal := tview.NewModal().
+		SetText(text).
+		AddButtons([]string{button, buttonNameCancel}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			c.pages.RemovePage(pageNameNotify)
+			if buttonLabel != button {
+				return
+			}
+
+			err := c.grpc.RevokeShare(ctx, id)
+			if err != nil {
+				log.Error().Err(err).Msg("error of revoke share")
+				c.NotifyPage("error of revoke share:" + err.Error())
+				return
+			}
+
+			c.pages.RemovePage(parent)
+		})
+
+	c.pages.AddPage(pageNameNotify, modal, true, true)
+}
+
+// sharedItemText – содержимое полученного предмета для просмотра.
+func sharedItemText(i *storage.Item) string {
+	var text string
+
+	switch b := i.Body.(type) {
+	case *storage.Password:
+		text = fmt.Sprintf("%s: %s\n%s: %s\n%s: %s", labelResource, b.Resource, labelUserName, b.UserName,
+			labelPassword, b.Password)
+	case *storage.Card:
+		text = fmt.Sprintf("%s: %s\n%s: %s\n%s: %s", labelCardNumber, b.Number, labelCardExpires, b.Expires,
+			labelCardHolder, b.Holder)
+	case *storage.Note:
+		text = fmt.Sprintf("%s: %s\n%s: %s", labelName, b.Name, labelNote, b.Body)
+	case *storage.File:
+		text = fmt.Sprintf("%s: %s, %d bytes", labelName, b.Name, len(b.Body))
+	case *storage.OTP:
+		text = fmt.Sprintf("%s: %s\n%s: %s\n%s: %s", labelOTPIssuer, b.Issuer, labelOTPAccount, b.Account,
+			labelOTPCode, otpText(b))
+	}
+
+	if v := i.Meta.Get(model.MetaKeyDescription); v != "" {
+		text += fmt.Sprintf("\n%s: %s", labelDescription, v)
+	}
+
+	if v := i.Meta.Get(model.MetaKeyAdditionalInformation); v != "" {
+		text += fmt.Sprintf("\n%s: %s", labelAdditionalInformation, v)
+	}
+
+	return text
+}
+
+// collectionDropDown – выбор коллекции, в которую добавляется предмет. Предлагаются коллекции организаций,
+// в которых пользователь может изменять предметы. Возвращает функцию получения выбранной коллекции,
+// 0 - личный предмет.
+func (c *client) collectionDropDown(ctx context.Context) (*tview.DropDown, func() int64) {
+	ids := []int64{0}
+	names := []string{"Personal"}
+
+	orgs, err := c.grpc.ListOrgs(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("error of list orgs")
+	}
+
+	roles := make(map[int64]string, len(orgs))
+	for _, o := range orgs {
+		roles[o.ID] = o.Role
+	}
+
+	collections, err := c.grpc.ListCollections(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("error of list collections")
+	}
+
+	for _, i := range collections {
+		if role, ok := roles[i.OrgID]; !ok || role == gclient.RoleReadOnly {
+			continue
+		}
+
+		ids = append(ids, i.ID)
+		names = append(names, i.Name)
+	}
+
+	var id int64
+	dd := tview.NewDropDown().
+		SetLabel(labelCollection).
+		SetOptions(names, func(_ string, index int) {
+			id = ids[index]
+		}).
+		SetCurrentOption(0)
+
+	return dd, func() int64 { return id }
+}
+
+// OrgsPage – организации пользователя.
+func (c *client) OrgsPage(ctx context.Context) {
+	log.Printf("Invoked Orgs page")
+
+	orgs, err := c.grpc.ListOrgs(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("error of list orgs")
+		c.NotifyPage(err.Error())
+		return
+	}
+
+	table := tview.NewTable().
+		SetFixed(1, 1).
+		SetSelectable(true, false).
+		SetSeparator(' ').
+		SetCell(0, columnName, tview.NewTableCell("Name").SetSelectable(false).SetTextColor(tcell.ColorYellow)).
+		SetCell(0, columnType, tview.NewTableCell("Role").SetSelectable(false).SetTextColor(tcell.ColorYellow))
+
+	for row, o := range orgs {
+		table.
+			SetCell(row+1, columnName, tview.NewTableCell(o.Name).SetTextColor(tcell.ColorWhite).SetReference(o)).
+			SetCell(row+1, columnType, tview.NewTableCell(o.Role).SetSelectable(false))
+	}
+
+	selected := func() (gclient.Org, bool) {
+		row, _ := table.GetSelection()
+		o, ok := table.GetCell(row, columnName).GetReference().(gclient.Org)
+		if !ok {
+			c.NotifyPage("Select organization first.")
+		}
+		return o, ok
+	}
+
+	table.SetSelectedFunc(func(row, column int) {
+		if o, ok := selected(); ok {
+			c.MembersPage(ctx, &o)
+		}
+	})
+
+	buttons := tview.NewForm().
+		AddButton("Create org", func() {
+			c.CreateOrgPage(ctx)
+		}).
+		AddButton("Members", func() {
+			if o, ok := selected(); ok {
+				c.MembersPage(ctx, &o)
+			}
+		}).
+		AddButton("Collections", func() {
+			if o, ok := selected(); ok {
+				c.CollectionsPage(ctx, &o)
+			}
+		}).
+		AddButton("Delete org", func() {
+			o, ok := selected()
+			if !ok {
+				return
+			}
+
+			c.confirmPage(fmt.Sprintf("Delete organization %s with all its collections and items?", o.Name),
+				buttonNameDelete, func() error {
+					return c.grpc.DeleteOrg(ctx, o.ID)
+				}, func() {
+					c.OrgsPage(ctx)
+				})
+		}).
+		AddButton("Invitations", func() {
+			c.InvitationsPage(ctx)
+		}).
+		AddButton(buttonNameCancel, func() {
+			c.pages.RemovePage(pageNameOrgs)
+		})
+
+	c.addTablePage(pageNameOrgs, "Organizations, Enter - members", buttons, table)
+}
+
+// CreateOrgPage – создание организации, пользователь становится ее владельцем.
+func (c *client) CreateOrgPage(ctx context.Context) {
+	log.Printf("Invoked Create org page")
+
+	var name string
+	form := tview.NewForm().
+		AddInputField(labelName, "", defaultFieldWidth, nil, func(text string) {
+			name = text
+		}).
+		AddButton(buttonNameOk, func() {
+			if name == "" {
+				c.NotifyPage("Name is empty")
+				return
+			}
+
+			_, err := c.grpc.CreateOrg(ctx, name)
+			if err != nil {
+				log.Error().Err(err).Msg("error of create org")
+				c.NotifyPage("error of create org:" + err.Error())
+				return
+			}
+
+			c.pages.RemovePage(pageNameCreateOrg)
+			c.OrgsPage(ctx)
+		}).
+		AddButton(buttonNameCancel, func() {
+			c.pages.RemovePage(pageNameCreateOrg)
+		})
+
+	c.addFormPage(pageNameCreateOrg, "Create organization", form)
+}
+
+// MembersPage – участники организации. Участник может исключить себя, т.е. покинуть организацию.
+func (c *client) MembersPage(ctx context.Context, o *gclient.Org) {
+	log.Printf("Invoked Members page, org(%v)", o.ID)
+
+	members, err := c.grpc.ListMembers(ctx, o.ID)
+	if err != nil {
+		log.Error().Err(err).Msg("error of list members")
+		c.NotifyPage(err.Error())
+		return
+	}
+
+	table := tview.NewTable().
+		SetFixed(1, 1).
+		SetSelectable(true, false).
+		SetSeparator(' ').
+		SetCell(0, columnName, tview.NewTableCell("Login").SetSelectable(false).SetTextColor(tcell.ColorYellow)).
+		SetCell(0, columnType, tview.NewTableCell("Role").SetSelectable(false).SetTextColor(tcell.ColorYellow)).
+		SetCell(0, columnUpdateTime,
+			tview.NewTableCell("Member since").SetSelectable(false).SetTextColor(tcell.ColorYellow))
+
+	for row, m := range members {
+		table.
+			SetCell(row+1, columnName, tview.NewTableCell(m.Login).SetTextColor(tcell.ColorWhite).SetReference(m)).
+			SetCell(row+1, columnType, tview.NewTableCell(m.Role).SetSelectable(false)).
+			SetCell(row+1, columnUpdateTime, newTableCellTime(m.CreateTime).SetSelectable(false))
+	}
+
+	selected := func() (gclient.OrgMember, bool) {
+		row, _ := table.GetSelection()
+		m, ok := table.GetCell(row, columnName).GetReference().(gclient.OrgMember)
+		if !ok {
+			c.NotifyPage("Select member first.")
+		}
+		return m, ok
+	}
+
+	table.SetSelectedFunc(func(row, column int) {
+		if m, ok := selected(); ok {
+			c.SetMemberRolePage(ctx, o, &m)
+		}
+	})
+
+	buttons := tview.NewForm().
+		AddButton("Invite", func() {
+			c.InviteMemberPage(ctx, o)
+		}).
+		AddButton("Set role", func() {
+			if m, ok := selected(); ok {
+				c.SetMemberRolePage(ctx, o, &m)
+			}
+		}).
+		AddButton(buttonNameRemove, func() {
+			m, ok := selected()
+			if !ok {
+				return
+			}
+
+			c.confirmPage(fmt.Sprintf("Remove %s from organization %s?", m.Login, o.Name), buttonNameRemove,
+				func() error {
+					return c.grpc.RemoveMember(ctx, o.ID, m.Login)
+				}, func() {
+					c.MembersPage(ctx, o)
+				})
+		}).
+		AddButton(buttonNameCancel, func() {
+			c.pages.RemovePage(pageNameMembers)
+		})
+
+	c.addTablePage(pageNameMembers, fmt.Sprintf("Members of %s, Enter - set role", o.Name), buttons, table)
+}
+
+// InviteMemberPage – приглашение пользователя в организацию.
+func (c *client) InviteMemberPage(ctx context.Context, o *gclient.Org) {
+	log.Printf("Invoked Invite member page, org(%v)", o.ID)
+
+	var login string
+	role := orgRoles[0]
+	form := tview.NewForm().
+		AddInputField(labelLogin, "", defaultFieldWidth, nil, func(text string) {
+			login = text
+		}).
+		AddDropDown(labelRole, orgRoles, 0, func(option string, _ int) {
+			role = option
+		}).
+		AddButton(buttonNameOk, func() {
+			if login == "" {
+				c.NotifyPage("Login is empty")
+				return
+			}
+
+			err := c.grpc.InviteMember(ctx, o.ID, login, role)
+			if err != nil {
+				log.Error().Err(err).Msg("error of invite member")
+				c.NotifyPage("error of invite member:" + err.Error())
+				return
+			}
+
+			c.pages.RemovePage(pageNameInviteMember)
+			c.NotifyPage(fmt.Sprintf("%s is invited, user becomes member after accepting invitation.", login))
+		}).
+		AddButton(buttonNameCancel, func() {
+			c.pages.RemovePage(pageNameInviteMember)
+		})
+
+	c.addFormPage(pageNameInviteMember, "Invite to "+o.Name, form)
+}
+
+// SetMemberRolePage – изменение роли участника организации.
+func (c *client) SetMemberRolePage(ctx context.Context, o *gclient.Org, m *gclient.OrgMember) {
+	log.Printf("Invoked Set member role page, org(%v)", o.ID)
+
+	role := m.Role
+	current := 0
+	for i, r := range orgRoles {
+		if r == m.Role {
+			current = i
+		}
+	}
+
+	form := tview.NewForm().
+		AddDropDown(labelRole, orgRoles, current, func(option string, _ int) {
+			role = option
+		}).
+		AddButton(buttonNameOk, func() {
+			err := c.grpc.SetMemberRole(ctx, o.ID, m.Login, role)
+			if err != nil {
+				log.Error().Err(err).Msg("error of set member role")
+				c.NotifyPage("error of set member role:" + err.Error())
+				return
+			}
+
+			c.pages.RemovePage(pageNameSetMemberRole)
+			c.MembersPage(ctx, o)
+		}).
+		AddButton(buttonNameCancel, func() {
+			c.pages.RemovePage(pageNameSetMemberRole)
+		})
+
+	c.addFormPage(pageNameSetMemberRole, fmt.Sprintf("Role of %s in %s", m.Login, o.Name), form)
+}
+
+// InvitationsPage – приглашения пользователя в организации.
+func (c *client) InvitationsPage(ctx context.Context) {
+	log.Printf("Invoked Invitations page")
+
+	invitations, err := c.grpc.ListInvitations(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("error of list invitations")
+		c.NotifyPage(err.Error())
+		return
+	}
+
+	if len(invitations) == 0 {
+		c.NotifyPage("No invitations.")
+		return
+	}
+
+	table := tview.NewTable().
+		SetFixed(1, 1).
+		SetSelectable(true, false).
+		SetSeparator(' ').
+		SetCell(0, columnName, tview.NewTableCell("Organization").SetSelectable(false).SetTextColor(tcell.ColorYellow)).
+		SetCell(0, columnType, tview.NewTableCell("Role").SetSelectable(false).SetTextColor(tcell.ColorYellow)).
+		SetCell(0, columnUpdateTime,
+			tview.NewTableCell("Inviter").SetSelectable(false).SetTextColor(tcell.ColorYellow))
+
+	for row, i := range invitations {
+		table.
+			SetCell(row+1, columnName, tview.NewTableCell(i.OrgName).SetTextColor(tcell.ColorWhite).SetReference(i)).
+			SetCell(row+1, columnType, tview.NewTableCell(i.Role).SetSelectable(false)).
+			SetCell(row+1, columnUpdateTime, tview.NewTableCell(i.Inviter).SetSelectable(false))
+	}
+
+	table.SetSelectedFunc(func(row, column int) {
+		i, ok := table.GetCell(row, columnName).GetReference().(gclient.Invitation)
+		if !ok {
+			log.Error().Msgf("error of get invitation by reference while selected, row:%v", row)
+			return
+		}
+
+		c.InvitationPage(ctx, &i)
+	})
+
+	table.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEscape {
+			c.pages.RemovePage(pageNameInvitations)
+		}
+	})
+
+	table.
+		SetTitle("Invitations, Enter - accept or decline, Esc - back").
+		SetBorder(true).
+		SetBorderColor(tcell.ColorSteelBlue)
+
+	c.pages.AddPage(pageNameInvitations, table, true, true)
+}
+
+// InvitationPage – принятие или отклонение приглашения.
+func (c *client) InvitationPage(ctx context.Context, i *gclient.Invitation) {
+	log.Printf("Invoked Invitation page, invitation(%v)", i.ID)
+
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("%s invites you to %s as %s", i.Inviter, i.OrgName, i.Role)).
+		AddButtons([]string{buttonNameAccept, buttonNameDecline, buttonNameCancel}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			c.pages.RemovePage(pageNameNotify)
+
+			var err error
+			switch buttonLabel {
+			case buttonNameAccept:
+				err = c.grpc.AcceptInvitation(ctx, i.ID)
+			case buttonNameDecline:
+				err = c.grpc.DeclineInvitation(ctx, i.ID)
+			default:
+				return
+			}
+			if err != nil {
+				log.Error().Err(err).Msg("error of answer invitation")
+				c.NotifyPage("error of answer invitation:" + err.Error())
+				return
+			}
+
+			c.pages.RemovePage(pageNameInvitations)
+		})
+
+	c.pages.AddPage(pageNameNotify, modal, true, true)
+}
+
+// CollectionsPage – коллекции организации.
+func (c *client) CollectionsPage(ctx context.Context, o *gclient.Org) {
+	log.Printf("Invoked Collections page, org(%v)", o.ID)
+
+	collections, err := c.grpc.ListCollections(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("error of list collections")
+		c.NotifyPage(err.Error())
+		return
+	}
+
+	table := tview.NewTable().
+		SetFixed(1, 1).
+		SetSelectable(true, false).
+		SetSeparator(' ').
+		SetCell(0, columnName, tview.NewTableCell("Name").SetSelectable(false).SetTextColor(tcell.ColorYellow))
+
+	row := 1
+	for _, i := range collections {
+		if i.OrgID != o.ID {
+			continue
+		}
+
+		table.SetCell(row, columnName, tview.NewTableCell(i.Name).SetTextColor(tcell.ColorWhite).SetReference(i))
+		row++
+	}
+
+	buttons := tview.NewForm().
+		AddButton("Create", func() {
+			c.CreateCollectionPage(ctx, o)
+		}).
+		AddButton(buttonNameDelete, func() {
+			row, _ := table.GetSelection()
+			i, ok := table.GetCell(row, columnName).GetReference().(gclient.Collection)
+			if !ok {
+				c.NotifyPage("Select collection first.")
+				return
+			}
+
+			c.confirmPage(fmt.Sprintf("Delete collection %s with all its items?", i.Name), buttonNameDelete,
+				func() error {
+					return c.grpc.DeleteCollection(ctx, i.ID)
+				}, func() {
+					c.CollectionsPage(ctx, o)
+				})
+		}).
+		AddButton(buttonNameCancel, func() {
+			c.pages.RemovePage(pageNameCollections)
+		})
+
+	c.addTablePage(pageNameCollections, "Collections of "+o.Name, buttons, table)
+}
+
+// CreateCollectionPage – создание коллекции организации.
+func (c *client) CreateCollectionPage(ctx context.Context, o *gclient.Org) {
+	log.Printf("Invoked Create collection page, org(%v)", o.ID)
+
+	var name string
+	form := tview.NewForm().
+		AddInputField(labelName, "", defaultFieldWidth, nil, func(text string) {
+			name = text
+		}).
+		AddButton(buttonNameOk, func() {
+			if name == "" {
+				c.NotifyPage("Name is empty")
+				return
+			}
+
+			_, err := c.grpc.CreateCollection(ctx, o.ID, name)
+			if err != nil {
+				log.Error().Err(err).Msg("error of create collection")
+				c.NotifyPage("error of create collection:" + err.Error())
+				return
+			}
+
+			c.pages.RemovePage(pageNameCreateCollection)
+			c.CollectionsPage(ctx, o)
+		}).
+		AddButton(buttonNameCancel, func() {
+			c.pages.RemovePage(pageNameCreateCollection)
+		})
+
+	c.addFormPage(pageNameCreateCollection, "Create collection in "+o.Name, form)
+}
+
+// EmergencyContactsPage – доверенные лица пользователя. Владелец может одобрить запрос раньше срока,
+// отклонить запрос или отозвать одобренный доступ.
+func (c *client) EmergencyContactsPage(ctx context.Context) {
+	log.Printf("Invoked Emergency contacts page")
+
+	contacts, err := c.grpc.ListEmergencyContacts(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("error of list emergency contacts")
+		c.NotifyPage(err.Error())
+		return
+	}
+
+	table := newEmergencyTable("Contact", contacts, func(e *gclient.EmergencyContact) string {
+		return e.Contact
+	})
+
+	selected := func() (gclient.EmergencyContact, bool) {
+		row, _ := table.GetSelection()
+		e, ok := table.GetCell(row, columnName).GetReference().(gclient.EmergencyContact)
+		if !ok {
+			c.NotifyPage("Select contact first.")
+		}
+		return e, ok
+	}
+
+	refresh := func() {
+		c.EmergencyContactsPage(ctx)
+	}
+
+	buttons := tview.NewForm().
+		AddButton(labelAdd, func() {
+			c.AddEmergencyContactPage(ctx)
+		}).
+		AddButton(buttonNameApprove, func() {
+			e, ok := selected()
+			if !ok {
+				return
+			}
+
+			c.confirmPage(fmt.Sprintf("Approve %s access of %s now?", e.Access, e.Contact), buttonNameApprove,
+				func() error {
+					return c.grpc.ApproveEmergencyAccess(ctx, e.ID)
+				}, refresh)
+		}).
+		AddButton(buttonNameReject, func() {
+			e, ok := selected()
+			if !ok {
+				return
+			}
+
+			c.confirmPage(fmt.Sprintf("Reject access of %s?", e.Contact), buttonNameReject,
+				func() error {
+					return c.grpc.RejectEmergencyAccess(ctx, e.ID)
+				}, refresh)
+		}).
+		AddButton(buttonNameDelete, func() {
+			e, ok := selected()
+			if !ok {
+				return
+			}
+
+			c.confirmPage(fmt.Sprintf("Delete emergency contact %s?", e.Contact), buttonNameDelete,
+				func() error {
+					return c.grpc.DeleteEmergencyContact(ctx, e.ID)
+				}, refresh)
+		}).
+		AddButton("Trusted by", func() {
+			c.EmergencyGrantorsPage(ctx)
+		}).
+		AddButton(buttonNameCancel, func() {
+			c.pages.RemovePage(pageNameEmergencyContacts)
+		})
+
+	c.addTablePage(pageNameEmergencyContacts, "Emergency contacts", buttons, table)
+}
+
+// AddEmergencyContactPage – назначение доверенного лица.
+func (c *client) AddEmergencyContactPage(ctx context.Context) {
+	log.Printf("Invoked Add emergency contact page")
+
+	var login string
+	access := emergencyAccesses[0]
+	days := "7"
+	form := tview.NewForm().
+		AddInputField(labelLogin, "", defaultFieldWidth, nil, func(text string) {
+			login = text
+		}).
+		AddDropDown(labelAccess, emergencyAccesses, 0, func(option string, _ int) {
+			access = option
+		}).
+		AddInputField(labelWaitDays, days, defaultFieldWidth, tview.InputFieldInteger, func(text string) {
+			days = text
+		}).
+		AddButton(buttonNameOk, func() {
+			if login == "" {
+				c.NotifyPage("Login is empty")
+				return
+			}
+
+			wait, err := strconv.ParseInt(days, 10, 32)
+			if err != nil {
+				c.NotifyPage("Invalid wait days")
+				return
+			}
+
+			err = c.grpc.CreateEmergencyContact(ctx, login, access, int32(wait))
+			if err != nil {
+				log.Error().Err(err).Msg("error of create emergency contact")
+				c.NotifyPage("error of create emergency contact:" + err.Error())
+				return
+			}
+
+			c.pages.RemovePage(pageNameAddEmergencyContact)
+			c.EmergencyContactsPage(ctx)
+		}).
+		AddButton(buttonNameCancel, func() {
+			c.pages.RemovePage(pageNameAddEmergencyContact)
+		})
+
+	c.addFormPage(pageNameAddEmergencyContact,
+		"Add emergency contact, access is approved automatically after wait days since request", form)
+}
+
+// EmergencyGrantorsPage – владельцы, назначившие пользователя доверенным лицом.
+func (c *client) EmergencyGrantorsPage(ctx context.Context) {
+	log.Printf("Invoked Emergency grantors page")
+
+	grantors, err := c.grpc.ListEmergencyGrantors(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("error of list emergency grantors")
+		c.NotifyPage(err.Error())
+		return
+	}
+
+	table := newEmergencyTable("Owner", grantors, func(e *gclient.EmergencyContact) string {
+		return e.Owner
+	})
+
+	selected := func() (gclient.EmergencyContact, bool) {
+		row, _ := table.GetSelection()
+		e, ok := table.GetCell(row, columnName).GetReference().(gclient.EmergencyContact)
+		if !ok {
+			c.NotifyPage("Select owner first.")
+		}
+		return e, ok
+	}
+
+	buttons := tview.NewForm().
+		AddButton("Request access", func() {
+			e, ok := selected()
+			if !ok {
+				return
+			}
+
+			var at time.Time
+			c.confirmPage(fmt.Sprintf("Request access to vault of %s? The owner is able to reject request.", e.Owner),
+				buttonNameOk, func() error {
+					var err error
+					at, err = c.grpc.RequestEmergencyAccess(ctx, e.ID)
+					return err
+				}, func() {
+					c.NotifyAndSwitch2Page("Access is approved automatically at "+at.Local().Format(time.RFC3339),
+						func() {
+							c.EmergencyGrantorsPage(ctx)
+						})
+				})
+		}).
+		AddButton("View vault", func() {
+			if e, ok := selected(); ok {
+				c.EmergencyVaultPage(ctx, &e)
+			}
+		}).
+		AddButton("Takeover", func() {
+			if e, ok := selected(); ok {
+				c.TakeoverPage(ctx, &e)
+			}
+		}).
+		AddButton(buttonNameRemove, func() {
+			e, ok := selected()
+			if !ok {
+				return
+			}
+
+			c.confirmPage(fmt.Sprintf("Stop being emergency contact of %s?", e.Owner), buttonNameRemove,
+				func() error {
+					return c.grpc.DeleteEmergencyContact(ctx, e.ID)
+				}, func() {
+					c.EmergencyGrantorsPage(ctx)
+				})
+		}).
+		AddButton(buttonNameCancel, func() {
+			c.pages.RemovePage(pageNameEmergencyGrantors)
+		})
+
+	c.addTablePage(pageNameEmergencyGrantors, "Trusted by", buttons, table)
+}
+
+// EmergencyVaultPage – личные предметы владельца при одобренном экстренном доступе, только для чтения.
+func (c *client) EmergencyVaultPage(ctx context.Context, e *gclient.EmergencyContact) {
+	log.Printf("Invoked Emergency vault page, contact(%v)", e.ID)
+
+	items, err := c.grpc.GetEmergencyVault(ctx, e.ID)
+	if err != nil {
+		log.Error().Err(err).Msg("error of get emergency vault")
+		c.NotifyPage(err.Error())
+		return
+	}
+
+	if len(items) == 0 {
+		c.NotifyPage(fmt.Sprintf("Vault of %s is empty.", e.Owner))
+		return
+	}
+
+	table := tview.NewTable().
+		SetFixed(1, 1).
+		SetSelectable(true, false).
+		SetSeparator(' ').
+		SetCell(0, columnName, tview.NewTableCell("Name").SetSelectable(false).SetTextColor(tcell.ColorYellow)).
+		SetCell(0, columnType, tview.NewTableCell("Type").SetSelectable(false).SetTextColor(tcell.ColorYellow)).
+		SetCell(0, columnUpdateTime, tview.NewTableCell("Update time").SetSelectable(false).SetTextColor(tcell.ColorYellow))
+
+	for row, i := range items {
+		item, err := storage.FromModel(&i.Body)
+		if err != nil {
+			log.Error().Err(err).Msg("error of parse emergency item")
+			continue
+		}
+
+		name, err := item.GetName()
+		if err != nil {
+			log.Error().Err(err).Msg("error of get emergency item name")
+		}
+
+		itype, err := item.GetType()
+		if err != nil {
+			log.Error().Err(err).Msg("error of get emergency item type")
+		}
+
+		table.
+			SetCell(row+1, columnName, tview.NewTableCell(name).SetTextColor(tcell.ColorWhite).SetReference(*item)).
+			SetCell(row+1, columnType, tview.NewTableCell(itype).SetTextColor(tcell.ColorWhite)).
+			SetCell(row+1, columnUpdateTime, newTableCellTime(i.UpdateTime).SetSelectable(false))
+	}
+
+	table.SetSelectedFunc(func(row, column int) {
+		item, ok := table.GetCell(row, columnName).GetReference().(storage.Item)
+		if !ok {
+			log.Error().Msgf("error of get emergency item by reference while selected, row:%v", row)
+			return
+		}
+
+		c.NotifyPage(sharedItemText(&item))
+	})
+
+	table.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEscape {
+			c.pages.RemovePage(pageNameEmergencyVault)
+		}
+	})
+
+	table.
+		SetTitle(fmt.Sprintf("Vault of %s, Enter - show item, Esc - back", e.Owner)).
+		SetBorder(true).
+		SetBorderColor(tcell.ColorSteelBlue)
+
+	c.pages.AddPage(pageNameEmergencyVault, table, true, true)
+}
+
+// TakeoverPage – установка нового пароля владельцу при одобренном доступе takeover.
+func (c *client) TakeoverPage(ctx context.Context, e *gclient.EmergencyContact) {
+	log.Printf("Invoked Takeover page, contact(%v)", e.ID)
+
+	var newPassword, repeatPassword string
+	form := tview.NewForm().
+		AddPasswordField(labelNewPassword, "", defaultFieldWidth, '*', func(text string) {
+			newPassword = text
+		}).
+		AddPasswordField(labelRepeatPassword, "", defaultFieldWidth, '*', func(text string) {
+			repeatPassword = text
+		}).
+		AddButton(buttonNameOk, func() {
+			if newPassword == "" {
+				c.NotifyPage("New password is empty")
+				return
+			}
+
+			if newPassword != repeatPassword {
+				c.NotifyPage("New passwords do not match")
+				return
+			}
+
+			err := c.grpc.TakeoverAccount(ctx, e.ID, e.Owner, newPassword)
+			if err != nil {
+				log.Error().Err(err).Msg("error of takeover account")
+				c.NotifyPage(err.Error())
+				return
+			}
+
+			c.pages.RemovePage(pageNameTakeover)
+			c.NotifyPage(fmt.Sprintf("Password of %s changed, sessions and 2FA of %s are revoked", e.Owner, e.Owner))
+		}).
+		AddButton(buttonNameCancel, func() {
+			c.pages.RemovePage(pageNameTakeover)
+		})
+
+	c.addFormPage(pageNameTakeover, "Set new password of "+e.Owner, form)
+}
+
+// newEmergencyTable – таблица экстренных доступов, login - логин другой стороны доступа.
+func newEmergencyTable(title string, l []gclient.EmergencyContact,
+	login func(e *gclient.EmergencyContact) string) *tview.Table {
+	table := tview.NewTable().
+		SetFixed(1, 1).
+		SetSelectable(true, false).
+		SetSeparator(' ').
+		SetCell(0, columnName, tview.NewTableCell(title).SetSelectable(false).SetTextColor(tcell.ColorYellow)).
+		SetCell(0, columnType, tview.NewTableCell("Access").SetSelectable(false).SetTextColor(tcell.ColorYellow)).
+		SetCell(0, columnUpdateTime, tview.NewTableCell("State").SetSelectable(false).SetTextColor(tcell.ColorYellow))
+
+	for row, e := range l {
+		state := e.State
+		if e.State == gclient.EmergencyStateRequested {
+			state = fmt.Sprintf("%s, approve at %s (%d days wait)", e.State,
+				e.ApproveTime.Local().Format(time.RFC3339), e.WaitDays)
+		}
+
+		table.
+			SetCell(row+1, columnName, tview.NewTableCell(login(&e)).SetTextColor(tcell.ColorWhite).SetReference(e)).
+			SetCell(row+1, columnType, tview.NewTableCell(e.Access).SetSelectable(false)).
+			SetCell(row+1, columnUpdateTime, tview.NewTableCell(state).SetSelectable(false))
+	}
+
+	return table
+}
+
+// confirmPage – подтверждение действия action, после успешного выполнения вызывается done.
+func (c *client) confirmPage(text, button string, action func() error, done func()) {
+	modal := tview.NewModal().
+		SetText(text).
+		AddButtons([]string{button, buttonNameCancel}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			c.pages.RemovePage(pageNameNotify)
+			if buttonLabel != button {
+				return
+			}
+
+			err := action()
+			if err != nil {
+				log.Error().Err(err).Msg("error of confirmed action")
+				c.NotifyPage(err.Error())
+				return
+			}
+
+			done()
+		})
+
+	c.pages.AddPage(pageNameNotify, modal, true, true)
+}
+
+// addTablePage – добавить страницу с кнопками над таблицей, Esc в таблице закрывает страницу.
+func (c *client) addTablePage(name, title string, buttons *tview.Form, table *tview.Table) {
+	table.
+		SetBorder(true).
+		SetBorderColor(tcell.ColorSteelBlue)
+
+	table.SetDoneFunc(func(key tcell.Key) {
+		switch key {
+		case tcell.KeyEscape:
+			c.pages.RemovePage(name)
+		case tcell.KeyTab:
+			c.app.SetFocus(buttons)
+		}
+	})
+
+	buttons.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		//nolint // no need here
+		switch event.Key() {
+		case tcell.KeyTab:
+			c.app.SetFocus(table)
+		case tcell.KeyRight:
+			return tcell.NewEventKey(tcell.KeyTab, 0, tcell.ModNone)
+		case tcell.KeyLeft:
+			return tcell.NewEventKey(tcell.KeyBacktab, 0, tcell.ModNone)
+		}
+		return event
+	})
+
+	buttons.
+		SetButtonsAlign(tview.AlignLeft).
+		SetBorderPadding(0, 0, 0, 0)
+
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(buttons, 1, 1, false).
+		AddItem(table, 0, 1, true)
+
+	flex.
+		SetTitle(title + ", Tab - buttons, Esc - back").
+		SetBorder(true)
+
+	c.pages.AddPage(name, flex, true, true)
+}
+
+// addFormPage – добавить страницу с формой.
+func (c *client) addFormPage(name, title string, form *tview.Form) {
+	form.
+		SetTitle(title).
+		SetBorder(true).
+		SetBorderColor(tcell.ColorSteelBlue)
+
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(form, 0, 1, true)
+
+	c.pages.AddPage(name, flex, true, true)
 }
 
 func (c *client) StartSync(ctx context.Context) {
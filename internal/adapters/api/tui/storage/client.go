@@ -12,8 +12,11 @@ import (
 )
 
 type ItemStorage interface {
+	Open(ctx context.Context, login string)
 	Clear(ctx context.Context)
 	CreateItem(ctx context.Context, body any, meta Meta) (string, error)
+	// CreateCollectionItem - создать предмет коллекции организации, 0 - личный предмет.
+	CreateCollectionItem(ctx context.Context, collectionID int64, body any, meta Meta) (string, error)
 	UpdateItem(ctx context.Context, item *Item) error
 	GetItem(ctx context.Context, id string) (*Item, error)
 	ListItems(ctx context.Context) ([]Item, error)
@@ -31,14 +34,26 @@ func New(s pclient.ItemStorage) *client {
 	}
 }
 
+// Open – открыть хранилище пользователя.
+func (c *client) Open(ctx context.Context, login string) {
+	c.storage.Open(ctx, login)
+}
+
 // Clear – подчистка хранилища.
 func (c *client) Clear(ctx context.Context) {
 	c.storage.Clear(ctx)
 }
 
-// CreateItem - создать предмет.
+// CreateItem - создать личный предмет.
 func (c *client) CreateItem(ctx context.Context, body any, meta Meta) (string, error) {
-	log.Ctx(ctx).Printf("Create item")
+	return c.CreateCollectionItem(ctx, 0, body, meta)
+}
+
+// CreateCollectionItem - создать предмет коллекции организации, 0 - личный предмет.
+// Коллекция предмета не меняется, предмет синхронизируется в нее и шифруется ее ключом.
+func (c *client) CreateCollectionItem(ctx context.Context, collectionID int64, body any, meta Meta) (string,
+	error) {
+	log.Ctx(ctx).Printf("Create item, collection id:%v", collectionID)
 
 	var item model.Item
 
@@ -52,10 +67,11 @@ func (c *client) CreateItem(ctx context.Context, body any, meta Meta) (string, e
 	now := time.Now()
 
 	si := &pclient.Item{
-		Body:       item,
-		CreateTime: now,
-		UpdateTime: now,
-		DeleteMark: false,
+		Body:         item,
+		CreateTime:   now,
+		UpdateTime:   now,
+		CollectionID: collectionID,
+		DeleteMark:   false,
 	}
 
 	id, err := c.storage.CreateItem(ctx, si)
@@ -81,10 +97,15 @@ func (c *client) UpdateItem(ctx context.Context, i *Item) error {
 	item.Meta = model.Meta(i.Meta)
 
 	ut := time.Now()
+	cm := true
+	// Изменение предмета пользователем разрешает конфликт синхронизации, если он был.
+	cf := ""
 	ui := &pclient.UpdateItem{
 		ID:         i.ID,
 		Body:       &item,
 		UpdateTime: &ut,
+		ChangeMark: &cm,
+		Conflict:   &cf,
 	}
 
 	err = c.storage.UpdateItem(ctx, ui)
@@ -172,6 +193,32 @@ func (c *client) DeleteItem(ctx context.Context, id string) error {
 	return nil
 }
 
+// ToModel - тело и метаинформация предмета в модели клиента, например, чтобы поделиться предметом.
+func ToModel(i *Item) (*model.Item, error) {
+	var item model.Item
+
+	err := convertAndFillBody(&item, i.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error of convert and fill body of item(%v):%w", i.ID, err)
+	}
+	item.Meta = model.Meta(i.Meta)
+
+	return &item, nil
+}
+
+// FromModel - предмет из модели клиента, который не хранится локально, например, полученный от другого пользователя.
+func FromModel(i *model.Item) (*Item, error) {
+	b, err := parseBody(i)
+	if err != nil {
+		return nil, fmt.Errorf("error of parse item body:%w", err)
+	}
+
+	return &Item{
+		Body: b,
+		Meta: Meta(i.Meta),
+	}, nil
+}
+
 func parseItem(i *pclient.Item) (*Item, error) {
 	b, err := parseBody(&i.Body)
 	if err != nil {
@@ -179,11 +226,15 @@ func parseItem(i *pclient.Item) (*Item, error) {
 	}
 
 	return &Item{
-		ID:         i.ID,
-		Body:       b,
-		Meta:       Meta(i.Body.Meta),
-		CreateTime: i.CreateTime,
-		UpdateTime: i.UpdateTime,
+		ID:           i.ID,
+		Body:         b,
+		Meta:         Meta(i.Body.Meta),
+		CreateTime:   i.CreateTime,
+		UpdateTime:   i.UpdateTime,
+		Conflict:     i.Conflict,
+		RemoteID:     i.RemoteID,
+		Revision:     i.Revision,
+		CollectionID: i.CollectionID,
 	}, nil
 }
 
@@ -211,10 +262,32 @@ func convertNote(b *Note) *model.Note {
 }
 
 func convertFile(b *File) *model.File {
-	return &model.File{
+	f := &model.File{
 		Name: b.Name,
 		Body: b.Body,
 	}
+
+	if b.BlobID != "" {
+		f.Blob = &model.Blob{
+			ID:   b.BlobID,
+			Size: b.Size,
+		}
+	}
+
+	return f
+}
+
+func convertOTP(b *OTP) *model.OTP {
+	return &model.OTP{
+		Issuer:    b.Issuer,
+		Account:   b.Account,
+		Type:      b.Type,
+		Secret:    b.Secret,
+		Algorithm: b.Algorithm,
+		Digits:    b.Digits,
+		Period:    b.Period,
+		Counter:   b.Counter,
+	}
 }
 
 func convertAndFillBody(i *model.Item, body any) error {
@@ -227,6 +300,8 @@ func convertAndFillBody(i *model.Item, body any) error {
 		i.Note = convertNote(b)
 	case *File:
 		i.File = convertFile(b)
+	case *OTP:
+		i.OTP = convertOTP(b)
 	default:
 		return fmt.Errorf("unkown item body type:%v", reflect.TypeOf(b))
 	}
@@ -258,10 +333,30 @@ func parseNote(b *model.Note) *Note {
 }
 
 func parseFile(b *model.File) *File {
-	return &File{
+	f := &File{
 		Name: b.Name,
 		Body: b.Body,
 	}
+
+	if b.Blob != nil {
+		f.BlobID = b.Blob.ID
+		f.Size = b.Blob.Size
+	}
+
+	return f
+}
+
+func parseOTP(b *model.OTP) *OTP {
+	return &OTP{
+		Issuer:    b.Issuer,
+		Account:   b.Account,
+		Type:      b.Type,
+		Secret:    b.Secret,
+		Algorithm: b.Algorithm,
+		Digits:    b.Digits,
+		Period:    b.Period,
+		Counter:   b.Counter,
+	}
 }
 
 func parseBody(i *model.Item) (any, error) {
@@ -281,6 +376,8 @@ func parseBody(i *model.Item) (any, error) {
 		pib = parseNote(b)
 	case *model.File:
 		pib = parseFile(b)
+	case *model.OTP:
+		pib = parseOTP(b)
 	default:
 		return nil, fmt.Errorf("unkown storage item body:%v", reflect.TypeOf(b))
 	}
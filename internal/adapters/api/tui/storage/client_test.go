@@ -70,6 +70,34 @@ func TestCreateItem(t *testing.T) {
 				model.MetaKeyAdditionalInformation: "File additional information",
 			},
 		},
+		{
+			name:    "Check CreateItem OTP",
+			storage: inmemory.New(),
+			body: &OTP{
+				Issuer:    "Issuer",
+				Account:   "Account",
+				Type:      model.OTPTypeTOTP,
+				Secret:    "JBSWY3DPEHPK3PXP",
+				Algorithm: model.OTPAlgorithmSHA1,
+				Digits:    6,
+				Period:    30,
+			},
+			meta: map[string]string{
+				model.MetaKeyDescription: "OTP description",
+			},
+		},
+		{
+			name:    "Check CreateItem File with blob",
+			storage: inmemory.New(),
+			body: &File{
+				Name:   "Name",
+				BlobID: "d5b1c9a6-3f5e-4f4e-9a38-8f4c3f0b6f4a",
+				Size:   10 * 1024 * 1024,
+			},
+			meta: map[string]string{
+				model.MetaKeyDescription: "File description",
+			},
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -290,6 +318,7 @@ func TestListItems(t *testing.T) {
 					UpdateTime: time.Date(2024, time.May, 5, 8, 10, 0, 0, time.UTC),
 					ID:         "ID",
 					RemoteID:   1,
+					Revision:   3,
 					DeleteMark: false,
 				},
 				pclient.Item{
@@ -324,7 +353,9 @@ func TestListItems(t *testing.T) {
 						"description":            "card description",
 						"additional information": "card additional information",
 					},
-					ID: "ID",
+					ID:       "ID",
+					RemoteID: 1,
+					Revision: 3,
 				},
 			},
 		},
@@ -1,22 +1,20 @@
 package storage
 
 import (
-	"errors"
 	"fmt"
 	"time"
 )
 
-var (
-	MaxFileSize  = 40 * 1024 * 1024 // 40MB
-	ErrLargeFile = errors.New("large file")
-)
-
 type Item struct {
-	CreateTime time.Time
-	UpdateTime time.Time
-	Body       any  // password, card, file, note
-	Meta       Meta // metainformation for body
-	ID         string
+	CreateTime   time.Time
+	UpdateTime   time.Time
+	Body         any  // password, card, file, note, otp
+	Meta         Meta // metainformation for body
+	ID           string
+	Conflict     string // description of sync conflict, empty if no conflict
+	RemoteID     int64  // item id on server, 0 if item is not synced yet
+	Revision     int64  // item revision on server
+	CollectionID int64  // collection of organization, 0 - personal item
 }
 
 func (i *Item) GetName() (string, error) {
@@ -29,6 +27,8 @@ func (i *Item) GetName() (string, error) {
 		return t.GetName(), nil
 	case *File:
 		return t.GetName(), nil
+	case *OTP:
+		return t.GetName(), nil
 	}
 
 	return "", fmt.Errorf("unknown item body type")
@@ -44,6 +44,8 @@ func (i *Item) GetType() (string, error) {
 		return t.GetType(), nil
 	case *File:
 		return t.GetType(), nil
+	case *OTP:
+		return t.GetType(), nil
 	}
 
 	return "", fmt.Errorf("unknown item body type")
@@ -91,8 +93,10 @@ func (n *Note) GetType() string {
 }
 
 type File struct {
-	Name string
-	Body []byte
+	Name   string
+	BlobID string // blob with file content on server, empty for file with inline body
+	Body   []byte
+	Size   int64
 }
 
 func (f *File) GetName() string {
@@ -103,6 +107,33 @@ func (f *File) GetType() string {
 	return "file"
 }
 
+type OTP struct {
+	Issuer    string
+	Account   string
+	Type      string
+	Secret    string
+	Algorithm string
+	Digits    int
+	Period    int64
+	Counter   uint64
+}
+
+func (o *OTP) GetName() string {
+	if o.Issuer == "" {
+		return o.Account
+	}
+
+	if o.Account == "" {
+		return o.Issuer
+	}
+
+	return o.Issuer + ":" + o.Account
+}
+
+func (o *OTP) GetType() string {
+	return "otp"
+}
+
 type Meta map[string]string
 
 func (m Meta) Get(label string) string {
@@ -0,0 +1,193 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/k0st1a/gophkeeper/internal/ports/server"
+	"github.com/rs/zerolog/log"
+)
+
+func (d *db) GetBlob(ctx context.Context, userID int64, blobID string) (*server.Blob, error) {
+	log.Ctx(ctx).Printf("GetBlob, userID:%v, blobID:%v", userID, blobID)
+	b := server.Blob{ID: blobID}
+
+	err := d.pool.QueryRow(ctx,
+		"SELECT chunks, size, complete FROM blobs WHERE user_id = $1 AND id = $2",
+		userID, blobID).Scan(&b.Chunks, &b.Size, &b.Complete)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, server.ErrBlobNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blob:%w", err)
+	}
+
+	log.Ctx(ctx).Printf("GetBlob success")
+	return &b, nil
+}
+
+func (d *db) AddBlobChunk(ctx context.Context, userID int64, blobID string,
+	chunk *server.BlobChunk, quota int64) (*server.Blob, error) {
+	log.Ctx(ctx).Printf("AddBlobChunk, userID:%v, blobID:%v, index:%v", userID, blobID, chunk.Index)
+	b := server.Blob{ID: blobID}
+
+	err := pgx.BeginFunc(ctx, d.pool, func(tx pgx.Tx) error {
+		// Строка пользователя блокируется до подсчета занятого места, иначе параллельные загрузки
+		// пользователя видят одну и ту же сумму и вместе превышают квоту.
+		if quota != 0 {
+			err := lockUser(ctx, tx, userID)
+			if err != nil {
+				return err
+			}
+		}
+
+		if chunk.Index == 0 {
+			_, err := tx.Exec(ctx,
+				"INSERT INTO blobs (user_id, id) VALUES($1, $2) ON CONFLICT DO NOTHING",
+				userID, blobID)
+			if err != nil {
+				return fmt.Errorf("query error of create blob:%w", err)
+			}
+		}
+
+		err := tx.QueryRow(ctx,
+			"SELECT chunks, complete FROM blobs WHERE user_id = $1 AND id = $2 FOR UPDATE",
+			userID, blobID).Scan(&b.Chunks, &b.Complete)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return server.ErrBlobNotFound
+		}
+
+		if err != nil {
+			return fmt.Errorf("query error of get blob:%w", err)
+		}
+
+		if b.Complete {
+			return server.ErrBlobComplete
+		}
+
+		if chunk.Index != b.Chunks {
+			return fmt.Errorf("%w, expected index:%v", server.ErrUnexpectedBlobChunk, b.Chunks)
+		}
+
+		if quota != 0 {
+			var size int64
+			err = tx.QueryRow(ctx,
+				"SELECT COALESCE(SUM(size), 0) FROM blobs WHERE user_id = $1",
+				userID).Scan(&size)
+			if err != nil {
+				return fmt.Errorf("query error of get blobs size:%w", err)
+			}
+
+			if size+int64(len(chunk.Data)) > quota {
+				return server.ErrBlobQuotaExceeded
+			}
+		}
+
+		_, err = tx.Exec(ctx,
+			"INSERT INTO blob_chunks (user_id, blob_id, chunk_index, data) VALUES($1, $2, $3, $4)",
+			userID, blobID, chunk.Index, chunk.Data)
+		if err != nil {
+			return fmt.Errorf("query error of insert blob chunk:%w", err)
+		}
+
+		err = tx.QueryRow(ctx,
+			"UPDATE blobs SET chunks = chunks + 1, size = size + $1, complete = $2 "+
+				"WHERE user_id = $3 AND id = $4 "+
+				"RETURNING chunks, size, complete",
+			len(chunk.Data), chunk.Last, userID, blobID).Scan(&b.Chunks, &b.Size, &b.Complete)
+		if err != nil {
+			return fmt.Errorf("query error of update blob:%w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to add blob chunk:%w", err)
+	}
+
+	log.Ctx(ctx).Printf("AddBlobChunk success, chunks:%v", b.Chunks)
+	return &b, nil
+}
+
+// lockUser - заблокировать строку пользователя до конца транзакции.
+func lockUser(ctx context.Context, tx pgx.Tx, userID int64) error {
+	var id int64
+
+	err := tx.QueryRow(ctx, "SELECT id FROM users WHERE id = $1 FOR UPDATE", userID).Scan(&id)
+	if err != nil {
+		return fmt.Errorf("query error of lock user:%w", err)
+	}
+
+	return nil
+}
+
+func (d *db) GetBlobChunk(ctx context.Context, userID int64, blobID string, index int64) (*server.BlobChunk, error) {
+	log.Ctx(ctx).Printf("GetBlobChunk, userID:%v, blobID:%v, index:%v", userID, blobID, index)
+	c := server.BlobChunk{Index: index}
+
+	err := d.pool.QueryRow(ctx,
+		"SELECT c.data, b.complete AND c.chunk_index = b.chunks - 1 FROM blob_chunks c "+
+			"JOIN blobs b ON b.user_id = c.user_id AND b.id = c.blob_id "+
+			"WHERE c.user_id = $1 AND c.blob_id = $2 AND c.chunk_index = $3",
+		userID, blobID, index).Scan(&c.Data, &c.Last)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, server.ErrBlobNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blob chunk:%w", err)
+	}
+
+	log.Ctx(ctx).Printf("GetBlobChunk success")
+	return &c, nil
+}
+
+// PurgeBlobs - удалить блобы всех пользователей, созданные раньше before, если их загрузка не завершена или на них
+// не ссылается ни один предмет пользователя и ни одна прошлая версия предмета. Возвращает количество удаленных блобов.
+func (d *db) PurgeBlobs(ctx context.Context, before time.Time) (int64, error) {
+	log.Ctx(ctx).Printf("PurgeBlobs, before:%v", before)
+
+	tag, err := d.pool.Exec(ctx,
+		"DELETE FROM blobs WHERE create_time < $1 AND (NOT complete OR ("+
+			"NOT EXISTS (SELECT 1 FROM items WHERE items.user_id = blobs.user_id AND blobs.id = ANY(items.blob_ids)) "+
+			"AND NOT EXISTS (SELECT 1 FROM items_history h "+
+			"WHERE h.user_id = blobs.user_id AND blobs.id = ANY(h.blob_ids))))",
+		before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge blobs:%w", err)
+	}
+
+	log.Ctx(ctx).Printf("PurgeBlobs success, blobs:%v", tag.RowsAffected())
+	return tag.RowsAffected(), nil
+}
+
+// deleteUnreferencedBlobs - удалить блобы из ids, на которые больше не ссылается ни один предмет пользователя
+// и ни одна прошлая версия предмета.
+func deleteUnreferencedBlobs(ctx context.Context, tx pgx.Tx, userID int64, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	_, err := tx.Exec(ctx,
+		"DELETE FROM blobs WHERE user_id = $1 AND id = ANY($2) "+
+			"AND NOT EXISTS (SELECT 1 FROM items WHERE items.user_id = $1 AND blobs.id = ANY(items.blob_ids)) "+
+			"AND NOT EXISTS (SELECT 1 FROM items_history h WHERE h.user_id = $1 AND blobs.id = ANY(h.blob_ids))",
+		userID, ids)
+	if err != nil {
+		return fmt.Errorf("query error of delete unreferenced blobs:%w", err)
+	}
+
+	return nil
+}
+
+// blobIDs - ссылки предмета на блобы, NULL в колонку не пишется.
+func blobIDs(ids []string) []string {
+	if ids == nil {
+		return []string{}
+	}
+	return ids
+}
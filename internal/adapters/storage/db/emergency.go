@@ -0,0 +1,237 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/k0st1a/gophkeeper/internal/ports/server"
+	"github.com/rs/zerolog/log"
+)
+
+// emergencyColumns - поля доверенного лица с логинами владельца и доверенного лица, см. scanEmergencyContact.
+const emergencyColumns = "SELECT e.id, e.owner_id, o.login, e.grantee_id, g.login, e.access, e.wait_days, " +
+	"e.vault_key, e.state, e.create_time, e.request_time, e.approve_time " +
+	"FROM emergency_contacts e " +
+	"JOIN users o ON o.id = e.owner_id " +
+	"JOIN users g ON g.id = e.grantee_id "
+
+func (d *db) CreateEmergencyContact(ctx context.Context, c *server.EmergencyContact) (int64, error) {
+	log.Ctx(ctx).Printf("CreateEmergencyContact, userID:%v, granteeID:%v", c.OwnerID, c.GranteeID)
+	var id int64
+
+	err := d.pool.QueryRow(ctx,
+		"INSERT INTO emergency_contacts (owner_id, grantee_id, access, wait_days, vault_key, create_time) "+
+			"VALUES($1, $2, $3, $4, $5, $6) "+
+			"ON CONFLICT (owner_id, grantee_id) DO UPDATE SET access = EXCLUDED.access, "+
+			"wait_days = EXCLUDED.wait_days, vault_key = EXCLUDED.vault_key, create_time = EXCLUDED.create_time, "+
+			"state = 'idle', request_time = NULL, approve_time = NULL "+
+			"RETURNING id",
+		c.OwnerID, c.GranteeID, c.Access, c.WaitDays, c.VaultKey, time.Now()).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create emergency contact:%w", err)
+	}
+
+	log.Ctx(ctx).Printf("CreateEmergencyContact success, id:%v", id)
+	return id, nil
+}
+
+func (d *db) GetEmergencyContact(ctx context.Context, id int64) (*server.EmergencyContact, error) {
+	log.Ctx(ctx).Printf("GetEmergencyContact, id:%v", id)
+
+	c, err := scanEmergencyContact(d.pool.QueryRow(ctx, emergencyColumns+"WHERE e.id = $1", id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, server.ErrEmergencyContactNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get emergency contact:%w", err)
+	}
+
+	return &c, nil
+}
+
+func (d *db) ListEmergencyContacts(ctx context.Context, ownerID int64) ([]server.EmergencyContact, error) {
+	log.Ctx(ctx).Printf("ListEmergencyContacts, userID:%v", ownerID)
+
+	return d.listEmergencyContacts(ctx, "WHERE e.owner_id = $1 ORDER BY g.login", ownerID)
+}
+
+func (d *db) ListEmergencyGrantors(ctx context.Context, granteeID int64) ([]server.EmergencyContact, error) {
+	log.Ctx(ctx).Printf("ListEmergencyGrantors, userID:%v", granteeID)
+
+	return d.listEmergencyContacts(ctx, "WHERE e.grantee_id = $1 ORDER BY o.login", granteeID)
+}
+
+// listEmergencyContacts - получить доверенных лиц по условию where.
+func (d *db) listEmergencyContacts(ctx context.Context, where string, userID int64) (
+	[]server.EmergencyContact, error) {
+	rows, err := d.pool.Query(ctx, emergencyColumns+where, userID)
+	if err != nil {
+		return nil, fmt.Errorf("query error of list emergency contacts:%w", err)
+	}
+
+	contacts, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (server.EmergencyContact, error) {
+		return scanEmergencyContact(row)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error of list emergency contacts:%w", err)
+	}
+
+	log.Ctx(ctx).Printf("List emergency contacts success, contacts:%v", len(contacts))
+	return contacts, nil
+}
+
+func (d *db) DeleteEmergencyContact(ctx context.Context, userID, id int64) error {
+	log.Ctx(ctx).Printf("DeleteEmergencyContact, userID:%v, id:%v", userID, id)
+
+	tag, err := d.pool.Exec(ctx,
+		"DELETE FROM emergency_contacts WHERE id = $1 AND (owner_id = $2 OR grantee_id = $2)",
+		id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete emergency contact:%w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return server.ErrEmergencyContactNotFound
+	}
+
+	log.Ctx(ctx).Printf("DeleteEmergencyContact success")
+	return nil
+}
+
+func (d *db) RequestEmergencyAccess(ctx context.Context, id int64) (time.Time, error) {
+	log.Ctx(ctx).Printf("RequestEmergencyAccess, id:%v", id)
+	var approve time.Time
+
+	err := d.pool.QueryRow(ctx,
+		"UPDATE emergency_contacts SET state = 'requested', request_time = $2, "+
+			"approve_time = $2 + make_interval(days => wait_days) "+
+			"WHERE id = $1 AND state = 'idle' "+
+			"RETURNING approve_time",
+		id, time.Now()).Scan(&approve)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return time.Time{}, server.ErrEmergencyStateChanged
+	}
+
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to request emergency access:%w", err)
+	}
+
+	log.Ctx(ctx).Printf("RequestEmergencyAccess success, approve time:%v", approve)
+	return approve, nil
+}
+
+func (d *db) ApproveEmergencyAccess(ctx context.Context, id int64) error {
+	log.Ctx(ctx).Printf("ApproveEmergencyAccess, id:%v", id)
+
+	tag, err := d.pool.Exec(ctx,
+		"UPDATE emergency_contacts SET state = 'approved', approve_time = $2 WHERE id = $1 AND state = 'requested'",
+		id, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to approve emergency access:%w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return server.ErrEmergencyStateChanged
+	}
+
+	log.Ctx(ctx).Printf("ApproveEmergencyAccess success")
+	return nil
+}
+
+func (d *db) RejectEmergencyAccess(ctx context.Context, id int64) error {
+	log.Ctx(ctx).Printf("RejectEmergencyAccess, id:%v", id)
+
+	tag, err := d.pool.Exec(ctx,
+		"UPDATE emergency_contacts SET state = 'idle', request_time = NULL, approve_time = NULL "+
+			"WHERE id = $1 AND state <> 'idle'",
+		id)
+	if err != nil {
+		return fmt.Errorf("failed to reject emergency access:%w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return server.ErrEmergencyStateChanged
+	}
+
+	log.Ctx(ctx).Printf("RejectEmergencyAccess success")
+	return nil
+}
+
+// ApproveExpiredEmergencyRequests - одобрить запросы всех пользователей, не отклоненные владельцем до времени
+// автоматического одобрения. Время одобрения не меняется и равно времени автоматического одобрения.
+func (d *db) ApproveExpiredEmergencyRequests(ctx context.Context, now time.Time) (int64, error) {
+	log.Ctx(ctx).Printf("ApproveExpiredEmergencyRequests, now:%v", now)
+
+	tag, err := d.pool.Exec(ctx,
+		"UPDATE emergency_contacts SET state = 'approved' WHERE state = 'requested' AND approve_time <= $1",
+		now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to approve expired emergency requests:%w", err)
+	}
+
+	log.Ctx(ctx).Printf("ApproveExpiredEmergencyRequests success, approved:%v", tag.RowsAffected())
+	return tag.RowsAffected(), nil
+}
+
+// TakeoverAccount - захватить аккаунт владельца по одобренному доступу id. Одобрение расходуется: доступ
+// возвращается в idle в той же транзакции, что и смена пароля с отключением второго фактора.
+func (d *db) TakeoverAccount(ctx context.Context, id int64, old *server.User, c *server.PasswordChange) (
+	int64, error) {
+	log.Ctx(ctx).Printf("TakeoverAccount, id:%v, owner UserId:%v", id, old.ID)
+
+	var revoked int64
+
+	err := pgx.BeginFunc(ctx, d.pool, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx,
+			"UPDATE emergency_contacts SET state = 'idle', request_time = NULL, approve_time = NULL "+
+				"WHERE id = $1 AND owner_id = $2 AND state = 'approved' AND access = 'takeover'",
+			id, old.ID)
+		if err != nil {
+			return fmt.Errorf("failed to reset emergency access:%w", err)
+		}
+
+		if tag.RowsAffected() == 0 {
+			return server.ErrEmergencyStateChanged
+		}
+
+		// Пустая сессия - отозвать все сессии владельца. Второй фактор остался у владельца, без его отключения
+		// доверенное лицо не сможет войти.
+		pc := *c
+		pc.ResetSecondFactor = true
+
+		revoked, err = changePassword(ctx, tx, old, "", &pc)
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("takeover account tx error:%w", err)
+	}
+
+	log.Ctx(ctx).Printf("TakeoverAccount success, revoked:%v", revoked)
+	return revoked, nil
+}
+
+// scanEmergencyContact - прочитать доверенное лицо, выбранное по emergencyColumns.
+func scanEmergencyContact(row pgx.Row) (server.EmergencyContact, error) {
+	var c server.EmergencyContact
+	var request, approve *time.Time
+
+	err := row.Scan(&c.ID, &c.OwnerID, &c.Owner, &c.GranteeID, &c.Grantee, &c.Access, &c.WaitDays, &c.VaultKey,
+		&c.State, &c.CreateTime, &request, &approve)
+	if err != nil {
+		return c, err //nolint:wrapcheck // wrapped by caller
+	}
+
+	if request != nil {
+		c.RequestTime = *request
+	}
+
+	if approve != nil {
+		c.ApproveTime = *approve
+	}
+
+	return c, nil
+}
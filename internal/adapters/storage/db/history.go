@@ -0,0 +1,234 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/k0st1a/gophkeeper/internal/ports/server"
+	"github.com/rs/zerolog/log"
+)
+
+// historyAccess - условие доступа пользователя $1 к прошлой версии предмета, как itemAccess для предметов.
+const historyAccess = "(h.collection_id IS NULL AND h.user_id = $1 OR h.collection_id IN (" +
+	"SELECT c.id FROM collections c JOIN org_members m ON m.org_id = c.org_id WHERE m.user_id = $1))"
+
+func (d *db) ListItemVersions(ctx context.Context, userID, itemID int64) ([]server.ItemVersion, error) {
+	log.Ctx(ctx).Printf("ListItemVersions, userID:%v, itemID:%v", userID, itemID)
+
+	rows, err := d.pool.Query(ctx,
+		"SELECT h.item_id, h.data, h.create_time, h.update_time, h.revision, h.blob_ids, "+
+			"COALESCE(h.collection_id, 0), h.deleted, h.archive_time "+
+			"FROM items_history h WHERE "+historyAccess+" AND h.item_id = $2 ORDER BY h.revision DESC",
+		userID, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("query error of list item versions:%w", err)
+	}
+	defer rows.Close()
+
+	var versions []server.ItemVersion
+
+	for rows.Next() {
+		var v server.ItemVersion
+		err := rows.Scan(
+			&v.Item.ID,
+			&v.Item.Data,
+			&v.Item.CreateTime,
+			&v.Item.UpdateTime,
+			&v.Item.Revision,
+			&v.Item.BlobIDs,
+			&v.Item.CollectionID,
+			&v.Deleted,
+			&v.ArchiveTime,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan error of list item versions:%w", err)
+		}
+		versions = append(versions, v)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("error of list item versions:%w", err)
+	}
+
+	log.Ctx(ctx).Printf("ListItemVersions success, versions:%v", len(versions))
+	return versions, nil
+}
+
+func (d *db) RestoreItemVersion(ctx context.Context, userID, itemID, revision, expected int64) (*server.Item, error) {
+	log.Ctx(ctx).Printf("RestoreItemVersion, userID:%v, itemID:%v, revision:%v, expected:%v",
+		userID, itemID, revision, expected)
+	var item server.Item
+
+	err := pgx.BeginFunc(ctx, d.pool, func(tx pgx.Tx) error {
+		seq, err := nextChangeSeq(ctx, tx, userID)
+		if err != nil {
+			return err
+		}
+
+		err = tx.QueryRow(ctx,
+			"SELECT data, create_time, blob_ids FROM items_history "+
+				"WHERE user_id = $1 AND item_id = $2 AND revision = $3 AND collection_id IS NULL",
+			userID, itemID, revision).Scan(&item.Data, &item.CreateTime, &item.BlobIDs)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return server.ErrVersionNotFound
+		}
+
+		if err != nil {
+			return fmt.Errorf("query error of get item version:%w", err)
+		}
+
+		item.ID = itemID
+		item.UpdateTime = time.Now().UTC()
+
+		var current int64
+		var old []string
+		var trashed bool
+		err = tx.QueryRow(ctx,
+			"SELECT revision, blob_ids, delete_time IS NOT NULL FROM items "+
+				"WHERE id = $1 AND user_id = $2 AND collection_id IS NULL FOR UPDATE",
+			itemID, userID).Scan(&current, &old, &trashed)
+		if errors.Is(err, pgx.ErrNoRows) {
+			if expected != 0 {
+				return server.ErrItemNotFound
+			}
+
+			return recreateItem(ctx, tx, userID, seq, &item)
+		}
+
+		if err != nil {
+			return fmt.Errorf("query error of get item revision:%w", err)
+		}
+
+		if trashed && expected != 0 {
+			return server.ErrItemNotFound
+		}
+
+		if expected != 0 && current != expected {
+			return &server.RevisionMismatchError{Revision: current}
+		}
+
+		// Восстановление версии предмета из корзины возвращает его из корзины.
+		if trashed {
+			err = deleteItemTombstone(ctx, tx, userID, itemID)
+			if err != nil {
+				return err
+			}
+		}
+
+		pruned, err := d.archiveItem(ctx, tx, userID, itemID, false)
+		if err != nil {
+			return err
+		}
+
+		err = tx.QueryRow(ctx,
+			"UPDATE items SET data = $1, update_time = $2, revision = revision + 1, change_seq = $3, blob_ids = $4, "+
+				"delete_time = NULL "+
+				"WHERE id = $5 AND user_id = $6 "+
+				"RETURNING revision",
+			item.Data, item.UpdateTime, seq, item.BlobIDs, itemID, userID).Scan(&item.Revision)
+		if err != nil {
+			return fmt.Errorf("query error of restore item:%w", err)
+		}
+
+		return deleteUnreferencedBlobs(ctx, tx, userID, append(old, pruned...))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore item version:%w", err)
+	}
+
+	log.Ctx(ctx).Printf("RestoreItemVersion success, revision:%v", item.Revision)
+	return &item, nil
+}
+
+// recreateItem - создать удаленный предмет заново с тем же идентификатором.
+// Ревизия продолжает ревизии из истории, чтобы версии предмета не пересекались.
+func recreateItem(ctx context.Context, tx pgx.Tx, userID, seq int64, item *server.Item) error {
+	err := tx.QueryRow(ctx,
+		"INSERT INTO items (id, user_id, data, create_time, update_time, revision, change_seq, blob_ids) "+
+			"SELECT $1, $2, $3, $4, $5, MAX(revision) + 1, $6, $7 FROM items_history "+
+			"WHERE user_id = $2 AND item_id = $1 "+
+			"RETURNING revision",
+		item.ID, userID, item.Data, item.CreateTime, item.UpdateTime, seq, item.BlobIDs).Scan(&item.Revision)
+	if err != nil {
+		return fmt.Errorf("query error of recreate item:%w", err)
+	}
+
+	return deleteItemTombstone(ctx, tx, userID, item.ID)
+}
+
+func (d *db) SetHistoryRetention(ctx context.Context, userID int64, versions int) error {
+	log.Ctx(ctx).Printf("SetHistoryRetention, userID:%v, versions:%v", userID, versions)
+
+	err := pgx.BeginFunc(ctx, d.pool, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx,
+			"UPDATE users SET history_retention = $1 WHERE id = $2",
+			versions, userID)
+		if err != nil {
+			return fmt.Errorf("query error of set history retention:%w", err)
+		}
+
+		pruned, err := d.pruneItemsHistory(ctx, tx, userID, 0)
+		if err != nil {
+			return err
+		}
+
+		return deleteUnreferencedBlobs(ctx, tx, userID, pruned)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set history retention:%w", err)
+	}
+
+	log.Ctx(ctx).Printf("SetHistoryRetention success")
+	return nil
+}
+
+// archiveItem - сохранить текущую версию предмета в историю перед обновлением или удалением в корзину.
+// Версия предмета в корзине уже сохранена при удалении. Для предмета коллекции userID - автор предмета.
+// Возвращает блобы версий, вытесненных из истории.
+func (d *db) archiveItem(ctx context.Context, tx pgx.Tx, userID, itemID int64, deleted bool) ([]string, error) {
+	_, err := tx.Exec(ctx,
+		"INSERT INTO items_history "+
+			"(user_id, item_id, revision, data, create_time, update_time, blob_ids, deleted, collection_id) "+
+			"SELECT user_id, id, revision, data, create_time, update_time, blob_ids, $3, collection_id FROM items "+
+			"WHERE user_id = $1 AND id = $2 AND delete_time IS NULL",
+		userID, itemID, deleted)
+	if err != nil {
+		return nil, fmt.Errorf("query error of archive item:%w", err)
+	}
+
+	return d.pruneItemsHistory(ctx, tx, userID, itemID)
+}
+
+// pruneItemsHistory - удалить версии предмета itemID (или всех предметов пользователя, если itemID 0),
+// которые не входят в количество хранимых версий. Возвращает блобы удаленных версий.
+func (d *db) pruneItemsHistory(ctx context.Context, tx pgx.Tx, userID, itemID int64) ([]string, error) {
+	rows, err := tx.Query(ctx,
+		"DELETE FROM items_history WHERE (user_id, item_id, revision) IN ("+
+			"SELECT user_id, item_id, revision FROM ("+
+			"SELECT user_id, item_id, revision, "+
+			"ROW_NUMBER() OVER (PARTITION BY item_id ORDER BY revision DESC) AS n "+
+			"FROM items_history WHERE user_id = $1 AND ($2::BIGINT = 0 OR item_id = $2)"+
+			") AS numbered "+
+			"WHERE n > (SELECT COALESCE(history_retention, $3) FROM users WHERE id = $1)"+
+			") RETURNING blob_ids",
+		userID, itemID, d.historyRetention)
+	if err != nil {
+		return nil, fmt.Errorf("query error of prune items history:%w", err)
+	}
+
+	l, err := pgx.CollectRows(rows, pgx.RowTo[[]string])
+	if err != nil {
+		return nil, fmt.Errorf("error of prune items history:%w", err)
+	}
+
+	var ids []string
+	for _, v := range l {
+		ids = append(ids, v...)
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,159 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/k0st1a/gophkeeper/internal/ports/server"
+	"github.com/rs/zerolog/log"
+)
+
+func (d *db) CheckLogin(ctx context.Context, keys []server.LoginKey) (time.Time, error) {
+	kinds := make([]string, 0, len(keys))
+	values := make([]string, 0, len(keys))
+	for _, k := range keys {
+		kinds = append(kinds, k.Kind)
+		values = append(values, k.Value)
+	}
+
+	var until *time.Time
+
+	err := d.pool.QueryRow(ctx,
+		"SELECT MAX(locked_until) FROM login_failures "+
+			"WHERE (kind, value) IN (SELECT * FROM unnest($1::TEXT[], $2::TEXT[])) AND locked_until > NOW()",
+		kinds, values).Scan(&until)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to check login:%w", err)
+	}
+
+	if until == nil {
+		return time.Time{}, nil
+	}
+
+	return *until, nil
+}
+
+func (d *db) AddLoginFailure(ctx context.Context, key server.LoginKey, window time.Duration,
+	delay func(failures int) (time.Duration, bool)) (time.Time, error) {
+	log.Ctx(ctx).Printf("AddLoginFailure, %v:%v", key.Kind, key.Value)
+	var until time.Time
+
+	err := pgx.BeginFunc(ctx, d.pool, func(tx pgx.Tx) error {
+		var failures int
+		err := tx.QueryRow(ctx,
+			"INSERT INTO login_failures AS f (kind, value, failures, last_failure_time) VALUES ($1, $2, 1, NOW()) "+
+				"ON CONFLICT (kind, value) DO UPDATE SET "+
+				"failures = CASE WHEN f.last_failure_time < NOW() - $3::INTERVAL THEN 1 ELSE f.failures + 1 END, "+
+				"last_failure_time = NOW() "+
+				"RETURNING failures",
+			key.Kind, key.Value, window).Scan(&failures)
+		if err != nil {
+			return fmt.Errorf("error of upsert login failure:%w", err)
+		}
+
+		d, locked := delay(failures)
+		if d <= 0 {
+			return nil
+		}
+
+		err = tx.QueryRow(ctx,
+			"UPDATE login_failures SET locked_until = NOW() + $3::INTERVAL WHERE kind = $1 AND value = $2 "+
+				"RETURNING locked_until",
+			key.Kind, key.Value, d).Scan(&until)
+		if err != nil {
+			return fmt.Errorf("error of update login lock:%w", err)
+		}
+
+		if !locked {
+			return nil
+		}
+
+		log.Ctx(ctx).Printf("Login locked, %v:%v, failures:%v, until:%v", key.Kind, key.Value, failures, until)
+
+		_, err = tx.Exec(ctx,
+			"INSERT INTO lockout_events (kind, value, failures, locked_until) VALUES ($1, $2, $3, $4)",
+			key.Kind, key.Value, failures, until)
+		if err != nil {
+			return fmt.Errorf("error of insert lockout event:%w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to add login failure:%w", err)
+	}
+
+	return until, nil
+}
+
+func (d *db) ResetLoginFailures(ctx context.Context, key server.LoginKey) error {
+	_, err := d.pool.Exec(ctx, "DELETE FROM login_failures WHERE kind = $1 AND value = $2", key.Kind, key.Value)
+	if err != nil {
+		return fmt.Errorf("failed to reset login failures:%w", err)
+	}
+
+	return nil
+}
+
+func (d *db) ListLockouts(ctx context.Context, all bool) ([]server.Lockout, error) {
+	log.Ctx(ctx).Printf("ListLockouts, all:%v", all)
+
+	rows, err := d.pool.Query(ctx,
+		"SELECT id, kind, value, failures, create_time, locked_until, clear_time FROM lockout_events "+
+			"WHERE $1 OR (clear_time IS NULL AND locked_until > NOW()) ORDER BY create_time DESC",
+		all)
+	if err != nil {
+		return nil, fmt.Errorf("query error of list lockouts:%w", err)
+	}
+
+	l, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (server.Lockout, error) {
+		var i server.Lockout
+		err := row.Scan(&i.ID, &i.Key.Kind, &i.Key.Value, &i.Failures, &i.CreateTime, &i.LockedUntil, &i.ClearTime)
+		return i, err //nolint:wrapcheck // wrapped below
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error of list lockouts:%w", err)
+	}
+
+	log.Ctx(ctx).Printf("ListLockouts success, lockouts:%v", len(l))
+	return l, nil
+}
+
+func (d *db) ClearLockout(ctx context.Context, id int64) error {
+	log.Ctx(ctx).Printf("ClearLockout, id:%v", id)
+
+	err := pgx.BeginFunc(ctx, d.pool, func(tx pgx.Tx) error {
+		var key server.LoginKey
+		err := tx.QueryRow(ctx,
+			"UPDATE lockout_events SET clear_time = NOW() WHERE id = $1 AND clear_time IS NULL "+
+				"RETURNING kind, value",
+			id).Scan(&key.Kind, &key.Value)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return server.ErrLockoutNotFound
+		}
+
+		if err != nil {
+			return fmt.Errorf("error of clear lockout event:%w", err)
+		}
+
+		_, err = tx.Exec(ctx, "DELETE FROM login_failures WHERE kind = $1 AND value = $2", key.Kind, key.Value)
+		if err != nil {
+			return fmt.Errorf("error of delete login failures:%w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, server.ErrLockoutNotFound) {
+			return err
+		}
+
+		return fmt.Errorf("failed to clear lockout:%w", err)
+	}
+
+	log.Ctx(ctx).Printf("ClearLockout success")
+	return nil
+}
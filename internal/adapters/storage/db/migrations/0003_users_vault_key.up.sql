@@ -0,0 +1,7 @@
+BEGIN TRANSACTION;
+
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS vault_salt BYTEA NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS vault_key  BYTEA NOT NULL DEFAULT '';
+
+COMMIT;
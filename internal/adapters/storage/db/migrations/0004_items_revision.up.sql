@@ -0,0 +1,6 @@
+BEGIN TRANSACTION;
+
+ALTER TABLE items
+    ADD COLUMN IF NOT EXISTS revision BIGINT NOT NULL DEFAULT 1;
+
+COMMIT;
@@ -0,0 +1,35 @@
+BEGIN TRANSACTION;
+
+-- Последний номер изменения предметов пользователя.
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS change_seq BIGINT NOT NULL DEFAULT 0;
+
+-- Номер изменения, которым предмет был создан или обновлен.
+ALTER TABLE items
+    ADD COLUMN IF NOT EXISTS change_seq BIGINT NOT NULL DEFAULT 0;
+
+-- Удаленные предметы, чтобы клиенты узнали об удалении из ленты изменений.
+CREATE TABLE IF NOT EXISTS items_deleted (
+    user_id    BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    item_id    BIGINT NOT NULL,
+    change_seq BIGINT NOT NULL,
+    PRIMARY KEY (user_id, item_id)
+);
+
+CREATE INDEX IF NOT EXISTS items_user_id_change_seq_idx ON items (user_id, change_seq);
+CREATE INDEX IF NOT EXISTS items_deleted_user_id_change_seq_idx ON items_deleted (user_id, change_seq);
+
+-- Пронумеровать предметы, созданные до появления ленты изменений.
+UPDATE items SET change_seq = numbered.seq
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY id) AS seq FROM items
+) AS numbered
+WHERE items.id = numbered.id;
+
+UPDATE users SET change_seq = counted.seq
+FROM (
+    SELECT user_id, COUNT(*) AS seq FROM items GROUP BY user_id
+) AS counted
+WHERE users.id = counted.user_id;
+
+COMMIT;
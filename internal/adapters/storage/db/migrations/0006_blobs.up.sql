@@ -0,0 +1,25 @@
+BEGIN TRANSACTION;
+
+CREATE TABLE IF NOT EXISTS blobs (
+    user_id     BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    id          TEXT NOT NULL,
+    chunks      BIGINT NOT NULL DEFAULT 0,
+    size        BIGINT NOT NULL DEFAULT 0,
+    complete    BOOLEAN NOT NULL DEFAULT FALSE,
+    create_time TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, id)
+);
+
+CREATE TABLE IF NOT EXISTS blob_chunks (
+    user_id     BIGINT NOT NULL,
+    blob_id     TEXT NOT NULL,
+    chunk_index BIGINT NOT NULL,
+    data        BYTEA NOT NULL,
+    PRIMARY KEY (user_id, blob_id, chunk_index),
+    FOREIGN KEY (user_id, blob_id) REFERENCES blobs (user_id, id) ON DELETE CASCADE
+);
+
+ALTER TABLE items
+    ADD COLUMN IF NOT EXISTS blob_ids TEXT[] NOT NULL DEFAULT '{}';
+
+COMMIT;
@@ -0,0 +1,21 @@
+BEGIN TRANSACTION;
+
+-- Сколько прошлых версий каждого предмета хранить, NULL - значение по умолчанию из конфигурации сервера.
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS history_retention INTEGER;
+
+-- Прошлые версии предметов, сохраняются при каждом обновлении и удалении предмета.
+CREATE TABLE IF NOT EXISTS items_history (
+    user_id      BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    item_id      BIGINT NOT NULL,
+    revision     BIGINT NOT NULL,
+    data         BYTEA NOT NULL,
+    create_time  TIMESTAMP WITH TIME ZONE NOT NULL,
+    update_time  TIMESTAMP WITH TIME ZONE NOT NULL,
+    blob_ids     TEXT[] NOT NULL DEFAULT '{}',
+    deleted      BOOLEAN NOT NULL DEFAULT FALSE,
+    archive_time TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, item_id, revision)
+);
+
+COMMIT;
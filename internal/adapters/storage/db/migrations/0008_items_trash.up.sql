@@ -0,0 +1,10 @@
+BEGIN TRANSACTION;
+
+-- Время удаления предмета в корзину, NULL - предмет не удален.
+-- Предметы в корзине окончательно удаляются после срока хранения корзины.
+ALTER TABLE items
+    ADD COLUMN IF NOT EXISTS delete_time TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS items_delete_time_idx ON items (delete_time) WHERE delete_time IS NOT NULL;
+
+COMMIT;
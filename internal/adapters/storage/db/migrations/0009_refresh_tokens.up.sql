@@ -0,0 +1,18 @@
+BEGIN TRANSACTION;
+
+-- Refresh токены пользователей. Хранится только хэш токена, сам токен знает лишь клиент.
+-- При обновлении токен помечается использованным (rotate_time) и заменяется новым из того же семейства (family_id).
+-- Повторное использование уже замененного токена означает его утечку, в этом случае отзывается все семейство.
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    token_hash  BYTEA PRIMARY KEY,
+    family_id   UUID NOT NULL,
+    user_id     BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    create_time TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    expire_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    rotate_time TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS refresh_tokens_user_id_idx ON refresh_tokens (user_id);
+CREATE INDEX IF NOT EXISTS refresh_tokens_family_id_idx ON refresh_tokens (family_id);
+
+COMMIT;
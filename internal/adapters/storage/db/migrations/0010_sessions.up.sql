@@ -0,0 +1,30 @@
+BEGIN TRANSACTION;
+
+-- Сессии пользователей. Сессия создается при логине, ее идентификатор передается в токене (jti)
+-- и является семейством refresh токенов сессии. Отзыв сессии удаляет ее вместе с refresh токенами,
+-- после чего токены сессии перестают приниматься сервером.
+CREATE TABLE IF NOT EXISTS sessions (
+    id             UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id        BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    device         TEXT NOT NULL DEFAULT '',
+    ip             TEXT NOT NULL DEFAULT '',
+    create_time    TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_seen_time TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    expire_time    TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS sessions_user_id_idx ON sessions (user_id);
+
+-- Refresh токены, выданные до появления сессий, не принадлежат ни одной сессии, пользователям нужно залогиниться заново.
+DELETE FROM refresh_tokens;
+
+DROP INDEX IF EXISTS refresh_tokens_family_id_idx;
+
+ALTER TABLE refresh_tokens RENAME COLUMN family_id TO session_id;
+
+ALTER TABLE refresh_tokens
+    ADD CONSTRAINT refresh_tokens_session_id_fkey FOREIGN KEY (session_id) REFERENCES sessions (id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS refresh_tokens_session_id_idx ON refresh_tokens (session_id);
+
+COMMIT;
@@ -0,0 +1,27 @@
+BEGIN TRANSACTION;
+
+-- Неудачные попытки входа подряд по логину (kind = 'login') и по IP адресу (kind = 'ip').
+-- До locked_until попытки входа отклоняются без проверки пароля.
+CREATE TABLE IF NOT EXISTS login_failures (
+    kind              TEXT NOT NULL,
+    value             TEXT NOT NULL,
+    failures          INTEGER NOT NULL,
+    last_failure_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    locked_until      TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (kind, value)
+);
+
+-- Блокировки входа, для просмотра и снятия администратором.
+CREATE TABLE IF NOT EXISTS lockout_events (
+    id           BIGSERIAL PRIMARY KEY,
+    kind         TEXT NOT NULL,
+    value        TEXT NOT NULL,
+    failures     INTEGER NOT NULL,
+    create_time  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    locked_until TIMESTAMP WITH TIME ZONE NOT NULL,
+    clear_time   TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS lockout_events_create_time_idx ON lockout_events (create_time);
+
+COMMIT;
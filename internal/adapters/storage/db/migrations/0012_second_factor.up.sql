@@ -0,0 +1,32 @@
+BEGIN TRANSACTION;
+
+-- Второй фактор входа (TOTP). Подключается после подтверждения первым кодом (enabled),
+-- last_step - последний принятый интервал TOTP, коды этого и предыдущих интервалов повторно не принимаются.
+CREATE TABLE IF NOT EXISTS second_factors (
+    user_id     BIGINT PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    secret      TEXT NOT NULL,
+    enabled     BOOLEAN NOT NULL DEFAULT FALSE,
+    last_step   BIGINT NOT NULL DEFAULT 0,
+    create_time TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Одноразовые резервные коды второго фактора, хранятся только хэши.
+CREATE TABLE IF NOT EXISTS backup_codes (
+    user_id   BIGINT NOT NULL REFERENCES second_factors (user_id) ON DELETE CASCADE,
+    hash      BYTEA NOT NULL,
+    used_time TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (user_id, hash)
+);
+
+-- Входы, ожидающие подтверждения вторым фактором. Создаются после проверки пароля, хранятся только хэши токенов.
+CREATE TABLE IF NOT EXISTS login_challenges (
+    hash        BYTEA PRIMARY KEY,
+    user_id     BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    device      TEXT NOT NULL DEFAULT '',
+    attempts    INTEGER NOT NULL DEFAULT 0,
+    expire_time TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS login_challenges_user_id_idx ON login_challenges (user_id);
+
+COMMIT;
@@ -0,0 +1,25 @@
+BEGIN TRANSACTION;
+
+-- Соль и верификатор SRP-6a пароля пользователя. У пользователей, зарегистрированных до SRP, они пустые,
+-- а в password лежит bcrypt хэш пароля. После первого входа по паролю клиент сохраняет верификатор,
+-- и bcrypt хэш стирается.
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS srp_salt     BYTEA NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS srp_verifier BYTEA NOT NULL DEFAULT '',
+    ALTER COLUMN password SET DEFAULT '';
+
+-- Начатые входы по SRP, secret - закрытое значение сервера b, public_a и public_b - открытые значения A и B.
+-- user_id пустой для незарегистрированного логина, такой вход никогда не завершится успешно.
+CREATE TABLE IF NOT EXISTS srp_logins (
+    hash        BYTEA PRIMARY KEY,
+    login       TEXT NOT NULL,
+    user_id     BIGINT REFERENCES users (id) ON DELETE CASCADE,
+    secret      BYTEA NOT NULL,
+    public_a    BYTEA NOT NULL,
+    public_b    BYTEA NOT NULL,
+    expire_time TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS srp_logins_expire_time_idx ON srp_logins (expire_time);
+
+COMMIT;
@@ -0,0 +1,26 @@
+BEGIN TRANSACTION;
+
+-- Пара ключей X25519 пользователя для получения предметов от других пользователей. Открытый ключ публикуется
+-- сервером, закрытый ключ зашифрован ключом хранилища. У пользователей, не входивших после появления
+-- совместного доступа, они пустые.
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS public_key  BYTEA NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS private_key BYTEA NOT NULL DEFAULT '';
+
+-- Копии предметов, зашифрованные открытым ключом получателя. Копия удаляется вместе с предметом,
+-- а для предметов в корзине не показывается получателю.
+CREATE TABLE IF NOT EXISTS shares (
+    id           BIGSERIAL PRIMARY KEY,
+    item_id      BIGINT NOT NULL REFERENCES items (id) ON DELETE CASCADE,
+    owner_id     BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    recipient_id BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    data         BYTEA NOT NULL,
+    create_time  TIMESTAMP WITH TIME ZONE NOT NULL,
+    update_time  TIMESTAMP WITH TIME ZONE NOT NULL,
+    UNIQUE (item_id, recipient_id)
+);
+
+CREATE INDEX IF NOT EXISTS shares_owner_id_idx ON shares (owner_id);
+CREATE INDEX IF NOT EXISTS shares_recipient_id_idx ON shares (recipient_id);
+
+COMMIT;
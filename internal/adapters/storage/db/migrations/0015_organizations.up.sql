@@ -0,0 +1,64 @@
+BEGIN TRANSACTION;
+
+CREATE TABLE IF NOT EXISTS organizations (
+    id          BIGSERIAL PRIMARY KEY,
+    name        TEXT NOT NULL,
+    create_time TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Участники организации. Ключ организации зашифрован открытым ключом участника, сервер его не знает.
+CREATE TABLE IF NOT EXISTS org_members (
+    org_id      BIGINT NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+    user_id     BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    role        TEXT NOT NULL CHECK (role IN ('owner', 'admin', 'member', 'read-only')),
+    org_key     BYTEA NOT NULL,
+    create_time TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (org_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS org_members_user_id_idx ON org_members (user_id);
+
+-- Приглашения в организацию, ключ организации уже зашифрован открытым ключом приглашенного.
+CREATE TABLE IF NOT EXISTS org_invitations (
+    id          BIGSERIAL PRIMARY KEY,
+    org_id      BIGINT NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+    user_id     BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    inviter_id  BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    role        TEXT NOT NULL CHECK (role IN ('owner', 'admin', 'member', 'read-only')),
+    org_key     BYTEA NOT NULL,
+    create_time TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (org_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS org_invitations_user_id_idx ON org_invitations (user_id);
+
+-- Коллекции предметов организации. Название и ключ коллекции зашифрованы ключом организации.
+CREATE TABLE IF NOT EXISTS collections (
+    id          BIGSERIAL PRIMARY KEY,
+    org_id      BIGINT NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+    name        BYTEA NOT NULL,
+    key         BYTEA NOT NULL,
+    create_time TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS collections_org_id_idx ON collections (org_id);
+
+-- Коллекция предмета, NULL - личный предмет пользователя user_id. У предмета коллекции user_id - автор предмета.
+ALTER TABLE items
+    ADD COLUMN IF NOT EXISTS collection_id BIGINT REFERENCES collections (id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS items_collection_id_idx ON items (collection_id) WHERE collection_id IS NOT NULL;
+
+-- Лента изменений предметов коллекций для каждого участника организации: номер изменения берется из
+-- users.change_seq участника, поэтому предметы коллекций синхронизируются вместе с личными предметами.
+CREATE TABLE IF NOT EXISTS collection_changes (
+    user_id    BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    item_id    BIGINT NOT NULL,
+    change_seq BIGINT NOT NULL,
+    deleted    BOOLEAN NOT NULL DEFAULT FALSE,
+    PRIMARY KEY (user_id, item_id)
+);
+
+CREATE INDEX IF NOT EXISTS collection_changes_user_id_change_seq_idx ON collection_changes (user_id, change_seq);
+
+COMMIT;
@@ -0,0 +1,11 @@
+BEGIN TRANSACTION;
+
+-- Ключ хранилища пользователя, зашифрованный ключом восстановления, и хэш значения, которым клиент доказывает
+-- знание ключа восстановления. Сам ключ восстановления сервер не получает. Пустые, если ключ восстановления
+-- не создан.
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS recovery_salt BYTEA NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS recovery_key  BYTEA NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS recovery_hash BYTEA NOT NULL DEFAULT '';
+
+COMMIT;
@@ -0,0 +1,24 @@
+BEGIN TRANSACTION;
+
+-- Доверенные лица пользователей для экстренного доступа. vault_key - ключ хранилища владельца, зашифрованный
+-- открытым ключом доверенного лица, выдается ему только в состоянии approved. Запрос доступа (requested)
+-- одобряется автоматически в approve_time, если владелец не отклонил его раньше.
+CREATE TABLE IF NOT EXISTS emergency_contacts (
+    id           BIGSERIAL PRIMARY KEY,
+    owner_id     BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    grantee_id   BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    access       TEXT NOT NULL,
+    wait_days    INTEGER NOT NULL,
+    vault_key    BYTEA NOT NULL,
+    state        TEXT NOT NULL DEFAULT 'idle',
+    create_time  TIMESTAMP WITH TIME ZONE NOT NULL,
+    request_time TIMESTAMP WITH TIME ZONE,
+    approve_time TIMESTAMP WITH TIME ZONE,
+    UNIQUE (owner_id, grantee_id)
+);
+
+CREATE INDEX IF NOT EXISTS emergency_contacts_grantee_id_idx ON emergency_contacts (grantee_id);
+CREATE INDEX IF NOT EXISTS emergency_contacts_approve_time_idx ON emergency_contacts (approve_time)
+    WHERE state = 'requested';
+
+COMMIT;
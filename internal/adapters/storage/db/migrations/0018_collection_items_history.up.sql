@@ -0,0 +1,8 @@
+BEGIN TRANSACTION;
+
+-- Коллекция прошлой версии предмета, NULL - версия личного предмета. Версии предметов коллекций видят
+-- все участники организации, у версии, как и у предмета, user_id - автор предмета.
+ALTER TABLE items_history
+    ADD COLUMN IF NOT EXISTS collection_id BIGINT REFERENCES collections (id) ON DELETE CASCADE;
+
+COMMIT;
@@ -0,0 +1,568 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/k0st1a/gophkeeper/internal/ports/server"
+	"github.com/rs/zerolog/log"
+)
+
+// itemAccess - условие доступа пользователя $1 к предмету: личный предмет пользователя или предмет коллекции
+// организации, в которой пользователь состоит.
+const itemAccess = "(items.collection_id IS NULL AND items.user_id = $1 OR items.collection_id IN (" +
+	"SELECT c.id FROM collections c JOIN org_members m ON m.org_id = c.org_id WHERE m.user_id = $1))"
+
+func (d *db) CreateOrg(ctx context.Context, org *server.Org, owner *server.OrgMember) (int64, error) {
+	log.Ctx(ctx).Printf("CreateOrg, userID:%v", owner.UserID)
+	var id int64
+
+	err := pgx.BeginFunc(ctx, d.pool, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx,
+			"INSERT INTO organizations (name) VALUES($1) RETURNING id",
+			org.Name).Scan(&id)
+		if err != nil {
+			return fmt.Errorf("query error of create org:%w", err)
+		}
+
+		_, err = tx.Exec(ctx,
+			"INSERT INTO org_members (org_id, user_id, role, org_key) VALUES($1, $2, $3, $4)",
+			id, owner.UserID, server.RoleOwner, owner.OrgKey)
+		if err != nil {
+			return fmt.Errorf("query error of create org owner:%w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to create org:%w", err)
+	}
+
+	log.Ctx(ctx).Printf("CreateOrg success, id:%v", id)
+	return id, nil
+}
+
+// DeleteOrg - удалить организацию. Предметы коллекций удаляются каскадно, а участникам они отмечаются
+// удаленными в ленте изменений.
+func (d *db) DeleteOrg(ctx context.Context, orgID int64) error {
+	log.Ctx(ctx).Printf("DeleteOrg, orgID:%v", orgID)
+
+	err := pgx.BeginFunc(ctx, d.pool, func(tx pgx.Tx) error {
+		members, err := queryIDs(ctx, tx,
+			"SELECT user_id FROM org_members WHERE org_id = $1 ORDER BY user_id",
+			orgID)
+		if err != nil {
+			return err
+		}
+
+		items, err := queryIDs(ctx, tx,
+			"SELECT i.id FROM items i JOIN collections c ON c.id = i.collection_id WHERE c.org_id = $1",
+			orgID)
+		if err != nil {
+			return err
+		}
+
+		err = touchCollectionItems(ctx, tx, members, items, true)
+		if err != nil {
+			return err
+		}
+
+		tag, err := tx.Exec(ctx, "DELETE FROM organizations WHERE id = $1", orgID)
+		if err != nil {
+			return fmt.Errorf("query error of delete org:%w", err)
+		}
+
+		if tag.RowsAffected() == 0 {
+			return server.ErrOrgNotFound
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete org:%w", err)
+	}
+
+	log.Ctx(ctx).Printf("DeleteOrg success")
+	return nil
+}
+
+func (d *db) ListOrgs(ctx context.Context, userID int64) ([]server.OrgMember, error) {
+	log.Ctx(ctx).Printf("ListOrgs, userID:%v", userID)
+
+	return d.listMembers(ctx, "WHERE m.user_id = $1 ORDER BY o.name", userID)
+}
+
+func (d *db) ListMembers(ctx context.Context, orgID int64) ([]server.OrgMember, error) {
+	log.Ctx(ctx).Printf("ListMembers, orgID:%v", orgID)
+
+	return d.listMembers(ctx, "WHERE m.org_id = $1 ORDER BY u.login", orgID)
+}
+
+// listMembers - получить участников организаций с логинами и названиями организаций по условию where.
+func (d *db) listMembers(ctx context.Context, where string, id int64) ([]server.OrgMember, error) {
+	rows, err := d.pool.Query(ctx,
+		"SELECT m.org_id, o.name, m.user_id, u.login, m.role, m.org_key, m.create_time "+
+			"FROM org_members m "+
+			"JOIN organizations o ON o.id = m.org_id "+
+			"JOIN users u ON u.id = m.user_id "+
+			where,
+		id)
+	if err != nil {
+		return nil, fmt.Errorf("query error of list org members:%w", err)
+	}
+
+	members, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (server.OrgMember, error) {
+		var m server.OrgMember
+		err := row.Scan(&m.OrgID, &m.OrgName, &m.UserID, &m.Login, &m.Role, &m.OrgKey, &m.CreateTime)
+		return m, err //nolint:wrapcheck // wrapped below
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error of list org members:%w", err)
+	}
+
+	log.Ctx(ctx).Printf("List org members success, members:%v", len(members))
+	return members, nil
+}
+
+func (d *db) GetRole(ctx context.Context, orgID, userID int64) (server.Role, error) {
+	log.Ctx(ctx).Printf("GetRole, orgID:%v, userID:%v", orgID, userID)
+	var role server.Role
+
+	err := d.pool.QueryRow(ctx,
+		"SELECT role FROM org_members WHERE org_id = $1 AND user_id = $2",
+		orgID, userID).Scan(&role)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", server.ErrNotOrgMember
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("failed to get role:%w", err)
+	}
+
+	return role, nil
+}
+
+func (d *db) SetMemberRole(ctx context.Context, orgID, userID int64, role server.Role) error {
+	log.Ctx(ctx).Printf("SetMemberRole, orgID:%v, userID:%v, role:%v", orgID, userID, role)
+
+	err := pgx.BeginFunc(ctx, d.pool, func(tx pgx.Tx) error {
+		if role != server.RoleOwner {
+			err := checkNotLastOwner(ctx, tx, orgID, userID)
+			if err != nil {
+				return err
+			}
+		}
+
+		tag, err := tx.Exec(ctx,
+			"UPDATE org_members SET role = $3 WHERE org_id = $1 AND user_id = $2",
+			orgID, userID, role)
+		if err != nil {
+			return fmt.Errorf("query error of set member role:%w", err)
+		}
+
+		if tag.RowsAffected() == 0 {
+			return server.ErrNotOrgMember
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set member role:%w", err)
+	}
+
+	log.Ctx(ctx).Printf("SetMemberRole success")
+	return nil
+}
+
+// DeleteMember - исключить участника. Ключ организации у него остается, поэтому для защиты предметов
+// от бывшего участника их нужно перешифровать новым ключом.
+func (d *db) DeleteMember(ctx context.Context, orgID, userID int64) error {
+	log.Ctx(ctx).Printf("DeleteMember, orgID:%v, userID:%v", orgID, userID)
+
+	err := pgx.BeginFunc(ctx, d.pool, func(tx pgx.Tx) error {
+		err := checkNotLastOwner(ctx, tx, orgID, userID)
+		if err != nil {
+			return err
+		}
+
+		tag, err := tx.Exec(ctx,
+			"DELETE FROM org_members WHERE org_id = $1 AND user_id = $2",
+			orgID, userID)
+		if err != nil {
+			return fmt.Errorf("query error of delete member:%w", err)
+		}
+
+		if tag.RowsAffected() == 0 {
+			return server.ErrNotOrgMember
+		}
+
+		items, err := queryIDs(ctx, tx,
+			"SELECT i.id FROM items i JOIN collections c ON c.id = i.collection_id WHERE c.org_id = $1",
+			orgID)
+		if err != nil {
+			return err
+		}
+
+		return touchCollectionItems(ctx, tx, []int64{userID}, items, true)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete member:%w", err)
+	}
+
+	log.Ctx(ctx).Printf("DeleteMember success")
+	return nil
+}
+
+func (d *db) CreateInvitation(ctx context.Context, inv *server.Invitation) (int64, error) {
+	log.Ctx(ctx).Printf("CreateInvitation, orgID:%v, userID:%v, role:%v", inv.OrgID, inv.UserID, inv.Role)
+	var id int64
+
+	err := d.pool.QueryRow(ctx,
+		"INSERT INTO org_invitations (org_id, user_id, inviter_id, role, org_key) "+
+			"SELECT $1, $2, $3, $4, $5 "+
+			"WHERE NOT EXISTS (SELECT 1 FROM org_members WHERE org_id = $1 AND user_id = $2) "+
+			"ON CONFLICT (org_id, user_id) DO UPDATE SET inviter_id = EXCLUDED.inviter_id, role = EXCLUDED.role, "+
+			"org_key = EXCLUDED.org_key, create_time = NOW() "+
+			"RETURNING id",
+		inv.OrgID, inv.UserID, inv.InviterID, inv.Role, inv.OrgKey).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, server.ErrAlreadyOrgMember
+	}
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to create invitation:%w", err)
+	}
+
+	log.Ctx(ctx).Printf("CreateInvitation success, id:%v", id)
+	return id, nil
+}
+
+func (d *db) ListInvitations(ctx context.Context, userID int64) ([]server.Invitation, error) {
+	log.Ctx(ctx).Printf("ListInvitations, userID:%v", userID)
+
+	rows, err := d.pool.Query(ctx,
+		"SELECT inv.id, inv.org_id, o.name, inv.user_id, inv.inviter_id, u.login, inv.role, inv.org_key, "+
+			"inv.create_time "+
+			"FROM org_invitations inv "+
+			"JOIN organizations o ON o.id = inv.org_id "+
+			"JOIN users u ON u.id = inv.inviter_id "+
+			"WHERE inv.user_id = $1 ORDER BY inv.create_time DESC",
+		userID)
+	if err != nil {
+		return nil, fmt.Errorf("query error of list invitations:%w", err)
+	}
+
+	invitations, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (server.Invitation, error) {
+		var i server.Invitation
+		err := row.Scan(&i.ID, &i.OrgID, &i.OrgName, &i.UserID, &i.InviterID, &i.Inviter, &i.Role, &i.OrgKey,
+			&i.CreateTime)
+		return i, err //nolint:wrapcheck // wrapped below
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error of list invitations:%w", err)
+	}
+
+	log.Ctx(ctx).Printf("ListInvitations success, invitations:%v", len(invitations))
+	return invitations, nil
+}
+
+// AcceptInvitation - принять приглашение: пользователь становится участником с ролью из приглашения,
+// а предметы коллекций организации попадают в его ленту изменений.
+func (d *db) AcceptInvitation(ctx context.Context, userID, invitationID int64) error {
+	log.Ctx(ctx).Printf("AcceptInvitation, userID:%v, id:%v", userID, invitationID)
+
+	err := pgx.BeginFunc(ctx, d.pool, func(tx pgx.Tx) error {
+		var orgID int64
+		err := tx.QueryRow(ctx,
+			"WITH inv AS ("+
+				"DELETE FROM org_invitations WHERE id = $1 AND user_id = $2 RETURNING org_id, user_id, role, org_key"+
+				") "+
+				"INSERT INTO org_members (org_id, user_id, role, org_key) SELECT org_id, user_id, role, org_key FROM inv "+
+				"ON CONFLICT DO NOTHING "+
+				"RETURNING org_id",
+			invitationID, userID).Scan(&orgID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return server.ErrInvitationNotFound
+		}
+
+		if err != nil {
+			return fmt.Errorf("query error of accept invitation:%w", err)
+		}
+
+		items, err := queryIDs(ctx, tx,
+			"SELECT i.id FROM items i JOIN collections c ON c.id = i.collection_id "+
+				"WHERE c.org_id = $1 AND i.delete_time IS NULL",
+			orgID)
+		if err != nil {
+			return err
+		}
+
+		return touchCollectionItems(ctx, tx, []int64{userID}, items, false)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to accept invitation:%w", err)
+	}
+
+	log.Ctx(ctx).Printf("AcceptInvitation success")
+	return nil
+}
+
+func (d *db) DeleteInvitation(ctx context.Context, userID, invitationID int64) error {
+	log.Ctx(ctx).Printf("DeleteInvitation, userID:%v, id:%v", userID, invitationID)
+
+	tag, err := d.pool.Exec(ctx,
+		"DELETE FROM org_invitations WHERE id = $1 AND user_id = $2",
+		invitationID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete invitation:%w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return server.ErrInvitationNotFound
+	}
+
+	log.Ctx(ctx).Printf("DeleteInvitation success")
+	return nil
+}
+
+func (d *db) CreateCollection(ctx context.Context, c *server.Collection) (int64, error) {
+	log.Ctx(ctx).Printf("CreateCollection, orgID:%v", c.OrgID)
+	var id int64
+
+	err := d.pool.QueryRow(ctx,
+		"INSERT INTO collections (org_id, name, key) VALUES($1, $2, $3) RETURNING id",
+		c.OrgID, c.Name, c.Key).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create collection:%w", err)
+	}
+
+	log.Ctx(ctx).Printf("CreateCollection success, id:%v", id)
+	return id, nil
+}
+
+func (d *db) GetCollection(ctx context.Context, collectionID int64) (*server.Collection, error) {
+	log.Ctx(ctx).Printf("GetCollection, id:%v", collectionID)
+	var c server.Collection
+
+	err := d.pool.QueryRow(ctx,
+		"SELECT id, org_id, name, key, create_time FROM collections WHERE id = $1",
+		collectionID).Scan(&c.ID, &c.OrgID, &c.Name, &c.Key, &c.CreateTime)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, server.ErrCollectionNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get collection:%w", err)
+	}
+
+	return &c, nil
+}
+
+func (d *db) ListCollections(ctx context.Context, userID int64) ([]server.Collection, error) {
+	log.Ctx(ctx).Printf("ListCollections, userID:%v", userID)
+
+	rows, err := d.pool.Query(ctx,
+		"SELECT c.id, c.org_id, c.name, c.key, c.create_time FROM collections c "+
+			"JOIN org_members m ON m.org_id = c.org_id "+
+			"WHERE m.user_id = $1 ORDER BY c.id",
+		userID)
+	if err != nil {
+		return nil, fmt.Errorf("query error of list collections:%w", err)
+	}
+
+	collections, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (server.Collection, error) {
+		var c server.Collection
+		err := row.Scan(&c.ID, &c.OrgID, &c.Name, &c.Key, &c.CreateTime)
+		return c, err //nolint:wrapcheck // wrapped below
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error of list collections:%w", err)
+	}
+
+	log.Ctx(ctx).Printf("ListCollections success, collections:%v", len(collections))
+	return collections, nil
+}
+
+// DeleteCollection - удалить коллекцию. Предметы коллекции удаляются каскадно, а участникам организации
+// они отмечаются удаленными в ленте изменений.
+func (d *db) DeleteCollection(ctx context.Context, collectionID int64) error {
+	log.Ctx(ctx).Printf("DeleteCollection, id:%v", collectionID)
+
+	err := pgx.BeginFunc(ctx, d.pool, func(tx pgx.Tx) error {
+		members, err := collectionMembers(ctx, tx, collectionID)
+		if err != nil {
+			return err
+		}
+
+		items, err := queryIDs(ctx, tx,
+			"SELECT id FROM items WHERE collection_id = $1",
+			collectionID)
+		if err != nil {
+			return err
+		}
+
+		err = touchCollectionItems(ctx, tx, members, items, true)
+		if err != nil {
+			return err
+		}
+
+		tag, err := tx.Exec(ctx, "DELETE FROM collections WHERE id = $1", collectionID)
+		if err != nil {
+			return fmt.Errorf("query error of delete collection:%w", err)
+		}
+
+		if tag.RowsAffected() == 0 {
+			return server.ErrCollectionNotFound
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete collection:%w", err)
+	}
+
+	log.Ctx(ctx).Printf("DeleteCollection success")
+	return nil
+}
+
+func (d *db) ListCollectionMembers(ctx context.Context, collectionID int64) ([]int64, error) {
+	log.Ctx(ctx).Printf("ListCollectionMembers, id:%v", collectionID)
+
+	return collectionMembers(ctx, d.pool, collectionID)
+}
+
+// querier - пул соединений или транзакция.
+type querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+// collectionMembers - участники организации коллекции по возрастанию идентификатора.
+func collectionMembers(ctx context.Context, q querier, collectionID int64) ([]int64, error) {
+	return queryIDs(ctx, q,
+		"SELECT m.user_id FROM org_members m JOIN collections c ON c.org_id = m.org_id "+
+			"WHERE c.id = $1 ORDER BY m.user_id",
+		collectionID)
+}
+
+// queryIDs - получить идентификаторы запросом sql.
+func queryIDs(ctx context.Context, q querier, sql string, args ...any) ([]int64, error) {
+	rows, err := q.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query error of list ids:%w", err)
+	}
+
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[int64])
+	if err != nil {
+		return nil, fmt.Errorf("error of list ids:%w", err)
+	}
+
+	return ids, nil
+}
+
+// checkNotLastOwner - проверить, что без владельца userID у организации останется другой владелец. Строки
+// владельцев блокируются до конца транзакции tx, поэтому два владельца не могут одновременно покинуть организацию
+// или снять друг с друга роль владельца.
+func checkNotLastOwner(ctx context.Context, tx pgx.Tx, orgID, userID int64) error {
+	owners, err := queryIDs(ctx, tx,
+		"SELECT user_id FROM org_members WHERE org_id = $1 AND role = $2 FOR UPDATE",
+		orgID, server.RoleOwner)
+	if err != nil {
+		return err
+	}
+
+	if slices.Contains(owners, userID) && len(owners) == 1 {
+		return server.ErrLastOwner
+	}
+
+	return nil
+}
+
+// checkCollectionWrite - проверить, что пользователь может изменять предметы коллекции. Строка участника
+// блокируется до конца транзакции tx, поэтому роль не изменится до записи предмета.
+func checkCollectionWrite(ctx context.Context, tx pgx.Tx, userID, collectionID int64, blobIDs []string) error {
+	if len(blobIDs) != 0 {
+		return server.ErrCollectionItemBlobs
+	}
+
+	var role server.Role
+	err := tx.QueryRow(ctx,
+		"SELECT m.role FROM collections c JOIN org_members m ON m.org_id = c.org_id "+
+			"WHERE c.id = $1 AND m.user_id = $2 FOR SHARE OF m",
+		collectionID, userID).Scan(&role)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return server.ErrCollectionNotFound
+	}
+
+	if err != nil {
+		return fmt.Errorf("query error of get collection role:%w", err)
+	}
+
+	if !canWriteItems(role) {
+		return server.ErrItemReadOnly
+	}
+
+	return nil
+}
+
+// canWriteItems - может ли участник с ролью r изменять предметы коллекций.
+func canWriteItems(r server.Role) bool {
+	return r == server.RoleOwner || r == server.RoleAdmin || r == server.RoleMember
+}
+
+// updateCollectionItem - обновить предмет коллекции, текущая версия уже сохранена в историю.
+func updateCollectionItem(ctx context.Context, tx pgx.Tx, collectionID int64, item *server.Item,
+	revision *int64) error {
+	err := tx.QueryRow(ctx,
+		"UPDATE items SET data = $1, update_time = $2, revision = revision + 1 WHERE id = $3 RETURNING revision",
+		item.Data, item.UpdateTime, item.ID).Scan(revision)
+	if err != nil {
+		return fmt.Errorf("query error of update collection item:%w", err)
+	}
+
+	return touchCollectionItem(ctx, tx, collectionID, item.ID, false)
+}
+
+// touchCollectionItem - отметить изменение или удаление предмета коллекции в ленте изменений
+// всех участников организации.
+func touchCollectionItem(ctx context.Context, tx pgx.Tx, collectionID, itemID int64, deleted bool) error {
+	members, err := collectionMembers(ctx, tx, collectionID)
+	if err != nil {
+		return err
+	}
+
+	return touchCollectionItems(ctx, tx, members, []int64{itemID}, deleted)
+}
+
+// touchCollectionItems - отметить изменение или удаление предметов itemIDs в ленте изменений пользователей userIDs.
+// Каждый пользователь получает один новый номер изменения на все предметы.
+func touchCollectionItems(ctx context.Context, tx pgx.Tx, userIDs, itemIDs []int64, deleted bool) error {
+	if len(userIDs) == 0 || len(itemIDs) == 0 {
+		return nil
+	}
+
+	// Строки пользователей блокируются по возрастанию идентификатора, чтобы параллельные изменения
+	// предметов одной организации не взаимоблокировались.
+	_, err := tx.Exec(ctx,
+		"SELECT id FROM users WHERE id = ANY($1) ORDER BY id FOR UPDATE",
+		userIDs)
+	if err != nil {
+		return fmt.Errorf("query error of lock users:%w", err)
+	}
+
+	_, err = tx.Exec(ctx,
+		"WITH seqs AS ("+
+			"UPDATE users SET change_seq = change_seq + 1 WHERE id = ANY($1) RETURNING id, change_seq"+
+			") "+
+			"INSERT INTO collection_changes (user_id, item_id, change_seq, deleted) "+
+			"SELECT s.id, i.id, s.change_seq, $3 FROM seqs s CROSS JOIN UNNEST($2::BIGINT[]) AS i(id) "+
+			"ON CONFLICT (user_id, item_id) DO UPDATE SET change_seq = EXCLUDED.change_seq, deleted = EXCLUDED.deleted",
+		userIDs, itemIDs, deleted)
+	if err != nil {
+		return fmt.Errorf("query error of touch collection items:%w", err)
+	}
+
+	return nil
+}
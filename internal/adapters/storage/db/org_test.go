@@ -0,0 +1,47 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/k0st1a/gophkeeper/internal/ports/server"
+)
+
+func TestCanWriteItems(t *testing.T) {
+	tests := []struct {
+		name string
+		role server.Role
+		want bool
+	}{
+		{
+			name: "Check owner writes items",
+			role: server.RoleOwner,
+			want: true,
+		},
+		{
+			name: "Check admin writes items",
+			role: server.RoleAdmin,
+			want: true,
+		},
+		{
+			name: "Check member writes items",
+			role: server.RoleMember,
+			want: true,
+		},
+		{
+			name: "Check read-only can not write items",
+			role: server.RoleReadOnly,
+		},
+		{
+			name: "Check unknown role can not write items",
+			role: "unknown",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.want, canWriteItems(test.role))
+		})
+	}
+}
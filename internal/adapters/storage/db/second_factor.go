@@ -0,0 +1,213 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/k0st1a/gophkeeper/internal/ports/server"
+	"github.com/rs/zerolog/log"
+)
+
+func (d *db) GetSecondFactor(ctx context.Context, userID int64) (*server.SecondFactor, error) {
+	var sf server.SecondFactor
+
+	err := d.pool.QueryRow(ctx,
+		"SELECT secret, last_step, enabled FROM second_factors WHERE user_id = $1",
+		userID).Scan(&sf.Secret, &sf.LastStep, &sf.Enabled)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, server.ErrSecondFactorNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get second factor:%w", err)
+	}
+
+	return &sf, nil
+}
+
+func (d *db) EnrollSecondFactor(ctx context.Context, userID int64, secret string, backupCodes [][]byte) error {
+	log.Ctx(ctx).Printf("EnrollSecondFactor, userID:%v", userID)
+
+	err := pgx.BeginFunc(ctx, d.pool, func(tx pgx.Tx) error {
+		var id int64
+		err := tx.QueryRow(ctx,
+			"INSERT INTO second_factors AS f (user_id, secret) VALUES ($1, $2) "+
+				"ON CONFLICT (user_id) DO UPDATE SET secret = $2, last_step = 0, create_time = NOW() "+
+				"WHERE NOT f.enabled "+
+				"RETURNING user_id",
+			userID, secret).Scan(&id)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return server.ErrSecondFactorEnabled
+		}
+
+		if err != nil {
+			return fmt.Errorf("error of upsert second factor:%w", err)
+		}
+
+		_, err = tx.Exec(ctx, "DELETE FROM backup_codes WHERE user_id = $1", userID)
+		if err != nil {
+			return fmt.Errorf("error of delete backup codes:%w", err)
+		}
+
+		_, err = tx.Exec(ctx,
+			"INSERT INTO backup_codes (user_id, hash) SELECT $1, unnest($2::BYTEA[])",
+			userID, backupCodes)
+		if err != nil {
+			return fmt.Errorf("error of insert backup codes:%w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, server.ErrSecondFactorEnabled) {
+			return err
+		}
+
+		return fmt.Errorf("failed to enroll second factor:%w", err)
+	}
+
+	log.Ctx(ctx).Printf("EnrollSecondFactor success")
+	return nil
+}
+
+func (d *db) EnableSecondFactor(ctx context.Context, userID, step int64) error {
+	log.Ctx(ctx).Printf("EnableSecondFactor, userID:%v", userID)
+
+	tag, err := d.pool.Exec(ctx,
+		"UPDATE second_factors SET enabled = TRUE, last_step = $2 WHERE user_id = $1 AND last_step < $2",
+		userID, step)
+	if err != nil {
+		return fmt.Errorf("failed to enable second factor:%w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return server.ErrCodeUsed
+	}
+
+	return nil
+}
+
+func (d *db) DeleteSecondFactor(ctx context.Context, userID int64) error {
+	log.Ctx(ctx).Printf("DeleteSecondFactor, userID:%v", userID)
+
+	tag, err := d.pool.Exec(ctx, "DELETE FROM second_factors WHERE user_id = $1", userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete second factor:%w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return server.ErrSecondFactorNotFound
+	}
+
+	return nil
+}
+
+func (d *db) UseTOTPStep(ctx context.Context, userID, step int64) error {
+	tag, err := d.pool.Exec(ctx,
+		"UPDATE second_factors SET last_step = $2 WHERE user_id = $1 AND last_step < $2",
+		userID, step)
+	if err != nil {
+		return fmt.Errorf("failed to use totp step:%w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return server.ErrCodeUsed
+	}
+
+	return nil
+}
+
+func (d *db) UseBackupCode(ctx context.Context, userID int64, hash []byte) error {
+	log.Ctx(ctx).Printf("UseBackupCode, userID:%v", userID)
+
+	tag, err := d.pool.Exec(ctx,
+		"UPDATE backup_codes SET used_time = NOW() WHERE user_id = $1 AND hash = $2 AND used_time IS NULL",
+		userID, hash)
+	if err != nil {
+		return fmt.Errorf("failed to use backup code:%w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return server.ErrCodeUsed
+	}
+
+	return nil
+}
+
+func (d *db) CreateLoginChallenge(ctx context.Context, challenge *server.LoginChallenge) error {
+	log.Ctx(ctx).Printf("CreateLoginChallenge, userID:%v", challenge.UserID)
+
+	err := pgx.BeginFunc(ctx, d.pool, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx,
+			"DELETE FROM login_challenges WHERE user_id = $1 AND expire_time < NOW()",
+			challenge.UserID)
+		if err != nil {
+			return fmt.Errorf("error of delete expired login challenges:%w", err)
+		}
+
+		_, err = tx.Exec(ctx,
+			"INSERT INTO login_challenges (hash, user_id, device, expire_time) VALUES ($1, $2, $3, $4)",
+			challenge.Hash, challenge.UserID, challenge.Device, challenge.ExpireTime)
+		if err != nil {
+			return fmt.Errorf("error of insert login challenge:%w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create login challenge:%w", err)
+	}
+
+	return nil
+}
+
+func (d *db) GetLoginChallenge(ctx context.Context, hash []byte) (*server.LoginChallenge, error) {
+	c := server.LoginChallenge{Hash: hash}
+
+	err := d.pool.QueryRow(ctx,
+		"SELECT c.user_id, u.login, c.device, c.expire_time FROM login_challenges c "+
+			"JOIN users u ON u.id = c.user_id "+
+			"WHERE c.hash = $1 AND c.expire_time > NOW()",
+		hash).Scan(&c.UserID, &c.Login, &c.Device, &c.ExpireTime)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, server.ErrLoginChallengeNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get login challenge:%w", err)
+	}
+
+	return &c, nil
+}
+
+func (d *db) FailLoginChallenge(ctx context.Context, hash []byte, maxAttempts int) error {
+	var attempts int
+
+	err := d.pool.QueryRow(ctx,
+		"UPDATE login_challenges SET attempts = attempts + 1 WHERE hash = $1 RETURNING attempts",
+		hash).Scan(&attempts)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to fail login challenge:%w", err)
+	}
+
+	if attempts < maxAttempts {
+		return nil
+	}
+
+	return d.DeleteLoginChallenge(ctx, hash)
+}
+
+func (d *db) DeleteLoginChallenge(ctx context.Context, hash []byte) error {
+	_, err := d.pool.Exec(ctx, "DELETE FROM login_challenges WHERE hash = $1", hash)
+	if err != nil {
+		return fmt.Errorf("failed to delete login challenge:%w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/k0st1a/gophkeeper/internal/ports/server"
+	"github.com/rs/zerolog/log"
+)
+
+// lastSeenInterval - время последнего обращения сессии обновляется не чаще этого интервала,
+// чтобы не писать в базу на каждый запрос.
+const lastSeenInterval = time.Minute
+
+// CreateSession - создать сессию и ее первый refresh токен, заодно удалить истекшие сессии пользователя.
+func (d *db) CreateSession(ctx context.Context, session *server.Session, token *server.RefreshToken) (string, error) {
+	log.Ctx(ctx).Printf("CreateSession, userID:%v, device:%v, ip:%v", session.UserID, session.Device, session.IP)
+	var id string
+
+	err := pgx.BeginFunc(ctx, d.pool, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx,
+			"DELETE FROM sessions WHERE user_id = $1 AND expire_time < NOW()",
+			session.UserID)
+		if err != nil {
+			return fmt.Errorf("error of delete expired sessions:%w", err)
+		}
+
+		err = tx.QueryRow(ctx,
+			"INSERT INTO sessions (user_id, device, ip, expire_time) VALUES ($1, $2, $3, $4) RETURNING id::TEXT",
+			session.UserID, session.Device, session.IP, token.ExpireTime).Scan(&id)
+		if err != nil {
+			return fmt.Errorf("error of insert session:%w", err)
+		}
+
+		_, err = tx.Exec(ctx,
+			"INSERT INTO refresh_tokens (token_hash, session_id, user_id, expire_time) VALUES ($1, $2, $3, $4)",
+			token.Hash, id, session.UserID, token.ExpireTime)
+		if err != nil {
+			return fmt.Errorf("error of insert refresh token:%w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create session:%w", err)
+	}
+
+	log.Ctx(ctx).Printf("CreateSession success, session:%v", id)
+	return id, nil
+}
+
+// CheckSession - проверить, что сессия действует, и обновить время и IP последнего обращения.
+func (d *db) CheckSession(ctx context.Context, userID int64, sessionID, ip string) error {
+	var found bool
+
+	err := d.pool.QueryRow(ctx,
+		"WITH s AS ("+
+			"SELECT id FROM sessions WHERE id = $2::UUID AND user_id = $1 AND expire_time > NOW()"+
+			"), u AS ("+
+			"UPDATE sessions SET last_seen_time = NOW(), ip = $3 "+
+			"WHERE id IN (SELECT id FROM s) AND (last_seen_time < NOW() - $4::INTERVAL OR ip <> $3)"+
+			") "+
+			"SELECT EXISTS (SELECT 1 FROM s)",
+		userID, sessionID, ip, lastSeenInterval).Scan(&found)
+	if err != nil {
+		return fmt.Errorf("failed to check session:%w", err)
+	}
+
+	if !found {
+		return server.ErrSessionNotFound
+	}
+
+	return nil
+}
+
+func (d *db) ListSessions(ctx context.Context, userID int64) ([]server.Session, error) {
+	log.Ctx(ctx).Printf("ListSessions, userID:%v", userID)
+
+	rows, err := d.pool.Query(ctx,
+		"SELECT id::TEXT, user_id, device, ip, create_time, last_seen_time, expire_time FROM sessions "+
+			"WHERE user_id = $1 AND expire_time > NOW() ORDER BY last_seen_time DESC",
+		userID)
+	if err != nil {
+		return nil, fmt.Errorf("query error of list sessions:%w", err)
+	}
+
+	sessions, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (server.Session, error) {
+		var s server.Session
+		err := row.Scan(&s.ID, &s.UserID, &s.Device, &s.IP, &s.CreateTime, &s.LastSeenTime, &s.ExpireTime)
+		return s, err //nolint:wrapcheck // wrapped below
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error of list sessions:%w", err)
+	}
+
+	log.Ctx(ctx).Printf("ListSessions success, sessions:%v", len(sessions))
+	return sessions, nil
+}
+
+func (d *db) RevokeSession(ctx context.Context, userID int64, sessionID string) error {
+	log.Ctx(ctx).Printf("RevokeSession, userID:%v, session:%v", userID, sessionID)
+	var id string
+
+	err := d.pool.QueryRow(ctx,
+		"DELETE FROM sessions WHERE id = $2::UUID AND user_id = $1 RETURNING id::TEXT",
+		userID, sessionID).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return server.ErrSessionNotFound
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to revoke session:%w", err)
+	}
+
+	log.Ctx(ctx).Printf("RevokeSession success")
+	return nil
+}
+
+func (d *db) RevokeOtherSessions(ctx context.Context, userID int64, sessionID string) (int64, error) {
+	log.Ctx(ctx).Printf("RevokeOtherSessions, userID:%v, session:%v", userID, sessionID)
+
+	t, err := d.pool.Exec(ctx,
+		"DELETE FROM sessions WHERE user_id = $1 AND id <> $2::UUID",
+		userID, sessionID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to revoke other sessions:%w", err)
+	}
+
+	log.Ctx(ctx).Printf("RevokeOtherSessions success, revoked:%v", t.RowsAffected())
+	return t.RowsAffected(), nil
+}
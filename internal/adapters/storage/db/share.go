@@ -0,0 +1,99 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/k0st1a/gophkeeper/internal/ports/server"
+	"github.com/rs/zerolog/log"
+)
+
+func (d *db) CreateShare(ctx context.Context, share *server.Share) (int64, error) {
+	log.Ctx(ctx).Printf("CreateShare, userID:%v, itemID:%v, recipientID:%v", share.OwnerID, share.ItemID,
+		share.RecipientID)
+	var id int64
+
+	now := time.Now()
+	err := d.pool.QueryRow(ctx,
+		"INSERT INTO shares (item_id, owner_id, recipient_id, data, create_time, update_time) "+
+			"SELECT id, user_id, $3, $4, $5, $5 FROM items "+
+			"WHERE id = $1 AND user_id = $2 AND collection_id IS NULL AND delete_time IS NULL "+
+			"ON CONFLICT (item_id, recipient_id) DO UPDATE SET data = EXCLUDED.data, update_time = EXCLUDED.update_time "+
+			"RETURNING id",
+		share.ItemID, share.OwnerID, share.RecipientID, share.Data, now).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, server.ErrItemNotFound
+	}
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to create share:%w", err)
+	}
+
+	log.Ctx(ctx).Printf("CreateShare success, id:%v", id)
+	return id, nil
+}
+
+func (d *db) DeleteShare(ctx context.Context, userID, shareID int64) error {
+	log.Ctx(ctx).Printf("DeleteShare, userID:%v, id:%v", userID, shareID)
+
+	tag, err := d.pool.Exec(ctx,
+		"DELETE FROM shares WHERE id = $1 AND (owner_id = $2 OR recipient_id = $2)",
+		shareID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete share:%w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return server.ErrShareNotFound
+	}
+
+	log.Ctx(ctx).Printf("DeleteShare success")
+	return nil
+}
+
+func (d *db) ListShares(ctx context.Context, ownerID int64) ([]server.Share, error) {
+	log.Ctx(ctx).Printf("ListShares, userID:%v", ownerID)
+
+	return d.listShares(ctx,
+		"WHERE s.owner_id = $1 ORDER BY s.update_time DESC",
+		ownerID)
+}
+
+func (d *db) ListSharedWithMe(ctx context.Context, recipientID int64) ([]server.Share, error) {
+	log.Ctx(ctx).Printf("ListSharedWithMe, userID:%v", recipientID)
+
+	return d.listShares(ctx,
+		"WHERE s.recipient_id = $1 AND i.delete_time IS NULL ORDER BY s.update_time DESC",
+		recipientID)
+}
+
+// listShares - получить доступы с логинами владельца и получателя по условию where.
+func (d *db) listShares(ctx context.Context, where string, userID int64) ([]server.Share, error) {
+	rows, err := d.pool.Query(ctx,
+		"SELECT s.id, s.item_id, s.owner_id, o.login, s.recipient_id, r.login, s.data, s.create_time, s.update_time "+
+			"FROM shares s "+
+			"JOIN items i ON i.id = s.item_id "+
+			"JOIN users o ON o.id = s.owner_id "+
+			"JOIN users r ON r.id = s.recipient_id "+
+			where,
+		userID)
+	if err != nil {
+		return nil, fmt.Errorf("query error of list shares:%w", err)
+	}
+
+	shares, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (server.Share, error) {
+		var s server.Share
+		err := row.Scan(&s.ID, &s.ItemID, &s.OwnerID, &s.Owner, &s.RecipientID, &s.Recipient, &s.Data,
+			&s.CreateTime, &s.UpdateTime)
+		return s, err //nolint:wrapcheck // wrapped below
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error of list shares:%w", err)
+	}
+
+	log.Ctx(ctx).Printf("List shares success, shares:%v", len(shares))
+	return shares, nil
+}
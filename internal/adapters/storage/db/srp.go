@@ -0,0 +1,65 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/k0st1a/gophkeeper/internal/ports/server"
+	"github.com/rs/zerolog/log"
+)
+
+func (d *db) CreateSRPLogin(ctx context.Context, login *server.SRPLogin) error {
+	log.Ctx(ctx).Printf("CreateSRPLogin, Login:%s", login.Login)
+
+	var userID *int64
+	if login.UserID != 0 {
+		userID = &login.UserID
+	}
+
+	err := pgx.BeginFunc(ctx, d.pool, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, "DELETE FROM srp_logins WHERE expire_time < NOW()")
+		if err != nil {
+			return fmt.Errorf("error of delete expired srp logins:%w", err)
+		}
+
+		_, err = tx.Exec(ctx,
+			"INSERT INTO srp_logins (hash, login, user_id, secret, public_a, public_b, expire_time) "+
+				"VALUES ($1, $2, $3, $4, $5, $6, $7)",
+			login.Hash, login.Login, userID, login.Secret, login.PublicA, login.PublicB, login.ExpireTime)
+		if err != nil {
+			return fmt.Errorf("error of insert srp login:%w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create srp login:%w", err)
+	}
+
+	return nil
+}
+
+func (d *db) TakeSRPLogin(ctx context.Context, hash []byte) (*server.SRPLogin, error) {
+	l := server.SRPLogin{Hash: hash}
+	var userID *int64
+
+	err := d.pool.QueryRow(ctx,
+		"DELETE FROM srp_logins WHERE hash = $1 AND expire_time > NOW() "+
+			"RETURNING login, user_id, secret, public_a, public_b, expire_time",
+		hash).Scan(&l.Login, &userID, &l.Secret, &l.PublicA, &l.PublicB, &l.ExpireTime)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, server.ErrSRPLoginNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to take srp login:%w", err)
+	}
+
+	if userID != nil {
+		l.UserID = *userID
+	}
+
+	return &l, nil
+}
@@ -13,9 +13,14 @@ import (
 
 type db struct {
 	pool *pgxpool.Pool
+	// Количество хранимых прошлых версий предмета, если пользователь не задал свое
+	historyRetention int
 }
 
-func NewDB(ctx context.Context, dsn string) (*db, error) {
+// NewDB - создать хранилище в базе данных, где:
+//   - dsn - адрес подключения к базе данных;
+//   - hr - количество хранимых прошлых версий предмета по умолчанию.
+func NewDB(ctx context.Context, dsn string, hr int) (*db, error) {
 	err := runMigrations(dsn)
 	if err != nil {
 		return nil, fmt.Errorf("failed to run DB migrations: %w", err)
@@ -27,7 +32,8 @@ func NewDB(ctx context.Context, dsn string) (*db, error) {
 	}
 
 	return &db{
-		pool: pool,
+		pool:             pool,
+		historyRetention: hr,
 	}, nil
 }
 
@@ -47,15 +53,18 @@ func (d *db) Ping(ctx context.Context) error {
 	return nil
 }
 
-func (d *db) CreateUser(ctx context.Context, login, password string) (int64, error) {
-	log.Ctx(ctx).Printf("CreateUser, Login:%s, password:%s", login, password)
+func (d *db) CreateUser(ctx context.Context, user *server.User) (int64, error) {
+	log.Ctx(ctx).Printf("CreateUser, Login:%s", user.Login)
 	var id int64
 
 	err := d.pool.QueryRow(ctx,
-		"INSERT INTO users (login,password) VALUES($1,$2) "+
+		"INSERT INTO users (login, password, vault_salt, vault_key, srp_salt, srp_verifier, "+
+			"recovery_salt, recovery_key, recovery_hash) "+
+			"VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9) "+
 			"ON CONFLICT DO NOTHING "+
 			"RETURNING id",
-		login, password).Scan(&id)
+		user.Login, user.Password, user.VaultKey.Salt, user.VaultKey.Key, user.SRP.Salt, user.SRP.Verifier,
+		user.Recovery.Salt, user.Recovery.Key, user.Recovery.Hash).Scan(&id)
 
 	if errors.Is(err, pgx.ErrNoRows) {
 		return 0, server.ErrLoginAlreadyBusy
@@ -68,30 +77,256 @@ func (d *db) CreateUser(ctx context.Context, login, password string) (int64, err
 	return id, nil
 }
 
-func (d *db) GetUserIDAndPassword(ctx context.Context, login string) (int64, string, error) {
-	log.Ctx(ctx).Printf("GetUserIDAndPassword, Login:%s", login)
+func (d *db) GetUser(ctx context.Context, login string) (*server.User, error) {
+	log.Ctx(ctx).Printf("GetUser, Login:%s", login)
+	var user server.User
+
+	err := d.pool.QueryRow(ctx,
+		"SELECT id, login, password, vault_salt, vault_key, srp_salt, srp_verifier, public_key, private_key, "+
+			"recovery_salt, recovery_key, recovery_hash "+
+			"FROM users WHERE login = $1",
+		login).Scan(&user.ID, &user.Login, &user.Password, &user.VaultKey.Salt, &user.VaultKey.Key,
+		&user.SRP.Salt, &user.SRP.Verifier, &user.KeyPair.Public, &user.KeyPair.Private,
+		&user.Recovery.Salt, &user.Recovery.Key, &user.Recovery.Hash)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, server.ErrUserNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user:%w", err)
+	}
+
+	return &user, nil
+}
+
+func (d *db) GetUserByID(ctx context.Context, userID int64) (*server.User, error) {
+	log.Ctx(ctx).Printf("GetUserByID, userID:%v", userID)
+	var user server.User
+
+	err := d.pool.QueryRow(ctx,
+		"SELECT id, login, password, vault_salt, vault_key, srp_salt, srp_verifier, public_key, private_key, "+
+			"recovery_salt, recovery_key, recovery_hash "+
+			"FROM users WHERE id = $1",
+		userID).Scan(&user.ID, &user.Login, &user.Password, &user.VaultKey.Salt, &user.VaultKey.Key,
+		&user.SRP.Salt, &user.SRP.Verifier, &user.KeyPair.Public, &user.KeyPair.Private,
+		&user.Recovery.Salt, &user.Recovery.Key, &user.Recovery.Hash)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, server.ErrUserNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user by id:%w", err)
+	}
+
+	return &user, nil
+}
+
+func (d *db) SetVaultKey(ctx context.Context, userID int64, key *server.VaultKey) error {
+	log.Ctx(ctx).Printf("SetVaultKey, userID:%v", userID)
 	var id int64
-	var password string
 
-	err := d.pool.QueryRow(ctx, "SELECT id, password FROM users WHERE login = $1", login).Scan(&id, &password)
+	err := d.pool.QueryRow(ctx,
+		"UPDATE users SET vault_salt = $1, vault_key = $2 WHERE id = $3 AND vault_key = '' RETURNING id",
+		key.Salt, key.Key, userID).Scan(&id)
 	if errors.Is(err, pgx.ErrNoRows) {
-		return 0, "", server.ErrUserNotFound
+		return server.ErrVaultKeyAlreadySet
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to set vault key:%w", err)
+	}
+
+	log.Ctx(ctx).Printf("SetVaultKey success")
+	return nil
+}
+
+func (d *db) SetKeyPair(ctx context.Context, userID int64, kp *server.KeyPair) error {
+	log.Ctx(ctx).Printf("SetKeyPair, userID:%v", userID)
+	var id int64
+
+	err := d.pool.QueryRow(ctx,
+		"UPDATE users SET public_key = $1, private_key = $2 WHERE id = $3 AND public_key = '' RETURNING id",
+		kp.Public, kp.Private, userID).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return server.ErrKeyPairAlreadySet
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to set key pair:%w", err)
+	}
+
+	log.Ctx(ctx).Printf("SetKeyPair success")
+	return nil
+}
+
+func (d *db) SetSRPVerifier(ctx context.Context, userID int64, v *server.SRPVerifier) error {
+	log.Ctx(ctx).Printf("SetSRPVerifier, userID:%v", userID)
+	var id int64
+
+	err := d.pool.QueryRow(ctx,
+		"UPDATE users SET srp_salt = $1, srp_verifier = $2, password = '' WHERE id = $3 AND srp_verifier = '' "+
+			"RETURNING id",
+		v.Salt, v.Verifier, userID).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return server.ErrSRPVerifierAlreadySet
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to set srp verifier:%w", err)
+	}
+
+	log.Ctx(ctx).Printf("SetSRPVerifier success")
+	return nil
+}
+
+func (d *db) UpdatePasswordHash(ctx context.Context, userID int64, old, hash string) error {
+	log.Ctx(ctx).Printf("UpdatePasswordHash, userID:%v", userID)
+
+	_, err := d.pool.Exec(ctx,
+		"UPDATE users SET password = $1 WHERE id = $2 AND password = $3",
+		hash, userID, old)
+	if err != nil {
+		return fmt.Errorf("failed to update password hash:%w", err)
+	}
+
+	return nil
+}
+
+func (d *db) ChangePassword(ctx context.Context, old *server.User, sessionID string,
+	c *server.PasswordChange) (int64, error) {
+	log.Ctx(ctx).Printf("ChangePassword, userID:%v", old.ID)
+	var revoked int64
+
+	err := pgx.BeginFunc(ctx, d.pool, func(tx pgx.Tx) error {
+		var err error
+		revoked, err = changePassword(ctx, tx, old, sessionID, c)
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("change password tx error:%w", err)
 	}
 
+	log.Ctx(ctx).Printf("ChangePassword success, revoked:%v", revoked)
+	return revoked, nil
+}
+
+// changePassword - заменить верификатор SRP и ключ хранилища, при необходимости отключить второй фактор и отозвать
+// сессии пользователя, кроме sessionID, в транзакции tx. Возвращает количество отозванных сессий.
+func changePassword(ctx context.Context, tx pgx.Tx, old *server.User, sessionID string,
+	c *server.PasswordChange) (int64, error) {
+	// Пароль проверен по прочитанным верификатору и хэшу, поэтому они не должны измениться до замены.
+	tag, err := tx.Exec(ctx,
+		"UPDATE users SET srp_salt = $1, srp_verifier = $2, password = '' "+
+			"WHERE id = $3 AND srp_verifier = $4 AND password = $5",
+		c.Verifier.Salt, c.Verifier.Verifier, old.ID, old.SRP.Verifier, old.Password)
+	if err != nil {
+		return 0, fmt.Errorf("failed to update srp verifier:%w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return 0, server.ErrPasswordChanged
+	}
+
+	if c.VaultKey != nil {
+		_, err = tx.Exec(ctx,
+			"UPDATE users SET vault_salt = $1, vault_key = $2 WHERE id = $3",
+			c.VaultKey.Salt, c.VaultKey.Key, old.ID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to update vault key:%w", err)
+		}
+	}
+
+	if c.ResetSecondFactor {
+		_, err = tx.Exec(ctx, "DELETE FROM second_factors WHERE user_id = $1", old.ID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to delete second factor:%w", err)
+		}
+	}
+
+	return revokeOtherSessions(ctx, tx, old.ID, sessionID)
+}
+
+// revokeOtherSessions - отозвать все сессии пользователя, кроме sessionID (пустой - все сессии).
+func revokeOtherSessions(ctx context.Context, tx pgx.Tx, userID int64, sessionID string) (int64, error) {
+	tag, err := tx.Exec(ctx,
+		"DELETE FROM sessions WHERE user_id = $1 AND id::TEXT <> $2",
+		userID, sessionID)
 	if err != nil {
-		return 0, "", fmt.Errorf("failed to get user id and password:%w", err)
+		return 0, fmt.Errorf("failed to revoke other sessions:%w", err)
 	}
 
-	return id, password, nil
+	return tag.RowsAffected(), nil
+}
+
+func (d *db) SetRecoveryKey(ctx context.Context, old *server.User, sessionID string,
+	key *server.RecoveryKey) (int64, error) {
+	log.Ctx(ctx).Printf("SetRecoveryKey, userID:%v", old.ID)
+	var revoked int64
+
+	err := pgx.BeginFunc(ctx, d.pool, func(tx pgx.Tx) error {
+		// Пароль проверен по прочитанным верификатору и хэшу, поэтому они не должны измениться до замены.
+		tag, err := tx.Exec(ctx,
+			"UPDATE users SET recovery_salt = $1, recovery_key = $2, recovery_hash = $3 "+
+				"WHERE id = $4 AND srp_verifier = $5 AND password = $6",
+			key.Salt, key.Key, key.Hash, old.ID, old.SRP.Verifier, old.Password)
+		if err != nil {
+			return fmt.Errorf("failed to set recovery key:%w", err)
+		}
+
+		if tag.RowsAffected() == 0 {
+			return server.ErrPasswordChanged
+		}
+
+		revoked, err = revokeOtherSessions(ctx, tx, old.ID, sessionID)
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("set recovery key tx error:%w", err)
+	}
+
+	log.Ctx(ctx).Printf("SetRecoveryKey success, revoked sessions:%v", revoked)
+	return revoked, nil
 }
 
 func (d *db) CreateItem(ctx context.Context, userID int64, item *server.Item) (int64, error) {
 	log.Ctx(ctx).Printf("CreateItem, userID:%v", userID)
 	var id int64
 
-	err := d.pool.QueryRow(ctx,
-		"INSERT INTO items (user_id, data, create_time, update_time) VALUES($1, $2, $3, $4) RETURNING id",
-		userID, item.Data, item.CreateTime, item.UpdateTime).Scan(&id)
+	err := pgx.BeginFunc(ctx, d.pool, func(tx pgx.Tx) error {
+		if item.CollectionID != 0 {
+			err := checkCollectionWrite(ctx, tx, userID, item.CollectionID, item.BlobIDs)
+			if err != nil {
+				return err
+			}
+
+			err = tx.QueryRow(ctx,
+				"INSERT INTO items (user_id, data, create_time, update_time, revision, blob_ids, collection_id) "+
+					"VALUES($1, $2, $3, $4, $5, $6, $7) RETURNING id",
+				userID, item.Data, item.CreateTime, item.UpdateTime, server.InitialRevision,
+				blobIDs(item.BlobIDs), item.CollectionID).Scan(&id)
+			if err != nil {
+				return fmt.Errorf("query error of create collection item:%w", err)
+			}
+
+			return touchCollectionItem(ctx, tx, item.CollectionID, id, false)
+		}
+
+		seq, err := nextChangeSeq(ctx, tx, userID)
+		if err != nil {
+			return err
+		}
+
+		err = tx.QueryRow(ctx,
+			"INSERT INTO items (user_id, data, create_time, update_time, revision, change_seq, blob_ids) "+
+				"VALUES($1, $2, $3, $4, $5, $6, $7) RETURNING id",
+			userID, item.Data, item.CreateTime, item.UpdateTime, server.InitialRevision, seq,
+			blobIDs(item.BlobIDs)).Scan(&id)
+		if err != nil {
+			return fmt.Errorf("query error of create item:%w", err)
+		}
+
+		return nil
+	})
 	if err != nil {
 		return 0, fmt.Errorf("failed to create item:%w", err)
 	}
@@ -100,19 +335,84 @@ func (d *db) CreateItem(ctx context.Context, userID int64, item *server.Item) (i
 	return id, nil
 }
 
-func (d *db) UpdateItem(ctx context.Context, userID int64, item *server.Item) error {
-	log.Ctx(ctx).Printf("UpdateItem, userID:%v, itemID:%v", userID, item.ID)
-	var id int64
+func (d *db) UpdateItem(ctx context.Context, userID int64, item *server.Item) (int64, error) {
+	log.Ctx(ctx).Printf("UpdateItem, userID:%v, itemID:%v, revision:%v", userID, item.ID, item.Revision)
+	var revision int64
+
+	err := pgx.BeginFunc(ctx, d.pool, func(tx pgx.Tx) error {
+		var ownerID, current, collectionID int64
+		var old []string
+		err := tx.QueryRow(ctx,
+			"SELECT user_id, revision, blob_ids, COALESCE(collection_id, 0) FROM items "+
+				"WHERE id = $2 AND "+itemAccess+" AND delete_time IS NULL FOR UPDATE",
+			userID, item.ID).Scan(&ownerID, &current, &old, &collectionID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return server.ErrItemNotFound
+		}
 
-	err := d.pool.QueryRow(ctx,
-		"UPDATE items SET data = $1, update_time = $2 WHERE id = $3 AND user_id = $4 RETURNING id",
-		item.Data, item.UpdateTime, item.ID, userID).Scan(&id)
+		if err != nil {
+			return fmt.Errorf("query error of get item revision:%w", err)
+		}
+
+		item.CollectionID = collectionID
+		if collectionID != 0 {
+			err = checkCollectionWrite(ctx, tx, userID, collectionID, item.BlobIDs)
+			if err != nil {
+				return err
+			}
+		}
+
+		if current != item.Revision {
+			return &server.RevisionMismatchError{Revision: current}
+		}
+
+		// Версия предмета коллекции сохраняется в историю его автора.
+		pruned, err := d.archiveItem(ctx, tx, ownerID, item.ID, false)
+		if err != nil {
+			return err
+		}
+
+		if collectionID != 0 {
+			return updateCollectionItem(ctx, tx, collectionID, item, &revision)
+		}
+
+		seq, err := nextChangeSeq(ctx, tx, userID)
+		if err != nil {
+			return err
+		}
+
+		err = tx.QueryRow(ctx,
+			"UPDATE items SET data = $1, update_time = $2, revision = revision + 1, change_seq = $3, blob_ids = $4 "+
+				"WHERE id = $5 AND user_id = $6 "+
+				"RETURNING revision",
+			item.Data, item.UpdateTime, seq, blobIDs(item.BlobIDs), item.ID, userID).Scan(&revision)
+		if err != nil {
+			return fmt.Errorf("query error of update item:%w", err)
+		}
+
+		return deleteUnreferencedBlobs(ctx, tx, userID, append(old, pruned...))
+	})
 	if err != nil {
-		return fmt.Errorf("query error of update item:%w", err)
+		return 0, fmt.Errorf("failed to update item:%w", err)
 	}
 
-	log.Ctx(ctx).Printf("UpdateItem success, id:%v", id)
-	return nil
+	log.Ctx(ctx).Printf("UpdateItem success, revision:%v", revision)
+	return revision, nil
+}
+
+// nextChangeSeq - получить следующий номер изменения предметов пользователя.
+// Строка пользователя блокируется до конца транзакции, поэтому номера изменений фиксируются в порядке возрастания.
+func nextChangeSeq(ctx context.Context, tx pgx.Tx, userID int64) (int64, error) {
+	var seq int64
+
+	err := tx.QueryRow(ctx,
+		"UPDATE users SET change_seq = change_seq + 1 WHERE id = $1 RETURNING change_seq",
+		userID).Scan(&seq)
+	if err != nil {
+		return 0, fmt.Errorf("query error of next change seq:%w", err)
+	}
+
+	return seq, nil
 }
 
 func (d *db) GetItem(ctx context.Context, userID, itemID int64) (*server.Item, error) {
@@ -120,8 +420,10 @@ func (d *db) GetItem(ctx context.Context, userID, itemID int64) (*server.Item, e
 	var item server.Item
 
 	err := d.pool.QueryRow(ctx,
-		"SELECT id, data, create_time, update_time FROM items WHERE user_id = $1 AND id = $2",
-		userID, itemID).Scan(&item.ID, &item.Data, &item.CreateTime, &item.UpdateTime)
+		"SELECT id, data, create_time, update_time, revision, blob_ids, COALESCE(collection_id, 0) FROM items "+
+			"WHERE "+itemAccess+" AND id = $2 AND delete_time IS NULL",
+		userID, itemID).Scan(&item.ID, &item.Data, &item.CreateTime, &item.UpdateTime, &item.Revision, &item.BlobIDs,
+		&item.CollectionID)
 
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, server.ErrItemNotFound
@@ -137,22 +439,189 @@ func (d *db) GetItem(ctx context.Context, userID, itemID int64) (*server.Item, e
 
 func (d *db) ListItems(ctx context.Context, userID int64) ([]server.Item, error) {
 	log.Ctx(ctx).Printf("ListItems, userID:%v", userID)
-	var items []server.Item
 
 	rows, err := d.pool.Query(ctx,
-		"SELECT id, data, create_time, update_time FROM items WHERE user_id = $1",
+		"SELECT id, data, create_time, update_time, revision, blob_ids, COALESCE(collection_id, 0) FROM items "+
+			"WHERE "+itemAccess+" AND delete_time IS NULL",
 		userID)
 	if err != nil {
-		return items, fmt.Errorf("query error of list item:%w", err)
+		return nil, fmt.Errorf("query error of list item:%w", err)
+	}
+
+	items, err := scanItems(rows)
+	if err != nil {
+		return items, err
+	}
+
+	log.Ctx(ctx).Printf("ListItems success")
+	return items, nil
+}
+
+// DeleteItem - переместить предмет в корзину. Блобы предмета удаляются только при очистке корзины.
+// Для клиентов предмет удален: он пропадает из списка предметов и попадает в удаленные в ленте изменений.
+func (d *db) DeleteItem(ctx context.Context, userID, itemID int64) (int64, error) {
+	log.Ctx(ctx).Printf("DeleteItem, userID:%v, itemID:%v", userID, itemID)
+	var collectionID int64
+
+	err := pgx.BeginFunc(ctx, d.pool, func(tx pgx.Tx) error {
+		var id, ownerID int64
+
+		err := tx.QueryRow(ctx,
+			"SELECT user_id, COALESCE(collection_id, 0) FROM items "+
+				"WHERE id = $2 AND "+itemAccess+" AND delete_time IS NULL FOR UPDATE",
+			userID, itemID).Scan(&ownerID, &collectionID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return server.ErrItemNotFound
+		}
+
+		if err != nil {
+			return fmt.Errorf("query error of get item collection:%w", err)
+		}
+
+		if collectionID != 0 {
+			err = checkCollectionWrite(ctx, tx, userID, collectionID, nil)
+			if err != nil {
+				return err
+			}
+
+			// У версий предметов коллекций нет блобов, поэтому вытесненные версии не освобождают блобы.
+			_, err = d.archiveItem(ctx, tx, ownerID, itemID, true)
+			if err != nil {
+				return err
+			}
+
+			_, err = tx.Exec(ctx, "UPDATE items SET delete_time = NOW() WHERE id = $1", itemID)
+			if err != nil {
+				return fmt.Errorf("query error of delete collection item:%w", err)
+			}
+
+			return touchCollectionItem(ctx, tx, collectionID, itemID, true)
+		}
+
+		pruned, err := d.archiveItem(ctx, tx, userID, itemID, true)
+		if err != nil {
+			return err
+		}
+
+		err = tx.QueryRow(ctx,
+			"UPDATE items SET delete_time = NOW() "+
+				"WHERE id = $1 AND user_id = $2 AND delete_time IS NULL RETURNING id",
+			itemID, userID).Scan(&id)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return server.ErrItemNotFound
+		}
+
+		if err != nil {
+			return fmt.Errorf("query error of delete item:%w", err)
+		}
+
+		seq, err := nextChangeSeq(ctx, tx, userID)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx,
+			"INSERT INTO items_deleted (user_id, item_id, change_seq) VALUES($1, $2, $3) "+
+				"ON CONFLICT (user_id, item_id) DO UPDATE SET change_seq = EXCLUDED.change_seq",
+			userID, itemID, seq)
+		if err != nil {
+			return fmt.Errorf("query error of insert deleted item:%w", err)
+		}
+
+		return deleteUnreferencedBlobs(ctx, tx, userID, pruned)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete item:%w", err)
 	}
 
+	log.Ctx(ctx).Printf("DeleteItem success")
+	return collectionID, nil
+}
+
+// ListChanges - получить изменения предметов пользователя после номера изменения cursor.
+// Если номер изменения 0 или больше последнего номера изменения (например, база была восстановлена из резервной
+// копии), то возвращаются все предметы.
+func (d *db) ListChanges(ctx context.Context, userID, cursor int64) (*server.Changes, error) {
+	log.Ctx(ctx).Printf("ListChanges, userID:%v, cursor:%v", userID, cursor)
+	var changes server.Changes
+
+	// Все запросы должны видеть один снимок базы, иначе номер изменения может не соответствовать предметам.
+	opts := pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly}
+
+	err := pgx.BeginTxFunc(ctx, d.pool, opts, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx,
+			"SELECT change_seq FROM users WHERE id = $1",
+			userID).Scan(&changes.Cursor)
+		if err != nil {
+			return fmt.Errorf("query error of get change seq:%w", err)
+		}
+
+		changes.Full = cursor <= 0 || cursor > changes.Cursor
+		if changes.Full {
+			cursor = 0
+		}
+
+		rows, err := tx.Query(ctx,
+			"SELECT id, data, create_time, update_time, revision, blob_ids, 0 FROM items "+
+				"WHERE user_id = $1 AND collection_id IS NULL AND change_seq > $2 AND delete_time IS NULL "+
+				"UNION ALL "+
+				"SELECT id, data, create_time, update_time, revision, blob_ids, collection_id FROM items "+
+				"JOIN collection_changes cc ON cc.item_id = items.id AND cc.user_id = $1 "+
+				"WHERE cc.change_seq > $2 AND NOT cc.deleted AND "+itemAccess+" AND delete_time IS NULL",
+			userID, cursor)
+		if err != nil {
+			return fmt.Errorf("query error of list changed items:%w", err)
+		}
+
+		changes.Items, err = scanItems(rows)
+		if err != nil {
+			return err
+		}
+
+		if changes.Full {
+			return nil
+		}
+
+		rows, err = tx.Query(ctx,
+			"SELECT item_id FROM items_deleted WHERE user_id = $1 AND change_seq > $2 "+
+				"UNION ALL "+
+				"SELECT item_id FROM collection_changes WHERE user_id = $1 AND deleted AND change_seq > $2",
+			userID, cursor)
+		if err != nil {
+			return fmt.Errorf("query error of list deleted items:%w", err)
+		}
+
+		changes.DeletedIDs, err = pgx.CollectRows(rows, pgx.RowTo[int64])
+		if err != nil {
+			return fmt.Errorf("error of list deleted items:%w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list changes:%w", err)
+	}
+
+	log.Ctx(ctx).Printf("ListChanges success, items:%v, deleted:%v, cursor:%v, full:%v",
+		len(changes.Items), len(changes.DeletedIDs), changes.Cursor, changes.Full)
+	return &changes, nil
+}
+
+// scanItems - прочитать предметы из результата запроса.
+func scanItems(rows pgx.Rows) ([]server.Item, error) {
+	defer rows.Close()
+	var items []server.Item
+
 	for rows.Next() {
 		var item server.Item
-		err = rows.Scan(
+		err := rows.Scan(
 			&item.ID,
 			&item.Data,
 			&item.CreateTime,
 			&item.UpdateTime,
+			&item.Revision,
+			&item.BlobIDs,
+			&item.CollectionID,
 		)
 		if err != nil {
 			return items, fmt.Errorf("scan error of list item:%w", err)
@@ -160,31 +629,10 @@ func (d *db) ListItems(ctx context.Context, userID int64) ([]server.Item, error)
 		items = append(items, item)
 	}
 
-	err = rows.Err()
+	err := rows.Err()
 	if err != nil {
 		return items, fmt.Errorf("error of list item:%w", err)
 	}
 
-	log.Ctx(ctx).Printf("ListItems success")
 	return items, nil
 }
-
-func (d *db) DeleteItem(ctx context.Context, userID, itemID int64) error {
-	log.Ctx(ctx).Printf("DeleteItem, userID:%v, itemID:%v", userID, itemID)
-	var id int64
-
-	err := d.pool.QueryRow(ctx,
-		"DELETE FROM items WHERE id = $1 AND user_id = $2 RETURNING id",
-		itemID, userID).Scan(&id)
-
-	if errors.Is(err, pgx.ErrNoRows) {
-		return server.ErrItemNotFound
-	}
-
-	if err != nil {
-		return fmt.Errorf("failed to delete item:%w", err)
-	}
-
-	log.Ctx(ctx).Printf("DeleteItem success")
-	return nil
-}
@@ -0,0 +1,90 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/k0st1a/gophkeeper/internal/ports/server"
+	"github.com/rs/zerolog/log"
+)
+
+// RotateRefreshToken - заменить refresh токен новым из той же сессии.
+func (d *db) RotateRefreshToken(ctx context.Context, hash []byte, next *server.RefreshToken) error {
+	log.Ctx(ctx).Printf("RotateRefreshToken")
+	var userID int64
+	var sessionID string
+	var reused bool
+
+	err := pgx.BeginFunc(ctx, d.pool, func(tx pgx.Tx) error {
+		var expire time.Time
+		var rotate *time.Time
+
+		err := tx.QueryRow(ctx,
+			"SELECT session_id::TEXT, user_id, expire_time, rotate_time FROM refresh_tokens "+
+				"WHERE token_hash = $1 FOR UPDATE",
+			hash).Scan(&sessionID, &userID, &expire, &rotate)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return server.ErrRefreshTokenNotFound
+		}
+
+		if err != nil {
+			return fmt.Errorf("error of select refresh token:%w", err)
+		}
+
+		if rotate != nil {
+			// Токен уже был заменен: им воспользовался кто-то еще, поэтому отзываем всю сессию.
+			_, err = tx.Exec(ctx, "DELETE FROM sessions WHERE id = $1", sessionID)
+			if err != nil {
+				return fmt.Errorf("error of delete session:%w", err)
+			}
+
+			reused = true
+			return nil
+		}
+
+		if expire.Before(time.Now()) {
+			return server.ErrRefreshTokenExpired
+		}
+
+		_, err = tx.Exec(ctx, "UPDATE refresh_tokens SET rotate_time = NOW() WHERE token_hash = $1", hash)
+		if err != nil {
+			return fmt.Errorf("error of update refresh token:%w", err)
+		}
+
+		_, err = tx.Exec(ctx,
+			"INSERT INTO refresh_tokens (token_hash, session_id, user_id, expire_time) VALUES ($1, $2, $3, $4)",
+			next.Hash, sessionID, userID, next.ExpireTime)
+		if err != nil {
+			return fmt.Errorf("error of insert refresh token:%w", err)
+		}
+
+		_, err = tx.Exec(ctx, "UPDATE sessions SET expire_time = $2 WHERE id = $1", sessionID, next.ExpireTime)
+		if err != nil {
+			return fmt.Errorf("error of prolong session:%w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, server.ErrRefreshTokenNotFound) || errors.Is(err, server.ErrRefreshTokenExpired) {
+			return err
+		}
+
+		return fmt.Errorf("failed to rotate refresh token:%w", err)
+	}
+
+	// Отзыв сессии должен быть зафиксирован, поэтому ошибка возвращается после завершения транзакции.
+	if reused {
+		log.Ctx(ctx).Printf("Refresh token reused => session(%v) revoked, userID:%v", sessionID, userID)
+		return server.ErrRefreshTokenReused
+	}
+
+	next.UserID = userID
+	next.SessionID = sessionID
+
+	log.Ctx(ctx).Printf("RotateRefreshToken success, userID:%v, session:%v", userID, sessionID)
+	return nil
+}
@@ -0,0 +1,154 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/k0st1a/gophkeeper/internal/ports/server"
+	"github.com/rs/zerolog/log"
+)
+
+func (d *db) ListTrash(ctx context.Context, userID int64) ([]server.TrashItem, error) {
+	log.Ctx(ctx).Printf("ListTrash, userID:%v", userID)
+
+	rows, err := d.pool.Query(ctx,
+		"SELECT id, data, create_time, update_time, revision, blob_ids, delete_time FROM items "+
+			"WHERE user_id = $1 AND collection_id IS NULL AND delete_time IS NOT NULL ORDER BY delete_time DESC",
+		userID)
+	if err != nil {
+		return nil, fmt.Errorf("query error of list trash:%w", err)
+	}
+	defer rows.Close()
+
+	var items []server.TrashItem
+
+	for rows.Next() {
+		var i server.TrashItem
+		err := rows.Scan(
+			&i.Item.ID,
+			&i.Item.Data,
+			&i.Item.CreateTime,
+			&i.Item.UpdateTime,
+			&i.Item.Revision,
+			&i.Item.BlobIDs,
+			&i.DeleteTime,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan error of list trash:%w", err)
+		}
+		items = append(items, i)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("error of list trash:%w", err)
+	}
+
+	log.Ctx(ctx).Printf("ListTrash success, items:%v", len(items))
+	return items, nil
+}
+
+// RestoreItem - восстановить предмет из корзины.
+// Для клиентов восстановленный предмет выглядит как новый: он попадает в ленту изменений с новой ревизией.
+func (d *db) RestoreItem(ctx context.Context, userID, itemID int64) (*server.Item, error) {
+	log.Ctx(ctx).Printf("RestoreItem, userID:%v, itemID:%v", userID, itemID)
+	var item server.Item
+
+	err := pgx.BeginFunc(ctx, d.pool, func(tx pgx.Tx) error {
+		seq, err := nextChangeSeq(ctx, tx, userID)
+		if err != nil {
+			return err
+		}
+
+		err = tx.QueryRow(ctx,
+			"UPDATE items SET delete_time = NULL, revision = revision + 1, change_seq = $1 "+
+				"WHERE id = $2 AND user_id = $3 AND collection_id IS NULL AND delete_time IS NOT NULL "+
+				"RETURNING id, data, create_time, update_time, revision, blob_ids",
+			seq, itemID, userID).Scan(&item.ID, &item.Data, &item.CreateTime, &item.UpdateTime, &item.Revision,
+			&item.BlobIDs)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return server.ErrItemNotFound
+		}
+
+		if err != nil {
+			return fmt.Errorf("query error of restore item:%w", err)
+		}
+
+		return deleteItemTombstone(ctx, tx, userID, itemID)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore item:%w", err)
+	}
+
+	log.Ctx(ctx).Printf("RestoreItem success, revision:%v", item.Revision)
+	return &item, nil
+}
+
+// PurgeTrash - окончательно удалить предметы всех пользователей, попавшие в корзину раньше before,
+// вместе с их историей и блобами, на которые больше никто не ссылается. Возвращает количество удаленных предметов.
+func (d *db) PurgeTrash(ctx context.Context, before time.Time) (int64, error) {
+	log.Ctx(ctx).Printf("PurgeTrash, before:%v", before)
+	var purged int64
+
+	err := pgx.BeginFunc(ctx, d.pool, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx,
+			"WITH purged AS ("+
+				"DELETE FROM items WHERE delete_time < $1 RETURNING user_id, id, blob_ids"+
+				"), history AS ("+
+				"DELETE FROM items_history h USING purged p WHERE h.user_id = p.user_id AND h.item_id = p.id "+
+				"RETURNING h.user_id, h.blob_ids"+
+				") "+
+				"SELECT user_id, blob_ids, TRUE FROM purged UNION ALL SELECT user_id, blob_ids, FALSE FROM history",
+			before)
+		if err != nil {
+			return fmt.Errorf("query error of purge trash:%w", err)
+		}
+
+		var userID int64
+		var ids []string
+		var item bool
+		blobs := make(map[int64][]string)
+
+		_, err = pgx.ForEachRow(rows, []any{&userID, &ids, &item}, func() error {
+			if item {
+				purged++
+			}
+			blobs[userID] = append(blobs[userID], ids...)
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("error of purge trash:%w", err)
+		}
+
+		for userID, ids := range blobs {
+			err = deleteUnreferencedBlobs(ctx, tx, userID, ids)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge trash:%w", err)
+	}
+
+	log.Ctx(ctx).Printf("PurgeTrash success, items:%v", purged)
+	return purged, nil
+}
+
+// deleteItemTombstone - удалить отметку об удалении предмета из ленты изменений.
+// Иначе клиенты, получившие удаление из ленты изменений, удалят восстановленный предмет.
+func deleteItemTombstone(ctx context.Context, tx pgx.Tx, userID, itemID int64) error {
+	_, err := tx.Exec(ctx,
+		"DELETE FROM items_deleted WHERE user_id = $1 AND item_id = $2",
+		userID, itemID)
+	if err != nil {
+		return fmt.Errorf("query error of delete item tombstone:%w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,18 @@
+//go:build unix
+
+package file
+
+import (
+	"os"
+	"syscall"
+)
+
+func lockFile(f *os.File) error {
+	//nolint:wrapcheck // wrapped by caller
+	return syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+}
+
+func unlockFile(f *os.File) error {
+	//nolint:wrapcheck // wrapped by caller
+	return syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+}
@@ -0,0 +1,408 @@
+// Package file is durable encrypted storage of client items in a single file.
+//
+// Все предметы хранятся в одном файле, зашифрованном ключом хранилища пользователя.
+// Файл перезаписывается атомарно: данные пишутся во временный файл, который после fsync переименовывается
+// поверх основного, поэтому при падении клиента на диске остается либо старая, либо новая версия хранилища.
+// Рядом с файлом хранилища создается файл блокировки, который не дает двум клиентам работать с одним хранилищем.
+// Предметы каждого пользователя хранятся в своем файле, имя которого получается добавлением к пути хэша логина.
+package file
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+
+	"github.com/google/uuid"
+	"github.com/k0st1a/gophkeeper/internal/ports/client"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	lockSuffix = ".lock"
+	tmpSuffix  = ".tmp"
+)
+
+var (
+	ErrStorageLocked = errors.New("storage is used by another client")
+	// ErrNotOpened - хранилище пользователя еще не открыто, см. Open.
+	ErrNotOpened = errors.New("storage is not opened")
+)
+
+// Cipher - шифрование содержимого файла хранилища.
+type Cipher interface {
+	Encrypt(plaintext []byte) ([]byte, error)
+	Decrypt(ciphertext []byte) ([]byte, error)
+}
+
+// data - содержимое файла хранилища.
+type data struct {
+	Items  map[string]client.Item `json:"items"`
+	Cursor int64                  `json:"cursor"`
+}
+
+type Storage struct {
+	mutex  *sync.Mutex
+	cipher Cipher
+	lock   *os.File
+	data   data
+	// Путь, к которому добавляется хэш логина
+	path string
+	// Файл хранилища открытого пользователя, пустой до вызова Open
+	file   string
+	loaded bool
+}
+
+// New - открыть хранилище в файле path, где:
+//   - path - путь до файла хранилища;
+//   - c - шифрование содержимого файла.
+//
+// Перед работой с предметами нужно открыть хранилище пользователя через Open. Содержимое файла читается при
+// первом обращении к хранилищу, т.к. ключ шифрования появляется только после логина.
+func New(path string, c Cipher) (*Storage, error) {
+	log.Printf("New file storage, path:%v", path)
+
+	lock, err := os.OpenFile(path+lockSuffix, os.O_CREATE|os.O_RDWR, syscall.S_IRUSR|syscall.S_IWUSR)
+	if err != nil {
+		return nil, fmt.Errorf("error of open lock file:%w", err)
+	}
+
+	err = lockFile(lock)
+	if err != nil {
+		_ = lock.Close()
+		return nil, fmt.Errorf("%w:%w", ErrStorageLocked, err)
+	}
+
+	return &Storage{
+		mutex:  &sync.Mutex{},
+		cipher: c,
+		lock:   lock,
+		path:   path,
+		data:   newData(),
+	}, nil
+}
+
+// Close - закрыть хранилище и снять блокировку.
+func (s *Storage) Close() error {
+	log.Printf("Close file storage")
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	err := unlockFile(s.lock)
+	if err != nil {
+		log.Error().Err(err).Msg("error of unlock file storage")
+	}
+
+	err = s.lock.Close()
+	if err != nil {
+		return fmt.Errorf("error of close lock file:%w", err)
+	}
+
+	return nil
+}
+
+// Open - открыть хранилище пользователя login, предметы другого пользователя забываются.
+func (s *Storage) Open(ctx context.Context, login string) {
+	log.Printf("Open, login:%v", login)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	h := sha256.Sum256([]byte(login))
+
+	s.file = s.path + "." + hex.EncodeToString(h[:8])
+	s.data = newData()
+	s.loaded = false
+}
+
+// Clear - забывает предметы в памяти при выходе пользователя. Файл хранилища остается на диске, т.к. в нем могут
+// быть еще не синхронизированные изменения, а без ключа хранилища его содержимое не прочитать.
+func (s *Storage) Clear(ctx context.Context) {
+	log.Printf("Clear")
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.file = ""
+	s.data = newData()
+	s.loaded = false
+}
+
+// ListItems - возвращает копию списка предметов.
+func (s *Storage) ListItems(ctx context.Context) ([]client.Item, error) {
+	log.Printf("List items")
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.Map2List(s.data.Items), nil
+}
+
+// GetItem - возвращает указатель на копию предмета.
+func (s *Storage) GetItem(ctx context.Context, id string) (*client.Item, error) {
+	log.Printf("Get item, id:%v", id)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	i, ok := s.data.Items[id]
+	if !ok {
+		log.Error().Msgf("Item(%v) not found", id)
+		return nil, client.ErrItemNotFound
+	}
+
+	return &i, nil
+}
+
+// CreateItem - создает предмет.
+func (s *Storage) CreateItem(ctx context.Context, i *client.Item) (string, error) {
+	log.Printf("Create item")
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	err := s.load()
+	if err != nil {
+		return "", err
+	}
+
+	i.ID = uuid.NewString()
+
+	s.data.Items[i.ID] = *i
+
+	err = s.save()
+	if err != nil {
+		delete(s.data.Items, i.ID)
+		return "", err
+	}
+
+	return i.ID, nil
+}
+
+// UpdateItem - обновляет предмет, если есть предмет с таким ID.
+func (s *Storage) UpdateItem(ctx context.Context, ui *client.UpdateItem) error {
+	log.Printf("Update item, id:%v", ui.ID)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	err := s.load()
+	if err != nil {
+		return err
+	}
+
+	old, ok := s.data.Items[ui.ID]
+	if !ok {
+		log.Error().Msgf("Item(%v) not found", ui.ID)
+		return client.ErrItemNotFound
+	}
+
+	i := old
+	client.ApplyUpdate(&i, ui)
+
+	s.data.Items[ui.ID] = i
+
+	err = s.save()
+	if err != nil {
+		s.data.Items[ui.ID] = old
+		return err
+	}
+
+	return nil
+}
+
+// DeleteItem - удаляет предмет.
+func (s *Storage) DeleteItem(ctx context.Context, id string) error {
+	log.Printf("Delete item, id:%v", id)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	err := s.load()
+	if err != nil {
+		return err
+	}
+
+	old, ok := s.data.Items[id]
+	if !ok {
+		log.Error().Msgf("Item(%v) not found", id)
+		return client.ErrItemNotFound
+	}
+
+	delete(s.data.Items, id)
+
+	err = s.save()
+	if err != nil {
+		s.data.Items[id] = old
+		return err
+	}
+
+	return nil
+}
+
+// GetSyncCursor - возвращает курсор синхронизации.
+func (s *Storage) GetSyncCursor(ctx context.Context) (int64, error) {
+	log.Printf("Get sync cursor")
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	err := s.load()
+	if err != nil {
+		return 0, err
+	}
+
+	return s.data.Cursor, nil
+}
+
+// SetSyncCursor - сохраняет курсор синхронизации.
+func (s *Storage) SetSyncCursor(ctx context.Context, cursor int64) error {
+	log.Printf("Set sync cursor:%v", cursor)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	err := s.load()
+	if err != nil {
+		return err
+	}
+
+	old := s.data.Cursor
+	s.data.Cursor = cursor
+
+	err = s.save()
+	if err != nil {
+		s.data.Cursor = old
+		return err
+	}
+
+	return nil
+}
+
+// load - прочитать файл хранилища, если он еще не прочитан.
+func (s *Storage) load() error {
+	if s.file == "" {
+		return ErrNotOpened
+	}
+
+	if s.loaded {
+		return nil
+	}
+
+	b, err := os.ReadFile(s.file)
+	if errors.Is(err, os.ErrNotExist) {
+		log.Printf("Storage file not exist => empty storage")
+		s.data = newData()
+		s.loaded = true
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("error of read storage file:%w", err)
+	}
+
+	p, err := s.cipher.Decrypt(b)
+	if err != nil {
+		return fmt.Errorf("error of decrypt storage file:%w", err)
+	}
+
+	d := newData()
+
+	err = json.Unmarshal(p, &d)
+	if err != nil {
+		return fmt.Errorf("error of unmarshal storage file:%w", err)
+	}
+
+	if d.Items == nil {
+		d.Items = make(map[string]client.Item)
+	}
+
+	s.data = d
+	s.loaded = true
+
+	return nil
+}
+
+// save - атомарно перезаписать файл хранилища.
+func (s *Storage) save() error {
+	p, err := json.Marshal(&s.data)
+	if err != nil {
+		return fmt.Errorf("error of marshal storage:%w", err)
+	}
+
+	b, err := s.cipher.Encrypt(p)
+	if err != nil {
+		return fmt.Errorf("error of encrypt storage:%w", err)
+	}
+
+	tmp := s.file + tmpSuffix
+
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, syscall.S_IRUSR|syscall.S_IWUSR)
+	if err != nil {
+		return fmt.Errorf("error of open temporary storage file:%w", err)
+	}
+
+	_, err = f.Write(b)
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("error of write temporary storage file:%w", err)
+	}
+
+	err = f.Sync()
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("error of sync temporary storage file:%w", err)
+	}
+
+	err = f.Close()
+	if err != nil {
+		return fmt.Errorf("error of close temporary storage file:%w", err)
+	}
+
+	err = os.Rename(tmp, s.file)
+	if err != nil {
+		return fmt.Errorf("error of rename temporary storage file:%w", err)
+	}
+
+	syncDir(filepath.Dir(s.file))
+
+	return nil
+}
+
+// syncDir - сбросить на диск запись каталога, чтобы переименование файла пережило падение системы.
+func syncDir(path string) {
+	d, err := os.Open(path)
+	if err != nil {
+		log.Error().Err(err).Msg("error of open storage dir")
+		return
+	}
+	defer d.Close() //nolint:errcheck // read only
+
+	err = d.Sync()
+	if err != nil {
+		log.Debug().Err(err).Msg("error of sync storage dir")
+	}
+}
+
+func newData() data {
+	return data{
+		Items: make(map[string]client.Item),
+	}
+}
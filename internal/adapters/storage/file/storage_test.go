@@ -0,0 +1,197 @@
+package file
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/k0st1a/gophkeeper/internal/pkg/client/keyring"
+	"github.com/k0st1a/gophkeeper/internal/pkg/client/model"
+	"github.com/k0st1a/gophkeeper/internal/ports/client"
+	"github.com/stretchr/testify/require"
+)
+
+func newKeyring(t *testing.T) *keyring.Keyring {
+	t.Helper()
+
+	key, _, err := keyring.NewVaultKey("master password")
+	require.NoError(t, err)
+
+	k := keyring.New()
+	k.Set(key)
+
+	return k
+}
+
+func TestPersistence(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "storage")
+	k := newKeyring(t)
+
+	s, err := New(path, k)
+	require.NoError(t, err)
+	s.Open(ctx, "login")
+
+	item := &client.Item{
+		Body: model.Item{
+			Password: &model.Password{
+				Resource: "Resource",
+				UserName: "Username",
+				Password: "Secret password",
+			},
+			Meta: model.Meta{
+				model.MetaKeyDescription: "Password description",
+			},
+		},
+		CreateTime: time.Date(2024, time.May, 5, 8, 10, 0, 0, time.UTC),
+		UpdateTime: time.Date(2024, time.May, 5, 8, 10, 0, 0, time.UTC),
+	}
+
+	id, err := s.CreateItem(ctx, item)
+	require.NoError(t, err)
+
+	deleteID, err := s.CreateItem(ctx, &client.Item{Body: model.Item{Note: &model.Note{Name: "Name"}}})
+	require.NoError(t, err)
+
+	remoteID := int64(10)
+	err = s.UpdateItem(ctx, &client.UpdateItem{ID: id, RemoteID: &remoteID})
+	require.NoError(t, err)
+
+	err = s.DeleteItem(ctx, deleteID)
+	require.NoError(t, err)
+
+	err = s.SetSyncCursor(ctx, 42)
+	require.NoError(t, err)
+
+	require.NoError(t, s.Close())
+
+	b, err := os.ReadFile(s.file)
+	require.NoError(t, err)
+	require.NotContains(t, string(b), "Secret password")
+
+	s, err = New(path, k)
+	require.NoError(t, err)
+	defer s.Close() //nolint:errcheck // test
+	s.Open(ctx, "login")
+
+	items, err := s.ListItems(ctx)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+
+	item.RemoteID = remoteID
+	require.Equal(t, *item, items[0])
+
+	cursor, err := s.GetSyncCursor(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(42), cursor)
+}
+
+func TestLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "storage")
+	k := newKeyring(t)
+
+	s, err := New(path, k)
+	require.NoError(t, err)
+
+	_, err = New(path, k)
+	require.ErrorIs(t, err, ErrStorageLocked)
+
+	require.NoError(t, s.Close())
+
+	s, err = New(path, k)
+	require.NoError(t, err)
+	require.NoError(t, s.Close())
+}
+
+func TestWrongKey(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "storage")
+
+	s, err := New(path, newKeyring(t))
+	require.NoError(t, err)
+	s.Open(ctx, "login")
+
+	_, err = s.CreateItem(ctx, &client.Item{Body: model.Item{Note: &model.Note{Name: "Name"}}})
+	require.NoError(t, err)
+	require.NoError(t, s.Close())
+
+	s, err = New(path, newKeyring(t))
+	require.NoError(t, err)
+	defer s.Close() //nolint:errcheck // test
+	s.Open(ctx, "login")
+
+	_, err = s.ListItems(ctx)
+	require.Error(t, err)
+}
+
+func TestLockedKeyring(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "storage")
+
+	s, err := New(path, keyring.New())
+	require.NoError(t, err)
+	defer s.Close() //nolint:errcheck // test
+	s.Open(context.Background(), "login")
+
+	_, err = s.CreateItem(context.Background(), &client.Item{Body: model.Item{Note: &model.Note{Name: "Name"}}})
+	require.ErrorIs(t, err, keyring.ErrLocked)
+
+	items, err := s.ListItems(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, items)
+}
+
+func TestClear(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "storage")
+
+	s, err := New(path, newKeyring(t))
+	require.NoError(t, err)
+	defer s.Close() //nolint:errcheck // test
+	s.Open(ctx, "login")
+
+	_, err = s.CreateItem(ctx, &client.Item{Body: model.Item{Note: &model.Note{Name: "Name"}}})
+	require.NoError(t, err)
+
+	file := s.file
+	s.Clear(ctx)
+
+	_, err = os.Stat(file)
+	require.NoError(t, err)
+
+	_, err = s.ListItems(ctx)
+	require.ErrorIs(t, err, ErrNotOpened)
+
+	s.Open(ctx, "login")
+
+	items, err := s.ListItems(ctx)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+}
+
+func TestOpen(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "storage")
+
+	s, err := New(path, newKeyring(t))
+	require.NoError(t, err)
+	defer s.Close() //nolint:errcheck // test
+
+	_, err = s.ListItems(ctx)
+	require.ErrorIs(t, err, ErrNotOpened)
+
+	s.Open(ctx, "first")
+	_, err = s.CreateItem(ctx, &client.Item{Body: model.Item{Note: &model.Note{Name: "Name"}}})
+	require.NoError(t, err)
+
+	s.Open(ctx, "second")
+	items, err := s.ListItems(ctx)
+	require.NoError(t, err)
+	require.Empty(t, items)
+
+	s.Open(ctx, "first")
+	items, err = s.ListItems(ctx)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+}
@@ -10,8 +10,10 @@ import (
 )
 
 type Storage struct {
-	mutex *sync.RWMutex
-	items map[string]client.Item
+	mutex  *sync.RWMutex
+	items  map[string]client.Item
+	login  string
+	cursor int64
 }
 
 func New() *Storage {
@@ -21,13 +23,31 @@ func New() *Storage {
 	}
 }
 
+// Open - открыть хранилище пользователя login, предметы другого пользователя забываются.
+func (s *Storage) Open(ctx context.Context, login string) {
+	log.Printf("Open, login:%v", login)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.login == login {
+		return
+	}
+
+	s.login = login
+	s.items = make(map[string]client.Item)
+	s.cursor = 0
+}
+
 func (s *Storage) Clear(ctx context.Context) {
 	log.Printf("Clear")
 
-	s.mutex.RLock()
-	defer s.mutex.RUnlock()
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
 
+	s.login = ""
 	s.items = make(map[string]client.Item)
+	s.cursor = 0
 }
 
 // ListItems - возвращает копию списка предметов.
@@ -83,7 +103,7 @@ func (s *Storage) UpdateItem(ctx context.Context, ui *client.UpdateItem) error {
 		return client.ErrItemNotFound
 	}
 
-	updateItem(&i, ui)
+	client.ApplyUpdate(&i, ui)
 
 	s.items[ui.ID] = i
 
@@ -108,26 +128,24 @@ func (s *Storage) DeleteItem(ctx context.Context, id string) error {
 	return nil
 }
 
-func updateItem(i *client.Item, ui *client.UpdateItem) {
-	log.Printf("Start updateItem(%v)", i.ID)
-	if ui.RemoteID != nil {
-		log.Printf("Update(%v) RemoteID:%v", i.ID, *ui.RemoteID)
-		i.RemoteID = *ui.RemoteID
-	}
+// GetSyncCursor - возвращает курсор синхронизации.
+func (s *Storage) GetSyncCursor(ctx context.Context) (int64, error) {
+	log.Printf("Get sync cursor")
 
-	if ui.Body != nil {
-		log.Printf("Update(%v) Body", i.ID)
-		i.Body = *ui.Body
-	}
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
 
-	if ui.UpdateTime != nil {
-		log.Printf("Update(%v) UpdateTime:%v", i.ID, *ui.UpdateTime)
-		i.UpdateTime = *ui.UpdateTime
-	}
+	return s.cursor, nil
+}
 
-	if ui.DeleteMark != nil {
-		log.Printf("Update(%v) DeleteMark:%v", i.ID, *ui.DeleteMark)
-		i.DeleteMark = *ui.DeleteMark
-	}
-	log.Printf("End updateItem")
+// SetSyncCursor - сохраняет курсор синхронизации.
+func (s *Storage) SetSyncCursor(ctx context.Context, cursor int64) error {
+	log.Printf("Set sync cursor:%v", cursor)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.cursor = cursor
+
+	return nil
 }
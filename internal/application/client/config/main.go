@@ -19,7 +19,12 @@ type Config struct {
 	// LogFile - имя файл, куда будут писаться логи. По умолчанию не задан, в это случае логи пишутся в stdout.
 	// Задается через флаг `-log-file=<ЗНАЧЕНИЕ>` или переменную окружения `LOG_FILE=<ЗНАЧЕНИЕ>`.
 	LogFile string
-	// SecretKey - ключ с помощью которого шифруются/проверяются пароли пользователя при регистрации и логине.
+	// StorageFile - путь до файла локального хранилища предметов, к которому добавляется хэш логина пользователя.
+	// По умолчанию не задан, в этом случае предметы хранятся в памяти и теряются при перезапуске клиента.
+	// Задается через флаг `-storage-file=<ЗНАЧЕНИЕ>` или переменную окружения `STORAGE_FILE=<ЗНАЧЕНИЕ>`.
+	StorageFile string
+	// SecretKey - мастер-пароль, из которого выводится ключ шифрования предметов пользователя.
+	// По умолчанию не задан, в этом случае мастер-паролем выступает пароль пользователя.
 	// Задается через флаг `-secret-key=<ЗНАЧЕНИЕ>` или переменную окружения `SECRET_KEY=<ЗНАЧЕНИЕ>`.
 	SecretKey string
 	// RequestTimeout - таймаут обращения к серверу, в секундах.
@@ -28,6 +33,26 @@ type Config struct {
 	// SyncInterval - интервал синхронизации предметов между локальным и удаленным хранилищем, в секундах.
 	// Задается через флаг `-sync-interval=<ЗНАЧЕНИЕ>` или переменную окружения `SYNC_INTERVAL=<ЗНАЧЕНИЕ>`.
 	SyncInterval int
+	// TLSCA - путь до сертификатов центров сертификации сервера в формате PEM. По умолчанию не задан, в этом случае
+	// сертификат сервера проверяется системными сертификатами.
+	// Задается через флаг `-tls-ca=<ЗНАЧЕНИЕ>` или переменную окружения `TLS_CA=<ЗНАЧЕНИЕ>`.
+	TLSCA string
+	// TLSCert - путь до сертификата клиента в формате PEM, нужен если сервер требует mTLS.
+	// Задается через флаг `-tls-cert=<ЗНАЧЕНИЕ>` или переменную окружения `TLS_CERT=<ЗНАЧЕНИЕ>`.
+	TLSCert string
+	// TLSKey - путь до ключа сертификата клиента в формате PEM, задается вместе с TLSCert.
+	// Задается через флаг `-tls-key=<ЗНАЧЕНИЕ>` или переменную окружения `TLS_KEY=<ЗНАЧЕНИЕ>`.
+	TLSKey string
+	// TLSServerName - имя сервера для проверки его сертификата, если отличается от имени в адресе сервера.
+	// Задается через флаг `-tls-server-name=<ЗНАЧЕНИЕ>` или переменную окружения `TLS_SERVER_NAME=<ЗНАЧЕНИЕ>`.
+	TLSServerName string
+	// Insecure - разрешить соединение с сервером без TLS (по умолчанию false). Пароли и токены в этом случае
+	// передаются открытым текстом. Задается через флаг `-insecure` или переменную окружения `INSECURE=true`.
+	Insecure bool
+	// LegacyLogin - разрешить вход по паролю пользователям, зарегистрированным до появления SRP, если вход по SRP
+	// не удался (по умолчанию false). Пароль в этом случае передается на сервер.
+	// Задается через флаг `-legacy-login` или переменную окружения `LEGACY_LOGIN=true`.
+	LegacyLogin bool
 }
 
 var (
@@ -54,6 +79,17 @@ func New() (*Config, error) {
 	return &cfg, nil
 }
 
+// String - строковое представление конфигурации, без мастер-пароля.
+func (c *Config) String() string {
+	cc := *c
+	if cc.SecretKey != "" {
+		cc.SecretKey = "***"
+	}
+
+	type config Config // без метода String, чтобы не уйти в рекурсию
+	return fmt.Sprintf("%+v", config(cc))
+}
+
 func (c *Config) applyFromEnvAndArgs() error {
 	// From ENV
 	a, ok := os.LookupEnv("ADDRESS")
@@ -71,6 +107,11 @@ func (c *Config) applyFromEnvAndArgs() error {
 		c.LogFile = lf
 	}
 
+	sf, ok := os.LookupEnv("STORAGE_FILE")
+	if ok {
+		c.StorageFile = sf
+	}
+
 	sk, ok := os.LookupEnv("SECRET_KEY")
 	if ok {
 		c.SecretKey = sk
@@ -94,6 +135,44 @@ func (c *Config) applyFromEnvAndArgs() error {
 		c.SyncInterval = siInt
 	}
 
+	tca, ok := os.LookupEnv("TLS_CA")
+	if ok {
+		c.TLSCA = tca
+	}
+
+	tc, ok := os.LookupEnv("TLS_CERT")
+	if ok {
+		c.TLSCert = tc
+	}
+
+	tk, ok := os.LookupEnv("TLS_KEY")
+	if ok {
+		c.TLSKey = tk
+	}
+
+	tsn, ok := os.LookupEnv("TLS_SERVER_NAME")
+	if ok {
+		c.TLSServerName = tsn
+	}
+
+	in, ok := os.LookupEnv("INSECURE")
+	if ok {
+		inBool, err := strconv.ParseBool(in)
+		if err != nil {
+			return fmt.Errorf("INSECURE parse error:%w", err)
+		}
+		c.Insecure = inBool
+	}
+
+	lgl, ok := os.LookupEnv("LEGACY_LOGIN")
+	if ok {
+		lglBool, err := strconv.ParseBool(lgl)
+		if err != nil {
+			return fmt.Errorf("LEGACY_LOGIN parse error:%w", err)
+		}
+		c.LegacyLogin = lglBool
+	}
+
 	flag.StringVar(&c.Address, "address", c.Address, "GRPC endpoint сервера в формате host:port.")
 	flag.StringVar(&c.LogLevel, "log-level", c.LogLevel,
 		"Уровень логирования. Задается через флаг `-log-level=<ЗНАЧЕНИЕ>` или переменную окружения "+
@@ -101,8 +180,12 @@ func (c *Config) applyFromEnvAndArgs() error {
 	flag.StringVar(&c.LogFile, "log-file", c.LogFile,
 		"Файл логирования. Задается через флаг `-log-file=<ЗНАЧЕНИЕ>` или переменную окружения "+
 			"`LOG_FILE=<ЗНАЧЕНИЕ>.\nПо умолчанию не задан, в этом случае логи пишутся в stdout.")
+	flag.StringVar(&c.StorageFile, "storage-file", c.StorageFile,
+		"Файл локального хранилища предметов. Задается через флаг `-storage-file=<ЗНАЧЕНИЕ>` или переменную "+
+			"окружения `STORAGE_FILE=<ЗНАЧЕНИЕ>.\nПо умолчанию не задан, в этом случае предметы хранятся в памяти.")
 	flag.StringVar(&c.SecretKey, "secret-key", c.SecretKey,
-		"Ключ, с помощью которого шифруются/проверяются пароли пользователя при регистрации и логине."+
+		"Мастер-пароль, из которого выводится ключ шифрования предметов пользователя.\n"+
+			"По умолчанию не задан, в этом случае мастер-паролем выступает пароль пользователя.\n"+
 			"Задается через флаг `-secret-key=<ЗНАЧЕНИЕ>` или переменную окружения `SECRET_KEY=<ЗНАЧЕНИЕ>`")
 	flag.IntVar(&c.RequestTimeout, "request-timeout", c.RequestTimeout,
 		"Таймаут обращения к серверу, в секундах.\n"+
@@ -111,11 +194,35 @@ func (c *Config) applyFromEnvAndArgs() error {
 		"Интервал синхронизации элементов между локальным хранилищем и удаленным, в секундах.\n"+
 			"Задается через флаг `-sync-interval=<ЗНАЧЕНИЕ>` или переменную окружения `SYNC_INTERVAL=<ЗНАЧЕНИЕ>`")
 
+	flag.StringVar(&c.TLSCA, "tls-ca", c.TLSCA,
+		"Сертификаты центров сертификации сервера в формате PEM.\n"+
+			"По умолчанию не задан, в этом случае используются системные сертификаты.\n"+
+			"Задается через флаг `-tls-ca=<ЗНАЧЕНИЕ>` или переменную окружения `TLS_CA=<ЗНАЧЕНИЕ>`")
+	flag.StringVar(&c.TLSCert, "tls-cert", c.TLSCert,
+		"Сертификат клиента в формате PEM для mTLS.\n"+
+			"Задается через флаг `-tls-cert=<ЗНАЧЕНИЕ>` или переменную окружения `TLS_CERT=<ЗНАЧЕНИЕ>`")
+	flag.StringVar(&c.TLSKey, "tls-key", c.TLSKey,
+		"Ключ сертификата клиента в формате PEM для mTLS.\n"+
+			"Задается через флаг `-tls-key=<ЗНАЧЕНИЕ>` или переменную окружения `TLS_KEY=<ЗНАЧЕНИЕ>`")
+	flag.StringVar(&c.TLSServerName, "tls-server-name", c.TLSServerName,
+		"Имя сервера для проверки его сертификата, если отличается от имени в адресе сервера.\n"+
+			"Задается через флаг `-tls-server-name=<ЗНАЧЕНИЕ>` или переменную окружения `TLS_SERVER_NAME=<ЗНАЧЕНИЕ>`")
+	flag.BoolVar(&c.Insecure, "insecure", c.Insecure,
+		"Разрешить соединение с сервером без TLS, пароли и токены передаются открытым текстом.\n"+
+			"Задается через флаг `-insecure` или переменную окружения `INSECURE=true`")
+	flag.BoolVar(&c.LegacyLogin, "legacy-login", c.LegacyLogin,
+		"Разрешить вход по паролю пользователям, зарегистрированным до появления SRP, пароль передается на сервер.\n"+
+			"Задается через флаг `-legacy-login` или переменную окружения `LEGACY_LOGIN=true`")
+
 	flag.Parse()
 
 	if len(flag.Args()) != 0 {
 		return fmt.Errorf("unknown args:%v", flag.Args())
 	}
 
+	if c.Insecure && (c.TLSCA != "" || c.TLSCert != "" || c.TLSKey != "" || c.TLSServerName != "") {
+		return fmt.Errorf("tls options can not be used with insecure connection")
+	}
+
 	return nil
 }
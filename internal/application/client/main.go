@@ -3,6 +3,7 @@ package client
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"os/signal"
 	"sync"
@@ -12,12 +13,17 @@ import (
 	"github.com/k0st1a/gophkeeper/internal/adapters/api/grpc/client"
 	"github.com/k0st1a/gophkeeper/internal/adapters/api/tui"
 	tstorage "github.com/k0st1a/gophkeeper/internal/adapters/api/tui/storage"
+	"github.com/k0st1a/gophkeeper/internal/adapters/storage/file"
 	"github.com/k0st1a/gophkeeper/internal/adapters/storage/inmemory"
 	"github.com/k0st1a/gophkeeper/internal/application/client/config"
+	"github.com/k0st1a/gophkeeper/internal/pkg/client/keyring"
 	"github.com/k0st1a/gophkeeper/internal/pkg/job"
 	"github.com/k0st1a/gophkeeper/internal/pkg/logwrap"
 	itemsync "github.com/k0st1a/gophkeeper/internal/pkg/sync"
 	"github.com/k0st1a/gophkeeper/internal/pkg/tick"
+	"github.com/k0st1a/gophkeeper/internal/pkg/tlsconfig"
+	"github.com/k0st1a/gophkeeper/internal/pkg/watch"
+	pclient "github.com/k0st1a/gophkeeper/internal/ports/client"
 	"github.com/rs/zerolog/log"
 )
 
@@ -41,17 +47,47 @@ func Run() error {
 		return fmt.Errorf("logwrap create error:%w", err)
 	}
 
-	gc, err := client.New(cfg.Address, time.Duration(cfg.RequestTimeout)*time.Second)
+	k := keyring.New()
+
+	var tc *tls.Config
+	if cfg.Insecure {
+		log.Warn().Msg("Insecure connection to server, passwords and tokens are sent in plain text")
+	} else {
+		tc, err = tlsconfig.NewClient(cfg.TLSCA, cfg.TLSCert, cfg.TLSKey, cfg.TLSServerName)
+		if err != nil {
+			return fmt.Errorf("make tls config error:%w", err)
+		}
+	}
+
+	gc, err := client.New(cfg.Address, tc, time.Duration(cfg.RequestTimeout)*time.Second, k, cfg.SecretKey,
+		cfg.LegacyLogin)
 	if err != nil {
 		return fmt.Errorf("make grpc client error:%w", err)
 	}
 
-	s := inmemory.New()
+	var s pclient.ItemStorage
+	if cfg.StorageFile != "" {
+		fs, err := file.New(cfg.StorageFile, k)
+		if err != nil {
+			return fmt.Errorf("make file storage error:%w", err)
+		}
+		defer func() {
+			err := fs.Close()
+			if err != nil {
+				log.Error().Err(err).Msg("failed to close file storage")
+			}
+		}()
+		s = fs
+	} else {
+		s = inmemory.New()
+	}
 
 	is := itemsync.New(s, gc)
 	t := tick.New(is, time.Duration(cfg.SyncInterval)*time.Second)
 
-	j := job.New(t)
+	w := watch.New(gc, t, watch.MinBackoff, watch.MaxBackoff)
+
+	j := job.New(t, w)
 
 	ctx, cancel = context.WithCancel(ctx)
 
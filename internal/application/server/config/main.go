@@ -4,7 +4,12 @@ package config
 import (
 	"flag"
 	"fmt"
+	"math"
 	"os"
+	"strconv"
+	"time"
+
+	"github.com/k0st1a/gophkeeper/internal/pkg/auth"
 )
 
 // Config - структура с конфигурационными параметрами сервера.
@@ -24,12 +29,75 @@ type Config struct {
 	// SecretKey - ключ с помощью которого шифруются/проверяются пароли пользователя при регистрации и логине.
 	// Задается через флаг `-secret-key=<ЗНАЧЕНИЕ>` или переменную окружения `SECRET_KEY=<ЗНАЧЕНИЕ>`.
 	SecretKey string
+	// SigningKeys - путь до файла ключа подписи токенов или каталога с файлами ключей `<kid>.pem` (Ed25519,
+	// ECDSA P-256 или HMAC). По умолчанию не задан, в этом случае токены подписываются ключом SecretKey.
+	// Задается через флаг `-signing-keys=<ЗНАЧЕНИЕ>` или переменную окружения `SIGNING_KEYS=<ЗНАЧЕНИЕ>`.
+	SigningKeys string
+	// SigningKeyGrace - время после появления нового ключа подписи, в течение которого принимаются токены,
+	// подписанные прошлыми ключами (по умолчанию 1h). Задается через флаг `-signing-key-grace=<ЗНАЧЕНИЕ>`
+	// или переменную окружения `SIGNING_KEY_GRACE=<ЗНАЧЕНИЕ>`.
+	SigningKeyGrace time.Duration
+	// HistoryRetention - количество хранимых прошлых версий каждого предмета, если пользователь не задал свое
+	// (по умолчанию 10). Задается через флаг `-history-retention=<ЗНАЧЕНИЕ>` или переменную окружения
+	// `HISTORY_RETENTION=<ЗНАЧЕНИЕ>`.
+	HistoryRetention int
+	// TrashRetention - срок хранения предметов в корзине, после которого они удаляются окончательно
+	// (по умолчанию 720h). Задается через флаг `-trash-retention=<ЗНАЧЕНИЕ>` или переменную окружения
+	// `TRASH_RETENTION=<ЗНАЧЕНИЕ>`.
+	TrashRetention time.Duration
+	// BlobTTL - срок, за который блоб должен быть загружен и привязан к предмету, после чего незагруженные блобы
+	// и блобы без ссылок из предметов и их прошлых версий удаляются (по умолчанию 24h).
+	// Задается через флаг `-blob-ttl=<ЗНАЧЕНИЕ>` или переменную окружения `BLOB_TTL=<ЗНАЧЕНИЕ>`.
+	BlobTTL time.Duration
+	// BlobQuota - максимальный суммарный размер блобов пользователя в байтах, 0 - без ограничения
+	// (по умолчанию 10737418240, 10GB). Задается через флаг `-blob-quota=<ЗНАЧЕНИЕ>` или переменную окружения
+	// `BLOB_QUOTA=<ЗНАЧЕНИЕ>`.
+	BlobQuota int64
+	// RefreshTokenTTL - срок действия refresh токена, выданного при логине или обновлении токена (по умолчанию 720h).
+	// Задается через флаг `-refresh-token-ttl=<ЗНАЧЕНИЕ>` или переменную окружения `REFRESH_TOKEN_TTL=<ЗНАЧЕНИЕ>`.
+	RefreshTokenTTL time.Duration
+	// TLSCert - путь до сертификата сервера в формате PEM. По умолчанию не задан, в этом случае сервер принимает
+	// соединения без TLS. Задается через флаг `-tls-cert=<ЗНАЧЕНИЕ>` или переменную окружения `TLS_CERT=<ЗНАЧЕНИЕ>`.
+	TLSCert string
+	// TLSKey - путь до ключа сертификата сервера в формате PEM, задается вместе с TLSCert.
+	// Задается через флаг `-tls-key=<ЗНАЧЕНИЕ>` или переменную окружения `TLS_KEY=<ЗНАЧЕНИЕ>`.
+	TLSKey string
+	// TLSClientCA - путь до сертификатов центров сертификации клиентов в формате PEM. Если задан, то сервер требует
+	// от клиентов сертификат, подписанный одним из них (mTLS).
+	// Задается через флаг `-tls-client-ca=<ЗНАЧЕНИЕ>` или переменную окружения `TLS_CLIENT_CA=<ЗНАЧЕНИЕ>`.
+	TLSClientCA string
+	// AdminToken - токен доступа к сервису администрирования, передается в метаданных `admin-token`.
+	// По умолчанию не задан, в этом случае сервис администрирования выключен.
+	// Задается через флаг `-admin-token=<ЗНАЧЕНИЕ>` или переменную окружения `ADMIN_TOKEN=<ЗНАЧЕНИЕ>`.
+	AdminToken string
+	// Argon2Time - количество проходов Argon2id при хэшировании паролей (по умолчанию 3).
+	// Задается через флаг `-argon2-time=<ЗНАЧЕНИЕ>` или переменную окружения `ARGON2_TIME=<ЗНАЧЕНИЕ>`.
+	Argon2Time int
+	// Argon2Memory - объем памяти Argon2id в KiB при хэшировании паролей (по умолчанию 65536, не больше 1048576).
+	// Задается через флаг `-argon2-memory=<ЗНАЧЕНИЕ>` или переменную окружения `ARGON2_MEMORY=<ЗНАЧЕНИЕ>`.
+	Argon2Memory int
+	// Argon2Threads - количество потоков Argon2id при хэшировании паролей (по умолчанию 4).
+	// Задается через флаг `-argon2-threads=<ЗНАЧЕНИЕ>` или переменную окружения `ARGON2_THREADS=<ЗНАЧЕНИЕ>`.
+	// При изменении любого из параметров Argon2id хэши паролей пересчитываются при следующем входе.
+	Argon2Threads int
 }
 
 var (
 	defaultAddress     = "localhost:8080"
 	defaultHTTPAddress = ""
 	defaultLogLevel    = "info"
+
+	defaultHistoryRetention = 10
+	defaultTrashRetention   = 30 * 24 * time.Hour
+	defaultBlobTTL          = 24 * time.Hour
+	defaultBlobQuota        = int64(10 * 1024 * 1024 * 1024)
+	defaultRefreshTokenTTL  = 30 * 24 * time.Hour
+	defaultSigningKeyGrace  = time.Hour
+
+	// Параметры Argon2id, рекомендованные RFC 9106 при ограниченной памяти.
+	defaultArgon2Time    = 3
+	defaultArgon2Memory  = 64 * 1024
+	defaultArgon2Threads = 4
 )
 
 // New - создать конфигурацию сервера из аргументов командой строки и переменных окружения.
@@ -38,6 +106,17 @@ func New() (*Config, error) {
 		Address:     defaultAddress,
 		HTTPAddress: defaultHTTPAddress,
 		LogLevel:    defaultLogLevel,
+
+		HistoryRetention: defaultHistoryRetention,
+		TrashRetention:   defaultTrashRetention,
+		BlobTTL:          defaultBlobTTL,
+		BlobQuota:        defaultBlobQuota,
+		RefreshTokenTTL:  defaultRefreshTokenTTL,
+		SigningKeyGrace:  defaultSigningKeyGrace,
+
+		Argon2Time:    defaultArgon2Time,
+		Argon2Memory:  defaultArgon2Memory,
+		Argon2Threads: defaultArgon2Threads,
 	}
 
 	err := cfg.applyFromEnvAndArgs()
@@ -48,6 +127,21 @@ func New() (*Config, error) {
 	return &cfg, nil
 }
 
+// String - строковое представление конфигурации, без секретного ключа и токена администратора.
+func (c *Config) String() string {
+	cc := *c
+	if cc.SecretKey != "" {
+		cc.SecretKey = "***"
+	}
+
+	if cc.AdminToken != "" {
+		cc.AdminToken = "***"
+	}
+
+	type config Config // без метода String, чтобы не уйти в рекурсию
+	return fmt.Sprintf("%+v", config(cc))
+}
+
 func (c *Config) applyFromEnvAndArgs() error {
 	// From ENV
 	a, ok := os.LookupEnv("ADDRESS")
@@ -75,6 +169,112 @@ func (c *Config) applyFromEnvAndArgs() error {
 		c.SecretKey = sk
 	}
 
+	sks, ok := os.LookupEnv("SIGNING_KEYS")
+	if ok {
+		c.SigningKeys = sks
+	}
+
+	skg, ok := os.LookupEnv("SIGNING_KEY_GRACE")
+	if ok {
+		v, err := time.ParseDuration(skg)
+		if err != nil {
+			return fmt.Errorf("signing key grace parse error:%w", err)
+		}
+		c.SigningKeyGrace = v
+	}
+
+	hr, ok := os.LookupEnv("HISTORY_RETENTION")
+	if ok {
+		v, err := strconv.Atoi(hr)
+		if err != nil {
+			return fmt.Errorf("history retention parse error:%w", err)
+		}
+		c.HistoryRetention = v
+	}
+
+	tr, ok := os.LookupEnv("TRASH_RETENTION")
+	if ok {
+		v, err := time.ParseDuration(tr)
+		if err != nil {
+			return fmt.Errorf("trash retention parse error:%w", err)
+		}
+		c.TrashRetention = v
+	}
+
+	bt, ok := os.LookupEnv("BLOB_TTL")
+	if ok {
+		v, err := time.ParseDuration(bt)
+		if err != nil {
+			return fmt.Errorf("blob ttl parse error:%w", err)
+		}
+		c.BlobTTL = v
+	}
+
+	bq, ok := os.LookupEnv("BLOB_QUOTA")
+	if ok {
+		v, err := strconv.ParseInt(bq, 10, 64)
+		if err != nil {
+			return fmt.Errorf("blob quota parse error:%w", err)
+		}
+		c.BlobQuota = v
+	}
+
+	rtt, ok := os.LookupEnv("REFRESH_TOKEN_TTL")
+	if ok {
+		v, err := time.ParseDuration(rtt)
+		if err != nil {
+			return fmt.Errorf("refresh token ttl parse error:%w", err)
+		}
+		c.RefreshTokenTTL = v
+	}
+
+	tc, ok := os.LookupEnv("TLS_CERT")
+	if ok {
+		c.TLSCert = tc
+	}
+
+	tk, ok := os.LookupEnv("TLS_KEY")
+	if ok {
+		c.TLSKey = tk
+	}
+
+	tca, ok := os.LookupEnv("TLS_CLIENT_CA")
+	if ok {
+		c.TLSClientCA = tca
+	}
+
+	at, ok := os.LookupEnv("ADMIN_TOKEN")
+	if ok {
+		c.AdminToken = at
+	}
+
+	a2t, ok := os.LookupEnv("ARGON2_TIME")
+	if ok {
+		v, err := strconv.Atoi(a2t)
+		if err != nil {
+			return fmt.Errorf("argon2 time parse error:%w", err)
+		}
+		c.Argon2Time = v
+	}
+
+	a2m, ok := os.LookupEnv("ARGON2_MEMORY")
+	if ok {
+		v, err := strconv.Atoi(a2m)
+		if err != nil {
+			return fmt.Errorf("argon2 memory parse error:%w", err)
+		}
+		c.Argon2Memory = v
+	}
+
+	a2p, ok := os.LookupEnv("ARGON2_THREADS")
+	if ok {
+		v, err := strconv.Atoi(a2p)
+		if err != nil {
+			return fmt.Errorf("argon2 threads parse error:%w", err)
+		}
+		c.Argon2Threads = v
+	}
+
 	flag.StringVar(&c.Address, "address", c.Address, "GRPC endpoint сервера в формате host:port.")
 	flag.StringVar(&c.HTTPAddress, "http-address", c.HTTPAddress, "HTTP endpoint сервера в формате host:port.")
 	flag.StringVar(&c.LogLevel, "log-level", c.LogLevel,
@@ -85,6 +285,49 @@ func (c *Config) applyFromEnvAndArgs() error {
 	flag.StringVar(&c.SecretKey, "secret-key", c.SecretKey,
 		"Ключ, с помощью которого шифруются/проверяются пароли пользователя при регистрации и логине."+
 			"Задается через флаг `-secret-key=<ЗНАЧЕНИЕ>` или переменную окружения `SECRET_KEY=<ЗНАЧЕНИЕ>`")
+	flag.StringVar(&c.SigningKeys, "signing-keys", c.SigningKeys,
+		"Файл ключа подписи токенов или каталог с файлами ключей `<kid>.pem`. "+
+			"Задается через флаг `-signing-keys=<ЗНАЧЕНИЕ>` или переменную окружения `SIGNING_KEYS=<ЗНАЧЕНИЕ>`")
+	flag.DurationVar(&c.SigningKeyGrace, "signing-key-grace", c.SigningKeyGrace,
+		"Время, в течение которого принимаются токены, подписанные прошлыми ключами. "+
+			"Задается через флаг `-signing-key-grace=<ЗНАЧЕНИЕ>` или переменную окружения `SIGNING_KEY_GRACE=<ЗНАЧЕНИЕ>`")
+	flag.IntVar(&c.HistoryRetention, "history-retention", c.HistoryRetention,
+		"Количество хранимых прошлых версий каждого предмета, если пользователь не задал свое. "+
+			"Задается через флаг `-history-retention=<ЗНАЧЕНИЕ>` или переменную окружения `HISTORY_RETENTION=<ЗНАЧЕНИЕ>`")
+	flag.DurationVar(&c.TrashRetention, "trash-retention", c.TrashRetention,
+		"Срок хранения предметов в корзине, после которого они удаляются окончательно. "+
+			"Задается через флаг `-trash-retention=<ЗНАЧЕНИЕ>` или переменную окружения `TRASH_RETENTION=<ЗНАЧЕНИЕ>`")
+	flag.DurationVar(&c.BlobTTL, "blob-ttl", c.BlobTTL,
+		"Срок, после которого удаляются незагруженные блобы и блобы без ссылок из предметов. "+
+			"Задается через флаг `-blob-ttl=<ЗНАЧЕНИЕ>` или переменную окружения `BLOB_TTL=<ЗНАЧЕНИЕ>`")
+	flag.Int64Var(&c.BlobQuota, "blob-quota", c.BlobQuota,
+		"Максимальный суммарный размер блобов пользователя в байтах, 0 - без ограничения. "+
+			"Задается через флаг `-blob-quota=<ЗНАЧЕНИЕ>` или переменную окружения `BLOB_QUOTA=<ЗНАЧЕНИЕ>`")
+
+	flag.DurationVar(&c.RefreshTokenTTL, "refresh-token-ttl", c.RefreshTokenTTL,
+		"Срок действия refresh токена. "+
+			"Задается через флаг `-refresh-token-ttl=<ЗНАЧЕНИЕ>` или переменную окружения `REFRESH_TOKEN_TTL=<ЗНАЧЕНИЕ>`")
+	flag.StringVar(&c.TLSCert, "tls-cert", c.TLSCert,
+		"Сертификат сервера в формате PEM. По умолчанию не задан, в этом случае соединения принимаются без TLS. "+
+			"Задается через флаг `-tls-cert=<ЗНАЧЕНИЕ>` или переменную окружения `TLS_CERT=<ЗНАЧЕНИЕ>`")
+	flag.StringVar(&c.TLSKey, "tls-key", c.TLSKey,
+		"Ключ сертификата сервера в формате PEM. "+
+			"Задается через флаг `-tls-key=<ЗНАЧЕНИЕ>` или переменную окружения `TLS_KEY=<ЗНАЧЕНИЕ>`")
+	flag.StringVar(&c.TLSClientCA, "tls-client-ca", c.TLSClientCA,
+		"Сертификаты центров сертификации клиентов в формате PEM, если задан, то включается mTLS. "+
+			"Задается через флаг `-tls-client-ca=<ЗНАЧЕНИЕ>` или переменную окружения `TLS_CLIENT_CA=<ЗНАЧЕНИЕ>`")
+	flag.StringVar(&c.AdminToken, "admin-token", c.AdminToken,
+		"Токен доступа к сервису администрирования, по умолчанию не задан и сервис выключен. "+
+			"Задается через флаг `-admin-token=<ЗНАЧЕНИЕ>` или переменную окружения `ADMIN_TOKEN=<ЗНАЧЕНИЕ>`")
+	flag.IntVar(&c.Argon2Time, "argon2-time", c.Argon2Time,
+		"Количество проходов Argon2id при хэшировании паролей. "+
+			"Задается через флаг `-argon2-time=<ЗНАЧЕНИЕ>` или переменную окружения `ARGON2_TIME=<ЗНАЧЕНИЕ>`")
+	flag.IntVar(&c.Argon2Memory, "argon2-memory", c.Argon2Memory,
+		"Объем памяти Argon2id в KiB при хэшировании паролей. "+
+			"Задается через флаг `-argon2-memory=<ЗНАЧЕНИЕ>` или переменную окружения `ARGON2_MEMORY=<ЗНАЧЕНИЕ>`")
+	flag.IntVar(&c.Argon2Threads, "argon2-threads", c.Argon2Threads,
+		"Количество потоков Argon2id при хэшировании паролей. "+
+			"Задается через флаг `-argon2-threads=<ЗНАЧЕНИЕ>` или переменную окружения `ARGON2_THREADS=<ЗНАЧЕНИЕ>`")
 
 	flag.Parse()
 
@@ -92,5 +335,45 @@ func (c *Config) applyFromEnvAndArgs() error {
 		return fmt.Errorf("unknown args:%v", flag.Args())
 	}
 
+	if c.HistoryRetention < 0 {
+		return fmt.Errorf("history retention must not be negative:%v", c.HistoryRetention)
+	}
+
+	if c.TrashRetention < 0 {
+		return fmt.Errorf("trash retention must not be negative:%v", c.TrashRetention)
+	}
+
+	if c.BlobTTL <= 0 {
+		return fmt.Errorf("blob ttl must be positive:%v", c.BlobTTL)
+	}
+
+	if c.BlobQuota < 0 {
+		return fmt.Errorf("blob quota must not be negative:%v", c.BlobQuota)
+	}
+
+	if c.RefreshTokenTTL <= 0 {
+		return fmt.Errorf("refresh token ttl must be positive:%v", c.RefreshTokenTTL)
+	}
+
+	if c.SigningKeyGrace < 0 {
+		return fmt.Errorf("signing key grace must not be negative:%v", c.SigningKeyGrace)
+	}
+
+	if c.Argon2Time <= 0 || c.Argon2Memory <= 0 || c.Argon2Threads <= 0 || c.Argon2Threads > math.MaxUint8 {
+		return fmt.Errorf("argon2 params must be positive and threads at most %v", math.MaxUint8)
+	}
+
+	if c.Argon2Memory > auth.MaxArgon2Memory {
+		return fmt.Errorf("argon2 memory must be at most %v KiB", auth.MaxArgon2Memory)
+	}
+
+	if (c.TLSCert == "") != (c.TLSKey == "") {
+		return fmt.Errorf("tls cert and tls key must be set together")
+	}
+
+	if c.TLSClientCA != "" && c.TLSCert == "" {
+		return fmt.Errorf("tls client ca requires tls cert and tls key")
+	}
+
 	return nil
 }
@@ -4,6 +4,7 @@ import (
 	"flag"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -32,12 +33,40 @@ func TestConfigFromEnv(t *testing.T) {
 			name: "Check config from env",
 			args: []string{""},
 			env: map[string]string{
-				"ADDRESS":   "localhost:8080",
-				"LOG_LEVEL": "LOG_LEVEL_FROM_ENV",
+				"ADDRESS":           "localhost:8080",
+				"LOG_LEVEL":         "LOG_LEVEL_FROM_ENV",
+				"HISTORY_RETENTION": "5",
+				"TRASH_RETENTION":   "48h",
+				"REFRESH_TOKEN_TTL": "24h",
+				"TLS_CERT":          "server.crt",
+				"TLS_KEY":           "server.key",
+				"TLS_CLIENT_CA":     "ca.crt",
+				"ADMIN_TOKEN":       "admin",
+				"SIGNING_KEYS":      "keys",
+				"SIGNING_KEY_GRACE": "2h",
+				"ARGON2_TIME":       "2",
+				"ARGON2_MEMORY":     "1024",
+				"ARGON2_THREADS":    "1",
+				"BLOB_TTL":          "12h",
+				"BLOB_QUOTA":        "1024",
 			},
 			cfg: Config{
-				Address:  "localhost:8080",
-				LogLevel: "LOG_LEVEL_FROM_ENV",
+				Address:          "localhost:8080",
+				LogLevel:         "LOG_LEVEL_FROM_ENV",
+				HistoryRetention: 5,
+				TrashRetention:   48 * time.Hour,
+				BlobTTL:          12 * time.Hour,
+				BlobQuota:        1024,
+				RefreshTokenTTL:  24 * time.Hour,
+				TLSCert:          "server.crt",
+				TLSKey:           "server.key",
+				TLSClientCA:      "ca.crt",
+				AdminToken:       "admin",
+				SigningKeys:      "keys",
+				SigningKeyGrace:  2 * time.Hour,
+				Argon2Time:       2,
+				Argon2Memory:     1024,
+				Argon2Threads:    1,
 			},
 		},
 	}
@@ -86,10 +115,25 @@ func TestConfigFromFlags(t *testing.T) {
 				"cmd",
 				"-address", "localhost:8081",
 				"-log-level", "LOG_LEVEL_FROM_FLAG",
+				"-history-retention", "0",
+				"-trash-retention", "1h",
+				"-blob-ttl", "2h",
+				"-blob-quota", "0",
+				"-tls-cert", "server.crt",
+				"-tls-key", "server.key",
 			},
 			cfg: Config{
-				Address:  "localhost:8081",
-				LogLevel: "LOG_LEVEL_FROM_FLAG",
+				Address:         "localhost:8081",
+				LogLevel:        "LOG_LEVEL_FROM_FLAG",
+				TrashRetention:  time.Hour,
+				BlobTTL:         2 * time.Hour,
+				RefreshTokenTTL: defaultRefreshTokenTTL,
+				SigningKeyGrace: defaultSigningKeyGrace,
+				TLSCert:         "server.crt",
+				TLSKey:          "server.key",
+				Argon2Time:      defaultArgon2Time,
+				Argon2Memory:    defaultArgon2Memory,
+				Argon2Threads:   defaultArgon2Threads,
 			},
 		},
 	}
@@ -142,8 +186,17 @@ func TestConfig(t *testing.T) {
 				"-log-level", "LOG_LEVEL_FROM_FLAG",
 			},
 			cfg: Config{
-				Address:  "localhost:8081",
-				LogLevel: "LOG_LEVEL_FROM_FLAG",
+				Address:          "localhost:8081",
+				LogLevel:         "LOG_LEVEL_FROM_FLAG",
+				HistoryRetention: defaultHistoryRetention,
+				TrashRetention:   defaultTrashRetention,
+				BlobTTL:          defaultBlobTTL,
+				BlobQuota:        defaultBlobQuota,
+				RefreshTokenTTL:  defaultRefreshTokenTTL,
+				SigningKeyGrace:  defaultSigningKeyGrace,
+				Argon2Time:       defaultArgon2Time,
+				Argon2Memory:     defaultArgon2Memory,
+				Argon2Threads:    defaultArgon2Threads,
 			},
 		},
 	}
@@ -166,3 +219,16 @@ func TestConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestConfigString(t *testing.T) {
+	cfg := Config{
+		Address:    "localhost:8080",
+		SecretKey:  "secret",
+		AdminToken: "admin",
+	}
+
+	s := cfg.String()
+	assert.Contains(t, s, "localhost:8080")
+	assert.NotContains(t, s, "secret")
+	assert.NotContains(t, s, "admin")
+}
@@ -6,21 +6,29 @@ import (
 	"fmt"
 	"os/signal"
 	"syscall"
+	"time"
 
+	"github.com/k0st1a/gophkeeper/internal/adapters/api/gateway"
 	grpcserver "github.com/k0st1a/gophkeeper/internal/adapters/api/grpc/server"
 	"github.com/k0st1a/gophkeeper/internal/adapters/storage/db"
 	"github.com/k0st1a/gophkeeper/internal/application/server/config"
 	"github.com/k0st1a/gophkeeper/internal/pkg/auth"
+	"github.com/k0st1a/gophkeeper/internal/pkg/broker"
+	"github.com/k0st1a/gophkeeper/internal/pkg/emergency"
 	"github.com/k0st1a/gophkeeper/internal/pkg/logwrap"
+	"github.com/k0st1a/gophkeeper/internal/pkg/purge"
 	"github.com/rs/zerolog/log"
 )
 
+// shutdownTimeout - время на завершение HTTP запросов при выключении сервера.
+const shutdownTimeout = 5 * time.Second
+
 func Run() error {
 	cfg, err := config.New()
 	if err != nil {
 		return fmt.Errorf("config create error:%w", err)
 	}
-	log.Printf("Cfg:%+v", cfg)
+	log.Printf("Cfg:%v", cfg)
 
 	ctx, cancelFunc := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT, syscall.SIGQUIT)
 	defer cancelFunc()
@@ -30,19 +38,76 @@ func Run() error {
 		return fmt.Errorf("logwrap create error:%w", err)
 	}
 
-	db, err := db.NewDB(ctx, cfg.DatabaseURI)
+	db, err := db.NewDB(ctx, cfg.DatabaseURI, cfg.HistoryRetention)
 	if err != nil {
 		return fmt.Errorf("failed to create db:%w", err)
 	}
 	defer db.Close()
 
-	auth := auth.New(cfg.SecretKey)
+	ks := auth.NewKeySet(cfg.SecretKey)
+	if cfg.SigningKeys != "" {
+		ks, err = auth.LoadKeySet(cfg.SigningKeys, cfg.SecretKey, cfg.SigningKeyGrace)
+		if err != nil {
+			return fmt.Errorf("load signing keys error:%w", err)
+		}
+	}
+
+	//nolint:gosec // параметры Argon2id проверены при чтении конфигурации
+	auth := auth.New(cfg.SecretKey, ks, auth.NewArgon2Hasher(auth.Argon2Params{
+		Time:    uint32(cfg.Argon2Time),
+		Memory:  uint32(cfg.Argon2Memory),
+		Threads: uint8(cfg.Argon2Threads),
+	}))
+
+	b := broker.New()
+
+	tc, err := grpcserver.TLSConfig(cfg)
+	if err != nil {
+		return fmt.Errorf("make tls config error:%w", err)
+	}
 
-	srv, err := grpcserver.New(cfg, db, auth, db)
+	srv, err := grpcserver.New(cfg, tc, db, db, db, db, db, db, auth, db, db, db, b, db, db, db, db)
 	if err != nil {
 		return fmt.Errorf("make grpc server error:%w", err)
 	}
 
+	var gw *gateway.Server
+	if cfg.HTTPAddress != "" {
+		cc, err := srv.Dial()
+		if err != nil {
+			return fmt.Errorf("make grpc connection of http gateway error:%w", err)
+		}
+		defer cc.Close() //nolint:errcheck // on shutdown
+
+		gw, err = gateway.New(ctx, cfg.HTTPAddress, cc, tc)
+		if err != nil {
+			return fmt.Errorf("make http gateway error:%w", err)
+		}
+
+		go func() {
+			err := gw.Run()
+			if err != nil {
+				log.Error().Err(err).Msg("failed to run http gateway")
+			}
+		}()
+	}
+
+	p := purge.New(db, cfg.TrashRetention, cfg.BlobTTL, purge.Interval)
+	go func() {
+		err := p.Run(ctx)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to run trash purge")
+		}
+	}()
+
+	e := emergency.New(db, emergency.Interval)
+	go func() {
+		err := e.Run(ctx)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to run emergency access approve")
+		}
+	}()
+
 	go func() {
 		err := srv.Run()
 		if err != nil {
@@ -52,6 +117,18 @@ func Run() error {
 
 	<-ctx.Done()
 
+	// Потоки уведомлений не завершаются сами, без этого graceful выключение зависнет.
+	b.Close()
+
+	if gw != nil {
+		sctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		err = gw.Shutdown(sctx)
+		cancel()
+		if err != nil {
+			log.Error().Err(err).Msg("error of shutdown http gateway")
+		}
+	}
+
 	err = srv.Shutdown()
 	if err != nil {
 		log.Error().Err(err).Msg("error of shutdown server")
@@ -1,32 +1,50 @@
 package auth
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt"
-	"golang.org/x/crypto/bcrypt"
 )
 
 type UserAuthentication interface {
-	GenerateToken(userID int64) (string, error)
-	GetUserID(token string) (int64, error)
+	// GenerateToken - создать токен пользователя в сессии sessionID, идентификатор сессии передается в jti.
+	GenerateToken(userID int64, sessionID string) (string, error)
+	// ParseToken - проверить токен и получить из него пользователя и сессию.
+	ParseToken(token string) (*Claims, error)
 	GeneratePasswordHash(password string) (string, error)
-	CheckPasswordHash(password, hash string) error
+	// CheckPasswordHash - проверить пароль по хэшу, rehash=true означает, что хэш устарел
+	// и его нужно заменить новым из GeneratePasswordHash.
+	CheckPasswordHash(password, hash string) (rehash bool, err error)
+	// FakeSRPSalt - соль верификатора для входа по SRP незарегистрированного логина. Для одного логина соль
+	// всегда одна и та же, чтобы по ответу сервера нельзя было узнать, зарегистрирован ли логин.
+	FakeSRPSalt(login string) []byte
 }
 
 const (
-	tokenTTL    = 1 * time.Hour
-	hashingCost = 10
+	tokenTTL = 1 * time.Hour
+	// fakeSaltSize - размер соли верификатора SRP, как у соли клиента.
+	fakeSaltSize = 16
 )
 
 type auth struct {
+	hasher    PasswordHasher
+	keys      *KeySet
 	secretKey string
 	tokenTTL  time.Duration
 }
 
-func New(secretKey string) *auth {
+// New - создать аутентификацию пользователей, где:
+//   - secretKey - секретный ключ сервера, из которого выводятся фиктивные соли SRP;
+//   - ks - ключи подписи токенов;
+//   - h - хэширование паролей пользователей.
+func New(secretKey string, ks *KeySet, h PasswordHasher) *auth {
 	return &auth{
+		hasher:    h,
+		keys:      ks,
 		secretKey: secretKey,
 		tokenTTL:  tokenTTL,
 	}
@@ -37,53 +55,59 @@ type Claims struct {
 	UserID int64 `json:"user_id"`
 }
 
-func (a *auth) GenerateToken(userID int64) (string, error) {
+func (a *auth) GenerateToken(userID int64, sessionID string) (string, error) {
 	claims := Claims{
 		jwt.StandardClaims{
 			ExpiresAt: time.Now().Add(a.tokenTTL).Unix(),
 			IssuedAt:  time.Now().Unix(),
+			Id:        sessionID,
 		},
 		userID,
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-
-	signedToken, err := token.SignedString([]byte(a.secretKey))
+	signedToken, err := a.keys.sign(claims)
 	if err != nil {
 		return "", fmt.Errorf("failed to sign token: %w", err)
 	}
 	return signedToken, nil
 }
 
-func (a *auth) GetUserID(tokenString string) (int64, error) {
+func (a *auth) ParseToken(tokenString string) (*Claims, error) {
 	claims := &Claims{}
-	token, err := jwt.ParseWithClaims(tokenString, claims,
-		func(t *jwt.Token) (interface{}, error) {
-			if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
-			}
-			return []byte(a.secretKey), nil
-		})
+	token, err := jwt.ParseWithClaims(tokenString, claims, a.keys.keyFunc)
 	if err != nil {
-		return 0, fmt.Errorf("failed to parse token with claims, %w", err)
+		// ValidationError не поддерживает errors.Is, поэтому оборачивается исходная ошибка.
+		var ve *jwt.ValidationError
+		if errors.As(err, &ve) && ve.Inner != nil {
+			err = ve.Inner
+		}
+
+		return nil, fmt.Errorf("failed to parse token with claims, %w", err)
 	}
 
 	if !token.Valid {
-		return 0, fmt.Errorf("token not valid")
+		return nil, fmt.Errorf("token not valid")
 	}
 
-	return claims.UserID, nil
+	return claims, nil
 }
 
 func (a *auth) GeneratePasswordHash(password string) (string, error) {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), hashingCost)
+	hash, err := a.hasher.Hash(password)
 	if err != nil {
 		return "", fmt.Errorf("failed to generate from password, %w", err)
 	}
-	return string(bytes), nil
+	return hash, nil
+}
+
+func (a *auth) FakeSRPSalt(login string) []byte {
+	mac := hmac.New(sha256.New, []byte(a.secretKey))
+	mac.Write([]byte("srp salt:" + login))
+
+	return mac.Sum(nil)[:fakeSaltSize]
 }
 
-func (a *auth) CheckPasswordHash(password, hash string) error {
+func (a *auth) CheckPasswordHash(password, hash string) (bool, error) {
 	//nolint // Не за чем оборачивать ошибку
-	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	return a.hasher.Check(password, hash)
 }
@@ -0,0 +1,231 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+var (
+	ErrNoSigningKeys     = errors.New("no signing keys found")
+	ErrBadSigningKey     = errors.New("unsupported signing key")
+	ErrUnknownSigningKey = errors.New("unknown or retired signing key")
+)
+
+const (
+	// keyFileExt - расширение файлов ключей подписи в каталоге.
+	keyFileExt = ".pem"
+	// hmacKeyType - тип PEM блока с ключом HMAC.
+	hmacKeyType = "HMAC KEY"
+	// minHMACKeySize - минимальный размер ключа HMAC, как у выхода SHA-256.
+	minHMACKeySize = 32
+	// kidHeader - заголовок токена с идентификатором ключа подписи.
+	kidHeader = "kid"
+)
+
+type signingKey struct {
+	method jwt.SigningMethod
+	sign   interface{}
+	verify interface{}
+	// Время, после которого ключ не принимается при проверке токенов, нулевое - без ограничения
+	expire time.Time
+}
+
+// KeySet - ключи подписи токенов. Токены подписываются текущим ключом, его идентификатор передается
+// в заголовке `kid`. Прошлые ключи принимаются при проверке токенов в течение grace периода.
+type KeySet struct {
+	keys    map[string]*signingKey
+	current string
+}
+
+// NewKeySet - набор из одного ключа HMAC secretKey. Токены подписываются без заголовка `kid`,
+// как до появления ротации ключей.
+func NewKeySet(secretKey string) *KeySet {
+	return &KeySet{
+		keys: map[string]*signingKey{
+			"": newHMACKey([]byte(secretKey)),
+		},
+	}
+}
+
+// LoadKeySet - загрузить ключи подписи, где:
+//   - path - файл ключа или каталог с файлами ключей `<kid>.pem`, идентификатор ключа - имя файла
+//     без расширения. Поддерживаются ключи Ed25519 и ECDSA P-256 (PKCS #8 или SEC 1) и ключи HMAC
+//     в PEM блоке `HMAC KEY`. Текущим ключом подписи выбирается ключ с наибольшим идентификатором,
+//     поэтому в качестве идентификатора удобно брать дату создания ключа, например `2024-10-01.pem`;
+//   - secretKey - ключ HMAC, которым подписывались токены без `kid` до появления файлов ключей,
+//     если пустой, то такие токены не принимаются;
+//   - grace - время после появления текущего ключа (времени изменения его файла), в течение которого
+//     принимаются токены, подписанные прошлыми ключами.
+func LoadKeySet(path, secretKey string, grace time.Duration) (*KeySet, error) {
+	files, err := keyFiles(path)
+	if err != nil {
+		return nil, err
+	}
+
+	ks := &KeySet{keys: make(map[string]*signingKey, len(files)+1)}
+
+	var currentFile string
+	for _, f := range files {
+		kid := strings.TrimSuffix(filepath.Base(f), filepath.Ext(f))
+
+		k, err := loadKey(f)
+		if err != nil {
+			return nil, fmt.Errorf("error of load signing key %q:%w", f, err)
+		}
+
+		ks.keys[kid] = k
+		if currentFile == "" || kid > ks.current {
+			ks.current, currentFile = kid, f
+		}
+	}
+
+	if secretKey != "" && ks.current != "" {
+		ks.keys[""] = newHMACKey([]byte(secretKey))
+	}
+
+	fi, err := os.Stat(currentFile)
+	if err != nil {
+		return nil, fmt.Errorf("error of stat signing key:%w", err)
+	}
+
+	for kid, k := range ks.keys {
+		if kid != ks.current {
+			k.expire = fi.ModTime().Add(grace)
+		}
+	}
+
+	return ks, nil
+}
+
+// keyFiles - файлы ключей подписи: сам path, если это файл, или файлы `*.pem` в каталоге path.
+func keyFiles(path string) ([]string, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("error of stat signing keys path:%w", err)
+	}
+
+	if !fi.IsDir() {
+		return []string{path}, nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(path, "*"+keyFileExt))
+	if err != nil {
+		return nil, fmt.Errorf("error of list signing keys:%w", err)
+	}
+
+	if len(files) == 0 {
+		return nil, ErrNoSigningKeys
+	}
+
+	return files, nil
+}
+
+// loadKey - прочитать ключ подписи из PEM файла.
+func loadKey(path string) (*signingKey, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error of read file:%w", err)
+	}
+
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, ErrBadSigningKey
+	}
+
+	switch block.Type {
+	case hmacKeyType:
+		if len(block.Bytes) < minHMACKeySize {
+			return nil, fmt.Errorf("%w: hmac key shorter than %v bytes", ErrBadSigningKey, minHMACKeySize)
+		}
+
+		return newHMACKey(block.Bytes), nil
+	case "EC PRIVATE KEY":
+		k, err := x509.ParseECPrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("error of parse ec private key:%w", err)
+		}
+
+		return newAsymmetricKey(k)
+	case "PRIVATE KEY":
+		k, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("error of parse pkcs8 private key:%w", err)
+		}
+
+		return newAsymmetricKey(k)
+	default:
+		return nil, fmt.Errorf("%w: pem block %q", ErrBadSigningKey, block.Type)
+	}
+}
+
+func newHMACKey(key []byte) *signingKey {
+	return &signingKey{
+		method: jwt.SigningMethodHS256,
+		sign:   key,
+		verify: key,
+	}
+}
+
+func newAsymmetricKey(k interface{}) (*signingKey, error) {
+	switch k := k.(type) {
+	case ed25519.PrivateKey:
+		return &signingKey{
+			method: jwt.SigningMethodEdDSA,
+			sign:   k,
+			verify: k.Public(),
+		}, nil
+	case *ecdsa.PrivateKey:
+		if k.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("%w: ecdsa curve %v, only P-256 supported", ErrBadSigningKey, k.Curve.Params().Name)
+		}
+
+		return &signingKey{
+			method: jwt.SigningMethodES256,
+			sign:   k,
+			verify: &k.PublicKey,
+		}, nil
+	default:
+		return nil, fmt.Errorf("%w: key type %T", ErrBadSigningKey, k)
+	}
+}
+
+// sign - подписать токен текущим ключом.
+func (ks *KeySet) sign(claims jwt.Claims) (string, error) {
+	k := ks.keys[ks.current]
+
+	token := jwt.NewWithClaims(k.method, claims)
+	if ks.current != "" {
+		token.Header[kidHeader] = ks.current
+	}
+
+	//nolint:wrapcheck // ошибка оборачивается вызывающим
+	return token.SignedString(k.sign)
+}
+
+// keyFunc - найти ключ проверки токена по заголовку `kid`, токен без заголовка проверяется ключом HMAC
+// из secretKey. Алгоритм токена должен совпадать с алгоритмом ключа.
+func (ks *KeySet) keyFunc(t *jwt.Token) (interface{}, error) {
+	kid, _ := t.Header[kidHeader].(string)
+
+	k, ok := ks.keys[kid]
+	if !ok || (!k.expire.IsZero() && time.Now().After(k.expire)) {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownSigningKey, kid)
+	}
+
+	if t.Method.Alg() != k.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+	}
+
+	return k.verify, nil
+}
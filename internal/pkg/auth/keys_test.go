@@ -0,0 +1,175 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/require"
+)
+
+func writeKey(t *testing.T, dir, kid, typ string, b []byte, mtime time.Time) string {
+	t.Helper()
+
+	path := filepath.Join(dir, kid+keyFileExt)
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: b}), 0o600))
+	require.NoError(t, os.Chtimes(path, mtime, mtime))
+
+	return path
+}
+
+func writeEd25519Key(t *testing.T, dir, kid string, mtime time.Time) string {
+	t.Helper()
+
+	_, k, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	b, err := x509.MarshalPKCS8PrivateKey(k)
+	require.NoError(t, err)
+
+	return writeKey(t, dir, kid, "PRIVATE KEY", b, mtime)
+}
+
+func writeECKey(t *testing.T, dir, kid string, curve elliptic.Curve, mtime time.Time) string {
+	t.Helper()
+
+	k, err := ecdsa.GenerateKey(curve, rand.Reader)
+	require.NoError(t, err)
+
+	b, err := x509.MarshalECPrivateKey(k)
+	require.NoError(t, err)
+
+	return writeKey(t, dir, kid, "EC PRIVATE KEY", b, mtime)
+}
+
+func TestKeySetRotation(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+
+	writeECKey(t, dir, "2024-01-01", elliptic.P256(), now.Add(-24*time.Hour))
+
+	old, err := LoadKeySet(dir, "secret", time.Hour)
+	require.NoError(t, err)
+
+	legacy, err := New("secret", NewKeySet("secret"), nil).GenerateToken(1, "legacy")
+	require.NoError(t, err)
+
+	prev, err := New("secret", old, nil).GenerateToken(1, "prev")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		mtime time.Time
+		token string
+		ok    bool
+	}{
+		{
+			name:  "Check token of previous key during grace period",
+			mtime: now.Add(-time.Minute),
+			token: prev,
+			ok:    true,
+		},
+		{
+			name:  "Check token of previous key after grace period",
+			mtime: now.Add(-2 * time.Hour),
+			token: prev,
+		},
+		{
+			name:  "Check token without kid during grace period",
+			mtime: now.Add(-time.Minute),
+			token: legacy,
+			ok:    true,
+		},
+		{
+			name:  "Check token without kid after grace period",
+			mtime: now.Add(-2 * time.Hour),
+			token: legacy,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			writeEd25519Key(t, dir, "2024-02-01", test.mtime)
+
+			ks, err := LoadKeySet(dir, "secret", time.Hour)
+			require.NoError(t, err)
+
+			a := New("secret", ks, nil)
+
+			token, err := a.GenerateToken(1, "current")
+			require.NoError(t, err)
+
+			claims, err := a.ParseToken(token)
+			require.NoError(t, err)
+			require.Equal(t, "current", claims.Id)
+
+			_, err = a.ParseToken(test.token)
+			if test.ok {
+				require.NoError(t, err)
+				return
+			}
+
+			require.ErrorIs(t, err, ErrUnknownSigningKey)
+		})
+	}
+}
+
+func TestKeySetKid(t *testing.T) {
+	dir := t.TempDir()
+	path := writeEd25519Key(t, dir, "current", time.Now())
+
+	ks, err := LoadKeySet(path, "", time.Hour)
+	require.NoError(t, err)
+
+	a := New("", ks, nil)
+
+	token, err := a.GenerateToken(1, "session")
+	require.NoError(t, err)
+
+	parsed, _, err := new(jwt.Parser).ParseUnverified(token, &Claims{})
+	require.NoError(t, err)
+	require.Equal(t, "current", parsed.Header[kidHeader])
+	require.Equal(t, jwt.SigningMethodEdDSA.Alg(), parsed.Method.Alg())
+
+	// Токен без kid не принимается, если не задан secretKey.
+	legacy, err := New("secret", NewKeySet("secret"), nil).GenerateToken(1, "legacy")
+	require.NoError(t, err)
+
+	_, err = a.ParseToken(legacy)
+	require.ErrorIs(t, err, ErrUnknownSigningKey)
+
+	// Токен с kid асимметричного ключа, подписанный HMAC, не принимается.
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, &Claims{UserID: 1})
+	forged.Header[kidHeader] = "current"
+	s, err := forged.SignedString([]byte("secret"))
+	require.NoError(t, err)
+
+	_, err = a.ParseToken(s)
+	require.Error(t, err)
+}
+
+func TestLoadKeySetErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := LoadKeySet(dir, "", time.Hour)
+	require.ErrorIs(t, err, ErrNoSigningKeys)
+
+	_, err = LoadKeySet(filepath.Join(dir, "not exist"), "", time.Hour)
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	path := writeECKey(t, t.TempDir(), "p384", elliptic.P384(), time.Now())
+	_, err = LoadKeySet(path, "", time.Hour)
+	require.ErrorIs(t, err, ErrBadSigningKey)
+
+	path = writeKey(t, t.TempDir(), "short", hmacKeyType, []byte("short"), time.Now())
+	_, err = LoadKeySet(path, "", time.Hour)
+	require.ErrorIs(t, err, ErrBadSigningKey)
+}
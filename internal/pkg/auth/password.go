@@ -0,0 +1,134 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrPasswordMismatch = errors.New("password does not match hash")
+	ErrUnknownHash      = errors.New("unknown password hash format")
+)
+
+// PasswordHasher - хэширование паролей, алгоритм и его параметры хранятся в самом хэше.
+type PasswordHasher interface {
+	// Hash - получить хэш пароля текущим алгоритмом с текущими параметрами.
+	Hash(password string) (string, error)
+	// Check - проверить пароль по хэшу. Если пароль верный, но хэш получен устаревшим алгоритмом или
+	// с устаревшими параметрами, то возвращается rehash=true: хэш нужно пересчитать через Hash.
+	Check(password, hash string) (rehash bool, err error)
+}
+
+// Argon2Params - параметры Argon2id.
+type Argon2Params struct {
+	// Time - количество проходов по памяти
+	Time uint32
+	// Memory - объем памяти в KiB
+	Memory uint32
+	// Threads - количество потоков
+	Threads uint8
+}
+
+// MaxArgon2Memory - максимальный объем памяти Argon2id в KiB (1 GiB). Хэши с большим объемом не проверяются,
+// чтобы испорченный хэш не приводил к неограниченному выделению памяти.
+const MaxArgon2Memory = 1024 * 1024
+
+const (
+	argon2SaltSize = 16
+	argon2KeySize  = 32
+	argon2Prefix   = "$argon2id$"
+)
+
+type argon2Hasher struct {
+	params Argon2Params
+}
+
+// NewArgon2Hasher - хэширование паролей Argon2id с параметрами p. Хэш хранится в формате PHC:
+// `$argon2id$v=19$m=<memory>,t=<time>,p=<threads>$<salt>$<hash>`.
+// Проверяются также хэши bcrypt, они всегда требуют пересчета.
+func NewArgon2Hasher(p Argon2Params) PasswordHasher {
+	return &argon2Hasher{params: p}
+}
+
+func (h *argon2Hasher) Hash(password string) (string, error) {
+	salt := make([]byte, argon2SaltSize)
+
+	_, err := rand.Read(salt)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate salt, %w", err)
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.params.Time, h.params.Memory, h.params.Threads, argon2KeySize)
+
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s", argon2Prefix, argon2.Version,
+		h.params.Memory, h.params.Time, h.params.Threads,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+func (h *argon2Hasher) Check(password, hash string) (bool, error) {
+	if !strings.HasPrefix(hash, argon2Prefix) {
+		return checkBcrypt(password, hash)
+	}
+
+	var version int
+	var p Argon2Params
+
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 {
+		return false, ErrUnknownHash
+	}
+
+	_, err := fmt.Sscanf(parts[2], "v=%d", &version)
+	if err != nil || version != argon2.Version {
+		return false, ErrUnknownHash
+	}
+
+	_, err = fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.Memory, &p.Time, &p.Threads)
+	if err != nil {
+		return false, ErrUnknownHash
+	}
+
+	// argon2.IDKey паникует при нулевом количестве проходов или потоков.
+	if p.Time < 1 || p.Threads < 1 || p.Memory < 1 || p.Memory > MaxArgon2Memory {
+		return false, ErrUnknownHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return false, ErrUnknownHash
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return false, ErrUnknownHash
+	}
+
+	//nolint:gosec // длина ключа задана в хэше и ограничена его размером
+	k := argon2.IDKey([]byte(password), salt, p.Time, p.Memory, p.Threads, uint32(len(key)))
+	if subtle.ConstantTimeCompare(k, key) != 1 {
+		return false, ErrPasswordMismatch
+	}
+
+	return p != h.params || len(salt) != argon2SaltSize || len(key) != argon2KeySize, nil
+}
+
+// checkBcrypt - проверить пароль по хэшу bcrypt, которым хэшировались пароли до перехода на Argon2id.
+func checkBcrypt(password, hash string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	if err != nil {
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, ErrPasswordMismatch
+		}
+
+		return false, ErrUnknownHash
+	}
+
+	return true, nil
+}
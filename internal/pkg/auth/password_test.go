@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestArgon2Hasher(t *testing.T) {
+	params := Argon2Params{Time: 1, Memory: 1024, Threads: 1}
+	h := NewArgon2Hasher(params)
+
+	hash, err := h.Hash("password")
+	require.NoError(t, err)
+	require.Regexp(t, `^\$argon2id\$v=19\$m=1024,t=1,p=1\$[^$]+\$[^$]+$`, hash)
+
+	old, err := NewArgon2Hasher(Argon2Params{Time: 1, Memory: 512, Threads: 1}).Hash("password")
+	require.NoError(t, err)
+
+	legacy, err := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		password string
+		hash     string
+		rehash   bool
+		err      error
+	}{
+		{
+			name:     "Check current hash",
+			password: "password",
+			hash:     hash,
+		},
+		{
+			name:     "Check wrong password",
+			password: "wrong",
+			hash:     hash,
+			err:      ErrPasswordMismatch,
+		},
+		{
+			name:     "Check hash with outdated params",
+			password: "password",
+			hash:     old,
+			rehash:   true,
+		},
+		{
+			name:     "Check bcrypt hash",
+			password: "password",
+			hash:     string(legacy),
+			rehash:   true,
+		},
+		{
+			name:     "Check wrong password of bcrypt hash",
+			password: "wrong",
+			hash:     string(legacy),
+			err:      ErrPasswordMismatch,
+		},
+		{
+			name:     "Check empty hash",
+			password: "password",
+			err:      ErrUnknownHash,
+		},
+		{
+			name:     "Check broken hash",
+			password: "password",
+			hash:     "$argon2id$v=19$m=1024$salt$hash",
+			err:      ErrUnknownHash,
+		},
+		{
+			name:     "Check hash with zero time",
+			password: "password",
+			hash:     "$argon2id$v=19$m=1024,t=0,p=1$c2FsdHNhbHRzYWx0c2FsdA$aGFzaA",
+			err:      ErrUnknownHash,
+		},
+		{
+			name:     "Check hash with zero threads",
+			password: "password",
+			hash:     "$argon2id$v=19$m=1024,t=1,p=0$c2FsdHNhbHRzYWx0c2FsdA$aGFzaA",
+			err:      ErrUnknownHash,
+		},
+		{
+			name:     "Check hash with too much memory",
+			password: "password",
+			hash:     "$argon2id$v=19$m=4294967295,t=1,p=1$c2FsdHNhbHRzYWx0c2FsdA$aGFzaA",
+			err:      ErrUnknownHash,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rehash, err := h.Check(test.password, test.hash)
+			require.ErrorIs(t, err, test.err)
+			require.Equal(t, test.rehash, rehash)
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+)
+
+// refreshTokenSize - размер случайной части refresh токена в байтах.
+const refreshTokenSize = 32
+
+// NewRefreshToken - создать refresh токен. Возвращает токен для клиента и его хэш для хранения на сервере.
+func NewRefreshToken() (string, []byte, error) {
+	t, err := newToken()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to generate refresh token:%w", err)
+	}
+
+	return t, HashRefreshToken(t), nil
+}
+
+// HashRefreshToken - хэш refresh токена, под которым он хранится на сервере.
+// Токен случайный и длинный, поэтому соль и медленный хэш не нужны.
+func HashRefreshToken(token string) []byte {
+	h := sha256.Sum256([]byte(token))
+	return h[:]
+}
+
+// NewChallenge - создать токен подтверждения входа вторым фактором, выдается после проверки пароля.
+// Возвращает токен для клиента и его хэш для хранения на сервере.
+func NewChallenge() (string, []byte, error) {
+	t, err := newToken()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to generate challenge:%w", err)
+	}
+
+	return t, HashChallenge(t), nil
+}
+
+// HashChallenge - хэш токена подтверждения входа, под которым он хранится на сервере.
+func HashChallenge(challenge string) []byte {
+	return HashRefreshToken(challenge)
+}
+
+func newToken() (string, error) {
+	b := make([]byte, refreshTokenSize)
+
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err //nolint:wrapcheck // wrapped by caller
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,118 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha1" //nolint:gosec // HMAC-SHA1 is required by RFC 4226
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/k0st1a/gophkeeper/internal/pkg/hotp"
+)
+
+const (
+	// TOTPIssuer - имя сервиса в otpauth URI, под которым приложение-аутентификатор показывает код.
+	TOTPIssuer = "GophKeeper"
+	// BackupCodes - количество одноразовых резервных кодов, выдаваемых при подключении второго фактора.
+	BackupCodes = 10
+
+	totpSecretSize = 20
+	totpDigits     = 6
+	totpPeriod     = 30
+	// totpSkew - сколько соседних интервалов принимается из-за расхождения часов клиента и сервера.
+	totpSkew = 1
+
+	backupCodeSize = 5
+)
+
+var base32NoPadding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// NewTOTPSecret - создать секрет TOTP в кодировке base32, как его принимают приложения-аутентификаторы.
+func NewTOTPSecret() (string, error) {
+	b := make([]byte, totpSecretSize)
+
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate totp secret:%w", err)
+	}
+
+	return base32NoPadding.EncodeToString(b), nil
+}
+
+// TOTPURI - otpauth URI секрета для добавления в приложение-аутентификатор, account - логин пользователя.
+func TOTPURI(account, secret string) string {
+	u := url.URL{
+		Scheme: "otpauth",
+		Host:   "totp",
+		Path:   "/" + TOTPIssuer + ":" + account,
+		RawQuery: url.Values{
+			"secret":    {secret},
+			"issuer":    {TOTPIssuer},
+			"algorithm": {"SHA1"},
+			"digits":    {fmt.Sprint(totpDigits)},
+			"period":    {fmt.Sprint(totpPeriod)},
+		}.Encode(),
+	}
+
+	return u.String()
+}
+
+// CheckTOTP - проверить код TOTP на момент t. Принимаются только интервалы после last, чтобы код
+// нельзя было использовать повторно. Возвращает интервал, которому соответствует код.
+func CheckTOTP(secret, code string, t time.Time, last int64) (int64, bool) {
+	key, err := base32NoPadding.DecodeString(secret)
+	if err != nil || len(code) != totpDigits {
+		return 0, false
+	}
+
+	now := t.Unix() / totpPeriod
+	for step := now - totpSkew; step <= now+totpSkew; step++ {
+		if step <= last {
+			continue
+		}
+
+		c := hotp.Generate(sha1.New, key, uint64(step), totpDigits) //nolint:gosec // unix time after 1970
+		if subtle.ConstantTimeCompare([]byte(c), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+// NewBackupCodes - создать одноразовые резервные коды для входа без приложения-аутентификатора.
+// Возвращает коды для пользователя и их хэши для хранения на сервере.
+func NewBackupCodes() ([]string, [][]byte, error) {
+	codes := make([]string, 0, BackupCodes)
+	hashes := make([][]byte, 0, BackupCodes)
+
+	for range BackupCodes {
+		b := make([]byte, backupCodeSize)
+
+		_, err := rand.Read(b)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to generate backup code:%w", err)
+		}
+
+		c := strings.ToLower(base32NoPadding.EncodeToString(b))
+		c = c[:len(c)/2] + "-" + c[len(c)/2:]
+
+		codes = append(codes, c)
+		hashes = append(hashes, HashBackupCode(c))
+	}
+
+	return codes, hashes, nil
+}
+
+// HashBackupCode - хэш резервного кода, под которым он хранится на сервере.
+// Регистр, пробелы и дефисы при вводе кода не важны.
+func HashBackupCode(code string) []byte {
+	code = strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+
+	h := sha256.Sum256([]byte(code))
+	return h[:]
+}
@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// rfcSecret - ключ "12345678901234567890" из тестовых векторов RFC 6238 в кодировке base32.
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCheckTOTP(t *testing.T) {
+	tests := []struct {
+		name string
+		code string
+		time time.Time
+		last int64
+		step int64
+		ok   bool
+	}{
+		{
+			name: "Check code of RFC 6238 test vector",
+			code: "287082",
+			time: time.Unix(59, 0),
+			step: 1,
+			ok:   true,
+		},
+		{
+			name: "Check code of previous step",
+			code: "287082",
+			time: time.Unix(89, 0),
+			step: 1,
+			ok:   true,
+		},
+		{
+			name: "Check code of too old step",
+			code: "287082",
+			time: time.Unix(120, 0),
+		},
+		{
+			name: "Check code of already used step",
+			code: "287082",
+			time: time.Unix(59, 0),
+			last: 1,
+		},
+		{
+			name: "Check wrong code",
+			code: "000000",
+			time: time.Unix(59, 0),
+		},
+		{
+			name: "Check code of RFC 6238 test vector of 2009 year",
+			code: "081804",
+			time: time.Unix(1111111109, 0),
+			step: 37037036,
+			ok:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			step, ok := CheckTOTP(rfcSecret, test.code, test.time, test.last)
+			require.Equal(t, test.ok, ok)
+			require.Equal(t, test.step, step)
+		})
+	}
+}
+
+func TestTOTPURI(t *testing.T) {
+	secret, err := NewTOTPSecret()
+	require.NoError(t, err)
+
+	u, err := url.Parse(TOTPURI("user@example.com", secret))
+	require.NoError(t, err)
+	require.Equal(t, "otpauth", u.Scheme)
+	require.Equal(t, "totp", u.Host)
+	require.Equal(t, "/GophKeeper:user@example.com", u.Path)
+	require.Equal(t, secret, u.Query().Get("secret"))
+}
+
+func TestBackupCodes(t *testing.T) {
+	codes, hashes, err := NewBackupCodes()
+	require.NoError(t, err)
+	require.Len(t, codes, BackupCodes)
+	require.Len(t, hashes, BackupCodes)
+
+	for i, c := range codes {
+		require.Equal(t, hashes[i], HashBackupCode(c))
+	}
+
+	require.Equal(t, HashBackupCode("abcde-fghij"), HashBackupCode(" ABCDE FGHIJ"))
+}
@@ -0,0 +1,97 @@
+// Package broker delivers notifications about changes of user items to subscribers of the same server.
+package broker
+
+import (
+	"sync"
+
+	"github.com/rs/zerolog/log"
+)
+
+type Broker struct {
+	mutex  *sync.Mutex
+	subs   map[int64]map[chan struct{}]struct{}
+	closed bool
+}
+
+// New - создать брокер уведомлений.
+func New() *Broker {
+	return &Broker{
+		mutex: &sync.Mutex{},
+		subs:  make(map[int64]map[chan struct{}]struct{}),
+	}
+}
+
+// Subscribe - подписаться на уведомления об изменениях предметов пользователя.
+// Уведомления, пришедшие пока подписчик занят, склеиваются в одно.
+// Канал закрывается при закрытии брокера. Функцию отписки нужно вызвать, когда уведомления больше не нужны.
+func (b *Broker) Subscribe(userID int64) (<-chan struct{}, func()) {
+	log.Printf("Subscribe, userID:%v", userID)
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	ch := make(chan struct{}, 1)
+
+	if b.closed {
+		close(ch)
+		return ch, func() {}
+	}
+
+	if b.subs[userID] == nil {
+		b.subs[userID] = make(map[chan struct{}]struct{})
+	}
+	b.subs[userID][ch] = struct{}{}
+
+	return ch, func() {
+		b.unsubscribe(userID, ch)
+	}
+}
+
+func (b *Broker) unsubscribe(userID int64, ch chan struct{}) {
+	log.Printf("Unsubscribe, userID:%v", userID)
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if _, ok := b.subs[userID][ch]; !ok {
+		return
+	}
+
+	delete(b.subs[userID], ch)
+	if len(b.subs[userID]) == 0 {
+		delete(b.subs, userID)
+	}
+	close(ch)
+}
+
+// Notify - уведомить подписчиков пользователя об изменении предметов.
+func (b *Broker) Notify(userID int64) {
+	log.Printf("Notify, userID:%v", userID)
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for ch := range b.subs[userID] {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Close - закрыть каналы всех подписчиков, чтобы потоки уведомлений завершились до остановки сервера.
+func (b *Broker) Close() {
+	log.Printf("Close broker")
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for _, subs := range b.subs {
+		for ch := range subs {
+			close(ch)
+		}
+	}
+
+	b.subs = make(map[int64]map[chan struct{}]struct{})
+	b.closed = true
+}
@@ -0,0 +1,57 @@
+package broker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotify(t *testing.T) {
+	b := New()
+
+	ch1, unsubscribe1 := b.Subscribe(1)
+	defer unsubscribe1()
+
+	ch2, unsubscribe2 := b.Subscribe(2)
+	defer unsubscribe2()
+
+	b.Notify(1)
+	b.Notify(1)
+
+	require.Len(t, ch1, 1, "notifications must be coalesced")
+	require.Empty(t, ch2, "other user must not be notified")
+
+	<-ch1
+	require.Empty(t, ch1)
+}
+
+func TestUnsubscribe(t *testing.T) {
+	b := New()
+
+	ch, unsubscribe := b.Subscribe(1)
+	unsubscribe()
+	unsubscribe()
+
+	_, ok := <-ch
+	require.False(t, ok)
+
+	b.Notify(1)
+}
+
+func TestClose(t *testing.T) {
+	b := New()
+
+	ch, unsubscribe := b.Subscribe(1)
+	defer unsubscribe()
+
+	b.Close()
+
+	_, ok := <-ch
+	require.False(t, ok)
+
+	ch, unsubscribe = b.Subscribe(1)
+	defer unsubscribe()
+
+	_, ok = <-ch
+	require.False(t, ok)
+}
@@ -0,0 +1,102 @@
+package crypto
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+// PublicKeySize - размер открытого ключа X25519, в байтах.
+const PublicKeySize = 32
+
+// boxInfo - контекст выработки ключа шифрования из общего секрета X25519.
+var boxInfo = []byte("gophkeeper box v1")
+
+// NewKeyPair - сгенерировать пару ключей X25519 для шифрования данных другим пользователям.
+func NewKeyPair() (private, public []byte, err error) {
+	k, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("x25519 generate key error:%w", err)
+	}
+
+	return k.Bytes(), k.PublicKey().Bytes(), nil
+}
+
+// SealTo - зашифровать данные открытым ключом получателя: ephemeral public || Encrypt(...).
+// Ключ шифрования вырабатывается через HKDF-SHA256 из общего секрета X25519 одноразового ключа
+// и ключа получателя, оба открытых ключа входят в дополнительные данные.
+func SealTo(public, plaintext []byte) ([]byte, error) {
+	pub, err := ecdh.X25519().NewPublicKey(public)
+	if err != nil {
+		return nil, fmt.Errorf("%w:%w", ErrBadKey, err)
+	}
+
+	eph, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("x25519 generate key error:%w", err)
+	}
+
+	secret, err := eph.ECDH(pub)
+	if err != nil {
+		return nil, fmt.Errorf("%w:%w", ErrBadKey, err)
+	}
+
+	key, ad, err := boxKey(secret, eph.PublicKey(), pub)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := EncryptWithAD(key, plaintext, ad)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(eph.PublicKey().Bytes(), c...), nil
+}
+
+// OpenFrom - расшифровать закрытым ключом данные, зашифрованные через SealTo.
+func OpenFrom(private, ciphertext []byte) ([]byte, error) {
+	priv, err := ecdh.X25519().NewPrivateKey(private)
+	if err != nil {
+		return nil, fmt.Errorf("%w:%w", ErrBadKey, err)
+	}
+
+	if len(ciphertext) < PublicKeySize {
+		return nil, ErrBadCiphertext
+	}
+
+	eph, err := ecdh.X25519().NewPublicKey(ciphertext[:PublicKeySize])
+	if err != nil {
+		return nil, fmt.Errorf("%w:%w", ErrBadCiphertext, err)
+	}
+
+	secret, err := priv.ECDH(eph)
+	if err != nil {
+		return nil, fmt.Errorf("%w:%w", ErrBadCiphertext, err)
+	}
+
+	key, ad, err := boxKey(secret, eph, priv.PublicKey())
+	if err != nil {
+		return nil, err
+	}
+
+	return DecryptWithAD(key, ciphertext[PublicKeySize:], ad)
+}
+
+// boxKey - выработать ключ шифрования из общего секрета X25519, ad - дополнительные данные
+// из открытых ключей одноразового ключа eph и получателя recipient.
+func boxKey(secret []byte, eph, recipient *ecdh.PublicKey) ([]byte, []byte, error) {
+	ad := append(eph.Bytes(), recipient.Bytes()...)
+
+	key := make([]byte, KeySize)
+	_, err := io.ReadFull(hkdf.New(sha256.New, secret, ad, boxInfo), key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("hkdf error:%w", err)
+	}
+
+	return key, ad, nil
+}
@@ -0,0 +1,63 @@
+package crypto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSealToOpenFrom(t *testing.T) {
+	priv, pub, err := NewKeyPair()
+	require.NoError(t, err)
+
+	otherPriv, _, err := NewKeyPair()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		private []byte
+		tamper  int
+		wantErr error
+	}{
+		{
+			name:    "Check SealTo and OpenFrom",
+			private: priv,
+			tamper:  -1,
+		},
+		{
+			name:    "Check OpenFrom with other key",
+			private: otherPriv,
+			tamper:  -1,
+			wantErr: ErrBadCiphertext,
+		},
+		{
+			name:    "Check OpenFrom with tampered ephemeral key",
+			private: priv,
+			tamper:  0,
+			wantErr: ErrBadCiphertext,
+		},
+		{
+			name:    "Check OpenFrom with tampered data",
+			private: priv,
+			tamper:  PublicKeySize + 10,
+			wantErr: ErrBadCiphertext,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c, err := SealTo(pub, []byte("data"))
+			require.NoError(t, err)
+
+			if test.tamper >= 0 {
+				c[test.tamper] ^= 1
+			}
+
+			p, err := OpenFrom(test.private, c)
+			require.ErrorIs(t, err, test.wantErr)
+			if test.wantErr == nil {
+				require.Equal(t, []byte("data"), p)
+			}
+		})
+	}
+}
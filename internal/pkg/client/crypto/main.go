@@ -0,0 +1,125 @@
+// Package crypto contains client side cryptographic primitives for end-to-end encryption of items.
+package crypto
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+const (
+	// KeySize - размер ключа шифрования, в байтах.
+	KeySize = chacha20poly1305.KeySize
+	// SaltSize - размер соли для выработки ключа из пароля, в байтах.
+	SaltSize = 16
+
+	// Параметры Argon2id, см. RFC 9106, раздел 4.
+	argonTime    = 3
+	argonMemory  = 64 * 1024 // 64MB
+	argonThreads = 4
+
+	// version - версия формата зашифрованных данных: version || nonce || ciphertext.
+	version = 1
+)
+
+var (
+	ErrBadKey        = errors.New("bad key")
+	ErrBadCiphertext = errors.New("bad ciphertext")
+)
+
+// NewSalt - сгенерировать случайную соль.
+func NewSalt() ([]byte, error) {
+	return random(SaltSize)
+}
+
+// NewKey - сгенерировать случайный ключ шифрования.
+func NewKey() ([]byte, error) {
+	return random(KeySize)
+}
+
+// DeriveKey - выработать ключ шифрования из пароля и соли с помощью Argon2id.
+func DeriveKey(password string, salt []byte) []byte {
+	return argon2.IDKey([]byte(password), salt, argonTime, argonMemory, argonThreads, KeySize)
+}
+
+// Encrypt - зашифровать данные ключом с помощью XChaCha20-Poly1305.
+func Encrypt(key, plaintext []byte) ([]byte, error) {
+	return EncryptWithAD(key, plaintext, nil)
+}
+
+// Decrypt - расшифровать данные, зашифрованные через Encrypt.
+func Decrypt(key, ciphertext []byte) ([]byte, error) {
+	return DecryptWithAD(key, ciphertext, nil)
+}
+
+// EncryptWithAD - зашифровать данные ключом и привязать их к дополнительным данным ad.
+// Дополнительные данные не шифруются и не хранятся в результате, но без них данные не расшифровать.
+func EncryptWithAD(key, plaintext, ad []byte) ([]byte, error) {
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return nil, fmt.Errorf("%w:%w", ErrBadKey, err)
+	}
+
+	nonce, err := random(aead.NonceSize())
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, 0, 1+len(nonce)+len(plaintext)+aead.Overhead())
+	out = append(out, version)
+	out = append(out, nonce...)
+
+	return aead.Seal(out, nonce, plaintext, additionalData(ad)), nil
+}
+
+// DecryptWithAD - расшифровать данные, зашифрованные через EncryptWithAD с теми же дополнительными данными.
+func DecryptWithAD(key, ciphertext, ad []byte) ([]byte, error) {
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return nil, fmt.Errorf("%w:%w", ErrBadKey, err)
+	}
+
+	if !IsEncrypted(ciphertext) || len(ciphertext) < 1+aead.NonceSize()+aead.Overhead() {
+		return nil, ErrBadCiphertext
+	}
+
+	nonce := ciphertext[1 : 1+aead.NonceSize()]
+	data := ciphertext[1+aead.NonceSize():]
+
+	plaintext, err := aead.Open(nil, nonce, data, additionalData(ad))
+	if err != nil {
+		return nil, fmt.Errorf("%w:%w", ErrBadCiphertext, err)
+	}
+
+	return plaintext, nil
+}
+
+// Overhead - на сколько байт зашифрованные данные больше исходных.
+func Overhead() int {
+	return 1 + chacha20poly1305.NonceSizeX + chacha20poly1305.Overhead
+}
+
+// IsEncrypted - проверить, что данные зашифрованы через Encrypt.
+// Незашифрованный предмет сериализуется в JSON и начинается с '{'.
+func IsEncrypted(data []byte) bool {
+	return len(data) > 0 && data[0] == version
+}
+
+// additionalData - версия формата всегда входит в дополнительные данные.
+func additionalData(ad []byte) []byte {
+	return append([]byte{version}, ad...)
+}
+
+func random(size int) ([]byte, error) {
+	b := make([]byte, size)
+
+	_, err := rand.Read(b)
+	if err != nil {
+		return nil, fmt.Errorf("rand read error:%w", err)
+	}
+
+	return b, nil
+}
@@ -0,0 +1,121 @@
+package crypto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	key, err := NewKey()
+	require.NoError(t, err)
+
+	otherKey, err := NewKey()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		plaintext  []byte
+		decryptKey []byte
+		tamper     bool
+		wantErr    error
+	}{
+		{
+			name:       "Check Encrypt and Decrypt",
+			plaintext:  []byte(`{"password":{"resource":"Resource"}}`),
+			decryptKey: key,
+		},
+		{
+			name:       "Check Decrypt with other key",
+			plaintext:  []byte("data"),
+			decryptKey: otherKey,
+			wantErr:    ErrBadCiphertext,
+		},
+		{
+			name:       "Check Decrypt of tampered data",
+			plaintext:  []byte("data"),
+			decryptKey: key,
+			tamper:     true,
+			wantErr:    ErrBadCiphertext,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c, err := Encrypt(key, test.plaintext)
+			require.NoError(t, err)
+			require.True(t, IsEncrypted(c))
+			require.NotContains(t, string(c), string(test.plaintext))
+
+			if test.tamper {
+				c[len(c)-1] ^= 0xff
+			}
+
+			p, err := Decrypt(test.decryptKey, c)
+			if test.wantErr != nil {
+				require.ErrorIs(t, err, test.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.plaintext, p)
+		})
+	}
+}
+
+func TestDeriveKey(t *testing.T) {
+	salt, err := NewSalt()
+	require.NoError(t, err)
+
+	otherSalt, err := NewSalt()
+	require.NoError(t, err)
+
+	k1 := DeriveKey("password", salt)
+	require.Len(t, k1, KeySize)
+	require.Equal(t, k1, DeriveKey("password", salt))
+	require.NotEqual(t, k1, DeriveKey("password", otherSalt))
+	require.NotEqual(t, k1, DeriveKey("other password", salt))
+}
+
+func TestIsEncrypted(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		expected bool
+	}{
+		{
+			name:     "Check IsEncrypted for empty data",
+			data:     nil,
+			expected: false,
+		},
+		{
+			name:     "Check IsEncrypted for serialized item",
+			data:     []byte(`{"card":null}`),
+			expected: false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expected, IsEncrypted(test.data))
+		})
+	}
+}
+
+func TestEncryptDecryptWithAD(t *testing.T) {
+	key, err := NewKey()
+	require.NoError(t, err)
+
+	plaintext := []byte("chunk of file")
+
+	ciphertext, err := EncryptWithAD(key, plaintext, []byte("blob-1:0"))
+	require.NoError(t, err)
+	require.Len(t, ciphertext, len(plaintext)+Overhead())
+
+	got, err := DecryptWithAD(key, ciphertext, []byte("blob-1:0"))
+	require.NoError(t, err)
+	require.Equal(t, plaintext, got)
+
+	_, err = DecryptWithAD(key, ciphertext, []byte("blob-1:1"))
+	require.ErrorIs(t, err, ErrBadCiphertext)
+
+	_, err = Decrypt(key, ciphertext)
+	require.ErrorIs(t, err, ErrBadCiphertext)
+}
@@ -0,0 +1,174 @@
+// Package keyring holds the vault key of the logged in user.
+//
+// Ключ хранилища (vault key) - случайный ключ, которым шифруются предметы пользователя.
+// На сервере он хранится в зашифрованном (wrapped) виде: зашифрован ключом, выработанным из мастер-пароля
+// пользователя и соли с помощью Argon2id. Сервер никогда не видит ни мастер-пароль, ни ключ хранилища.
+package keyring
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/k0st1a/gophkeeper/internal/pkg/client/crypto"
+)
+
+var (
+	ErrLocked              = errors.New("keyring locked")
+	ErrWrongMasterPassword = errors.New("wrong master password")
+)
+
+// WrappedKey - ключ хранилища, зашифрованный мастер-ключом.
+type WrappedKey struct {
+	// Соль для выработки мастер-ключа из мастер-пароля.
+	Salt []byte
+	// Ключ хранилища, зашифрованный мастер-ключом.
+	Key []byte
+}
+
+type Keyring struct {
+	mutex *sync.RWMutex
+	key   []byte
+}
+
+// New - создать пустую (заблокированную) связку ключей.
+func New() *Keyring {
+	return &Keyring{
+		mutex: &sync.RWMutex{},
+	}
+}
+
+// NewVaultKey - сгенерировать новый ключ хранилища и зашифровать его мастер-паролем.
+func NewVaultKey(password string) ([]byte, *WrappedKey, error) {
+	key, err := crypto.NewKey()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error of generate vault key:%w", err)
+	}
+
+	wk, err := Wrap(password, key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return key, wk, nil
+}
+
+// Wrap - зашифровать ключ хранилища мастер-паролем с новой солью.
+func Wrap(password string, key []byte) (*WrappedKey, error) {
+	salt, err := crypto.NewSalt()
+	if err != nil {
+		return nil, fmt.Errorf("error of generate salt:%w", err)
+	}
+
+	wrapped, err := crypto.Encrypt(crypto.DeriveKey(password, salt), key)
+	if err != nil {
+		return nil, fmt.Errorf("error of wrap vault key:%w", err)
+	}
+
+	return &WrappedKey{
+		Salt: salt,
+		Key:  wrapped,
+	}, nil
+}
+
+// Unwrap - расшифровать ключ хранилища мастер-паролем.
+func Unwrap(password string, wk *WrappedKey) ([]byte, error) {
+	key, err := crypto.Decrypt(crypto.DeriveKey(password, wk.Salt), wk.Key)
+	if err != nil {
+		return nil, fmt.Errorf("%w:%w", ErrWrongMasterPassword, err)
+	}
+
+	return key, nil
+}
+
+// Unlock - расшифровать ключ хранилища мастер-паролем и запомнить его.
+func (k *Keyring) Unlock(password string, wk *WrappedKey) error {
+	key, err := Unwrap(password, wk)
+	if err != nil {
+		return err
+	}
+
+	k.Set(key)
+	return nil
+}
+
+// Set - запомнить ключ хранилища.
+func (k *Keyring) Set(key []byte) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	k.key = append([]byte(nil), key...)
+}
+
+// Lock - забыть ключ хранилища.
+func (k *Keyring) Lock() {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	clear(k.key)
+	k.key = nil
+}
+
+// Key - получить копию ключа хранилища.
+func (k *Keyring) Key() ([]byte, error) {
+	k.mutex.RLock()
+	defer k.mutex.RUnlock()
+
+	if k.key == nil {
+		return nil, ErrLocked
+	}
+
+	return append([]byte(nil), k.key...), nil
+}
+
+// Encrypt - зашифровать данные ключом хранилища.
+func (k *Keyring) Encrypt(plaintext []byte) ([]byte, error) {
+	k.mutex.RLock()
+	defer k.mutex.RUnlock()
+
+	if k.key == nil {
+		return nil, ErrLocked
+	}
+
+	//nolint:wrapcheck // errors of crypto package are descriptive
+	return crypto.Encrypt(k.key, plaintext)
+}
+
+// Decrypt - расшифровать данные ключом хранилища.
+func (k *Keyring) Decrypt(ciphertext []byte) ([]byte, error) {
+	k.mutex.RLock()
+	defer k.mutex.RUnlock()
+
+	if k.key == nil {
+		return nil, ErrLocked
+	}
+
+	//nolint:wrapcheck // errors of crypto package are descriptive
+	return crypto.Decrypt(k.key, ciphertext)
+}
+
+// EncryptWithAD - зашифровать данные ключом хранилища с дополнительными данными.
+func (k *Keyring) EncryptWithAD(plaintext, ad []byte) ([]byte, error) {
+	k.mutex.RLock()
+	defer k.mutex.RUnlock()
+
+	if k.key == nil {
+		return nil, ErrLocked
+	}
+
+	//nolint:wrapcheck // errors of crypto package are descriptive
+	return crypto.EncryptWithAD(k.key, plaintext, ad)
+}
+
+// DecryptWithAD - расшифровать данные ключом хранилища с дополнительными данными.
+func (k *Keyring) DecryptWithAD(ciphertext, ad []byte) ([]byte, error) {
+	k.mutex.RLock()
+	defer k.mutex.RUnlock()
+
+	if k.key == nil {
+		return nil, ErrLocked
+	}
+
+	//nolint:wrapcheck // errors of crypto package are descriptive
+	return crypto.DecryptWithAD(k.key, ciphertext, ad)
+}
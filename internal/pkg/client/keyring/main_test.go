@@ -0,0 +1,69 @@
+package keyring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnlock(t *testing.T) {
+	key, wk, err := NewVaultKey("master password")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		password string
+		wantErr  error
+	}{
+		{
+			name:     "Check Unlock with right master password",
+			password: "master password",
+		},
+		{
+			name:     "Check Unlock with wrong master password",
+			password: "wrong password",
+			wantErr:  ErrWrongMasterPassword,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			k := New()
+			err := k.Unlock(test.password, wk)
+			if test.wantErr != nil {
+				require.ErrorIs(t, err, test.wantErr)
+				_, err = k.Key()
+				require.ErrorIs(t, err, ErrLocked)
+				return
+			}
+			require.NoError(t, err)
+
+			got, err := k.Key()
+			require.NoError(t, err)
+			require.Equal(t, key, got)
+		})
+	}
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	key, _, err := NewVaultKey("master password")
+	require.NoError(t, err)
+
+	k := New()
+
+	_, err = k.Encrypt([]byte("data"))
+	require.ErrorIs(t, err, ErrLocked)
+
+	k.Set(key)
+
+	c, err := k.Encrypt([]byte("data"))
+	require.NoError(t, err)
+
+	p, err := k.Decrypt(c)
+	require.NoError(t, err)
+	require.Equal(t, []byte("data"), p)
+
+	k.Lock()
+
+	_, err = k.Decrypt(c)
+	require.ErrorIs(t, err, ErrLocked)
+}
@@ -1,11 +1,21 @@
 package model
 
 // File - описание файла.
+// Содержимое файла хранится в Body (файлы, добавленные до появления блобов) или в блобе, на который ссылается Blob.
 //
 //easyjson:json
 type File struct {
-	Name string `json:"name"` // Путь до файла
-	Body []byte `json:"body"` // Тело файла
+	Blob *Blob  `json:"blob,omitempty"` // Ссылка на блоб с содержимым файла
+	Name string `json:"name"`           // Путь до файла
+	Body []byte `json:"body,omitempty"` // Тело файла
+}
+
+// Blob - ссылка на блоб, содержимое которого передается на сервер блоками.
+//
+//easyjson:json
+type Blob struct {
+	ID   string `json:"id"`   // Идентификатор блоба
+	Size int64  `json:"size"` // Размер содержимого блоба
 }
 
 func (f *File) GetName() string {
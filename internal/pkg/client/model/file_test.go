@@ -27,3 +27,26 @@ func TestFileGetName(t *testing.T) {
 		})
 	}
 }
+
+func TestFileWithBlob(t *testing.T) {
+	i := &Item{
+		File: &File{
+			Name: "disk.img",
+			Blob: &Blob{
+				ID:   "0b7ae4a4-6d87-4b5e-8d43-1d6f9b4c9a70",
+				Size: 1 << 30,
+			},
+		},
+	}
+
+	b, err := Serialize(i)
+	require.NoError(t, err)
+	require.NotContains(t, string(b), `"body"`)
+
+	got, err := Deserialize(b)
+	require.NoError(t, err)
+	require.Equal(t, i, got)
+	require.Equal(t, []string{"0b7ae4a4-6d87-4b5e-8d43-1d6f9b4c9a70"}, got.BlobIDs())
+
+	require.Nil(t, (&Item{File: &File{Name: "Name", Body: []byte("Body")}}).BlobIDs())
+}
@@ -14,7 +14,7 @@ var (
 )
 
 // Item - описание предмета клиента.
-// Должно быть заполнено одно из полей: Card, Password, Note, File.
+// Должно быть заполнено одно из полей: Card, Password, Note, File, OTP.
 //
 //easyjson:json
 type Item struct {
@@ -26,6 +26,8 @@ type Item struct {
 	Note *Note `json:"note"`
 	// Поле File заполняется, если предмет содержит информацию о файле (бинарные данные).
 	File *File `json:"file"`
+	// Поле OTP заполняется, если предмет содержит информацию о генераторе одноразовых паролей.
+	OTP *OTP `json:"otp,omitempty"`
 	// Поле Meta содержит опциональную информацию о предмете.
 	Meta Meta `json:"meta"`
 }
@@ -47,6 +49,10 @@ func (i *Item) GetBody() (any, error) {
 		return i.File, nil
 	}
 
+	if i.OTP != nil {
+		return i.OTP, nil
+	}
+
 	return "", ErrBadItem
 }
 
@@ -67,9 +73,22 @@ func (i *Item) GetName() (string, error) {
 		return i.File.GetName(), nil
 	}
 
+	if i.OTP != nil {
+		return i.OTP.GetName(), nil
+	}
+
 	return "", ErrBadItem
 }
 
+// BlobIDs - блобы, на которые ссылается предмет.
+func (i *Item) BlobIDs() []string {
+	if i.File != nil && i.File.Blob != nil {
+		return []string{i.File.Blob.ID}
+	}
+
+	return nil
+}
+
 // Deserialize - распаковка байт в формат Item.
 func Deserialize(b []byte) (*Item, error) {
 	i := &Item{}
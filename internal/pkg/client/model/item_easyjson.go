@@ -76,6 +76,16 @@ func easyjsonA80d3b19DecodeGithubComK0st1aGophkeeperInternalPkgClientModel(in *j
 				}
 				easyjsonA80d3b19DecodeGithubComK0st1aGophkeeperInternalPkgClientModel4(in, out.File)
 			}
+		case "otp":
+			if in.IsNull() {
+				in.Skip()
+				out.OTP = nil
+			} else {
+				if out.OTP == nil {
+					out.OTP = new(OTP)
+				}
+				easyjsonA80d3b19DecodeGithubComK0st1aGophkeeperInternalPkgClientModel5(in, out.OTP)
+			}
 		case "meta":
 			if in.IsNull() {
 				in.Skip()
@@ -142,6 +152,11 @@ func easyjsonA80d3b19EncodeGithubComK0st1aGophkeeperInternalPkgClientModel(out *
 			easyjsonA80d3b19EncodeGithubComK0st1aGophkeeperInternalPkgClientModel4(out, *in.File)
 		}
 	}
+	if in.OTP != nil {
+		const prefix string = ",\"otp\":"
+		out.RawString(prefix)
+		easyjsonA80d3b19EncodeGithubComK0st1aGophkeeperInternalPkgClientModel5(out, *in.OTP)
+	}
 	{
 		const prefix string = ",\"meta\":"
 		out.RawString(prefix)
@@ -189,6 +204,97 @@ func (v *Item) UnmarshalJSON(data []byte) error {
 func (v *Item) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjsonA80d3b19DecodeGithubComK0st1aGophkeeperInternalPkgClientModel(l, v)
 }
+func easyjsonA80d3b19DecodeGithubComK0st1aGophkeeperInternalPkgClientModel5(in *jlexer.Lexer, out *OTP) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "issuer":
+			out.Issuer = string(in.String())
+		case "account":
+			out.Account = string(in.String())
+		case "type":
+			out.Type = string(in.String())
+		case "secret":
+			out.Secret = string(in.String())
+		case "algorithm":
+			out.Algorithm = string(in.String())
+		case "digits":
+			out.Digits = int(in.Int())
+		case "period":
+			out.Period = int64(in.Int64())
+		case "counter":
+			out.Counter = uint64(in.Uint64())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonA80d3b19EncodeGithubComK0st1aGophkeeperInternalPkgClientModel5(out *jwriter.Writer, in OTP) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"issuer\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Issuer))
+	}
+	{
+		const prefix string = ",\"account\":"
+		out.RawString(prefix)
+		out.String(string(in.Account))
+	}
+	{
+		const prefix string = ",\"type\":"
+		out.RawString(prefix)
+		out.String(string(in.Type))
+	}
+	{
+		const prefix string = ",\"secret\":"
+		out.RawString(prefix)
+		out.String(string(in.Secret))
+	}
+	{
+		const prefix string = ",\"algorithm\":"
+		out.RawString(prefix)
+		out.String(string(in.Algorithm))
+	}
+	{
+		const prefix string = ",\"digits\":"
+		out.RawString(prefix)
+		out.Int(int(in.Digits))
+	}
+	{
+		const prefix string = ",\"period\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.Period))
+	}
+	{
+		const prefix string = ",\"counter\":"
+		out.RawString(prefix)
+		out.Uint64(uint64(in.Counter))
+	}
+	out.RawByte('}')
+}
 func easyjsonA80d3b19DecodeGithubComK0st1aGophkeeperInternalPkgClientModel4(in *jlexer.Lexer, out *File) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
@@ -208,6 +314,16 @@ func easyjsonA80d3b19DecodeGithubComK0st1aGophkeeperInternalPkgClientModel4(in *
 			continue
 		}
 		switch key {
+		case "blob":
+			if in.IsNull() {
+				in.Skip()
+				out.Blob = nil
+			} else {
+				if out.Blob == nil {
+					out.Blob = new(Blob)
+				}
+				easyjsonA80d3b19DecodeGithubComK0st1aGophkeeperInternalPkgClientModel6(in, out.Blob)
+			}
 		case "name":
 			out.Name = string(in.String())
 		case "body":
@@ -231,18 +347,78 @@ func easyjsonA80d3b19EncodeGithubComK0st1aGophkeeperInternalPkgClientModel4(out
 	out.RawByte('{')
 	first := true
 	_ = first
+	if in.Blob != nil {
+		const prefix string = ",\"blob\":"
+		first = false
+		out.RawString(prefix[1:])
+		easyjsonA80d3b19EncodeGithubComK0st1aGophkeeperInternalPkgClientModel6(out, *in.Blob)
+	}
 	{
 		const prefix string = ",\"name\":"
-		out.RawString(prefix[1:])
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
 		out.String(string(in.Name))
 	}
-	{
+	if len(in.Body) != 0 {
 		const prefix string = ",\"body\":"
 		out.RawString(prefix)
 		out.Base64Bytes(in.Body)
 	}
 	out.RawByte('}')
 }
+func easyjsonA80d3b19DecodeGithubComK0st1aGophkeeperInternalPkgClientModel6(in *jlexer.Lexer, out *Blob) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.ID = string(in.String())
+		case "size":
+			out.Size = int64(in.Int64())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonA80d3b19EncodeGithubComK0st1aGophkeeperInternalPkgClientModel6(out *jwriter.Writer, in Blob) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.ID))
+	}
+	{
+		const prefix string = ",\"size\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.Size))
+	}
+	out.RawByte('}')
+}
 func easyjsonA80d3b19DecodeGithubComK0st1aGophkeeperInternalPkgClientModel3(in *jlexer.Lexer, out *Note) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
@@ -0,0 +1,39 @@
+package model
+
+const (
+	OTPTypeTOTP = "totp" // Одноразовый пароль по времени, RFC 6238
+	OTPTypeHOTP = "hotp" // Одноразовый пароль по счетчику, RFC 4226
+
+	OTPAlgorithmSHA1   = "SHA1"
+	OTPAlgorithmSHA256 = "SHA256"
+	OTPAlgorithmSHA512 = "SHA512"
+
+	OTPDefaultDigits = 6
+	OTPDefaultPeriod = 30
+)
+
+// OTP - описание генератора одноразовых паролей.
+//
+//easyjson:json
+type OTP struct {
+	Issuer    string `json:"issuer"`    // Сервис, выдавший секрет
+	Account   string `json:"account"`   // Учетная запись в сервисе
+	Type      string `json:"type"`      // Тип одноразового пароля: totp или hotp
+	Secret    string `json:"secret"`    // Секрет в кодировке base32
+	Algorithm string `json:"algorithm"` // Алгоритм HMAC: SHA1, SHA256 или SHA512
+	Digits    int    `json:"digits"`    // Количество цифр в пароле
+	Period    int64  `json:"period"`    // Время действия пароля в секундах для totp
+	Counter   uint64 `json:"counter"`   // Счетчик для hotp
+}
+
+func (o *OTP) GetName() string {
+	if o.Issuer == "" {
+		return o.Account
+	}
+
+	if o.Account == "" {
+		return o.Issuer
+	}
+
+	return o.Issuer + ":" + o.Account
+}
@@ -0,0 +1,75 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOTPGetName(t *testing.T) {
+	tests := []struct {
+		name         string
+		otp          OTP
+		expectedName string
+	}{
+		{
+			name: "Check GetName for OTP",
+			otp: OTP{
+				Issuer:  "Issuer",
+				Account: "Account",
+			},
+			expectedName: "Issuer:Account",
+		},
+		{
+			name: "Check GetName for OTP without issuer",
+			otp: OTP{
+				Account: "Account",
+			},
+			expectedName: "Account",
+		},
+		{
+			name: "Check GetName for OTP without account",
+			otp: OTP{
+				Issuer: "Issuer",
+			},
+			expectedName: "Issuer",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expectedName, test.otp.GetName())
+		})
+	}
+}
+
+func TestOTPSerialize(t *testing.T) {
+	i := &Item{
+		OTP: &OTP{
+			Issuer:    "Issuer",
+			Account:   "Account",
+			Type:      OTPTypeTOTP,
+			Secret:    "JBSWY3DPEHPK3PXP",
+			Algorithm: OTPAlgorithmSHA256,
+			Digits:    8,
+			Period:    60,
+		},
+		Meta: Meta{
+			MetaKeyDescription: "OTP description",
+		},
+	}
+
+	b, err := Serialize(i)
+	require.NoError(t, err)
+
+	got, err := Deserialize(b)
+	require.NoError(t, err)
+	require.Equal(t, i, got)
+
+	body, err := got.GetBody()
+	require.NoError(t, err)
+	require.Equal(t, i.OTP, body)
+
+	b, err = Serialize(&Item{Note: &Note{Name: "Name"}})
+	require.NoError(t, err)
+	require.NotContains(t, string(b), `"otp"`)
+}
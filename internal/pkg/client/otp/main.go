@@ -0,0 +1,207 @@
+// Package otp generates one-time passwords (RFC 4226 HOTP, RFC 6238 TOTP) and parses otpauth URI.
+package otp
+
+import (
+	"crypto/sha1" //nolint:gosec // HMAC-SHA1 is required by RFC 4226
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"hash"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/k0st1a/gophkeeper/internal/pkg/client/model"
+	"github.com/k0st1a/gophkeeper/internal/pkg/hotp"
+)
+
+const (
+	scheme     = "otpauth"
+	minDigits  = 6
+	maxDigits  = 10
+	maxPeriod  = 24 * 60 * 60
+	labelDelim = ":"
+)
+
+var (
+	ErrBadURI       = errors.New("bad otpauth uri")
+	ErrBadType      = errors.New("bad otp type")
+	ErrBadSecret    = errors.New("bad otp secret")
+	ErrBadAlgorithm = errors.New("bad otp algorithm")
+	ErrBadDigits    = errors.New("bad otp digits")
+	ErrBadPeriod    = errors.New("bad otp period")
+)
+
+// New - создать TOTP с параметрами по умолчанию.
+func New(issuer, account, secret string) (*model.OTP, error) {
+	o := &model.OTP{
+		Issuer:    issuer,
+		Account:   account,
+		Type:      model.OTPTypeTOTP,
+		Secret:    normalizeSecret(secret),
+		Algorithm: model.OTPAlgorithmSHA1,
+		Digits:    model.OTPDefaultDigits,
+		Period:    model.OTPDefaultPeriod,
+	}
+
+	err := Validate(o)
+	if err != nil {
+		return nil, err
+	}
+
+	return o, nil
+}
+
+// ParseURI - разобрать URI вида otpauth://TYPE/ISSUER:ACCOUNT?secret=SECRET&issuer=ISSUER&algorithm=ALGORITHM
+// &digits=DIGITS&period=PERIOD&counter=COUNTER.
+func ParseURI(uri string) (*model.OTP, error) {
+	u, err := url.Parse(strings.TrimSpace(uri))
+	if err != nil {
+		return nil, fmt.Errorf("%w:%w", ErrBadURI, err)
+	}
+
+	if u.Scheme != scheme {
+		return nil, fmt.Errorf("%w: unexpected scheme:%v", ErrBadURI, u.Scheme)
+	}
+
+	q := u.Query()
+	o := &model.OTP{
+		Type:      strings.ToLower(u.Host),
+		Secret:    normalizeSecret(q.Get("secret")),
+		Algorithm: model.OTPAlgorithmSHA1,
+		Digits:    model.OTPDefaultDigits,
+	}
+
+	label := strings.TrimPrefix(u.Path, "/")
+	issuer, account, ok := strings.Cut(label, labelDelim)
+	if ok {
+		o.Issuer = strings.TrimSpace(issuer)
+		o.Account = strings.TrimSpace(account)
+	} else {
+		o.Account = strings.TrimSpace(label)
+	}
+
+	// Параметр issuer приоритетнее префикса метки.
+	if v := q.Get("issuer"); v != "" {
+		o.Issuer = v
+	}
+
+	if v := q.Get("algorithm"); v != "" {
+		o.Algorithm = strings.ToUpper(v)
+	}
+
+	if v := q.Get("digits"); v != "" {
+		o.Digits, err = strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("%w:%w", ErrBadDigits, err)
+		}
+	}
+
+	switch o.Type {
+	case model.OTPTypeTOTP:
+		o.Period = model.OTPDefaultPeriod
+		if v := q.Get("period"); v != "" {
+			o.Period, err = strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%w:%w", ErrBadPeriod, err)
+			}
+		}
+	case model.OTPTypeHOTP:
+		o.Counter, err = strconv.ParseUint(q.Get("counter"), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: bad counter:%w", ErrBadURI, err)
+		}
+	}
+
+	err = Validate(o)
+	if err != nil {
+		return nil, err
+	}
+
+	return o, nil
+}
+
+// Validate - проверить параметры генератора одноразовых паролей.
+func Validate(o *model.OTP) error {
+	if o.Type != model.OTPTypeTOTP && o.Type != model.OTPTypeHOTP {
+		return fmt.Errorf("%w:%v", ErrBadType, o.Type)
+	}
+
+	_, err := decodeSecret(o.Secret)
+	if err != nil {
+		return err
+	}
+
+	_, err = hashFunc(o.Algorithm)
+	if err != nil {
+		return err
+	}
+
+	if o.Digits < minDigits || o.Digits > maxDigits {
+		return fmt.Errorf("%w:%v", ErrBadDigits, o.Digits)
+	}
+
+	if o.Type == model.OTPTypeTOTP && (o.Period <= 0 || o.Period > maxPeriod) {
+		return fmt.Errorf("%w:%v", ErrBadPeriod, o.Period)
+	}
+
+	return nil
+}
+
+// Code - получить одноразовый пароль на момент t и время, через которое он истечет.
+// Пароль hotp не истекает по времени, для него возвращается нулевое время.
+func Code(o *model.OTP, t time.Time) (string, time.Duration, error) {
+	err := Validate(o)
+	if err != nil {
+		return "", 0, err
+	}
+
+	key, _ := decodeSecret(o.Secret)
+	h, _ := hashFunc(o.Algorithm)
+
+	if o.Type == model.OTPTypeHOTP {
+		return hotp.Generate(h, key, o.Counter, o.Digits), 0, nil
+	}
+
+	now := t.Unix()
+	counter := uint64(now / o.Period) //nolint:gosec // unix time after 1970
+	remaining := time.Duration(o.Period-now%o.Period) * time.Second
+
+	return hotp.Generate(h, key, counter, o.Digits), remaining, nil
+}
+
+func hashFunc(algorithm string) (func() hash.Hash, error) {
+	switch algorithm {
+	case model.OTPAlgorithmSHA1:
+		return sha1.New, nil
+	case model.OTPAlgorithmSHA256:
+		return sha256.New, nil
+	case model.OTPAlgorithmSHA512:
+		return sha512.New, nil
+	default:
+		return nil, fmt.Errorf("%w:%v", ErrBadAlgorithm, algorithm)
+	}
+}
+
+func decodeSecret(secret string) ([]byte, error) {
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	if err != nil {
+		return nil, fmt.Errorf("%w:%w", ErrBadSecret, err)
+	}
+
+	if len(key) == 0 {
+		return nil, fmt.Errorf("%w: empty secret", ErrBadSecret)
+	}
+
+	return key, nil
+}
+
+// normalizeSecret - привести секрет к виду, в котором его выдают сервисы: без пробелов, дополнения
+// и в верхнем регистре.
+func normalizeSecret(secret string) string {
+	secret = strings.ToUpper(strings.ReplaceAll(secret, " ", ""))
+	return strings.TrimRight(secret, "=")
+}
@@ -0,0 +1,192 @@
+package otp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/k0st1a/gophkeeper/internal/pkg/client/model"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	// Секреты из тестовых векторов RFC 4226 и RFC 6238 в base32.
+	secretSHA1   = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+	secretSHA256 = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQGEZA"
+	secretSHA512 = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ" +
+		"GEZDGNBVGY3TQOJQGEZDGNA"
+)
+
+func TestHOTP(t *testing.T) {
+	// RFC 4226, Appendix D.
+	expected := []string{
+		"755224", "287082", "359152", "969429", "338314",
+		"254676", "287922", "162583", "399871", "520489",
+	}
+
+	for counter, code := range expected {
+		o := &model.OTP{
+			Type:      model.OTPTypeHOTP,
+			Secret:    secretSHA1,
+			Algorithm: model.OTPAlgorithmSHA1,
+			Digits:    6,
+			Counter:   uint64(counter),
+		}
+
+		got, remaining, err := Code(o, time.Now())
+		require.NoError(t, err)
+		require.Equal(t, code, got)
+		require.Zero(t, remaining)
+	}
+}
+
+func TestTOTP(t *testing.T) {
+	// RFC 6238, Appendix B.
+	tests := []struct {
+		name      string
+		unix      int64
+		algorithm string
+		secret    string
+		code      string
+	}{
+		{name: "SHA1 59", unix: 59, algorithm: model.OTPAlgorithmSHA1, secret: secretSHA1, code: "94287082"},
+		{name: "SHA256 59", unix: 59, algorithm: model.OTPAlgorithmSHA256, secret: secretSHA256, code: "46119246"},
+		{name: "SHA512 59", unix: 59, algorithm: model.OTPAlgorithmSHA512, secret: secretSHA512, code: "90693936"},
+		{name: "SHA1 1111111109", unix: 1111111109, algorithm: model.OTPAlgorithmSHA1, secret: secretSHA1,
+			code: "07081804"},
+		{name: "SHA256 1234567890", unix: 1234567890, algorithm: model.OTPAlgorithmSHA256, secret: secretSHA256,
+			code: "91819424"},
+		{name: "SHA512 20000000000", unix: 20000000000, algorithm: model.OTPAlgorithmSHA512, secret: secretSHA512,
+			code: "47863826"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			o := &model.OTP{
+				Type:      model.OTPTypeTOTP,
+				Secret:    test.secret,
+				Algorithm: test.algorithm,
+				Digits:    8,
+				Period:    30,
+			}
+
+			code, remaining, err := Code(o, time.Unix(test.unix, 0))
+			require.NoError(t, err)
+			require.Equal(t, test.code, code)
+			require.Equal(t, time.Duration(30-test.unix%30)*time.Second, remaining)
+		})
+	}
+}
+
+func TestParseURI(t *testing.T) {
+	tests := []struct {
+		name string
+		uri  string
+		otp  *model.OTP
+		err  error
+	}{
+		{
+			name: "Check TOTP with all parameters",
+			uri: "otpauth://totp/ACME%20Co:john.doe@email.com?secret=HXDMVJECJJWSRB3HWIZR4IFUGFTMXBOZ" +
+				"&issuer=ACME%20Co&algorithm=SHA256&digits=8&period=60",
+			otp: &model.OTP{
+				Issuer:    "ACME Co",
+				Account:   "john.doe@email.com",
+				Type:      model.OTPTypeTOTP,
+				Secret:    "HXDMVJECJJWSRB3HWIZR4IFUGFTMXBOZ",
+				Algorithm: model.OTPAlgorithmSHA256,
+				Digits:    8,
+				Period:    60,
+			},
+		},
+		{
+			name: "Check TOTP with defaults",
+			uri:  "otpauth://totp/alice@google.com?secret=jbsw%20y3dp%20ehpk%203pxp",
+			otp: &model.OTP{
+				Account:   "alice@google.com",
+				Type:      model.OTPTypeTOTP,
+				Secret:    "JBSWY3DPEHPK3PXP",
+				Algorithm: model.OTPAlgorithmSHA1,
+				Digits:    6,
+				Period:    30,
+			},
+		},
+		{
+			name: "Check HOTP",
+			uri:  "otpauth://hotp/Example:alice?secret=JBSWY3DPEHPK3PXP&counter=42",
+			otp: &model.OTP{
+				Issuer:    "Example",
+				Account:   "alice",
+				Type:      model.OTPTypeHOTP,
+				Secret:    "JBSWY3DPEHPK3PXP",
+				Algorithm: model.OTPAlgorithmSHA1,
+				Digits:    6,
+				Counter:   42,
+			},
+		},
+		{
+			name: "Check bad scheme",
+			uri:  "https://totp/alice?secret=JBSWY3DPEHPK3PXP",
+			err:  ErrBadURI,
+		},
+		{
+			name: "Check bad type",
+			uri:  "otpauth://motp/alice?secret=JBSWY3DPEHPK3PXP",
+			err:  ErrBadType,
+		},
+		{
+			name: "Check bad secret",
+			uri:  "otpauth://totp/alice?secret=1234",
+			err:  ErrBadSecret,
+		},
+		{
+			name: "Check empty secret",
+			uri:  "otpauth://totp/alice",
+			err:  ErrBadSecret,
+		},
+		{
+			name: "Check bad algorithm",
+			uri:  "otpauth://totp/alice?secret=JBSWY3DPEHPK3PXP&algorithm=MD5",
+			err:  ErrBadAlgorithm,
+		},
+		{
+			name: "Check bad digits",
+			uri:  "otpauth://totp/alice?secret=JBSWY3DPEHPK3PXP&digits=4",
+			err:  ErrBadDigits,
+		},
+		{
+			name: "Check bad period",
+			uri:  "otpauth://totp/alice?secret=JBSWY3DPEHPK3PXP&period=0",
+			err:  ErrBadPeriod,
+		},
+		{
+			name: "Check HOTP without counter",
+			uri:  "otpauth://hotp/alice?secret=JBSWY3DPEHPK3PXP",
+			err:  ErrBadURI,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			o, err := ParseURI(test.uri)
+			require.ErrorIs(t, err, test.err)
+			require.Equal(t, test.otp, o)
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	o, err := New("Issuer", "Account", "jbsw y3dp ehpk 3pxp====")
+	require.NoError(t, err)
+	require.Equal(t, &model.OTP{
+		Issuer:    "Issuer",
+		Account:   "Account",
+		Type:      model.OTPTypeTOTP,
+		Secret:    "JBSWY3DPEHPK3PXP",
+		Algorithm: model.OTPAlgorithmSHA1,
+		Digits:    model.OTPDefaultDigits,
+		Period:    model.OTPDefaultPeriod,
+	}, o)
+
+	_, err = New("Issuer", "Account", "")
+	require.ErrorIs(t, err, ErrBadSecret)
+}
@@ -0,0 +1,105 @@
+// Package recovery implements the recovery key and the emergency kit of the user.
+//
+// Ключ восстановления - случайный ключ, который генерирует клиент. Им, как и мастер-паролем, шифруется ключ
+// хранилища, поэтому по нему можно установить новый мастер-пароль, не потеряв данные. Сервер хранит только
+// ключ хранилища, зашифрованный ключом восстановления, и хэш значения Auth, которым клиент доказывает знание
+// ключа восстановления. Ключ восстановления записывается в аварийный набор (emergency kit), который
+// пользователь печатает и хранит отдельно от устройств.
+package recovery
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// Длина ключа восстановления в байтах, 160 бит.
+	keySize = 20
+	// Количество символов в группе при выводе ключа.
+	groupSize = 4
+	// Домен значения Auth, чтобы оно не совпадало с другими значениями, выработанными из ключа.
+	authDomain = "gophkeeper recovery auth"
+)
+
+var ErrInvalidKey = errors.New("invalid recovery key")
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// NewKey - сгенерировать новый ключ восстановления в виде групп символов base32, разделенных дефисами.
+func NewKey() (string, error) {
+	b := make([]byte, keySize)
+
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", fmt.Errorf("error of generate recovery key:%w", err)
+	}
+
+	s := encoding.EncodeToString(b)
+
+	groups := make([]string, 0, len(s)/groupSize)
+	for i := 0; i < len(s); i += groupSize {
+		groups = append(groups, s[i:i+groupSize])
+	}
+
+	return strings.Join(groups, "-"), nil
+}
+
+// Normalize - привести введенный пользователем ключ восстановления к каноническому виду: без дефисов
+// и пробелов, в верхнем регистре. Возвращает ErrInvalidKey, если это не ключ восстановления.
+func Normalize(key string) (string, error) {
+	s := strings.ToUpper(strings.NewReplacer("-", "", " ", "", "\t", "").Replace(key))
+
+	b, err := encoding.DecodeString(s)
+	if err != nil || len(b) != keySize {
+		return "", ErrInvalidKey
+	}
+
+	return s, nil
+}
+
+// Auth - значение, которым клиент доказывает серверу знание ключа восстановления. По нему нельзя получить
+// ключ восстановления, поэтому его можно отправлять на сервер. key должен быть нормализован.
+func Auth(key string) []byte {
+	m := hmac.New(sha256.New, []byte(key))
+	m.Write([]byte(authDomain))
+
+	return m.Sum(nil)
+}
+
+// Kit - аварийный набор пользователя.
+type Kit struct {
+	CreateTime time.Time
+	Login      string
+	Server     string
+	Key        string
+}
+
+// Lines - строки аварийного набора для вывода пользователю.
+func (k *Kit) Lines() []string {
+	return []string{
+		"GophKeeper Emergency Kit",
+		"",
+		"Created: " + k.CreateTime.Format(time.DateTime),
+		"Server:  " + k.Server,
+		"Login:   " + k.Login,
+		"",
+		"Recovery key:",
+		"    " + k.Key,
+		"",
+		"The recovery key lets you set a new master password if you forget it.",
+		"Anyone who has it and your login can take over your account.",
+		"Print this kit or write the key down, keep it in a safe place",
+		"apart from your devices. A new recovery key makes this kit invalid.",
+	}
+}
+
+// Text - аварийный набор в виде текста.
+func (k *Kit) Text() string {
+	return strings.Join(k.Lines(), "\n") + "\n"
+}
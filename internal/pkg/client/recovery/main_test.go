@@ -0,0 +1,92 @@
+package recovery
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	key, err := NewKey()
+	require.NoError(t, err)
+
+	want := strings.ReplaceAll(key, "-", "")
+
+	tests := []struct {
+		name    string
+		key     string
+		want    string
+		wantErr error
+	}{
+		{
+			name: "Check Normalize of generated key",
+			key:  key,
+			want: want,
+		},
+		{
+			name: "Check Normalize of key in lower case with spaces",
+			key:  " " + strings.ToLower(strings.ReplaceAll(key, "-", " ")) + " ",
+			want: want,
+		},
+		{
+			name:    "Check Normalize of short key",
+			key:     key[:len(key)-5],
+			wantErr: ErrInvalidKey,
+		},
+		{
+			name:    "Check Normalize of key with invalid symbol",
+			key:     "1" + key[1:],
+			wantErr: ErrInvalidKey,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := Normalize(test.key)
+			if test.wantErr != nil {
+				require.ErrorIs(t, err, test.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestAuth(t *testing.T) {
+	key1, err := NewKey()
+	require.NoError(t, err)
+	key2, err := NewKey()
+	require.NoError(t, err)
+
+	n1, err := Normalize(key1)
+	require.NoError(t, err)
+	n2, err := Normalize(key2)
+	require.NoError(t, err)
+
+	require.Equal(t, Auth(n1), Auth(n1))
+	require.NotEqual(t, Auth(n1), Auth(n2))
+	require.NotContains(t, string(Auth(n1)), n1)
+}
+
+func TestKit(t *testing.T) {
+	k := &Kit{
+		CreateTime: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Login:      "user (1)",
+		Server:     "localhost:8080",
+		Key:        "ABCD-EFGH",
+	}
+
+	text := k.Text()
+	require.Contains(t, text, "Login:   user (1)")
+	require.Contains(t, text, "Server:  localhost:8080")
+	require.Contains(t, text, "ABCD-EFGH")
+
+	pdf := k.PDF()
+	require.True(t, bytes.HasPrefix(pdf, []byte("%PDF-1.4\n")))
+	require.True(t, bytes.HasSuffix(pdf, []byte("%%EOF\n")))
+	require.Contains(t, string(pdf), `(Login:   user \(1\)) '`)
+	require.Contains(t, string(pdf), "ABCD-EFGH")
+}
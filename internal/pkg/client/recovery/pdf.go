@@ -0,0 +1,77 @@
+package recovery
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+const (
+	// Размер страницы A4 в пунктах.
+	pageWidth  = 595
+	pageHeight = 842
+	// Отступ текста от края страницы.
+	margin = 72
+	// Размер шрифта и межстрочный интервал.
+	fontSize = 12
+	leading  = 18
+)
+
+// PDF - аварийный набор в виде одностраничного PDF для печати. Используется стандартный шрифт Courier,
+// поэтому символы вне ASCII заменяются на '?'.
+func (k *Kit) PDF() []byte {
+	var content bytes.Buffer
+
+	fmt.Fprintf(&content, "BT\n/F1 %d Tf\n%d TL\n%d %d Td\n", fontSize, leading, margin, pageHeight-margin)
+	for _, l := range k.Lines() {
+		fmt.Fprintf(&content, "(%s) '\n", escape(l))
+	}
+	content.WriteString("ET\n")
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] "+
+			"/Resources << /Font << /F1 4 0 R >> >> /Contents 5 0 R >>", pageWidth, pageHeight),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()),
+	}
+
+	var b bytes.Buffer
+
+	b.WriteString("%PDF-1.4\n")
+
+	offsets := make([]int, 0, len(objects))
+	for i, o := range objects {
+		offsets = append(offsets, b.Len())
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, o)
+	}
+
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, o := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", o)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	return b.Bytes()
+}
+
+// escape - экранировать строку для строкового литерала PDF.
+func escape(s string) string {
+	var b strings.Builder
+
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < ' ' || r > '~':
+			b.WriteByte('?')
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
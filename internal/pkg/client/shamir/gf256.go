@@ -0,0 +1,49 @@
+package shamir
+
+// Арифметика поля GF(2^8) по модулю многочлена x^8 + x^4 + x^3 + x + 1 (как в AES). Сложение и вычитание - XOR.
+// Умножение выполняется без таблиц и ветвлений по значениям, чтобы время не зависело от байтов секрета.
+
+// mul - произведение a и b в GF(2^8).
+func mul(a, b byte) byte {
+	var p byte
+
+	for range 8 {
+		// Маска из единиц, если младший бит b установлен.
+		p ^= a & -(b & 1)
+		// Умножение a на x с приведением по модулю 0x11b.
+		a = a<<1 ^ 0x1b&-(a>>7)
+		b >>= 1
+	}
+
+	return p
+}
+
+// inv - обратный к a элемент в GF(2^8): a^254, т.к. a^255 = 1. Для 0 возвращает 0.
+func inv(a byte) byte {
+	// a^254 = a^(2+4+8+16+32+64+128)
+	r := byte(1)
+	s := a
+
+	for range 7 {
+		s = mul(s, s)
+		r = mul(r, s)
+	}
+
+	return r
+}
+
+// div - частное a и b в GF(2^8), b не должно быть 0.
+func div(a, b byte) byte {
+	return mul(a, inv(b))
+}
+
+// eval - значение многочлена с коэффициентами c (c[0] - свободный член) в точке x по схеме Горнера.
+func eval(c []byte, x byte) byte {
+	var y byte
+
+	for i := len(c) - 1; i >= 0; i-- {
+		y = mul(y, x) ^ c[i]
+	}
+
+	return y
+}
@@ -0,0 +1,146 @@
+// Package shamir splits a secret into shares by Shamir's secret sharing over GF(256).
+//
+// Каждый байт секрета - свободный член случайного многочлена степени k-1, доля с номером x - значения всех
+// многочленов в точке x. Любые k долей восстанавливают секрет интерполяцией Лагранжа, а меньшее число долей
+// не дает о нем никакой информации. Все доли одного разбиения содержат отпечаток секрета, по которому
+// проверяется, что доли относятся к одному секрету и восстановлен именно он.
+package shamir
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+)
+
+const (
+	// Максимальное количество долей: номер доли - ненулевой элемент GF(256).
+	MaxShares = 255
+	// Размер отпечатка секрета в доле.
+	fingerprintSize = 4
+	// Домен отпечатка, чтобы он не совпадал с другими значениями, выработанными из секрета.
+	fingerprintDomain = "gophkeeper shamir fingerprint"
+)
+
+var (
+	ErrInvalidParams    = errors.New("invalid number of shares or threshold")
+	ErrNotEnoughShares  = errors.New("not enough shares")
+	ErrMismatchedShares = errors.New("shares are from different splits")
+	ErrDuplicateShare   = errors.New("duplicate share")
+	ErrWrongShares      = errors.New("shares do not restore secret")
+)
+
+// Share - доля секрета.
+type Share struct {
+	// Отпечаток секрета, одинаковый у всех долей.
+	Fingerprint [fingerprintSize]byte
+	// Значения многочленов в точке X, по одному на каждый байт секрета.
+	Y []byte
+	// Минимальное количество долей для восстановления секрета.
+	Threshold byte
+	// Номер доли, от 1 до MaxShares.
+	X byte
+}
+
+// Split - разделить secret на n долей, любые k из которых восстанавливают секрет, 2 <= k <= n <= MaxShares.
+func Split(secret []byte, n, k int) ([]Share, error) {
+	if k < 2 || k > n || n > MaxShares || len(secret) == 0 {
+		return nil, ErrInvalidParams
+	}
+
+	fp := fingerprint(secret)
+
+	shares := make([]Share, n)
+	for i := range shares {
+		shares[i] = Share{
+			Fingerprint: fp,
+			Y:           make([]byte, len(secret)),
+			Threshold:   byte(k),
+			X:           byte(i + 1),
+		}
+	}
+
+	c := make([]byte, k)
+	defer clear(c)
+
+	for i, b := range secret {
+		c[0] = b
+
+		_, err := rand.Read(c[1:])
+		if err != nil {
+			return nil, fmt.Errorf("error of generate coefficients:%w", err)
+		}
+
+		for j := range shares {
+			shares[j].Y[i] = eval(c, shares[j].X)
+		}
+	}
+
+	return shares, nil
+}
+
+// Combine - восстановить секрет по долям. Долей должно быть не меньше порога, все они должны относиться
+// к одному разбиению.
+func Combine(shares []Share) ([]byte, error) {
+	if len(shares) == 0 {
+		return nil, ErrNotEnoughShares
+	}
+
+	first := shares[0]
+	seen := make(map[byte]struct{}, len(shares))
+
+	for _, s := range shares {
+		if s.Fingerprint != first.Fingerprint || s.Threshold != first.Threshold || len(s.Y) != len(first.Y) {
+			return nil, ErrMismatchedShares
+		}
+
+		if s.X == 0 {
+			return nil, fmt.Errorf("%w:zero share number", ErrWrongShares)
+		}
+
+		if _, ok := seen[s.X]; ok {
+			return nil, fmt.Errorf("%w:%v", ErrDuplicateShare, s.X)
+		}
+		seen[s.X] = struct{}{}
+	}
+
+	if len(shares) < int(first.Threshold) {
+		return nil, fmt.Errorf("%w:need %v, got %v", ErrNotEnoughShares, first.Threshold, len(shares))
+	}
+
+	shares = shares[:first.Threshold]
+	secret := make([]byte, len(first.Y))
+
+	// Интерполяция Лагранжа в точке 0: secret = sum(y_j * prod(x_m / (x_m - x_j))), m != j.
+	for j, sj := range shares {
+		l := byte(1)
+		for m, sm := range shares {
+			if m != j {
+				l = mul(l, div(sm.X, sm.X^sj.X))
+			}
+		}
+
+		for i, y := range sj.Y {
+			secret[i] ^= mul(y, l)
+		}
+	}
+
+	if fingerprint(secret) != first.Fingerprint {
+		clear(secret)
+		return nil, ErrWrongShares
+	}
+
+	return secret, nil
+}
+
+// fingerprint - отпечаток секрета. Он короткий и не позволяет подобрать секрет, но отличает доли разных секретов.
+func fingerprint(secret []byte) [fingerprintSize]byte {
+	m := hmac.New(sha256.New, secret)
+	m.Write([]byte(fingerprintDomain))
+
+	var fp [fingerprintSize]byte
+	copy(fp[:], m.Sum(nil))
+
+	return fp
+}
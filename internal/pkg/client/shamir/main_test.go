@@ -0,0 +1,141 @@
+package shamir
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInv(t *testing.T) {
+	for a := 1; a < 256; a++ {
+		require.Equal(t, byte(1), mul(byte(a), inv(byte(a))), "a:%v", a)
+	}
+}
+
+func TestMul(t *testing.T) {
+	// Пример из FIPS-197: {57} * {83} = {c1}.
+	require.Equal(t, byte(0xc1), mul(0x57, 0x83))
+	require.Equal(t, byte(0), mul(0x57, 0))
+	require.Equal(t, byte(0x57), mul(0x57, 1))
+}
+
+func TestSplitCombine(t *testing.T) {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+
+	shares, err := Split(secret, 5, 3)
+	require.NoError(t, err)
+	require.Len(t, shares, 5)
+
+	// Любые 3 доли из 5 восстанавливают секрет.
+	for i := range shares {
+		for j := i + 1; j < len(shares); j++ {
+			for k := j + 1; k < len(shares); k++ {
+				got, err := Combine([]Share{shares[k], shares[i], shares[j]})
+				require.NoError(t, err)
+				require.Equal(t, secret, got)
+			}
+		}
+	}
+
+	got, err := Combine(shares)
+	require.NoError(t, err)
+	require.Equal(t, secret, got)
+}
+
+func TestCombineErrors(t *testing.T) {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+
+	shares, err := Split(secret, 3, 2)
+	require.NoError(t, err)
+
+	other, err := Split([]byte("fedcba9876543210fedcba9876543210"), 3, 2)
+	require.NoError(t, err)
+
+	corrupted := shares[1]
+	corrupted.Y = append([]byte(nil), corrupted.Y...)
+	corrupted.Y[0] ^= 1
+
+	tests := []struct {
+		name    string
+		shares  []Share
+		wantErr error
+	}{
+		{
+			name:    "Check Combine without shares",
+			wantErr: ErrNotEnoughShares,
+		},
+		{
+			name:    "Check Combine with shares less than threshold",
+			shares:  shares[:1],
+			wantErr: ErrNotEnoughShares,
+		},
+		{
+			name:    "Check Combine with duplicate share",
+			shares:  []Share{shares[0], shares[0]},
+			wantErr: ErrDuplicateShare,
+		},
+		{
+			name:    "Check Combine with shares of different secrets",
+			shares:  []Share{shares[0], other[1]},
+			wantErr: ErrMismatchedShares,
+		},
+		{
+			name:    "Check Combine with corrupted share",
+			shares:  []Share{shares[0], corrupted},
+			wantErr: ErrWrongShares,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Combine(test.shares)
+			require.ErrorIs(t, err, test.wantErr)
+		})
+	}
+}
+
+func TestSplitInvalidParams(t *testing.T) {
+	tests := []struct {
+		name string
+		n    int
+		k    int
+	}{
+		{name: "Check Split with threshold 1", n: 3, k: 1},
+		{name: "Check Split with threshold greater than shares", n: 2, k: 3},
+		{name: "Check Split with too many shares", n: MaxShares + 1, k: 2},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Split([]byte("secret"), test.n, test.k)
+			require.ErrorIs(t, err, ErrInvalidParams)
+		})
+	}
+}
+
+func TestStringParse(t *testing.T) {
+	shares, err := Split([]byte("0123456789abcdef0123456789abcdef"), 3, 2)
+	require.NoError(t, err)
+
+	text := shares[1].String()
+	require.True(t, strings.HasPrefix(text, prefix+"-"))
+	require.Equal(t, strings.ToUpper(text), text)
+
+	got, err := Parse(" " + strings.ToLower(strings.ReplaceAll(text, "-", " ")) + "\n")
+	require.NoError(t, err)
+	require.Equal(t, shares[1], got)
+
+	// Опечатка в одном символе выявляется контрольной суммой.
+	typo := []byte(text)
+	i := len(typo) - 10
+	if typo[i] == 'A' {
+		typo[i] = 'B'
+	} else {
+		typo[i] = 'A'
+	}
+
+	_, err = Parse(string(typo))
+	require.ErrorIs(t, err, ErrInvalidShare)
+
+	_, err = Parse("not a share")
+	require.ErrorIs(t, err, ErrInvalidShare)
+}
@@ -0,0 +1,77 @@
+package shamir
+
+import (
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"strings"
+)
+
+const (
+	// Префикс текстового вида доли.
+	prefix = "GKS"
+	// Версия формата доли.
+	version = 1
+	// Количество символов в группе текстового вида.
+	groupSize = 5
+	// Размер заголовка: версия, отпечаток, порог, номер доли.
+	headerSize = 1 + fingerprintSize + 1 + 1
+	// Размер контрольной суммы CRC-32.
+	checksumSize = 4
+)
+
+var ErrInvalidShare = errors.New("invalid share")
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// String - текстовый вид доли: префикс и группы символов base32, разделенные дефисами. Используются только
+// заглавные латинские буквы, цифры и дефис, поэтому строку можно без потерь закодировать в QR-код
+// в алфавитно-цифровом режиме. Контрольная сумма выявляет опечатки при ручном вводе.
+func (s *Share) String() string {
+	b := make([]byte, 0, headerSize+len(s.Y)+checksumSize)
+	b = append(b, version)
+	b = append(b, s.Fingerprint[:]...)
+	b = append(b, s.Threshold, s.X)
+	b = append(b, s.Y...)
+	b = binary.BigEndian.AppendUint32(b, crc32.ChecksumIEEE(b))
+
+	e := encoding.EncodeToString(b)
+
+	groups := []string{prefix}
+	for i := 0; i < len(e); i += groupSize {
+		groups = append(groups, e[i:min(i+groupSize, len(e))])
+	}
+
+	return strings.Join(groups, "-")
+}
+
+// Parse - разобрать текстовый вид доли. Регистр, пробелы и дефисы не важны.
+func Parse(text string) (Share, error) {
+	t := strings.ToUpper(strings.Join(strings.Fields(text), ""))
+	t = strings.ReplaceAll(t, "-", "")
+
+	t, ok := strings.CutPrefix(t, prefix)
+	if !ok {
+		return Share{}, ErrInvalidShare
+	}
+
+	b, err := encoding.DecodeString(t)
+	if err != nil || len(b) <= headerSize+checksumSize {
+		return Share{}, ErrInvalidShare
+	}
+
+	body, sum := b[:len(b)-checksumSize], b[len(b)-checksumSize:]
+	if crc32.ChecksumIEEE(body) != binary.BigEndian.Uint32(sum) || body[0] != version {
+		return Share{}, ErrInvalidShare
+	}
+
+	s := Share{
+		Threshold: body[1+fingerprintSize],
+		X:         body[2+fingerprintSize],
+		Y:         append([]byte(nil), body[headerSize:]...),
+	}
+	copy(s.Fingerprint[:], body[1:1+fingerprintSize])
+
+	return s, nil
+}
@@ -0,0 +1,67 @@
+// Package emergency periodically approves emergency access requests which the owner did not reject in time.
+package emergency
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Interval - период проверки запросов экстренного доступа.
+const Interval = time.Minute
+
+// Approver - одобрение запросов экстренного доступа.
+type Approver interface {
+	// ApproveExpiredEmergencyRequests - одобрить запросы, время автоматического одобрения которых наступило
+	// до now. Возвращает количество одобренных запросов.
+	ApproveExpiredEmergencyRequests(ctx context.Context, now time.Time) (int64, error)
+}
+
+type emergency struct {
+	approver Approver
+	now      func() time.Time
+	interval time.Duration
+}
+
+// New - создать задачу одобрения запросов экстренного доступа, где:
+//   - a - одобрение запросов;
+//   - i - период проверки запросов.
+func New(a Approver, i time.Duration) *emergency {
+	return &emergency{
+		approver: a,
+		now:      time.Now,
+		interval: i,
+	}
+}
+
+// Run - одобрять запросы сразу после запуска и далее с периодом проверки, пока не отменен ctx.
+// Запрос, не отклоненный владельцем, одобряется не позже чем через период проверки после срока.
+func (e *emergency) Run(ctx context.Context) error {
+	log.Printf("Run emergency access approve, interval:%v", e.interval)
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		e.do(ctx)
+
+		select {
+		case <-ctx.Done():
+			log.Printf("Emergency access approve closed with cause:%s", ctx.Err())
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (e *emergency) do(ctx context.Context) {
+	n, err := e.approver.ApproveExpiredEmergencyRequests(ctx, e.now())
+	if err != nil {
+		log.Error().Err(err).Msg("error of approve emergency access requests")
+		return
+	}
+
+	if n != 0 {
+		log.Printf("Emergency access requests approved:%v", n)
+	}
+}
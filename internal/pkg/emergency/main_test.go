@@ -0,0 +1,74 @@
+package emergency
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type approver struct {
+	mutex sync.Mutex
+	now   []time.Time
+	err   error
+}
+
+func (a *approver) ApproveExpiredEmergencyRequests(ctx context.Context, now time.Time) (int64, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.now = append(a.now, now)
+	return 1, a.err
+}
+
+func (a *approver) calls() []time.Time {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	return append([]time.Time(nil), a.now...)
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{
+			name: "Check approve",
+		},
+		{
+			name: "Check approve continues after error",
+			err:  errors.New("approve error"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			now := time.Date(2024, time.May, 5, 8, 10, 0, 0, time.UTC)
+
+			a := &approver{err: test.err}
+			j := New(a, 10*time.Millisecond)
+			j.now = func() time.Time { return now }
+
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan error)
+			go func() {
+				done <- j.Run(ctx)
+			}()
+
+			require.Eventually(t, func() bool {
+				return len(a.calls()) >= 2
+			}, time.Second, time.Millisecond)
+
+			cancel()
+			require.NoError(t, <-done)
+
+			for _, got := range a.calls() {
+				require.Equal(t, now, got)
+			}
+		})
+	}
+}
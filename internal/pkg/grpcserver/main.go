@@ -2,31 +2,54 @@
 package grpcserver
 
 import (
+	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"net"
+	"slices"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
 
 	"github.com/rs/zerolog/log"
 )
 
+// inprocBufferSize - размер буфера соединений внутри процесса.
+const inprocBufferSize = 1024 * 1024
+
 type Server struct {
 	Listener *net.Listener
 	Server   *grpc.Server
+	inproc   *bufconn.Listener
 }
 
 // New create grpc server, where:
 //   - address - server host and port;
-//   - server - launcing grpc api.
-func New(address string, server *grpc.Server) (*Server, error) {
+//   - server - launcing grpc api;
+//   - tc - TLS configuration, if nil then connections are accepted in plain text.
+//
+// TLS is terminated on listener, not by grpc credentials, so in process connections made by Dial
+// don't need certificates, while network connections are still checked, including client certificate for mTLS.
+func New(address string, server *grpc.Server, tc *tls.Config) (*Server, error) {
 	l, err := net.Listen("tcp", address)
 	if err != nil {
 		return nil, fmt.Errorf("net listen error:%w", err)
 	}
 
+	if tc != nil {
+		tc = tc.Clone()
+		if !slices.Contains(tc.NextProtos, "h2") {
+			tc.NextProtos = append(tc.NextProtos, "h2")
+		}
+		l = tls.NewListener(l, tc)
+	}
+
 	return &Server{
 		Listener: &l,
 		Server:   server,
+		inproc:   bufconn.Listen(inprocBufferSize),
 	}, nil
 }
 
@@ -34,6 +57,13 @@ func New(address string, server *grpc.Server) (*Server, error) {
 func (s *Server) Run() error {
 	log.Printf("Run grpc api")
 
+	go func() {
+		err := s.Server.Serve(s.inproc)
+		if err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			log.Error().Err(err).Msg("in process listen error")
+		}
+	}()
+
 	err := s.Server.Serve(*s.Listener)
 	if err != nil {
 		return fmt.Errorf("server listen error:%w", err)
@@ -42,6 +72,21 @@ func (s *Server) Run() error {
 	return nil
 }
 
+// Dial - соединение с сервером внутри процесса, например для HTTP шлюза.
+func (s *Server) Dial() (*grpc.ClientConn, error) {
+	cc, err := grpc.NewClient("passthrough:///inproc",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return s.inproc.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("in process dial error:%w", err)
+	}
+
+	return cc, nil
+}
+
 // Shutdown - graceful выключение сервера.
 func (s *Server) Shutdown() error {
 	s.Server.GracefulStop()
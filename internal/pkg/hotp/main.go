@@ -0,0 +1,27 @@
+// Package hotp generates HMAC-based one-time passwords (RFC 4226) for the server second factor and the client.
+package hotp
+
+import (
+	"crypto/hmac"
+	"encoding/binary"
+	"fmt"
+	"hash"
+)
+
+// Generate - HOTP(K, C) = Truncate(HMAC(K, C)) mod 10^digits, RFC 4226.
+// TOTP (RFC 6238) - это HOTP, где счетчик - номер интервала времени.
+func Generate(h func() hash.Hash, key []byte, counter uint64, digits int) string {
+	mac := hmac.New(h, key)
+	_ = binary.Write(mac, binary.BigEndian, counter)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	code := uint64(binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff)
+
+	mod := uint64(1)
+	for range digits {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", digits, code%mod)
+}
@@ -0,0 +1,23 @@
+package hotp
+
+import (
+	"crypto/sha1" //nolint:gosec // HMAC-SHA1 is required by RFC 4226
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	// RFC 4226, Appendix D.
+	key := []byte("12345678901234567890")
+	expected := []string{
+		"755224", "287082", "359152", "969429", "338314",
+		"254676", "287922", "162583", "399871", "520489",
+	}
+
+	for counter, code := range expected {
+		require.Equal(t, code, Generate(sha1.New, key, uint64(counter), 6))
+	}
+
+	require.Equal(t, "84755224", Generate(sha1.New, key, 0, 8))
+}
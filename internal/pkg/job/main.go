@@ -15,11 +15,12 @@ type StartStopper interface {
 
 type job struct {
 	cancel func()
-	do     tick.Runner
+	do     []tick.Runner
 	wg     sync.WaitGroup
 }
 
-func New(do tick.Runner) *job {
+// New - создать задачу, которая запускает runners параллельно.
+func New(do ...tick.Runner) *job {
 	return &job{
 		do:     do,
 		cancel: func() {},
@@ -32,14 +33,16 @@ func (j *job) Start(ctx context.Context) {
 	ctx, cancel := context.WithCancel(ctx)
 	j.cancel = cancel
 
-	j.wg.Add(1)
-	go func() {
-		defer j.wg.Done()
-		err := j.do.Run(ctx)
-		if err != nil {
-			log.Error().Err(err).Msg("failed to run Runner")
-		}
-	}()
+	for _, r := range j.do {
+		j.wg.Add(1)
+		go func() {
+			defer j.wg.Done()
+			err := r.Run(ctx)
+			if err != nil {
+				log.Error().Err(err).Msg("failed to run Runner")
+			}
+		}()
+	}
 	log.Ctx(ctx).Printf("Job started")
 }
 
@@ -0,0 +1,75 @@
+// Package lockout is policy of delays and lockout after failed login attempts.
+package lockout
+
+import "time"
+
+const (
+	// FreeAttempts - количество неудачных попыток подряд без задержки.
+	FreeAttempts = 3
+	// BaseDelay - задержка после первой неудачной попытки сверх FreeAttempts, далее удваивается с каждой попыткой.
+	BaseDelay = time.Second
+	// MaxDelay - наибольшая задержка между попытками до блокировки.
+	MaxDelay = 5 * time.Minute
+	// Threshold - количество неудачных попыток подряд, после которого вход блокируется на Duration.
+	Threshold = 10
+	// Duration - время блокировки входа.
+	Duration = 15 * time.Minute
+	// Window - неудачные попытки старше этого времени забываются.
+	Window = time.Hour
+
+	// StartFreeAttempts - количество начатых входов с одного IP адреса без задержки.
+	StartFreeAttempts = 20
+	// StartThreshold - количество начатых входов с одного IP адреса, после которого начало входа блокируется
+	// на Duration.
+	StartThreshold = 100
+)
+
+type Policy struct {
+	FreeAttempts int
+	Threshold    int
+	BaseDelay    time.Duration
+	MaxDelay     time.Duration
+	Duration     time.Duration
+	Window       time.Duration
+}
+
+// New - политика по умолчанию.
+func New() *Policy {
+	return &Policy{
+		FreeAttempts: FreeAttempts,
+		Threshold:    Threshold,
+		BaseDelay:    BaseDelay,
+		MaxDelay:     MaxDelay,
+		Duration:     Duration,
+		Window:       Window,
+	}
+}
+
+// NewStart - политика для начатых входов по SRP с одного IP адреса. Каждое начало входа сохраняется на сервере,
+// поэтому учитываются все начатые входы, а не только неудачные, и пороги выше, чем для неудачных попыток.
+func NewStart() *Policy {
+	p := New()
+	p.FreeAttempts = StartFreeAttempts
+	p.Threshold = StartThreshold
+
+	return p
+}
+
+// Delay - на сколько запрещаются попытки входа после failures неудачных попыток подряд.
+// Второе значение true, если это блокировка, а не задержка между попытками.
+func (p *Policy) Delay(failures int) (time.Duration, bool) {
+	if failures >= p.Threshold {
+		return p.Duration, true
+	}
+
+	if failures <= p.FreeAttempts {
+		return 0, false
+	}
+
+	d := p.BaseDelay
+	for i := p.FreeAttempts + 1; i < failures && d < p.MaxDelay; i++ {
+		d *= 2
+	}
+
+	return min(d, p.MaxDelay), false
+}
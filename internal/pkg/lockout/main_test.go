@@ -0,0 +1,79 @@
+package lockout
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDelay(t *testing.T) {
+	p := New()
+
+	tests := []struct {
+		name     string
+		failures int
+		delay    time.Duration
+		locked   bool
+	}{
+		{
+			name:     "Check free attempt",
+			failures: 1,
+		},
+		{
+			name:     "Check last free attempt",
+			failures: FreeAttempts,
+		},
+		{
+			name:     "Check first delay",
+			failures: FreeAttempts + 1,
+			delay:    BaseDelay,
+		},
+		{
+			name:     "Check exponential delay",
+			failures: FreeAttempts + 4,
+			delay:    8 * BaseDelay,
+		},
+		{
+			name:     "Check lockout",
+			failures: Threshold,
+			delay:    Duration,
+			locked:   true,
+		},
+		{
+			name:     "Check lockout after threshold",
+			failures: Threshold + 5,
+			delay:    Duration,
+			locked:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			d, locked := p.Delay(test.failures)
+			require.Equal(t, test.delay, d)
+			require.Equal(t, test.locked, locked)
+		})
+	}
+}
+
+func TestStartDelay(t *testing.T) {
+	p := NewStart()
+
+	d, locked := p.Delay(StartFreeAttempts)
+	require.Zero(t, d)
+	require.False(t, locked)
+
+	d, locked = p.Delay(StartThreshold)
+	require.Equal(t, Duration, d)
+	require.True(t, locked)
+}
+
+func TestMaxDelay(t *testing.T) {
+	p := New()
+	p.Threshold = 100
+
+	d, locked := p.Delay(50)
+	require.Equal(t, MaxDelay, d)
+	require.False(t, locked)
+}
@@ -0,0 +1,41 @@
+// Package peerip gets IP address of client of grpc request.
+package peerip
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+const xForwardedFor = "x-forwarded-for"
+
+// Get - IP адрес клиента запроса.
+//
+// Запросы HTTP шлюза приходят по соединению внутри процесса, у которого нет IP адреса, в этом случае
+// берется последний адрес из x-forwarded-for: его добавляет сам шлюз, остальные адреса присылает клиент.
+// У запросов по сети x-forwarded-for игнорируется, т.к. его может подделать клиент.
+func Get(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if ok {
+		if a, ok := p.Addr.(*net.TCPAddr); ok {
+			return a.IP.String()
+		}
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	xff := md.Get(xForwardedFor)
+	if len(xff) == 0 {
+		return ""
+	}
+
+	l := strings.Split(xff[len(xff)-1], ",")
+
+	return strings.TrimSpace(l[len(l)-1])
+}
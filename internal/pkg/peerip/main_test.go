@@ -0,0 +1,51 @@
+package peerip
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+type pipeAddr struct{}
+
+func (pipeAddr) Network() string { return "pipe" }
+func (pipeAddr) String() string  { return "pipe" }
+
+func Test(t *testing.T) {
+	tests := []struct {
+		addr net.Addr
+		md   metadata.MD
+		name string
+		ip   string
+	}{
+		{
+			name: "Check tcp peer",
+			addr: &net.TCPAddr{IP: net.ParseIP("192.0.2.1"), Port: 5000},
+			md:   metadata.Pairs(xForwardedFor, "198.51.100.1"),
+			ip:   "192.0.2.1",
+		},
+		{
+			name: "Check gateway peer",
+			addr: pipeAddr{},
+			md:   metadata.Pairs(xForwardedFor, "198.51.100.1, 203.0.113.7"),
+			ip:   "203.0.113.7",
+		},
+		{
+			name: "Check gateway peer without x-forwarded-for",
+			addr: pipeAddr{},
+			md:   metadata.MD{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: test.addr})
+			ctx = metadata.NewIncomingContext(ctx, test.md)
+			require.Equal(t, test.ip, Get(ctx))
+		})
+	}
+}
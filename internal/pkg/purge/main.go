@@ -0,0 +1,91 @@
+// Package purge periodically removes items which stay in trash longer than retention period
+// and blobs which are not uploaded or not referenced by items longer than blob TTL.
+package purge
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Interval - период очистки корзины и блобов.
+const Interval = time.Hour
+
+// Purger - окончательное удаление предметов из корзины и брошенных блобов.
+type Purger interface {
+	// PurgeTrash - удалить предметы, попавшие в корзину раньше before. Возвращает количество удаленных предметов.
+	PurgeTrash(ctx context.Context, before time.Time) (int64, error)
+	// PurgeBlobs - удалить созданные раньше before блобы, которые не загружены до конца или на которые не ссылается
+	// ни один предмет. Возвращает количество удаленных блобов.
+	PurgeBlobs(ctx context.Context, before time.Time) (int64, error)
+}
+
+type purge struct {
+	purger    Purger
+	now       func() time.Time
+	retention time.Duration
+	blobTTL   time.Duration
+	interval  time.Duration
+}
+
+// New - создать задачу очистки корзины и блобов, где:
+//   - p - удаление предметов и блобов;
+//   - r - срок хранения предметов в корзине;
+//   - b - срок, за который блоб должен быть загружен и привязан к предмету;
+//   - i - период очистки.
+func New(p Purger, r, b, i time.Duration) *purge {
+	return &purge{
+		purger:    p,
+		now:       time.Now,
+		retention: r,
+		blobTTL:   b,
+		interval:  i,
+	}
+}
+
+// Run - очищать корзину и блобы сразу после запуска и далее с периодом очистки, пока не отменен ctx.
+func (p *purge) Run(ctx context.Context) error {
+	log.Printf("Run trash purge, retention:%v, blob ttl:%v, interval:%v", p.retention, p.blobTTL, p.interval)
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		p.do(ctx)
+
+		select {
+		case <-ctx.Done():
+			log.Printf("Trash purge closed with cause:%s", ctx.Err())
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *purge) do(ctx context.Context) {
+	now := p.now()
+
+	// Блобы очищаются после корзины, чтобы сразу удалить блобы окончательно удаленных предметов.
+	p.purgeTrash(ctx, now)
+	p.purgeBlobs(ctx, now)
+}
+
+func (p *purge) purgeTrash(ctx context.Context, now time.Time) {
+	n, err := p.purger.PurgeTrash(ctx, now.Add(-p.retention))
+	if err != nil {
+		log.Error().Err(err).Msg("error of purge trash")
+		return
+	}
+
+	log.Printf("Trash purged, items:%v", n)
+}
+
+func (p *purge) purgeBlobs(ctx context.Context, now time.Time) {
+	n, err := p.purger.PurgeBlobs(ctx, now.Add(-p.blobTTL))
+	if err != nil {
+		log.Error().Err(err).Msg("error of purge blobs")
+		return
+	}
+
+	log.Printf("Blobs purged, blobs:%v", n)
+}
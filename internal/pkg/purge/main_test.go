@@ -0,0 +1,91 @@
+package purge
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type purger struct {
+	mutex  sync.Mutex
+	before []time.Time
+	blobs  []time.Time
+	err    error
+}
+
+func (p *purger) PurgeTrash(ctx context.Context, before time.Time) (int64, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.before = append(p.before, before)
+	return 1, p.err
+}
+
+func (p *purger) PurgeBlobs(ctx context.Context, before time.Time) (int64, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.blobs = append(p.blobs, before)
+	return 1, p.err
+}
+
+func (p *purger) calls() ([]time.Time, []time.Time) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return append([]time.Time(nil), p.before...), append([]time.Time(nil), p.blobs...)
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{
+			name: "Check purge",
+		},
+		{
+			name: "Check purge continues after error",
+			err:  errors.New("purge error"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			now := time.Date(2024, time.May, 5, 8, 10, 0, 0, time.UTC)
+			retention := 24 * time.Hour
+			blobTTL := time.Hour
+
+			p := &purger{err: test.err}
+			j := New(p, retention, blobTTL, 10*time.Millisecond)
+			j.now = func() time.Time { return now }
+
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan error)
+			go func() {
+				done <- j.Run(ctx)
+			}()
+
+			require.Eventually(t, func() bool {
+				_, blobs := p.calls()
+				return len(blobs) >= 2
+			}, time.Second, time.Millisecond)
+
+			cancel()
+			require.NoError(t, <-done)
+
+			trash, blobs := p.calls()
+			for _, before := range trash {
+				require.Equal(t, now.Add(-retention), before)
+			}
+
+			for _, before := range blobs {
+				require.Equal(t, now.Add(-blobTTL), before)
+			}
+		})
+	}
+}
@@ -0,0 +1,17 @@
+// Package sessionid Add/Get session id from context.
+package sessionid
+
+import "context"
+
+type sessionIDKey struct{}
+
+// Set adds session id to context.
+func Set(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, sessionIDKey{}, id)
+}
+
+// Get gets session id from context.
+func Get(ctx context.Context) (string, bool) {
+	i, ok := ctx.Value(sessionIDKey{}).(string)
+	return i, ok
+}
@@ -0,0 +1,30 @@
+package sessionid
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         string
+		expectedID string
+	}{
+		{
+			name:       "Check Set and Get",
+			id:         "3f0c1b8e-2d4a-4a43-9d5e-6f1b2c3d4e5f",
+			expectedID: "3f0c1b8e-2d4a-4a43-9d5e-6f1b2c3d4e5f",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := Set(context.Background(), test.id)
+			id, ok := Get(ctx)
+			require.True(t, ok)
+			require.Equal(t, test.expectedID, id)
+		})
+	}
+}
@@ -0,0 +1,230 @@
+// Package srp implements SRP-6a password-authenticated key exchange (RFC 2945, RFC 5054).
+//
+// Сервер хранит только соль и верификатор v = g^x mod N, пароль на сервер не передается.
+// Закрытое значение x вырабатывается из логина и пароля с помощью Argon2id, как и ключ шифрования ключа
+// хранилища, поэтому перебор паролей по украденному верификатору так же дорог, как и по ключу хранилища.
+//
+// Обмен:
+//
+//	клиент -> сервер: I, A = g^a
+//	сервер -> клиент: s, B = k*v + g^b
+//	клиент -> сервер: M1 = H(H(N) xor H(g) | H(I) | s | A | B | K)
+//	сервер -> клиент: M2 = H(A | M1 | K)
+//
+// где u = H(A | B), K = H(S), у клиента S = (B - k*g^x)^(a + u*x), у сервера S = (A * v^u)^b.
+package srp
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/k0st1a/gophkeeper/internal/pkg/client/crypto"
+)
+
+// SaltSize - размер соли верификатора, в байтах.
+const SaltSize = crypto.SaltSize
+
+// secretSize - размер закрытых значений a и b, в байтах.
+const secretSize = 32
+
+// 2048-битная группа из RFC 5054, приложение A.
+const groupN = "AC6BDB41324A9A9BF166DE5E1389582FAF72B6651987EE07FC3192943DB56050A37329CBB4" +
+	"A099ED8193E0757767A13DD52312AB4B03310DCD7F48A9DA04FD50E8083969EDB767B0CF60" +
+	"95179A163AB3661A05FBD5FAAAE82918A9962F0B93B855F97993EC975EEAA80D740ADBF4FF" +
+	"747359D041D5C33EA71D281E446B14773BCA97B43A23FB801676BD207A436C6481F1D2B907" +
+	"8717461A5B9D32E688F87748544523B524B0D57D5EA77A2775D2ECFA032CFBDBF52FB37861" +
+	"60279004E57AE6AF874E7303CE53299CCC041C7BC308D82A5698F3A8D0C38271AE35F8E9DB" +
+	"FBB694B5C803D89F7AE435DE236D525F54759B65E372FCD68EF20FA7111F9E4AFF73"
+
+var (
+	n = func() *big.Int {
+		v, _ := new(big.Int).SetString(groupN, 16)
+		return v
+	}()
+	g = big.NewInt(2)
+	// k = H(N | PAD(g))
+	k = new(big.Int).SetBytes(hash(n.Bytes(), pad(g)))
+)
+
+var (
+	ErrBadPublic = errors.New("bad srp public value")
+	ErrBadProof  = errors.New("bad srp proof")
+)
+
+// NewVerifier - создать соль и верификатор пароля для регистрации пользователя.
+func NewVerifier(login, password string) ([]byte, []byte, error) {
+	salt := make([]byte, SaltSize)
+
+	_, err := rand.Read(salt)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate salt:%w", err)
+	}
+
+	x := privateKey(login, password, salt)
+
+	return salt, new(big.Int).Exp(g, x, n).Bytes(), nil
+}
+
+// Client - сторона клиента одного обмена.
+type Client struct {
+	a     *big.Int
+	A     []byte
+	login string
+	// Ожидаемое подтверждение сервера, вычисляется в Proof
+	m2 []byte
+}
+
+// NewClient - начать обмен: создать закрытое a и открытое A, которое передается серверу.
+func NewClient(login string) (*Client, error) {
+	a, err := randomSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{
+		a:     a,
+		A:     pad(new(big.Int).Exp(g, a, n)),
+		login: login,
+	}, nil
+}
+
+// Proof - вычислить доказательство знания пароля M1 по соли и открытому значению сервера B.
+func (c *Client) Proof(password string, salt, pubB []byte) ([]byte, error) {
+	b := new(big.Int).SetBytes(pubB)
+	if new(big.Int).Mod(b, n).Sign() == 0 {
+		return nil, ErrBadPublic
+	}
+
+	u := scramble(c.A, pubB)
+	if u.Sign() == 0 {
+		return nil, ErrBadPublic
+	}
+
+	x := privateKey(c.login, password, salt)
+
+	// S = (B - k*g^x) ^ (a + u*x) mod N
+	base := new(big.Int).Mul(k, new(big.Int).Exp(g, x, n))
+	base.Sub(b, base).Mod(base, n)
+	exp := new(big.Int).Mul(u, x)
+	exp.Add(exp, c.a)
+	s := new(big.Int).Exp(base, exp, n)
+
+	key := hash(pad(s))
+	m1 := proof(c.login, salt, c.A, pubB, key)
+	c.m2 = hash(c.A, m1, key)
+
+	return m1, nil
+}
+
+// VerifyServer - проверить подтверждение сервера M2, что он знает верификатор, а не выдает себя за сервер.
+func (c *Client) VerifyServer(m2 []byte) error {
+	if c.m2 == nil || subtle.ConstantTimeCompare(c.m2, m2) != 1 {
+		return ErrBadProof
+	}
+
+	return nil
+}
+
+// NewServer - ответ сервера на начало обмена: закрытое b, которое сервер хранит до конца обмена,
+// и открытое B, которое передается клиенту.
+func NewServer(verifier []byte) ([]byte, []byte, error) {
+	b, err := randomSecret()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// B = k*v + g^b mod N
+	pubB := new(big.Int).Mul(k, new(big.Int).SetBytes(verifier))
+	pubB.Add(pubB, new(big.Int).Exp(g, b, n)).Mod(pubB, n)
+
+	return b.Bytes(), pad(pubB), nil
+}
+
+// VerifyClient - проверить доказательство клиента M1, где secret и pubB - результат NewServer, pubA - открытое
+// значение клиента. Возвращает подтверждение сервера M2.
+func VerifyClient(login string, salt, verifier, secret, pubA, pubB, m1 []byte) ([]byte, error) {
+	a := new(big.Int).SetBytes(pubA)
+	if new(big.Int).Mod(a, n).Sign() == 0 {
+		return nil, ErrBadPublic
+	}
+
+	u := scramble(pubA, pubB)
+	if u.Sign() == 0 {
+		return nil, ErrBadPublic
+	}
+
+	// S = (A * v^u) ^ b mod N
+	base := new(big.Int).Exp(new(big.Int).SetBytes(verifier), u, n)
+	base.Mul(base, a).Mod(base, n)
+	s := new(big.Int).Exp(base, new(big.Int).SetBytes(secret), n)
+
+	key := hash(pad(s))
+	if subtle.ConstantTimeCompare(proof(login, salt, pubA, pubB, key), m1) != 1 {
+		return nil, ErrBadProof
+	}
+
+	return hash(pubA, m1, key), nil
+}
+
+// privateKey - x = H(s | Argon2id(I ":" P, s)).
+func privateKey(login, password string, salt []byte) *big.Int {
+	return new(big.Int).SetBytes(hash(salt, crypto.DeriveKey(login+":"+password, salt)))
+}
+
+// scramble - u = H(PAD(A) | PAD(B)).
+func scramble(pubA, pubB []byte) *big.Int {
+	return new(big.Int).SetBytes(hash(padBytes(pubA), padBytes(pubB)))
+}
+
+// proof - M1 = H(H(N) xor H(g) | H(I) | s | A | B | K).
+func proof(login string, salt, pubA, pubB, key []byte) []byte {
+	hn := hash(n.Bytes())
+	hg := hash(pad(g))
+	for i := range hn {
+		hn[i] ^= hg[i]
+	}
+
+	return hash(hn, hash([]byte(login)), salt, padBytes(pubA), padBytes(pubB), key)
+}
+
+func randomSecret() (*big.Int, error) {
+	b := make([]byte, secretSize)
+
+	_, err := rand.Read(b)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate srp secret:%w", err)
+	}
+
+	return new(big.Int).SetBytes(b), nil
+}
+
+func hash(parts ...[]byte) []byte {
+	h := sha256.New()
+	for _, p := range parts {
+		h.Write(p)
+	}
+
+	return h.Sum(nil)
+}
+
+// pad - число в виде байт длины N.
+func pad(v *big.Int) []byte {
+	return v.FillBytes(make([]byte, len(n.Bytes())))
+}
+
+// padBytes - дополнить открытое значение нулями слева до длины N, значения длиннее N не меняются.
+func padBytes(v []byte) []byte {
+	size := len(n.Bytes())
+	if len(v) >= size {
+		return v
+	}
+
+	p := make([]byte, size)
+	copy(p[size-len(v):], v)
+
+	return p
+}
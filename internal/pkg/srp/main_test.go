@@ -0,0 +1,80 @@
+package srp
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExchange(t *testing.T) {
+	salt, verifier, err := NewVerifier("user", "password")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		login    string
+		password string
+		err      error
+	}{
+		{
+			name:     "Check right password",
+			login:    "user",
+			password: "password",
+		},
+		{
+			name:     "Check wrong password",
+			login:    "user",
+			password: "wrong",
+			err:      ErrBadProof,
+		},
+		{
+			name:     "Check wrong login",
+			login:    "other",
+			password: "password",
+			err:      ErrBadProof,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c, err := NewClient(test.login)
+			require.NoError(t, err)
+
+			secret, pubB, err := NewServer(verifier)
+			require.NoError(t, err)
+
+			m1, err := c.Proof(test.password, salt, pubB)
+			require.NoError(t, err)
+
+			m2, err := VerifyClient("user", salt, verifier, secret, c.A, pubB, m1)
+			require.ErrorIs(t, err, test.err)
+			if test.err != nil {
+				require.ErrorIs(t, c.VerifyServer(m2), ErrBadProof)
+				return
+			}
+
+			require.NoError(t, c.VerifyServer(m2))
+		})
+	}
+}
+
+func TestBadPublic(t *testing.T) {
+	salt, verifier, err := NewVerifier("user", "password")
+	require.NoError(t, err)
+
+	c, err := NewClient("user")
+	require.NoError(t, err)
+
+	secret, pubB, err := NewServer(verifier)
+	require.NoError(t, err)
+
+	// A = 0 и A = N дают S = 0 у сервера при любом пароле.
+	for _, a := range [][]byte{{0}, n.Bytes(), new(big.Int).Mul(n, big.NewInt(2)).Bytes()} {
+		_, err = VerifyClient("user", salt, verifier, secret, a, pubB, []byte("proof"))
+		require.ErrorIs(t, err, ErrBadPublic)
+	}
+
+	_, err = c.Proof("password", salt, n.Bytes())
+	require.ErrorIs(t, err, ErrBadPublic)
+}
@@ -15,6 +15,16 @@ const (
 	NeedUpdateLocalItem
 	NeedUpdateRemoteItem
 	NeedDeleteBothItems
+	NeedResolveConflict
+)
+
+const (
+	// ConflictRemoteDeleted - описание конфликта, когда предмет изменен локально, но удален другим клиентом.
+	ConflictRemoteDeleted = "item was deleted on another device, local changes were kept as a new item"
+	// ConflictRemoteChanged - описание конфликта, когда предмет изменен и локально, и другим клиентом.
+	ConflictRemoteChanged = "item was changed on another device, local changes were kept as a new item"
+	// ConflictLocalDeleted - описание конфликта, когда предмет удален локально, но изменен другим клиентом.
+	ConflictLocalDeleted = "item was changed on another device, local deletion was canceled"
 )
 
 type Doer interface {
@@ -75,70 +85,241 @@ func (s *sync) Do(ctx context.Context) error {
 	log.Printf("uitems size:%v", len(uitems))
 	log.Printf("citems size:%v", len(citems))
 
-	// remote items
-	ritems, err := s.remote.ListItems(ctx)
+	cursor, err := s.local.GetSyncCursor(ctx)
 	if err != nil {
-		log.Ctx(ctx).Error().Err(err).Msg("error of list remote items")
-		return fmt.Errorf("error of list remote items:%w", err)
+		log.Ctx(ctx).Error().Err(err).Msg("error of get sync cursor")
+		return fmt.Errorf("error of get sync cursor:%w", err)
 	}
-	log.Printf("ritems size:%v", len(ritems))
+
+	// remote changes since last sync
+	changes, err := s.remote.ListChanges(ctx, cursor)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("error of list remote changes")
+		return fmt.Errorf("error of list remote changes:%w", err)
+	}
+	log.Printf("cursor:%v, changed ritems size:%v, deleted ritems size:%v, full:%v",
+		changes.Cursor, len(changes.Items), len(changes.DeletedIDs), changes.Full)
 
 	mcitems := pclient.List2MapWithRemoteID(citems)
 
+	failed := s.processRemoteItems(ctx, mcitems, changes.Items)
+
+	if s.processRemoteDeletedItems(ctx, mcitems, changes) {
+		failed = true
+	}
+
+	s.pushLocalChanges(ctx, mcitems)
+
+	s.uploadItems(ctx, uitems)
+
+	// Если изменение с сервера не применилось локально, то курсор не сдвигается,
+	// чтобы получить это изменение при следующей синхронизации.
+	if failed {
+		log.Ctx(ctx).Printf("Not all remote changes applied => keep sync cursor:%v", cursor)
+		return nil
+	}
+
+	err = s.local.SetSyncCursor(ctx, changes.Cursor)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("error of set sync cursor")
+		return fmt.Errorf("error of set sync cursor:%w", err)
+	}
+
+	return nil
+}
+
+// processRemoteItems - применить созданные или обновленные удаленные предметы.
+// Обработанные локальные предметы удаляются из mcitems. Возвращает true, если были ошибки.
+func (s *sync) processRemoteItems(ctx context.Context, mcitems map[int64]*pclient.Item, ritems []gclient.Item) bool {
+	failed := false
+
 	for _, ri := range ritems {
-		log.Ctx(ctx).Printf("Remote item id(%v), find local item", ri.ID)
-		li, ok := mcitems[ri.ID]
+		err := s.processRemoteItem(ctx, mcitems[ri.ID], &ri)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("")
+			failed = true
+		}
+		delete(mcitems, ri.ID)
+	}
+
+	return failed
+}
+
+// processRemoteItem - применить созданный или обновленный удаленный предмет, li - его локальная копия или nil.
+func (s *sync) processRemoteItem(ctx context.Context, li *pclient.Item, ri *gclient.Item) error {
+	log.Ctx(ctx).Printf("Remote item id(%v), find local item", ri.ID)
+	if li == nil {
+		log.Ctx(ctx).Printf("Not found local item(%v) => need download item", ri.ID)
+		return s.downloadItem(ctx, ri)
+	}
+	log.Ctx(ctx).Printf("Found local item(%v) => compare items", li.ID)
+
+	cmp := compare(li, ri)
+	switch cmp {
+	case EqualItems:
+		log.Ctx(ctx).Printf("local item(%v) equal remote item(%v) => skip", li.ID, ri.ID)
+		return nil
+	case NeedUpdateLocalItem:
+		log.Printf("Need update local item(%v)", li.ID)
+		return s.updateLocalItem(ctx, li, ri)
+	case NeedUpdateRemoteItem:
+		log.Printf("Need update remote item(%v)", ri.ID)
+		return s.updateRemoteItem(ctx, ri, li)
+	case NeedDeleteBothItems:
+		log.Printf("Need delete remote item(%v) and remote item(%v)", ri.ID, li.ID)
+		return s.deleteBothItems(ctx, ri, li)
+	case NeedResolveConflict:
+		log.Printf("Need resolve conflict of local item(%v) and remote item(%v)", li.ID, ri.ID)
+		return s.resolveConflict(ctx, li, ri)
+	default:
+		return fmt.Errorf("compare return unknown code:%v", cmp)
+	}
+}
+
+// processRemoteDeletedItems - обработать локальные предметы, которые были удалены другим клиентом.
+// Если предмет не менялся локально, то он удаляется. Иначе локальные изменения не теряются: предмет
+// загружается на удаленное хранилище как новый, а пользователь видит описание конфликта.
+// Обработанные локальные предметы удаляются из mcitems. Возвращает true, если были ошибки.
+func (s *sync) processRemoteDeletedItems(ctx context.Context, mcitems map[int64]*pclient.Item,
+	changes *gclient.Changes) bool {
+	// При полной выгрузке удаленными считаются все предметы, которых нет в списке.
+	ids := changes.DeletedIDs
+	if changes.Full {
+		ids = make([]int64, 0, len(mcitems))
+		for id := range mcitems {
+			ids = append(ids, id)
+		}
+	}
+
+	failed := false
+
+	for _, id := range ids {
+		li, ok := mcitems[id]
 		if !ok {
-			log.Ctx(ctx).Printf("Not found local item(%v) => need download item", ri.ID)
-			err := s.downloadItem(ctx, &ri)
-			if err != nil {
-				log.Ctx(ctx).Error().Err(err).Msg("")
-			}
 			continue
 		}
-		log.Ctx(ctx).Printf("Found local item(%v) => compare items", li.ID)
+		delete(mcitems, id)
 
-		cmp := compare(li, &ri)
-		if cmp == EqualItems {
-			log.Ctx(ctx).Printf("local item(%v) equal remote item(%v) => skip", li.ID, ri.ID)
-			continue
+		err := s.processRemoteDeletedItem(ctx, li)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("")
+			failed = true
 		}
+	}
 
-		if cmp == NeedUpdateLocalItem {
-			log.Printf("Need update local item(%v)", li.ID)
-			err := s.updateLocalItem(ctx, li, &ri)
-			if err != nil {
-				log.Ctx(ctx).Error().Err(err).Msg("")
-			}
-			continue
+	return failed
+}
+
+// processRemoteDeletedItem - обработать локальный предмет, удаленный другим клиентом.
+func (s *sync) processRemoteDeletedItem(ctx context.Context, li *pclient.Item) error {
+	if li.DeleteMark || !li.ChangeMark {
+		log.Ctx(ctx).Printf("Remote item(%v) deleted => delete local item(%v)", li.RemoteID, li.ID)
+		err := s.local.DeleteItem(ctx, li.ID)
+		if err != nil {
+			return fmt.Errorf("error of delete local item(%v):%w", li.ID, err)
 		}
+		return nil
+	}
 
-		if cmp == NeedUpdateRemoteItem {
-			log.Printf("Need update remote item(%v)", ri.ID)
-			err := s.updateRemoteItem(ctx, &ri, li)
-			if err != nil {
-				log.Ctx(ctx).Error().Err(err).Msg("")
-			}
+	log.Ctx(ctx).Printf("Remote item(%v) deleted, but local item(%v) changed => upload as new item",
+		li.RemoteID, li.ID)
+	return s.restoreRemoteDeletedItem(ctx, li)
+}
+
+// pushLocalChanges - отправить на удаленное хранилище локальные изменения предметов,
+// которые не менялись другими клиентами с прошлой синхронизации.
+func (s *sync) pushLocalChanges(ctx context.Context, mcitems map[int64]*pclient.Item) {
+	for _, li := range mcitems {
+		var err error
+
+		switch {
+		case li.DeleteMark:
+			log.Printf("Need delete remote item(%v) and local item(%v)", li.RemoteID, li.ID)
+			err = s.deleteBothItems(ctx, makeRemoteItem(li), li)
+		case li.ChangeMark:
+			log.Printf("Need update remote item(%v)", li.RemoteID)
+			err = s.updateRemoteItem(ctx, makeRemoteItem(li), li)
+		default:
 			continue
 		}
 
-		if cmp == NeedDeleteBothItems {
-			log.Printf("Need delete remote item(%v) and remote item(%v)", ri.ID, li.ID)
-			err := s.deleteBothItems(ctx, &ri, li)
-			if err != nil {
-				log.Ctx(ctx).Error().Err(err).Msg("")
-			}
-			continue
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("")
 		}
+	}
+}
+
+// restoreRemoteDeletedItem - загрузить измененный локальный предмет, удаленный другим клиентом, как новый.
+func (s *sync) restoreRemoteDeletedItem(ctx context.Context, l *pclient.Item) error {
+	return s.uploadAsNewItem(ctx, l, ConflictRemoteDeleted)
+}
 
-		log.Ctx(ctx).Error().Msgf("Compare return unknown code:%v", cmp)
+// resolveConflict - разрешить конфликт, когда предмет изменен другим клиентом после ревизии локальной копии.
+// Если предмет удален локально, то удаление отменяется и берется удаленная версия.
+// Если предмет изменен локально, то сохраняются обе версии: локальная загружается как новый предмет,
+// а удаленная скачивается.
+func (s *sync) resolveConflict(ctx context.Context, l *pclient.Item, r *gclient.Item) error {
+	if l.DeleteMark {
+		return s.restoreLocalDeletedItem(ctx, l, r)
 	}
 
-	s.uploadItems(ctx, uitems)
+	err := s.uploadAsNewItem(ctx, l, ConflictRemoteChanged)
+	if err != nil {
+		return err
+	}
+
+	return s.downloadItem(ctx, r)
+}
+
+// restoreLocalDeletedItem - отменить локальное удаление предмета, измененного другим клиентом.
+func (s *sync) restoreLocalDeletedItem(ctx context.Context, l *pclient.Item, r *gclient.Item) error {
+	log.Ctx(ctx).Printf("Restore local item(%v) from remote item(%v)", l.ID, r.ID)
+
+	dm := false
+	cm := false
+	cf := ConflictLocalDeleted
+	ui := &pclient.UpdateItem{
+		ID:         l.ID,
+		Revision:   &r.Revision,
+		Body:       &r.Body,
+		UpdateTime: &r.UpdateTime,
+		DeleteMark: &dm,
+		ChangeMark: &cm,
+		Conflict:   &cf,
+	}
+
+	err := s.local.UpdateItem(ctx, ui)
+	if err != nil {
+		return fmt.Errorf("error of update local item(%v):%w", l.ID, err)
+	}
+	log.Ctx(ctx).Printf("Local item(%v) restored", l.ID)
 
 	return nil
 }
 
+// uploadAsNewItem - отвязать локальный предмет от удаленного и загрузить его как новый с описанием конфликта.
+func (s *sync) uploadAsNewItem(ctx context.Context, l *pclient.Item, conflict string) error {
+	var rid, rev int64
+
+	ui := &pclient.UpdateItem{
+		ID:       l.ID,
+		RemoteID: &rid,
+		Revision: &rev,
+		Conflict: &conflict,
+	}
+
+	err := s.local.UpdateItem(ctx, ui)
+	if err != nil {
+		return fmt.Errorf("error of update local item(%v):%w", l.ID, err)
+	}
+
+	l.RemoteID = rid
+	l.Revision = rev
+	l.Conflict = conflict
+
+	return s.uploadItem(ctx, l)
+}
+
 // uploadItems - загрузить локальные предмет на удаленное хранилище.
 func (s *sync) uploadItems(ctx context.Context, items []*pclient.Item) {
 	log.Ctx(ctx).Printf("Upload items(%v)", len(items))
@@ -155,17 +336,18 @@ func (s *sync) uploadItems(ctx context.Context, items []*pclient.Item) {
 func (s *sync) uploadItem(ctx context.Context, l *pclient.Item) error {
 	log.Ctx(ctx).Printf("Upload local item(%v)", l.ID)
 
-	r := makeRemoteItem(l)
-
-	id, err := s.remote.CreateItem(ctx, r)
+	r, err := s.remote.CreateItem(ctx, makeRemoteItem(l))
 	if err != nil {
 		return fmt.Errorf("error of upload local item(%v):%w", l.ID, err)
 	}
-	log.Ctx(ctx).Printf("Local item(%v) uploaded, remote id:%v", l.ID, id)
+	log.Ctx(ctx).Printf("Local item(%v) uploaded, remote id:%v", l.ID, r.ID)
 
+	cm := false
 	ui := &pclient.UpdateItem{
-		ID:       l.ID,
-		RemoteID: &id,
+		ID:         l.ID,
+		RemoteID:   &r.ID,
+		Revision:   &r.Revision,
+		ChangeMark: &cm,
 	}
 
 	err = s.local.UpdateItem(ctx, ui)
@@ -195,11 +377,14 @@ func (s *sync) downloadItem(ctx context.Context, r *gclient.Item) error {
 func (s *sync) updateLocalItem(ctx context.Context, l *pclient.Item, r *gclient.Item) error {
 	log.Printf("Update local item(%v)", l.ID)
 
+	cm := false
 	ui := &pclient.UpdateItem{
 		ID:         l.ID,
 		RemoteID:   &r.ID,
+		Revision:   &r.Revision,
 		Body:       &r.Body,
 		UpdateTime: &r.UpdateTime,
+		ChangeMark: &cm,
 	}
 
 	err := s.local.UpdateItem(ctx, ui)
@@ -211,16 +396,28 @@ func (s *sync) updateLocalItem(ctx context.Context, l *pclient.Item, r *gclient.
 	return nil
 }
 
+// updateRemoteItem - отправить локальные изменения на удаленное хранилище.
+// Если предмет успели изменить после ревизии локальной копии, то конфликт разрешится при следующей синхронизации.
 func (s *sync) updateRemoteItem(ctx context.Context, r *gclient.Item, l *pclient.Item) error {
 	log.Printf("Update remote item(%v)", r.ID)
 
-	r = makeRemoteItem(l)
+	r, err := s.remote.UpdateItem(ctx, makeRemoteItem(l))
+	if err != nil {
+		return fmt.Errorf("error of update remote item(%v):%w", l.RemoteID, err)
+	}
+	log.Ctx(ctx).Printf("Remote item(%v) updated, revision:%v", r.ID, r.Revision)
+
+	cm := false
+	ui := &pclient.UpdateItem{
+		ID:         l.ID,
+		Revision:   &r.Revision,
+		ChangeMark: &cm,
+	}
 
-	err := s.remote.UpdateItem(ctx, r)
+	err = s.local.UpdateItem(ctx, ui)
 	if err != nil {
-		return fmt.Errorf("error of update remote item(%v)", r.ID)
+		return fmt.Errorf("error of update local item(%v):%w", l.ID, err)
 	}
-	log.Ctx(ctx).Printf("Remote item(%v) updated", r.ID)
 
 	return nil
 }
@@ -244,42 +441,52 @@ func (s *sync) deleteBothItems(ctx context.Context, r *gclient.Item, l *pclient.
 }
 
 // compare - сравить локальный и удаленный предметы.
+// Сравниваются ревизии, назначенные сервером, а не время изменения: часы клиентов могут расходиться.
 func compare(l *pclient.Item, r *gclient.Item) int {
-	log.Printf("Compare items, l.UpdateTime:%v, r.UpdateTime:%v", l.UpdateTime, r.UpdateTime)
-	ld := l.UpdateTime.UnixMilli()
-	rd := r.UpdateTime.UnixMilli()
-	if ld > rd {
-		log.Printf("l.DeleteMark:%v", l.DeleteMark)
+	log.Printf("Compare items, l.Revision:%v, r.Revision:%v, l.ChangeMark:%v, l.DeleteMark:%v",
+		l.Revision, r.Revision, l.ChangeMark, l.DeleteMark)
+
+	if l.Revision == r.Revision {
 		if l.DeleteMark {
 			return NeedDeleteBothItems
 		}
-		return NeedUpdateRemoteItem
+
+		if l.ChangeMark {
+			return NeedUpdateRemoteItem
+		}
+
+		return EqualItems
 	}
 
-	if ld < rd {
-		return NeedUpdateLocalItem
+	// Предмет изменен другим клиентом.
+	if l.DeleteMark || l.ChangeMark {
+		return NeedResolveConflict
 	}
 
-	return EqualItems
+	return NeedUpdateLocalItem
 }
 
 // makeLocalItem - создать локальный прдемет на основе удаленного.
 func makeLocalItem(r *gclient.Item) *pclient.Item {
 	return &pclient.Item{
-		RemoteID:   r.ID,
-		Body:       r.Body,
-		CreateTime: r.CreateTime,
-		UpdateTime: r.UpdateTime,
-		DeleteMark: false,
+		RemoteID:     r.ID,
+		Revision:     r.Revision,
+		Body:         r.Body,
+		CreateTime:   r.CreateTime,
+		UpdateTime:   r.UpdateTime,
+		CollectionID: r.CollectionID,
+		DeleteMark:   false,
 	}
 }
 
 // makeRemoteItem - создать удаленный прдемет на основе локального.
 func makeRemoteItem(l *pclient.Item) *gclient.Item {
 	return &gclient.Item{
-		ID:         l.RemoteID,
-		Body:       l.Body,
-		CreateTime: l.CreateTime,
-		UpdateTime: l.UpdateTime,
+		ID:           l.RemoteID,
+		Revision:     l.Revision,
+		Body:         l.Body,
+		CreateTime:   l.CreateTime,
+		UpdateTime:   l.UpdateTime,
+		CollectionID: l.CollectionID,
 	}
 }
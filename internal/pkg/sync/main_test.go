@@ -0,0 +1,439 @@
+package sync
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	gclient "github.com/k0st1a/gophkeeper/internal/adapters/api/grpc/client"
+	"github.com/k0st1a/gophkeeper/internal/adapters/storage/inmemory"
+	"github.com/k0st1a/gophkeeper/internal/pkg/client/model"
+	pclient "github.com/k0st1a/gophkeeper/internal/ports/client"
+	"github.com/stretchr/testify/require"
+)
+
+// remote - удаленное хранилище предметов в памяти.
+type remote struct {
+	items   map[int64]gclient.Item
+	changed map[int64]int64
+	deleted map[int64]int64
+	lastID  int64
+	seq     int64
+	// Количество предметов, отданных через ListChanges
+	listed int
+}
+
+func newRemote() *remote {
+	return &remote{
+		items:   make(map[int64]gclient.Item),
+		changed: make(map[int64]int64),
+		deleted: make(map[int64]int64),
+	}
+}
+
+// put - сохранить предмет как изменение другого клиента.
+func (r *remote) put(i gclient.Item) {
+	r.seq++
+	r.items[i.ID] = i
+	r.changed[i.ID] = r.seq
+	if i.ID > r.lastID {
+		r.lastID = i.ID
+	}
+}
+
+func (r *remote) GetItem(ctx context.Context, id int64) (*gclient.Item, error) {
+	i, ok := r.items[id]
+	if !ok {
+		return nil, gclient.ErrItemNotFound
+	}
+	return &i, nil
+}
+
+func (r *remote) ListItems(ctx context.Context) ([]gclient.Item, error) {
+	l := make([]gclient.Item, 0, len(r.items))
+	for _, i := range r.items {
+		l = append(l, i)
+	}
+	return l, nil
+}
+
+func (r *remote) ListChanges(ctx context.Context, cursor int64) (*gclient.Changes, error) {
+	c := &gclient.Changes{
+		Cursor: r.seq,
+		Full:   cursor <= 0 || cursor > r.seq,
+	}
+
+	for id, seq := range r.changed {
+		if c.Full || seq > cursor {
+			c.Items = append(c.Items, r.items[id])
+		}
+	}
+
+	for id, seq := range r.deleted {
+		if !c.Full && seq > cursor {
+			c.DeletedIDs = append(c.DeletedIDs, id)
+		}
+	}
+
+	r.listed += len(c.Items)
+	return c, nil
+}
+
+func (r *remote) CreateItem(ctx context.Context, item *gclient.Item) (*gclient.Item, error) {
+	i := *item
+	i.ID = r.lastID + 1
+	i.Revision = 1
+	r.put(i)
+	return &i, nil
+}
+
+func (r *remote) UpdateItem(ctx context.Context, item *gclient.Item) (*gclient.Item, error) {
+	old, ok := r.items[item.ID]
+	if !ok {
+		return nil, gclient.ErrItemNotFound
+	}
+
+	if old.Revision != item.Revision {
+		return nil, &gclient.RevisionMismatchError{Revision: old.Revision}
+	}
+
+	i := *item
+	i.Revision++
+	r.put(i)
+	return &i, nil
+}
+
+func (r *remote) DeleteItem(ctx context.Context, id int64) error {
+	_, ok := r.items[id]
+	if !ok {
+		return gclient.ErrItemNotFound
+	}
+
+	r.seq++
+	delete(r.items, id)
+	delete(r.changed, id)
+	r.deleted[id] = r.seq
+	return nil
+}
+
+func note(name string) model.Item {
+	return model.Item{
+		Note: &model.Note{
+			Name: name,
+			Body: "Body",
+		},
+	}
+}
+
+func TestRemoteDeletedItems(t *testing.T) {
+	now := time.Date(2024, time.May, 5, 8, 10, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		local        pclient.Item
+		wantLocal    bool
+		wantConflict string
+		wantUpload   bool
+	}{
+		{
+			name: "Check remote deleted item, local item not changed",
+			local: pclient.Item{
+				Body:       note("Not changed"),
+				CreateTime: now,
+				UpdateTime: now,
+				RemoteID:   100,
+			},
+			wantLocal: false,
+		},
+		{
+			name: "Check remote deleted item, local item marked to delete",
+			local: pclient.Item{
+				Body:       note("Deleted"),
+				CreateTime: now,
+				UpdateTime: now,
+				RemoteID:   100,
+				DeleteMark: true,
+				ChangeMark: true,
+			},
+			wantLocal: false,
+		},
+		{
+			name: "Check remote deleted item, local item changed",
+			local: pclient.Item{
+				Body:       note("Changed"),
+				CreateTime: now,
+				UpdateTime: now,
+				RemoteID:   100,
+				ChangeMark: true,
+			},
+			wantLocal:    true,
+			wantConflict: ConflictRemoteDeleted,
+			wantUpload:   true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			l := inmemory.New()
+			r := newRemote()
+			r.lastID = 100
+			r.seq = 100
+
+			id, err := l.CreateItem(ctx, &test.local)
+			require.NoError(t, err)
+
+			err = New(l, r).Do(ctx)
+			require.NoError(t, err)
+
+			li, err := l.GetItem(ctx, id)
+			if !test.wantLocal {
+				require.ErrorIs(t, err, pclient.ErrItemNotFound)
+				require.Empty(t, r.items)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.wantConflict, li.Conflict)
+			require.False(t, li.ChangeMark)
+
+			if test.wantUpload {
+				require.Len(t, r.items, 1)
+				ri, ok := r.items[li.RemoteID]
+				require.True(t, ok)
+				require.Equal(t, test.local.Body, ri.Body)
+			}
+		})
+	}
+}
+
+func TestUploadAndDownload(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, time.May, 5, 8, 10, 0, 0, time.UTC)
+
+	l := inmemory.New()
+	r := newRemote()
+
+	r.put(gclient.Item{ID: 1, Body: note("Remote"), CreateTime: now, UpdateTime: now, Revision: 1})
+
+	id, err := l.CreateItem(ctx, &pclient.Item{Body: note("Local"), CreateTime: now, UpdateTime: now, ChangeMark: true})
+	require.NoError(t, err)
+
+	err = New(l, r).Do(ctx)
+	require.NoError(t, err)
+
+	items, err := l.ListItems(ctx)
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	require.Len(t, r.items, 2)
+
+	li, err := l.GetItem(ctx, id)
+	require.NoError(t, err)
+	require.NotZero(t, li.RemoteID)
+	require.Equal(t, note("Local"), r.items[li.RemoteID].Body)
+}
+
+func TestCollectionItems(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, time.May, 5, 8, 10, 0, 0, time.UTC)
+
+	l := inmemory.New()
+	r := newRemote()
+
+	r.put(gclient.Item{ID: 1, Body: note("Remote"), CreateTime: now, UpdateTime: now, Revision: 1, CollectionID: 7})
+
+	id, err := l.CreateItem(ctx, &pclient.Item{Body: note("Local"), CreateTime: now, UpdateTime: now,
+		CollectionID: 8, ChangeMark: true})
+	require.NoError(t, err)
+
+	err = New(l, r).Do(ctx)
+	require.NoError(t, err)
+
+	li, err := l.GetItem(ctx, id)
+	require.NoError(t, err)
+	require.Equal(t, int64(8), r.items[li.RemoteID].CollectionID)
+
+	items, err := l.ListItems(ctx)
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	for _, i := range items {
+		if i.RemoteID == 1 {
+			require.Equal(t, int64(7), i.CollectionID)
+		}
+	}
+}
+
+func TestRevisionConflicts(t *testing.T) {
+	now := time.Date(2024, time.May, 5, 8, 10, 0, 0, time.UTC)
+	// Часы другого клиента отстают: время не должно влиять на результат синхронизации.
+	past := now.Add(-time.Hour)
+
+	tests := []struct {
+		name           string
+		local          pclient.Item
+		remote         gclient.Item
+		wantLocalBody  []model.Item
+		wantRemoteBody []model.Item
+		wantConflict   string
+	}{
+		{
+			name: "Check remote item changed, local item not changed",
+			local: pclient.Item{
+				Body:       note("Local"),
+				UpdateTime: now,
+				RemoteID:   1,
+				Revision:   1,
+			},
+			remote: gclient.Item{
+				ID:         1,
+				Body:       note("Remote"),
+				UpdateTime: past,
+				Revision:   2,
+			},
+			wantLocalBody:  []model.Item{note("Remote")},
+			wantRemoteBody: []model.Item{note("Remote")},
+		},
+		{
+			name: "Check local item changed, remote item not changed",
+			local: pclient.Item{
+				Body:       note("Local"),
+				UpdateTime: past,
+				RemoteID:   1,
+				Revision:   2,
+				ChangeMark: true,
+			},
+			remote: gclient.Item{
+				ID:         1,
+				Body:       note("Remote"),
+				UpdateTime: now,
+				Revision:   2,
+			},
+			wantLocalBody:  []model.Item{note("Local")},
+			wantRemoteBody: []model.Item{note("Local")},
+		},
+		{
+			name: "Check both items changed",
+			local: pclient.Item{
+				Body:       note("Local"),
+				UpdateTime: now,
+				RemoteID:   1,
+				Revision:   1,
+				ChangeMark: true,
+			},
+			remote: gclient.Item{
+				ID:         1,
+				Body:       note("Remote"),
+				UpdateTime: past,
+				Revision:   2,
+			},
+			wantLocalBody:  []model.Item{note("Local"), note("Remote")},
+			wantRemoteBody: []model.Item{note("Local"), note("Remote")},
+			wantConflict:   ConflictRemoteChanged,
+		},
+		{
+			name: "Check local item deleted, remote item changed",
+			local: pclient.Item{
+				Body:       note("Local"),
+				UpdateTime: now,
+				RemoteID:   1,
+				Revision:   1,
+				DeleteMark: true,
+			},
+			remote: gclient.Item{
+				ID:         1,
+				Body:       note("Remote"),
+				UpdateTime: past,
+				Revision:   2,
+			},
+			wantLocalBody:  []model.Item{note("Remote")},
+			wantRemoteBody: []model.Item{note("Remote")},
+			wantConflict:   ConflictLocalDeleted,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			l := inmemory.New()
+			r := newRemote()
+			r.put(test.remote)
+
+			id, err := l.CreateItem(ctx, &test.local)
+			require.NoError(t, err)
+
+			err = New(l, r).Do(ctx)
+			require.NoError(t, err)
+
+			li, err := l.GetItem(ctx, id)
+			require.NoError(t, err)
+			require.Equal(t, test.wantConflict, li.Conflict)
+			require.False(t, li.ChangeMark)
+			require.False(t, li.DeleteMark)
+			require.Equal(t, r.items[li.RemoteID].Revision, li.Revision)
+
+			items, err := l.ListItems(ctx)
+			require.NoError(t, err)
+
+			lb := make([]model.Item, 0, len(items))
+			for _, i := range items {
+				lb = append(lb, i.Body)
+			}
+			require.ElementsMatch(t, test.wantLocalBody, lb)
+
+			rb := make([]model.Item, 0, len(r.items))
+			for _, i := range r.items {
+				rb = append(rb, i.Body)
+			}
+			require.ElementsMatch(t, test.wantRemoteBody, rb)
+		})
+	}
+}
+
+func TestIncrementalSync(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, time.May, 5, 8, 10, 0, 0, time.UTC)
+
+	l := inmemory.New()
+	r := newRemote()
+	s := New(l, r)
+
+	r.put(gclient.Item{ID: 1, Body: note("First"), UpdateTime: now, Revision: 1})
+	r.put(gclient.Item{ID: 2, Body: note("Second"), UpdateTime: now, Revision: 1})
+
+	err := s.Do(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, r.listed)
+
+	cursor, err := l.GetSyncCursor(ctx)
+	require.NoError(t, err)
+	require.Equal(t, r.seq, cursor)
+
+	// Нет изменений - ничего не скачивается.
+	r.listed = 0
+	err = s.Do(ctx)
+	require.NoError(t, err)
+	require.Zero(t, r.listed)
+
+	// Скачиваются только измененные предметы, удаленные предметы удаляются локально.
+	r.put(gclient.Item{ID: 1, Body: note("First changed"), UpdateTime: now, Revision: 2})
+	err = r.DeleteItem(ctx, 2)
+	require.NoError(t, err)
+
+	err = s.Do(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, r.listed)
+
+	items, err := l.ListItems(ctx)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	require.Equal(t, note("First changed"), items[0].Body)
+	require.Equal(t, int64(2), items[0].Revision)
+
+	// Локальные изменения отправляются, даже если предмет не менялся на сервере.
+	cm := true
+	body := note("First local")
+	err = l.UpdateItem(ctx, &pclient.UpdateItem{ID: items[0].ID, Body: &body, ChangeMark: &cm})
+	require.NoError(t, err)
+
+	err = s.Do(ctx)
+	require.NoError(t, err)
+	require.Equal(t, note("First local"), r.items[1].Body)
+	require.Equal(t, int64(3), r.items[1].Revision)
+}
@@ -12,8 +12,13 @@ type Runner interface {
 	Run(context.Context) error
 }
 
+type Triggerer interface {
+	Trigger()
+}
+
 type tick struct {
 	sync     sync.Doer
+	trigger  chan struct{}
 	interval time.Duration
 }
 
@@ -21,6 +26,16 @@ func New(s sync.Doer, i time.Duration) *tick {
 	return &tick{
 		sync:     s,
 		interval: i,
+		trigger:  make(chan struct{}, 1),
+	}
+}
+
+// Trigger - запустить синхронизацию, не дожидаясь тика.
+// Запросы, пришедшие во время синхронизации, склеиваются в один.
+func (t *tick) Trigger() {
+	select {
+	case t.trigger <- struct{}{}:
+	default:
 	}
 }
 
@@ -35,10 +50,18 @@ func (t *tick) Run(ctx context.Context) error {
 			return nil
 		case <-ticker.C:
 			log.Printf("Got tick")
-			err := t.sync.Do(ctx)
-			if err != nil {
-				log.Error().Err(err).Msg("error of do sync")
-			}
+			t.do(ctx)
+		case <-t.trigger:
+			log.Printf("Got trigger")
+			t.do(ctx)
+			ticker.Reset(t.interval)
 		}
 	}
 }
+
+func (t *tick) do(ctx context.Context) {
+	err := t.sync.Do(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("error of do sync")
+	}
+}
@@ -0,0 +1,97 @@
+// Package tlsconfig builds TLS configuration of server and client from certificate files.
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+)
+
+var (
+	ErrNoCertificate  = errors.New("certificate and key must be set together")
+	ErrBadCertificate = errors.New("no certificate found in pem file")
+)
+
+// NewServer - TLS конфигурация сервера, где:
+//   - cert, key - пути до сертификата и ключа сервера в формате PEM;
+//   - clientCA - путь до сертификатов центров сертификации клиентов. Если задан, то включается mTLS:
+//     сервер требует от клиента сертификат, подписанный одним из них.
+func NewServer(cert, key, clientCA string) (*tls.Config, error) {
+	if cert == "" || key == "" {
+		return nil, ErrNoCertificate
+	}
+
+	c, err := tls.LoadX509KeyPair(cert, key)
+	if err != nil {
+		return nil, fmt.Errorf("error of load server certificate:%w", err)
+	}
+
+	cfg := &tls.Config{
+		Certificates: []tls.Certificate{c},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if clientCA != "" {
+		p, err := loadPool(clientCA)
+		if err != nil {
+			return nil, fmt.Errorf("error of load client ca:%w", err)
+		}
+
+		cfg.ClientCAs = p
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return cfg, nil
+}
+
+// NewClient - TLS конфигурация клиента, где:
+//   - ca - путь до сертификатов центров сертификации сервера, если не задан, то используются системные;
+//   - cert, key - пути до сертификата и ключа клиента для mTLS, задаются вместе или не задаются вовсе;
+//   - serverName - имя сервера для проверки его сертификата, если отличается от имени в адресе сервера.
+func NewClient(ca, cert, key, serverName string) (*tls.Config, error) {
+	cfg := &tls.Config{
+		ServerName: serverName,
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if ca != "" {
+		p, err := loadPool(ca)
+		if err != nil {
+			return nil, fmt.Errorf("error of load ca:%w", err)
+		}
+
+		cfg.RootCAs = p
+	}
+
+	if cert != "" || key != "" {
+		if cert == "" || key == "" {
+			return nil, ErrNoCertificate
+		}
+
+		c, err := tls.LoadX509KeyPair(cert, key)
+		if err != nil {
+			return nil, fmt.Errorf("error of load client certificate:%w", err)
+		}
+
+		cfg.Certificates = []tls.Certificate{c}
+	}
+
+	return cfg, nil
+}
+
+// loadPool - прочитать сертификаты в формате PEM из файла.
+func loadPool(path string) (*x509.CertPool, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error of read file:%w", err)
+	}
+
+	p := x509.NewCertPool()
+	if !p.AppendCertsFromPEM(b) {
+		return nil, ErrBadCertificate
+	}
+
+	return p, nil
+}
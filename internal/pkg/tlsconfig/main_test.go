@@ -0,0 +1,202 @@
+package tlsconfig
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"io"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type pair struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	crt  string
+	pem  string
+}
+
+// newPair - выпустить сертификат, подписанный parent, или самоподписанный, если parent не задан.
+func newPair(t *testing.T, dir, name string, parent *pair, isCA bool) *pair {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		DNSNames:              []string{name},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+
+	signer, signerKey := tmpl, key
+	if parent != nil {
+		signer, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	kb, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	p := &pair{
+		cert: cert,
+		key:  key,
+		crt:  filepath.Join(dir, name+".crt"),
+		pem:  filepath.Join(dir, name+".key"),
+	}
+
+	err = os.WriteFile(p.crt, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600)
+	require.NoError(t, err)
+
+	err = os.WriteFile(p.pem, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: kb}), 0o600)
+	require.NoError(t, err)
+
+	return p
+}
+
+// handshake - установить TLS соединение между клиентом и сервером через pipe, вернуть ошибки обеих сторон.
+func handshake(t *testing.T, sc, cc *tls.Config) error {
+	t.Helper()
+
+	s, c := net.Pipe()
+	defer s.Close() //nolint:errcheck // test
+	defer c.Close() //nolint:errcheck // test
+
+	errc := make(chan error, 1)
+	go func() {
+		ts := tls.Server(s, sc)
+		errc <- ts.Handshake()
+		_ = ts.Close()
+	}()
+
+	tc := tls.Client(c, cc)
+	cerr := tc.Handshake()
+	if cerr == nil {
+		// Ошибку проверки сертификата клиента сервер отправляет после завершения рукопожатия на клиенте.
+		_, cerr = tc.Read(make([]byte, 1))
+		if errors.Is(cerr, io.EOF) {
+			cerr = nil
+		}
+	}
+	_ = tc.Close()
+
+	return errors.Join(<-errc, cerr)
+}
+
+func TestTLS(t *testing.T) {
+	dir := t.TempDir()
+
+	ca := newPair(t, dir, "ca", nil, true)
+	srv := newPair(t, dir, "server", ca, false)
+	cli := newPair(t, dir, "client", ca, false)
+	other := newPair(t, dir, "other", nil, true)
+
+	tests := []struct {
+		name     string
+		clientCA string
+		ca       string
+		cert     string
+		key      string
+		sn       string
+		ok       bool
+	}{
+		{
+			name: "Check TLS",
+			ca:   ca.crt,
+			sn:   "server",
+			ok:   true,
+		},
+		{
+			name: "Check TLS with unknown server ca",
+			ca:   other.crt,
+			sn:   "server",
+		},
+		{
+			name: "Check TLS with wrong server name",
+			ca:   ca.crt,
+			sn:   "wrong",
+		},
+		{
+			name:     "Check mTLS",
+			clientCA: ca.crt,
+			ca:       ca.crt,
+			cert:     cli.crt,
+			key:      cli.pem,
+			sn:       "server",
+			ok:       true,
+		},
+		{
+			name:     "Check mTLS without client certificate",
+			clientCA: ca.crt,
+			ca:       ca.crt,
+			sn:       "server",
+		},
+		{
+			name:     "Check mTLS with unknown client ca",
+			clientCA: other.crt,
+			ca:       ca.crt,
+			cert:     cli.crt,
+			key:      cli.pem,
+			sn:       "server",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sc, err := NewServer(srv.crt, srv.pem, test.clientCA)
+			require.NoError(t, err)
+
+			cc, err := NewClient(test.ca, test.cert, test.key, test.sn)
+			require.NoError(t, err)
+
+			err = handshake(t, sc, cc)
+			if test.ok {
+				require.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestConfigErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	ca := newPair(t, dir, "ca", nil, true)
+
+	_, err := NewServer("", "", "")
+	require.ErrorIs(t, err, ErrNoCertificate)
+
+	_, err = NewServer(ca.crt, ca.pem, ca.pem)
+	require.ErrorIs(t, err, ErrBadCertificate)
+
+	_, err = NewClient("", ca.crt, "", "")
+	require.ErrorIs(t, err, ErrNoCertificate)
+
+	_, err = NewClient(filepath.Join(dir, "not exist"), "", "", "")
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,82 @@
+// Package watch keeps the stream of item notifications open and triggers sync on each notification.
+package watch
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	gclient "github.com/k0st1a/gophkeeper/internal/adapters/api/grpc/client"
+	"github.com/k0st1a/gophkeeper/internal/pkg/tick"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// MinBackoff - задержка перед первой попыткой переподключения.
+	MinBackoff = time.Second
+	// MaxBackoff - максимальная задержка перед попыткой переподключения.
+	MaxBackoff = time.Minute
+)
+
+type watch struct {
+	watcher    gclient.ItemWatcher
+	trigger    tick.Triggerer
+	minBackoff time.Duration
+	maxBackoff time.Duration
+}
+
+// New - создать подписку на уведомления об изменениях предметов, где:
+//   - w - поток уведомлений;
+//   - t - запуск синхронизации по уведомлению;
+//   - minb, maxb - минимальная и максимальная задержка переподключения.
+func New(w gclient.ItemWatcher, t tick.Triggerer, minb, maxb time.Duration) *watch {
+	return &watch{
+		watcher:    w,
+		trigger:    t,
+		minBackoff: minb,
+		maxBackoff: maxb,
+	}
+}
+
+// Run - держать поток уведомлений открытым, переподключаясь с экспоненциальной задержкой.
+// Пока потока нет, синхронизация выполняется по тику.
+func (w *watch) Run(ctx context.Context) error {
+	log.Printf("Run watch")
+	backoff := w.minBackoff
+
+	for {
+		notified := false
+		err := w.watcher.WatchItems(ctx, func() {
+			notified = true
+			w.trigger.Trigger()
+		})
+
+		if ctx.Err() != nil {
+			log.Printf("Watch closed with cause:%s", ctx.Err())
+			return nil
+		}
+
+		// Поток был установлен, значит сервер доступен: начинаем задержки заново.
+		if notified {
+			backoff = w.minBackoff
+		}
+
+		delay := jitter(backoff)
+		log.Error().Err(err).Msgf("watch items interrupted, reconnect after %v", delay)
+
+		select {
+		case <-ctx.Done():
+			log.Printf("Watch closed with cause:%s", ctx.Err())
+			return nil
+		case <-time.After(delay):
+		}
+
+		backoff = min(backoff*2, w.maxBackoff)
+	}
+}
+
+// jitter - случайная задержка от d/2 до d, чтобы клиенты не переподключались одновременно после рестарта сервера.
+func jitter(d time.Duration) time.Duration {
+	half := d / 2
+	return half + rand.N(d-half+1)
+}
@@ -0,0 +1,72 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type watcher struct {
+	cancel func()
+	calls  []time.Time
+	// Количество уведомлений на каждый вызов WatchItems
+	notifications []int
+}
+
+func (w *watcher) WatchItems(ctx context.Context, notify func()) error {
+	w.calls = append(w.calls, time.Now())
+
+	n := len(w.calls) - 1
+	if n >= len(w.notifications) {
+		w.cancel()
+		return ctx.Err()
+	}
+
+	for range w.notifications[n] {
+		notify()
+	}
+
+	return errors.New("stream broken")
+}
+
+type trigger struct {
+	count int
+}
+
+func (t *trigger) Trigger() {
+	t.count++
+}
+
+func TestRun(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	w := &watcher{
+		cancel:        cancel,
+		notifications: []int{2, 0, 0, 1},
+	}
+	tr := &trigger{}
+
+	minb := 20 * time.Millisecond
+	err := New(w, tr, minb, 40*time.Millisecond).Run(ctx)
+	require.NoError(t, err)
+
+	require.Equal(t, 3, tr.count)
+	require.Len(t, w.calls, 5)
+
+	// Задержки растут, пока сервер недоступен, и не превышают максимальную.
+	require.GreaterOrEqual(t, w.calls[1].Sub(w.calls[0]), minb/2)
+	require.GreaterOrEqual(t, w.calls[2].Sub(w.calls[1]), minb)
+	require.GreaterOrEqual(t, w.calls[3].Sub(w.calls[2]), minb)
+}
+
+func TestJitter(t *testing.T) {
+	for range 100 {
+		d := jitter(time.Second)
+		require.GreaterOrEqual(t, d, time.Second/2)
+		require.LessOrEqual(t, d, time.Second)
+	}
+}
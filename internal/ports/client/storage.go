@@ -6,15 +6,23 @@ import (
 	"time"
 
 	"github.com/k0st1a/gophkeeper/internal/pkg/client/model"
+	"github.com/rs/zerolog/log"
 )
 
 type ItemStorage interface {
+	// Open - открыть хранилище пользователя login. Предметы разных пользователей хранятся раздельно.
+	Open(ctx context.Context, login string)
+	// Clear - забыть предметы пользователя при выходе.
 	Clear(ctx context.Context)
 	CreateItem(ctx context.Context, item *Item) (string, error)
 	UpdateItem(ctx context.Context, item *UpdateItem) error
 	GetItem(ctx context.Context, id string) (*Item, error)
 	ListItems(ctx context.Context) ([]Item, error)
 	DeleteItem(ctx context.Context, id string) error
+	// GetSyncCursor - получить курсор ленты изменений сервера, до которого выполнена синхронизация.
+	GetSyncCursor(ctx context.Context) (int64, error)
+	// SetSyncCursor - сохранить курсор ленты изменений сервера.
+	SetSyncCursor(ctx context.Context, cursor int64) error
 }
 
 // Item - предмет, хранящегося в базе на стороне клиента.
@@ -29,8 +37,16 @@ type Item struct {
 	ID string
 	// Идендификатор предмета на удаленном сервере
 	RemoteID int64
+	// Ревизия предмета на удаленном сервере, на которой основана локальная копия
+	Revision int64
+	// Коллекция организации, к которой относится предмет, 0 - личный предмет. Не меняется после создания.
+	CollectionID int64
+	// Описание конфликта синхронизации, если он был. Хранится только локально.
+	Conflict string
 	// Отметка о необходимости удаления предмета
 	DeleteMark bool
+	// Отметка о наличии локальных изменений, не отправленных на удаленный сервер
+	ChangeMark bool
 }
 
 // UpdateItem - для обновления полей предмета, хранящегося в базе на стороне клиента.
@@ -38,12 +54,18 @@ type Item struct {
 type UpdateItem struct {
 	// Идендификатор предмета на удаленном сервере
 	RemoteID *int64
+	// Ревизия предмета на удаленном сервере
+	Revision *int64
 	// Тело предмета
 	Body *model.Item
 	// Идендификатор предмета на удаленном сервере
 	UpdateTime *time.Time
 	// Отметка о необходимости удаления предмета
 	DeleteMark *bool
+	// Отметка о наличии локальных изменений, не отправленных на удаленный сервер
+	ChangeMark *bool
+	// Описание конфликта синхронизации
+	Conflict *string
 	// Идентификатор предмета
 	ID string
 }
@@ -69,3 +91,43 @@ func Map2List(m map[string]Item) []Item {
 	}
 	return l
 }
+
+// ApplyUpdate - применить к предмету изменения из UpdateItem.
+func ApplyUpdate(i *Item, ui *UpdateItem) {
+	log.Printf("Start ApplyUpdate(%v)", i.ID)
+	if ui.RemoteID != nil {
+		log.Printf("Update(%v) RemoteID:%v", i.ID, *ui.RemoteID)
+		i.RemoteID = *ui.RemoteID
+	}
+
+	if ui.Revision != nil {
+		log.Printf("Update(%v) Revision:%v", i.ID, *ui.Revision)
+		i.Revision = *ui.Revision
+	}
+
+	if ui.Body != nil {
+		log.Printf("Update(%v) Body", i.ID)
+		i.Body = *ui.Body
+	}
+
+	if ui.UpdateTime != nil {
+		log.Printf("Update(%v) UpdateTime:%v", i.ID, *ui.UpdateTime)
+		i.UpdateTime = *ui.UpdateTime
+	}
+
+	if ui.DeleteMark != nil {
+		log.Printf("Update(%v) DeleteMark:%v", i.ID, *ui.DeleteMark)
+		i.DeleteMark = *ui.DeleteMark
+	}
+
+	if ui.ChangeMark != nil {
+		log.Printf("Update(%v) ChangeMark:%v", i.ID, *ui.ChangeMark)
+		i.ChangeMark = *ui.ChangeMark
+	}
+
+	if ui.Conflict != nil {
+		log.Printf("Update(%v) Conflict:%v", i.ID, *ui.Conflict)
+		i.Conflict = *ui.Conflict
+	}
+	log.Printf("End ApplyUpdate")
+}
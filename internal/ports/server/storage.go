@@ -3,25 +3,300 @@ package server
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 )
 
 type UserStorage interface {
-	CreateUser(ctx context.Context, login, password string) (int64, error)
-	GetUserIDAndPassword(ctx context.Context, login string) (int64, string, error)
+	CreateUser(ctx context.Context, user *User) (int64, error)
+	GetUser(ctx context.Context, login string) (*User, error)
+	GetUserByID(ctx context.Context, userID int64) (*User, error)
+	SetVaultKey(ctx context.Context, userID int64, key *VaultKey) error
+	// SetKeyPair - сохранить пару ключей пользователя, если она еще не сохранена, иначе ErrKeyPairAlreadySet.
+	SetKeyPair(ctx context.Context, userID int64, kp *KeyPair) error
+	// SetSRPVerifier - сохранить верификатор SRP пароля и стереть хэш пароля, если верификатор еще не сохранен,
+	// иначе ErrSRPVerifierAlreadySet. Сменить пароль можно только через ChangePassword.
+	SetSRPVerifier(ctx context.Context, userID int64, v *SRPVerifier) error
+	// UpdatePasswordHash - заменить хэш пароля old на пересчитанный hash. Если хэш уже изменен
+	// (например, стерт SetSRPVerifier), то ничего не делается.
+	UpdatePasswordHash(ctx context.Context, userID int64, old, hash string) error
+	// ChangePassword - в одной транзакции заменить верификатор SRP и ключ хранилища пользователя old и отозвать
+	// все его сессии, кроме sessionID (пустой - все сессии). Если пароль пользователя изменен после чтения old,
+	// то ничего не делается и возвращается ErrPasswordChanged. Возвращает количество отозванных сессий.
+	ChangePassword(ctx context.Context, old *User, sessionID string, c *PasswordChange) (int64, error)
+	// SetRecoveryKey - в одной транзакции заменить ключ хранилища, зашифрованный ключом восстановления, и хэш ключа
+	// восстановления пользователя old и отозвать все его сессии, кроме sessionID. Если пароль пользователя изменен
+	// после чтения old, то ничего не делается и возвращается ErrPasswordChanged. Возвращает количество отозванных
+	// сессий.
+	SetRecoveryKey(ctx context.Context, old *User, sessionID string, key *RecoveryKey) (int64, error)
+}
+
+// PasswordChange - новый пароль пользователя.
+type PasswordChange struct {
+	// Ключ хранилища, зашифрованный новым паролем, nil - ключ не меняется
+	VaultKey *VaultKey
+	Verifier SRPVerifier
+	// Отключить второй фактор пользователя, например при восстановлении доступа без потерянного устройства
+	ResetSecondFactor bool
+}
+
+type User struct {
+	VaultKey VaultKey
+	SRP      SRPVerifier
+	KeyPair  KeyPair
+	Recovery RecoveryKey
+	Login    string
+	// Хэш пароля (Argon2id или bcrypt) пользователя, зарегистрированного до SRP, пустой после сохранения верификатора
+	Password string
+	ID       int64
+}
+
+// KeyPair - пара ключей X25519 пользователя для получения предметов от других пользователей.
+type KeyPair struct {
+	Public []byte
+	// Закрытый ключ, зашифрованный ключом хранилища пользователя
+	Private []byte
+}
+
+// RecoveryKey - ключ хранилища пользователя, зашифрованный ключом восстановления, и SHA-256 хэш значения,
+// которым клиент доказывает знание ключа восстановления. Все поля пустые, если ключ восстановления не создан.
+type RecoveryKey struct {
+	Salt []byte
+	Key  []byte
+	Hash []byte
+}
+
+// SRPVerifier - соль и верификатор SRP-6a пароля пользователя, по ним нельзя получить пароль.
+type SRPVerifier struct {
+	Salt     []byte
+	Verifier []byte
+}
+
+type SRPLoginStorage interface {
+	// CreateSRPLogin - сохранить начатый вход по SRP. Заодно удаляются истекшие входы.
+	CreateSRPLogin(ctx context.Context, login *SRPLogin) error
+	// TakeSRPLogin - получить и удалить действующий вход по хэшу токена, каждый вход завершается один раз.
+	TakeSRPLogin(ctx context.Context, hash []byte) (*SRPLogin, error)
+}
+
+// SRPLogin - начатый вход по SRP, ожидающий доказательства знания пароля.
+type SRPLogin struct {
+	ExpireTime time.Time
+	Login      string
+	Hash       []byte
+	// Закрытое значение сервера b
+	Secret  []byte
+	PublicA []byte
+	PublicB []byte
+	// Пользователь, 0 для незарегистрированного логина
+	UserID int64
+}
+
+// VaultKey - ключ хранилища пользователя, зашифрованный на стороне клиента.
+// Сервер хранит его как есть и не может расшифровать.
+type VaultKey struct {
+	Salt []byte
+	Key  []byte
+}
+
+var (
+	ErrLoginAlreadyBusy      = errors.New("login already busy")
+	ErrUserNotFound          = errors.New("user not found")
+	ErrVaultKeyAlreadySet    = errors.New("vault key already set")
+	ErrPasswordChanged       = errors.New("password changed concurrently")
+	ErrKeyPairAlreadySet     = errors.New("key pair already set")
+	ErrSRPLoginNotFound      = errors.New("srp login not found")
+	ErrSRPVerifierAlreadySet = errors.New("srp verifier already set")
+)
+
+type SessionStorage interface {
+	// CreateSession - создать сессию пользователя вместе с ее первым refresh токеном, возвращает идентификатор сессии.
+	// Заодно удаляются истекшие сессии пользователя.
+	CreateSession(ctx context.Context, session *Session, token *RefreshToken) (string, error)
+	// CheckSession - проверить, что сессия пользователя действует, и запомнить время и IP последнего обращения.
+	CheckSession(ctx context.Context, userID int64, sessionID, ip string) error
+	// ListSessions - получить действующие сессии пользователя, начиная с последней активной.
+	ListSessions(ctx context.Context, userID int64) ([]Session, error)
+	// RevokeSession - отозвать сессию пользователя вместе с ее refresh токенами.
+	RevokeSession(ctx context.Context, userID int64, sessionID string) error
+	// RevokeOtherSessions - отозвать все сессии пользователя, кроме sessionID. Возвращает количество отозванных сессий.
+	RevokeOtherSessions(ctx context.Context, userID int64, sessionID string) (int64, error)
+}
+
+// Session - сессия пользователя, создается при логине.
+type Session struct {
+	CreateTime time.Time
+	// Время последнего обращения к серверу
+	LastSeenTime time.Time
+	// Время, после которого сессия недействительна, продлевается при обновлении токена
+	ExpireTime time.Time
+	ID         string
+	// Имя устройства, переданное клиентом при логине
+	Device string
+	// IP адрес последнего обращения к серверу
+	IP     string
+	UserID int64
+}
+
+var (
+	ErrSessionNotFound = errors.New("session not found")
+)
+
+type LoginFailureStorage interface {
+	// CheckLogin - время, до которого запрещены попытки входа по любому из ключей, или нулевое время.
+	CheckLogin(ctx context.Context, keys []LoginKey) (time.Time, error)
+	// AddLoginFailure - учесть неудачную попытку входа по ключу, где delay - задержка после failures неудачных
+	// попыток подряд и признак блокировки, попытки старше window забываются.
+	// Блокировка записывается в события блокировок. Возвращает время, до которого запрещены попытки входа.
+	AddLoginFailure(ctx context.Context, key LoginKey, window time.Duration,
+		delay func(failures int) (time.Duration, bool)) (time.Time, error)
+	// ResetLoginFailures - забыть неудачные попытки входа по ключу.
+	ResetLoginFailures(ctx context.Context, key LoginKey) error
+	// ListLockouts - получить блокировки входа, начиная с последней. Если all false, то только действующие.
+	ListLockouts(ctx context.Context, all bool) ([]Lockout, error)
+	// ClearLockout - снять блокировку входа и забыть неудачные попытки по ее ключу.
+	ClearLockout(ctx context.Context, id int64) error
+}
+
+// Виды ключей неудачных попыток входа.
+const (
+	LoginKeyLogin = "login"
+	LoginKeyIP    = "ip"
+	// LoginKeyStart - начатые с IP адреса входы по SRP, учитываются все, а не только неудачные.
+	LoginKeyStart = "start"
+)
+
+// LoginKey - ключ, по которому считаются неудачные попытки входа.
+type LoginKey struct {
+	Kind  string
+	Value string
+}
+
+// Lockout - блокировка входа после неудачных попыток.
+type Lockout struct {
+	CreateTime  time.Time
+	LockedUntil time.Time
+	// Время снятия блокировки администратором
+	ClearTime *time.Time
+	Key       LoginKey
+	ID        int64
+	Failures  int
+}
+
+var (
+	ErrLockoutNotFound = errors.New("lockout not found")
+)
+
+type SecondFactorStorage interface {
+	// GetSecondFactor - получить второй фактор пользователя.
+	GetSecondFactor(ctx context.Context, userID int64) (*SecondFactor, error)
+	// EnrollSecondFactor - создать неподтвержденный второй фактор с резервными кодами, заменив прежний
+	// неподтвержденный. Если второй фактор уже подключен, то ErrSecondFactorEnabled.
+	EnrollSecondFactor(ctx context.Context, userID int64, secret string, backupCodes [][]byte) error
+	// EnableSecondFactor - подключить второй фактор, подтвержденный кодом интервала step.
+	EnableSecondFactor(ctx context.Context, userID, step int64) error
+	// DeleteSecondFactor - отключить второй фактор вместе с резервными кодами.
+	DeleteSecondFactor(ctx context.Context, userID int64) error
+	// UseTOTPStep - запомнить интервал принятого кода TOTP. Если код этого интервала уже принят, то ErrCodeUsed.
+	UseTOTPStep(ctx context.Context, userID, step int64) error
+	// UseBackupCode - погасить резервный код по хэшу. Если кода нет или он уже погашен, то ErrCodeUsed.
+	UseBackupCode(ctx context.Context, userID int64, hash []byte) error
+
+	// CreateLoginChallenge - создать вход, ожидающий подтверждения вторым фактором.
+	// Заодно удаляются истекшие входы пользователя.
+	CreateLoginChallenge(ctx context.Context, challenge *LoginChallenge) error
+	// GetLoginChallenge - получить действующий вход по хэшу токена.
+	GetLoginChallenge(ctx context.Context, hash []byte) (*LoginChallenge, error)
+	// FailLoginChallenge - учесть неудачную попытку подтверждения входа, после maxAttempts попыток вход удаляется.
+	FailLoginChallenge(ctx context.Context, hash []byte, maxAttempts int) error
+	// DeleteLoginChallenge - удалить вход после подтверждения.
+	DeleteLoginChallenge(ctx context.Context, hash []byte) error
+}
+
+// SecondFactor - второй фактор входа пользователя (TOTP).
+type SecondFactor struct {
+	// Секрет TOTP в кодировке base32
+	Secret string
+	// Последний принятый интервал TOTP
+	LastStep int64
+	// Второй фактор подтвержден кодом и требуется при входе
+	Enabled bool
+}
+
+// LoginChallenge - вход, пароль которого проверен, ожидающий подтверждения вторым фактором.
+type LoginChallenge struct {
+	ExpireTime time.Time
+	// Логин пользователя, заполняется при получении
+	Login string
+	// Имя устройства, переданное клиентом при логине
+	Device string
+	Hash   []byte
+	UserID int64
 }
 
 var (
-	ErrLoginAlreadyBusy = errors.New("login already busy")
-	ErrUserNotFound     = errors.New("user not found")
+	ErrSecondFactorNotFound   = errors.New("second factor not found")
+	ErrSecondFactorEnabled    = errors.New("second factor already enabled")
+	ErrCodeUsed               = errors.New("code already used")
+	ErrLoginChallengeNotFound = errors.New("login challenge not found")
 )
 
+type RefreshTokenStorage interface {
+	// RotateRefreshToken - заменить действующий refresh токен с хэшем hash на next из той же сессии и продлить сессию.
+	// Заполняет пользователя и сессию в next. Если токен уже был заменен, то сессия отзывается.
+	RotateRefreshToken(ctx context.Context, hash []byte, next *RefreshToken) error
+}
+
+// RefreshToken - refresh токен пользователя, хранится только его хэш.
+type RefreshToken struct {
+	// Время, после которого токен недействителен
+	ExpireTime time.Time
+	SessionID  string
+	Hash       []byte
+	UserID     int64
+}
+
+var (
+	ErrRefreshTokenNotFound = errors.New("refresh token not found")
+	ErrRefreshTokenExpired  = errors.New("refresh token expired")
+	ErrRefreshTokenReused   = errors.New("refresh token reused")
+)
+
+// ItemStorage - предметы пользователя: личные предметы и предметы коллекций организаций, в которых он состоит.
+// Права участника организации на изменение предметов коллекций проверяются вызывающим.
 type ItemStorage interface {
+	// CreateItem - создать предмет, предмет коллекции создается, если item.CollectionID не 0.
+	// Для предметов коллекций роль пользователя проверяется в той же транзакции, что и изменение: возвращается
+	// ErrCollectionNotFound, если пользователь не состоит в организации коллекции, и ErrItemReadOnly, если роль
+	// не позволяет изменять предметы. Это же относится к UpdateItem и DeleteItem.
 	CreateItem(ctx context.Context, userID int64, item *Item) (int64, error)
-	UpdateItem(ctx context.Context, userID int64, item *Item) error
+	// UpdateItem - обновить предмет, если его текущая ревизия равна item.Revision. Возвращает новую ревизию,
+	// коллекция предмета записывается в item.CollectionID.
+	UpdateItem(ctx context.Context, userID int64, item *Item) (int64, error)
 	GetItem(ctx context.Context, userID int64, itemID int64) (*Item, error)
 	ListItems(ctx context.Context, userID int64) ([]Item, error)
-	DeleteItem(ctx context.Context, userID int64, itemID int64) error
+	// DeleteItem - переместить предмет в корзину. Возвращает коллекцию предмета, 0 - личный предмет.
+	DeleteItem(ctx context.Context, userID int64, itemID int64) (int64, error)
+	// ListChanges - получить предметы, созданные, обновленные или удаленные после номера изменения cursor.
+	ListChanges(ctx context.Context, userID int64, cursor int64) (*Changes, error)
+}
+
+type ItemTrashStorage interface {
+	// ListTrash - получить предметы в корзине, начиная с последнего удаленного.
+	ListTrash(ctx context.Context, userID int64) ([]TrashItem, error)
+	// RestoreItem - восстановить предмет из корзины.
+	RestoreItem(ctx context.Context, userID int64, itemID int64) (*Item, error)
+}
+
+type ItemHistoryStorage interface {
+	// ListItemVersions - получить прошлые версии предмета, начиная с последней. Версии предмета коллекции
+	// доступны всем участникам организации.
+	ListItemVersions(ctx context.Context, userID int64, itemID int64) ([]ItemVersion, error)
+	// RestoreItemVersion - сделать прошлую версию личного предмета текущей, удаленный предмет создается заново.
+	// Если expected не 0, то текущая ревизия предмета должна быть равна expected.
+	RestoreItemVersion(ctx context.Context, userID int64, itemID int64, revision int64, expected int64) (*Item, error)
+	// SetHistoryRetention - задать количество хранимых прошлых версий каждого предмета пользователя.
+	SetHistoryRetention(ctx context.Context, userID int64, versions int) error
 }
 
 type Item struct {
@@ -29,6 +304,317 @@ type Item struct {
 	UpdateTime time.Time
 	Data       []byte
 	ID         int64
+	// Ревизия предмета, назначается сервером и увеличивается при каждом обновлении.
+	Revision int64
+	// Блобы, на которые ссылается предмет
+	BlobIDs []string
+	// Коллекция организации, к которой относится предмет, 0 - личный предмет пользователя
+	CollectionID int64
+}
+
+// TrashItem - предмет в корзине.
+type TrashItem struct {
+	// Время удаления предмета в корзину
+	DeleteTime time.Time
+	Item       Item
+}
+
+// ItemVersion - прошлая версия предмета.
+type ItemVersion struct {
+	// Время сохранения версии
+	ArchiveTime time.Time
+	// Предмет до обновления или удаления
+	Item Item
+	// Версия сохранена при удалении предмета
+	Deleted bool
+}
+
+// Changes - изменения предметов пользователя после номера изменения.
+type Changes struct {
+	// Созданные или обновленные предметы
+	Items []Item
+	// Идентификаторы удаленных предметов
+	DeletedIDs []int64
+	// Номер последнего изменения
+	Cursor int64
+	// Items содержит все предметы пользователя, т.к. номер изменения неизвестен серверу
+	Full bool
+}
+
+// InitialRevision - ревизия созданного предмета.
+const InitialRevision int64 = 1
+
+var (
+	ErrItemNotFound     = errors.New("item not found")
+	ErrRevisionMismatch = errors.New("revision mismatch")
+	ErrVersionNotFound  = errors.New("item version not found")
+	ErrItemReadOnly     = errors.New("role can not change items of collection")
+	// ErrCollectionItemBlobs - предметы коллекций не ссылаются на блобы: блобы принадлежат пользователю
+	// и другим участникам недоступны.
+	ErrCollectionItemBlobs = errors.New("items of collection can not have blobs")
+)
+
+// RevisionMismatchError - предмет был изменен после ожидаемой ревизии.
+type RevisionMismatchError struct {
+	// Текущая ревизия предмета
+	Revision int64
+}
+
+func (e *RevisionMismatchError) Error() string {
+	return fmt.Sprintf("%v, current revision:%v", ErrRevisionMismatch, e.Revision)
+}
+
+func (e *RevisionMismatchError) Unwrap() error {
+	return ErrRevisionMismatch
+}
+
+type BlobStorage interface {
+	GetBlob(ctx context.Context, userID int64, blobID string) (*Blob, error)
+	// AddBlobChunk - добавить следующий блок блоба, блок с индексом 0 создает блоб. Если quota не 0, то
+	// суммарный размер блобов пользователя вместе с блоком не должен превышать quota, иначе ErrBlobQuotaExceeded.
+	AddBlobChunk(ctx context.Context, userID int64, blobID string, chunk *BlobChunk, quota int64) (*Blob, error)
+	GetBlobChunk(ctx context.Context, userID int64, blobID string, index int64) (*BlobChunk, error)
+}
+
+// Blob - большие бинарные данные пользователя, загружаемые блоками.
+// Содержимое блоков зашифровано на стороне клиента.
+type Blob struct {
+	ID string
+	// Количество загруженных блоков
+	Chunks int64
+	// Размер загруженных данных
+	Size int64
+	// Загружен последний блок
+	Complete bool
+}
+
+// BlobChunk - блок блоба.
+type BlobChunk struct {
+	Data  []byte
+	Index int64
+	Last  bool
+}
+
+var (
+	ErrBlobNotFound        = errors.New("blob not found")
+	ErrBlobComplete        = errors.New("blob already complete")
+	ErrUnexpectedBlobChunk = errors.New("unexpected blob chunk")
+	ErrBlobQuotaExceeded   = errors.New("blob quota exceeded")
+)
+
+type ShareStorage interface {
+	// CreateShare - сохранить копию предмета владельца для получателя. Повторный доступ к тому же предмету
+	// для того же получателя заменяет копию. Возвращает ErrItemNotFound, если предмета нет у владельца,
+	// он в корзине или это предмет коллекции.
+	CreateShare(ctx context.Context, share *Share) (int64, error)
+	// DeleteShare - отозвать доступ, отозвать может как владелец, так и получатель.
+	DeleteShare(ctx context.Context, userID, shareID int64) error
+	// ListShares - доступы, выданные пользователем.
+	ListShares(ctx context.Context, ownerID int64) ([]Share, error)
+	// ListSharedWithMe - доступы, выданные пользователю, без предметов в корзине.
+	ListSharedWithMe(ctx context.Context, recipientID int64) ([]Share, error)
+}
+
+// Share - копия предмета, зашифрованная открытым ключом получателя.
+type Share struct {
+	CreateTime time.Time
+	UpdateTime time.Time
+	// Логин владельца предмета
+	Owner string
+	// Логин получателя
+	Recipient   string
+	Data        []byte
+	ID          int64
+	ItemID      int64
+	OwnerID     int64
+	RecipientID int64
+}
+
+var (
+	ErrShareNotFound = errors.New("share not found")
+)
+
+type OrgStorage interface {
+	// CreateOrg - создать организацию, владельцем которой становится owner.
+	CreateOrg(ctx context.Context, org *Org, owner *OrgMember) (int64, error)
+	// DeleteOrg - удалить организацию вместе с ее коллекциями и их предметами.
+	DeleteOrg(ctx context.Context, orgID int64) error
+	// ListOrgs - организации, в которых состоит пользователь, с его ролью и ключом организации.
+	ListOrgs(ctx context.Context, userID int64) ([]OrgMember, error)
+	// GetRole - роль пользователя в организации, ErrNotOrgMember, если он в ней не состоит.
+	GetRole(ctx context.Context, orgID, userID int64) (Role, error)
+	ListMembers(ctx context.Context, orgID int64) ([]OrgMember, error)
+	// SetMemberRole - назначить роль участнику. Возвращает ErrLastOwner, если это последний владелец
+	// организации и новая роль не владелец.
+	SetMemberRole(ctx context.Context, orgID, userID int64, role Role) error
+	// DeleteMember - исключить участника, предметы коллекций организации удаляются из его ленты изменений.
+	// Возвращает ErrLastOwner, если это последний владелец организации.
+	DeleteMember(ctx context.Context, orgID, userID int64) error
+	// CreateInvitation - пригласить пользователя, повторное приглашение заменяет прошлое.
+	// Возвращает ErrAlreadyOrgMember, если пользователь уже состоит в организации.
+	CreateInvitation(ctx context.Context, inv *Invitation) (int64, error)
+	// ListInvitations - приглашения пользователя.
+	ListInvitations(ctx context.Context, userID int64) ([]Invitation, error)
+	// AcceptInvitation - принять приглашение, предметы коллекций организации добавляются в ленту изменений.
+	AcceptInvitation(ctx context.Context, userID, invitationID int64) error
+	// DeleteInvitation - отклонить приглашение пользователя.
+	DeleteInvitation(ctx context.Context, userID, invitationID int64) error
+	CreateCollection(ctx context.Context, c *Collection) (int64, error)
+	GetCollection(ctx context.Context, collectionID int64) (*Collection, error)
+	// ListCollections - коллекции организаций, в которых состоит пользователь.
+	ListCollections(ctx context.Context, userID int64) ([]Collection, error)
+	// DeleteCollection - удалить коллекцию вместе с ее предметами.
+	DeleteCollection(ctx context.Context, collectionID int64) error
+	// ListCollectionMembers - участники организации коллекции, например, для уведомления об изменении предметов.
+	ListCollectionMembers(ctx context.Context, collectionID int64) ([]int64, error)
+}
+
+// Role - роль участника организации.
+type Role string
+
+const (
+	// RoleOwner - управляет организацией и всеми участниками, в том числе другими владельцами.
+	RoleOwner Role = "owner"
+	// RoleAdmin - управляет коллекциями, участниками и приглашениями, кроме владельцев и администраторов.
+	RoleAdmin Role = "admin"
+	// RoleMember - читает и изменяет предметы коллекций.
+	RoleMember Role = "member"
+	// RoleReadOnly - только читает предметы коллекций.
+	RoleReadOnly Role = "read-only"
+)
+
+// Org - организация, предметы которой доступны ее участникам.
+type Org struct {
+	CreateTime time.Time
+	Name       string
+	ID         int64
+}
+
+// OrgMember - участник организации.
+type OrgMember struct {
+	CreateTime time.Time
+	// Название организации
+	OrgName string
+	// Логин участника
+	Login string
+	Role  Role
+	// Ключ организации, зашифрованный открытым ключом участника
+	OrgKey []byte
+	OrgID  int64
+	UserID int64
+}
+
+// Invitation - приглашение пользователя в организацию.
+type Invitation struct {
+	CreateTime time.Time
+	// Название организации
+	OrgName string
+	// Логин пригласившего
+	Inviter string
+	Role    Role
+	// Ключ организации, зашифрованный открытым ключом приглашенного
+	OrgKey    []byte
+	ID        int64
+	OrgID     int64
+	UserID    int64
+	InviterID int64
 }
 
-var ErrItemNotFound = errors.New("item not found")
+// Collection - коллекция предметов организации.
+type Collection struct {
+	CreateTime time.Time
+	// Название коллекции, зашифрованное ключом организации
+	Name []byte
+	// Ключ коллекции, которым шифруются ее предметы, зашифрованный ключом организации
+	Key   []byte
+	ID    int64
+	OrgID int64
+}
+
+var (
+	ErrOrgNotFound        = errors.New("organization not found")
+	ErrNotOrgMember       = errors.New("user is not member of organization")
+	ErrAlreadyOrgMember   = errors.New("user is already member of organization")
+	ErrInvitationNotFound = errors.New("invitation not found")
+	ErrCollectionNotFound = errors.New("collection not found")
+	ErrLastOwner          = errors.New("last owner of organization")
+)
+
+type EmergencyStorage interface {
+	// CreateEmergencyContact - назначить доверенное лицо владельца. Повторное назначение того же пользователя
+	// заменяет прежнее и сбрасывает запрос доступа.
+	CreateEmergencyContact(ctx context.Context, c *EmergencyContact) (int64, error)
+	// GetEmergencyContact - доверенное лицо по идентификатору, ErrEmergencyContactNotFound, если его нет.
+	GetEmergencyContact(ctx context.Context, id int64) (*EmergencyContact, error)
+	// ListEmergencyContacts - доверенные лица, назначенные владельцем.
+	ListEmergencyContacts(ctx context.Context, ownerID int64) ([]EmergencyContact, error)
+	// ListEmergencyGrantors - назначения пользователя доверенным лицом другими пользователями.
+	ListEmergencyGrantors(ctx context.Context, granteeID int64) ([]EmergencyContact, error)
+	// DeleteEmergencyContact - удалить доверенное лицо, удалить может как владелец, так и доверенное лицо.
+	DeleteEmergencyContact(ctx context.Context, userID, id int64) error
+	// RequestEmergencyAccess - запросить доступ: idle -> requested, доступ будет одобрен автоматически через
+	// wait_days дней. Возвращает время автоматического одобрения, ErrEmergencyStateChanged, если состояние не idle.
+	RequestEmergencyAccess(ctx context.Context, id int64) (time.Time, error)
+	// ApproveEmergencyAccess - одобрить запрос раньше срока: requested -> approved.
+	// Возвращает ErrEmergencyStateChanged, если состояние не requested.
+	ApproveEmergencyAccess(ctx context.Context, id int64) error
+	// RejectEmergencyAccess - отклонить запрос или отозвать доступ: requested или approved -> idle.
+	// Возвращает ErrEmergencyStateChanged, если состояние idle.
+	RejectEmergencyAccess(ctx context.Context, id int64) error
+	// ApproveExpiredEmergencyRequests - одобрить запросы, время автоматического одобрения которых наступило
+	// до now. Возвращает количество одобренных запросов.
+	ApproveExpiredEmergencyRequests(ctx context.Context, now time.Time) (int64, error)
+	// TakeoverAccount - в одной транзакции сменить пароль владельца old, отозвать все его сессии, отключить второй
+	// фактор и вернуть доступ id в состояние idle, чтобы повторный захват требовал нового запроса. Возвращает
+	// количество отозванных сессий, ErrEmergencyStateChanged, если доступ уже не одобрен, и ErrPasswordChanged,
+	// если пароль владельца изменен после чтения old.
+	TakeoverAccount(ctx context.Context, id int64, old *User, c *PasswordChange) (int64, error)
+}
+
+// EmergencyAccess - доступ доверенного лица к хранилищу владельца.
+type EmergencyAccess string
+
+const (
+	// EmergencyAccessView - чтение личных предметов владельца.
+	EmergencyAccessView EmergencyAccess = "view"
+	// EmergencyAccessTakeover - чтение предметов и установка нового пароля владельца.
+	EmergencyAccessTakeover EmergencyAccess = "takeover"
+)
+
+// EmergencyState - состояние экстренного доступа.
+type EmergencyState string
+
+const (
+	// EmergencyStateIdle - доверенное лицо назначено, доступ не запрошен.
+	EmergencyStateIdle EmergencyState = "idle"
+	// EmergencyStateRequested - доступ запрошен и будет одобрен автоматически в ApproveTime.
+	EmergencyStateRequested EmergencyState = "requested"
+	// EmergencyStateApproved - доступ одобрен.
+	EmergencyStateApproved EmergencyState = "approved"
+)
+
+// EmergencyContact - доверенное лицо владельца.
+type EmergencyContact struct {
+	CreateTime time.Time
+	// Время запроса доступа, нулевое в состоянии idle
+	RequestTime time.Time
+	// Время автоматического одобрения в состоянии requested или время одобрения в состоянии approved
+	ApproveTime time.Time
+	// Логин владельца
+	Owner string
+	// Логин доверенного лица
+	Grantee string
+	Access  EmergencyAccess
+	State   EmergencyState
+	// Ключ хранилища владельца, зашифрованный открытым ключом доверенного лица
+	VaultKey  []byte
+	ID        int64
+	OwnerID   int64
+	GranteeID int64
+	WaitDays  int32
+}
+
+var (
+	ErrEmergencyContactNotFound = errors.New("emergency contact not found")
+	ErrEmergencyStateChanged    = errors.New("emergency access state changed")
+)
@@ -97,6 +97,21 @@ func (mr *MockItemStorageMockRecorder) GetItem(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItem", reflect.TypeOf((*MockItemStorage)(nil).GetItem), ctx, id)
 }
 
+// GetSyncCursor mocks base method.
+func (m *MockItemStorage) GetSyncCursor(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSyncCursor", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSyncCursor indicates an expected call of GetSyncCursor.
+func (mr *MockItemStorageMockRecorder) GetSyncCursor(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSyncCursor", reflect.TypeOf((*MockItemStorage)(nil).GetSyncCursor), ctx)
+}
+
 // ListItems mocks base method.
 func (m *MockItemStorage) ListItems(ctx context.Context) ([]client.Item, error) {
 	m.ctrl.T.Helper()
@@ -112,6 +127,32 @@ func (mr *MockItemStorageMockRecorder) ListItems(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListItems", reflect.TypeOf((*MockItemStorage)(nil).ListItems), ctx)
 }
 
+// Open mocks base method.
+func (m *MockItemStorage) Open(ctx context.Context, login string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Open", ctx, login)
+}
+
+// Open indicates an expected call of Open.
+func (mr *MockItemStorageMockRecorder) Open(ctx, login any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Open", reflect.TypeOf((*MockItemStorage)(nil).Open), ctx, login)
+}
+
+// SetSyncCursor mocks base method.
+func (m *MockItemStorage) SetSyncCursor(ctx context.Context, cursor int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSyncCursor", ctx, cursor)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetSyncCursor indicates an expected call of SetSyncCursor.
+func (mr *MockItemStorageMockRecorder) SetSyncCursor(ctx, cursor any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSyncCursor", reflect.TypeOf((*MockItemStorage)(nil).SetSyncCursor), ctx, cursor)
+}
+
 // UpdateItem mocks base method.
 func (m *MockItemStorage) UpdateItem(ctx context.Context, item *client.UpdateItem) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,11 @@
+// Package openapi is OpenAPI (Swagger) description of gophkeeper API generated from proto files.
+package openapi
+
+import (
+	_ "embed"
+)
+
+// Spec - описание API в формате OpenAPI v2, см. Makefile цель openapi2-generate.
+//
+//go:embed gophkeeper.swagger.json
+var Spec []byte